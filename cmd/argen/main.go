@@ -6,10 +6,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"runtime"
+	"syscall"
 
 	argen "github.com/mailru/activerecord/internal/app"
 	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/generator"
 	"golang.org/x/mod/modfile"
 )
 
@@ -37,6 +41,18 @@ func main() {
 	destinationDir := flag.String("destination", "generated", "generation subdir")
 	moduleName := flag.String("module", "", "module name from go.mod")
 	version := flag.Bool("version", false, "print version")
+	localPrefix := flag.String("local", "", "comma-separated import prefixes grouped after 3rd-party packages in generated files")
+	templatesDir := flag.String("templates", "", "path to dir with *.tmpl files overriding embedded templates")
+	manifestPath := flag.String("manifest", "", "path to JSON manifest of generated files")
+	incremental := flag.Bool("incremental", false, "regenerate only packages whose declaration changed since the manifest was written (requires -manifest)")
+	dryRun := flag.Bool("dry_run", false, "print diff with existing generated files instead of writing them, fail if files differ")
+	typeCheck := flag.Bool("type_check", false, "type check generated packages (slow)")
+	ddl := flag.Bool("ddl", false, "generate SQL DDL file (CREATE TABLE) for each model")
+	proto := flag.Bool("proto", false, "generate protobuf message file (.proto) for each model")
+	stableHeader := flag.Bool("stable_header", false, "omit generator version and generation time from headers of generated files and write them to .argen file of destination dir")
+	strictUnused := flag.Bool("strict_unused", false, "fail generation if a model declares serializers, mutators or triggers not used by any field or lifecycle phase")
+	concurrency := flag.Int("concurrency", runtime.NumCPU(), "max number of packages generated in parallel")
+	watch := flag.Bool("watch", false, "watch declaration and templates dirs and regenerate on change")
 	flag.Parse()
 
 	if *version {
@@ -59,12 +75,49 @@ func main() {
 		}
 	}
 
-	gen, err := argen.Init(ctx, getAppInfo(), srcDir, dstDir, *fixturePath, *moduleName)
-	if err != nil {
-		log.Fatalf("error initialization: %s", err)
+	generator.GenerateConcurrency = *concurrency
+
+	run := func() error {
+		gen, err := argen.Init(ctx, getAppInfo(), srcDir, dstDir, *fixturePath, *moduleName)
+		if err != nil {
+			return fmt.Errorf("error initialization: %w", err)
+		}
+
+		genOpts := generator.Options{LocalPrefix: *localPrefix, TypeCheck: *typeCheck, DDL: *ddl, Proto: *proto, StableHeader: *stableHeader, StrictUnused: *strictUnused}
+
+		if *templatesDir != "" {
+			genOpts.TemplateOverrides, err = generator.LoadTemplateOverrides(*templatesDir)
+			if err != nil {
+				return fmt.Errorf("error load templates: %w", err)
+			}
+		}
+
+		gen.WithGenerateOptions(genOpts).WithManifest(*manifestPath).WithIncremental(*incremental).WithDryRun(*dryRun)
+
+		if err := gen.Run(); err != nil {
+			return fmt.Errorf("error generate repository: %w", err)
+		}
+
+		return nil
+	}
+
+	if *watch {
+		roots := []string{srcDir}
+		if *templatesDir != "" {
+			roots = append(roots, *templatesDir)
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if err := argen.Watch(ctx, roots, argen.DefaultWatchOptions, run); err != nil {
+			log.Fatalf("error watch: %s", err)
+		}
+
+		return
 	}
 
-	if err := gen.Run(); err != nil {
-		log.Fatalf("error generate repository: %s", err)
+	if err := run(); err != nil {
+		log.Fatal(err)
 	}
 }
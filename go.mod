@@ -4,9 +4,15 @@ go 1.19
 
 require (
 	github.com/gobwas/pool v0.2.1
+	github.com/google/go-cmp v0.5.9
+	github.com/google/uuid v1.3.0
 	github.com/mailru/mapstructure v0.0.0-20230117153631-a4140f9ccc45
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.4
+	github.com/tarantool/go-tarantool v1.12.0
+	github.com/vmihailenco/msgpack/v5 v5.3.5
 	golang.org/x/mod v0.7.0
 	golang.org/x/net v0.7.0
 	golang.org/x/sync v0.1.0
@@ -20,7 +26,12 @@ require (
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
-	github.com/google/go-cmp v0.5.9 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/golang/protobuf v1.3.1 // indirect
+	github.com/mattn/go-pointer v0.0.1 // indirect
+	github.com/spacemonkeygo/spacelog v0.0.0-20180420211403-2296661a0572 // indirect
 	github.com/stretchr/objx v0.5.0 // indirect
+	github.com/tarantool/go-openssl v0.0.8-0.20230307065445-720eeb389195 // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
+	google.golang.org/appengine v1.6.7 // indirect
+	gopkg.in/vmihailenco/msgpack.v2 v2.9.2 // indirect
 )
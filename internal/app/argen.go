@@ -14,6 +14,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
 	"sync"
 
@@ -46,6 +47,42 @@ type ArGen struct {
 	modName            string
 	fileToRemove       map[string]bool
 	dstFixture         string
+	generateOpts       generator.Options
+	manifestPath       string
+	dryRun             bool
+	incremental        bool
+	generated          []generator.GenerateFile
+	kept               []generator.ManifestFile
+}
+
+// WithGenerateOptions задаёт параметры форматирования сгенерированных файлов
+func (a *ArGen) WithGenerateOptions(opts generator.Options) *ArGen {
+	a.generateOpts = opts
+
+	return a
+}
+
+// WithManifest задаёт путь к файлу, в который записывается манифест сгенерированных файлов
+func (a *ArGen) WithManifest(path string) *ArGen {
+	a.manifestPath = path
+
+	return a
+}
+
+// WithIncremental включает генерацию только тех пакетов, декларации которых изменились с момента
+// записи манифеста. Требует указания пути к манифесту
+func (a *ArGen) WithIncremental(incremental bool) *ArGen {
+	a.incremental = incremental
+
+	return a
+}
+
+// WithDryRun включает режим, в котором сгенерированные файлы не записываются на диск,
+// а выводится разница с существующими файлами
+func (a *ArGen) WithDryRun(dryRun bool) *ArGen {
+	a.dryRun = dryRun
+
+	return a
 }
 
 // Пропускает этап генерации сторов фикстур,
@@ -145,25 +182,65 @@ func (a *ArGen) prepareGenerate(cl *ds.RecordPackage) (map[string]ds.RecordPacka
 		}
 	}
 
+	if err := a.prepareEmbedded(cl); err != nil {
+		return nil, err
+	}
+
 	// Подготавливаем информацию по типам индексов
 	for indexNum := range cl.Indexes {
 		if len(cl.Indexes[indexNum].Fields) > 1 {
 			cl.Indexes[indexNum].Type = cl.Indexes[indexNum].Name + "IndexType"
+		} else if fld := cl.Fields[cl.Indexes[indexNum].Fields[0]]; fld.NamedType() != "" {
+			cl.Indexes[indexNum].Type = fld.NamedType()
 		} else {
-			cl.Indexes[indexNum].Type = string(cl.Fields[cl.Indexes[indexNum].Fields[0]].Format)
+			cl.Indexes[indexNum].Type = string(fld.Format)
 		}
 	}
 
 	return linkObjects, nil
 }
 
-func (a *ArGen) prepareFixtureGenerate(cl *ds.RecordPackage, name string) error {
+// Поддерживаемые бекенды встраиваемых моделей: в их пакетах генерируются функции упаковки записи в поле другой модели
+var embeddedBackends = map[string]bool{"octopus": true, "tarantool15": true, "tarantool2": true}
+
+// prepareEmbedded заполняет сериализаторы встроенных моделей путём импорта, типом и функциями
+// сгенерированного пакета встраиваемой модели
+func (a *ArGen) prepareEmbedded(cl *ds.RecordPackage) error {
+	for name, sd := range cl.SerializerMap {
+		if sd.Embed == "" {
+			continue
+		}
+
+		embedded := a.packagesParsed[a.packagesLinked[sd.Embed]]
+
+		for _, backend := range embedded.Backends {
+			if !embeddedBackends[backend] {
+				return &arerror.ErrCheckPackageLinkedDecl{Pkg: cl.Namespace.PackageName, Object: sd.Embed, Err: arerror.ErrCheckBackendFeatureUnsupported}
+			}
+		}
+
+		if _, err := cl.FindOrAddImport(embedded.Namespace.ModuleName, embedded.Namespace.PackageName); err != nil {
+			return fmt.Errorf("error process `%s` embedded serializer for package `%s`: %s", name, cl.Namespace.PublicName, err)
+		}
+
+		sd.Pkg = embedded.Namespace.ModuleName
+		sd.ImportName = embedded.Namespace.PackageName
+		sd.Type = "*" + embedded.Namespace.PackageName + "." + embedded.Namespace.PublicName
+		cl.SerializerMap[name] = sd
+	}
+
+	return nil
+}
+
+func (a *ArGen) prepareFixtureGenerate(cl *ds.RecordPackage, name string) (map[string]ds.RecordPackage, error) {
+	linkObjects := map[string]ds.RecordPackage{}
+
 	for _, fo := range cl.FieldsObjectMap {
-		linkObjectPackage := *a.packagesParsed[a.packagesLinked[fo.ObjectName]]
+		linkObjects[fo.ObjectName] = *a.packagesParsed[a.packagesLinked[fo.ObjectName]]
 
-		_, err := cl.FindOrAddImport(linkObjectPackage.Namespace.ModuleName, linkObjectPackage.Namespace.PackageName)
+		_, err := cl.FindOrAddImport(linkObjects[fo.ObjectName].Namespace.ModuleName, linkObjects[fo.ObjectName].Namespace.PackageName)
 		if err != nil {
-			return fmt.Errorf("error process `%s` linkObject for package `%s`: %s", fo.ObjectName, cl.Namespace.PublicName, err)
+			return nil, fmt.Errorf("error process `%s` linkObject for package `%s`: %s", fo.ObjectName, cl.Namespace.PublicName, err)
 		}
 	}
 
@@ -171,10 +248,10 @@ func (a *ArGen) prepareFixtureGenerate(cl *ds.RecordPackage, name string) error
 
 	_, err := cl.FindOrAddImport(recordPackage.Namespace.ModuleName, name)
 	if err != nil {
-		return fmt.Errorf("error process `%s` add import declaration for package `%s`: %s", name, cl.Namespace.PublicName, err)
+		return nil, fmt.Errorf("error process `%s` add import declaration for package `%s`: %s", name, cl.Namespace.PublicName, err)
 	}
 
-	return nil
+	return linkObjects, nil
 }
 
 func (a *ArGen) saveGenerateResult(name, dst string, genRes []generator.GenerateFile) error {
@@ -182,6 +259,17 @@ func (a *ArGen) saveGenerateResult(name, dst string, genRes []generator.Generate
 		dirPkg := filepath.Join(dst, gen.Dir)
 		dstFileName := filepath.Join(dirPkg, gen.Name)
 
+		saved := gen
+		saved.Dir = dirPkg
+		a.generated = append(a.generated, saved)
+
+		if a.dryRun {
+			delete(a.fileToRemove, dstFileName)
+			delete(a.fileToRemove, dirPkg)
+
+			continue
+		}
+
 		// Сохранение результата генерации в файл
 		log.Printf("Write package `%s` (%s) into file `%s`", name, dstFileName, dstFileName)
 
@@ -210,36 +298,51 @@ func (a *ArGen) saveGenerateResult(name, dst string, genRes []generator.Generate
 // Процесс генерации пакетов по подготовленным данным
 func (a *ArGen) generate() error {
 	metadata := generator.MetaData{
-		AppInfo: a.appInfo.String(),
+		AppInfo: *a.appInfo,
 	}
-	// Запускаем цикл с проходом по всем полученным файлам для генерации
-	// результирующих пакетов
-	for name, cl := range a.packagesParsed {
+	packages := make([]ds.RecordPackage, 0, len(a.packagesParsed))
+	linkObjects := map[string]ds.RecordPackage{}
+
+	// Запускаем цикл с проходом по всем полученным файлам для подготовки
+	// к генерации результирующих пакетов
+	for _, cl := range a.packagesParsed {
 		// Подготовка информации по ссылкам на другие пакеты
-		linkObjects, err := a.prepareGenerate(cl)
+		links, err := a.prepareGenerate(cl)
 		if err != nil {
 			return fmt.Errorf("prepare generate error: %s", err)
 		}
 
-		// Процесс генерации
-		genRes, genErr := generator.Generate(a.appInfo.String(), *cl, linkObjects)
-		if genErr != nil {
-			return fmt.Errorf("generate error: %s", genErr)
-		}
-
-		if err := a.saveGenerateResult(name, a.dst, genRes); err != nil {
-			return fmt.Errorf("error save result: %w", err)
+		for name, link := range links {
+			linkObjects[name] = link
 		}
 
+		packages = append(packages, *cl)
 		metadata.Namespaces = append(metadata.Namespaces, cl)
 	}
 
-	genRes, genErr := generator.GenerateMeta(metadata)
+	// Порядок пакетов в мета-информации не должен зависеть от порядка обхода, чтобы результат генерации был стабильным
+	sort.Slice(metadata.Namespaces, func(i, j int) bool {
+		return metadata.Namespaces[i].Namespace.PublicName < metadata.Namespaces[j].Namespace.PublicName
+	})
+
+	// Процесс генерации, пакеты генерируются параллельно
+	genRes, genErr := a.generatePackages(packages, linkObjects)
 	if genErr != nil {
-		return fmt.Errorf("generate meta error: %s", genErr)
+		return fmt.Errorf("generate error: %s", genErr)
+	}
+
+	for _, file := range genRes {
+		if err := a.saveGenerateResult(file.Dir, a.dst, []generator.GenerateFile{file}); err != nil {
+			return fmt.Errorf("error save result: %w", err)
+		}
+	}
+
+	metaRes, metaErr := generator.GenerateMeta(metadata, a.generateOpts)
+	if metaErr != nil {
+		return fmt.Errorf("generate meta error: %s", metaErr)
 	}
 
-	if err := a.saveGenerateResult("meta", a.dst, genRes); err != nil {
+	if err := a.saveGenerateResult("meta", a.dst, metaRes); err != nil {
 		return fmt.Errorf("error save meta result: %w", err)
 	}
 
@@ -256,14 +359,19 @@ func (a *ArGen) generate() error {
 	dir, pkg := filepath.Split(a.dstFixture)
 
 	for name, cl := range a.packagesParsed {
+		// Фикстуры поддерживаются только для octopus
+		if len(cl.Backends) > 0 && cl.Backends[0] != "octopus" && cl.Backends[0] != "tarantool15" {
+			continue
+		}
+
 		// Подготовка информации по ссылкам на другие пакеты
-		err := a.prepareFixtureGenerate(cl, name)
+		linkObjects, err := a.prepareFixtureGenerate(cl, name)
 		if err != nil {
 			return fmt.Errorf("prepare generate %s fixture store error: %w", name, err)
 		}
 
 		// Процесс генерации
-		genRes, genErr := generator.GenerateFixture(a.appInfo.String(), *cl, name, pkg)
+		genRes, genErr := generator.GenerateFixture(*a.appInfo, *cl, linkObjects, name, pkg, a.generateOpts)
 		if genErr != nil {
 			return fmt.Errorf("generate %s fixture store error: %w", name, genErr)
 		}
@@ -276,6 +384,70 @@ func (a *ArGen) generate() error {
 	return nil
 }
 
+// Генерация пакетов моделей. При инкрементальной генерации пакеты, декларации которых не менялись
+// с момента записи манифеста, не генерируются, а их файлы остаются на диске
+func (a *ArGen) generatePackages(packages []ds.RecordPackage, linkObjects map[string]ds.RecordPackage) ([]generator.GenerateFile, error) {
+	if !a.incremental {
+		return generator.GenerateAll(*a.appInfo, packages, linkObjects, a.generateOpts)
+	}
+
+	prev, err := a.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := generator.GenerateIncremental(*a.appInfo, prev, packages, linkObjects, a.generateOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range res.Kept {
+		file.Dir = filepath.Join(a.dst, file.Dir)
+
+		delete(a.fileToRemove, filepath.Join(file.Dir, file.Name))
+		delete(a.fileToRemove, file.Dir)
+
+		a.kept = append(a.kept, file)
+	}
+
+	for _, file := range res.Stale {
+		log.Printf("Stale file: %s", filepath.Join(a.dst, file.Dir, file.Name))
+	}
+
+	return res.Files, nil
+}
+
+// Чтение манифеста предыдущей генерации. Директории файлов приводятся к путям относительно каталога генерации.
+// Если манифеста ещё нет, то генерируются все пакеты
+func (a *ArGen) readManifest() ([]generator.ManifestFile, error) {
+	if a.manifestPath == "" {
+		return nil, fmt.Errorf("incremental generation requires manifest path")
+	}
+
+	manifest, err := os.Open(a.manifestPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer manifest.Close()
+
+	files, err := generator.ReadManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range files {
+		if dir, err := filepath.Rel(a.dst, files[i].Dir); err == nil {
+			files[i].Dir = dir
+		}
+	}
+
+	return files, nil
+}
+
 // Основная функция запускающая конвеер на выполнение
 // всех этапов генерации
 // - парсинг
@@ -305,15 +477,88 @@ func (a *ArGen) Run() error {
 		return fmt.Errorf("error generate: %w", err)
 	}
 
+	if a.dryRun {
+		return a.diff()
+	}
+
 	// Очищаем лишние файлы
 	for name := range a.fileToRemove {
 		log.Printf("Drop file `%s`\n", name)
 		os.Remove(name)
 	}
 
+	if a.manifestPath != "" {
+		if err := a.writeManifest(); err != nil {
+			return fmt.Errorf("error write manifest: %w", err)
+		}
+	}
+
+	if a.generateOpts.StableHeader {
+		if err := a.writeBuildInfo(); err != nil {
+			return fmt.Errorf("error write build info: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Вывод разницы между сгенерированными и существующими файлами без записи на диск
+func (a *ArGen) diff() error {
+	diffs, err := generator.DiffAll(a.generated, "")
+	if err != nil {
+		return fmt.Errorf("error diff generated files: %w", err)
+	}
+
+	paths := make([]string, 0, len(diffs)+len(a.fileToRemove))
+
+	for path := range diffs {
+		paths = append(paths, path)
+	}
+
+	for path := range a.fileToRemove {
+		if _, ok := diffs[path]; !ok {
+			diffs[path] = fmt.Sprintf("--- %s\n+++ /dev/null\n", path)
+			paths = append(paths, path)
+		}
+	}
+
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		fmt.Print(diffs[path])
+	}
+
+	if len(paths) != 0 {
+		return fmt.Errorf("%w: %d files differ", arerror.ErrGeneratorOutdated, len(paths))
+	}
+
 	return nil
 }
 
+// Запись манифеста сгенерированных файлов
+func (a *ArGen) writeManifest() error {
+	manifest, err := os.Create(a.manifestPath)
+	if err != nil {
+		return err
+	}
+
+	if err := generator.WriteManifest(a.generated, manifest, a.kept...); err != nil {
+		manifest.Close()
+		return err
+	}
+
+	return manifest.Close()
+}
+
+// Запись информации о генераторе и времени генерации в служебный файл .argen каталога генерации.
+// Используется, когда эта информация не записывается в заголовки сгенерированных файлов
+func (a *ArGen) writeBuildInfo() error {
+	info := fmt.Sprintf("DO NOT DELETE THIS FILE\nGenerate info: %s\nVersion: %s\nRevision: %s\nGenerated at: %s\n",
+		a.appInfo, a.appInfo.Version(), a.appInfo.Revision(), a.appInfo.GeneratedAt())
+
+	return os.WriteFile(filepath.Join(a.dst, ".argen"), []byte(info), 0600)
+}
+
 // Создание директории для пакета и запись пакета на диск
 func writeToFile(dirPkg string, dstFileName string, data []byte) error {
 	if !strings.HasPrefix(dstFileName, dirPkg) {
@@ -330,17 +575,28 @@ func writeToFile(dirPkg string, dstFileName string, data []byte) error {
 		}
 	}
 
-	dstFile, err := os.Create(dstFileName) //nolint:gosec
+	// Файл записывается во временный и переименовывается, чтобы при ошибке не оставить частично записанный файл
+	tmpFileName := dstFileName + ".tmp"
+
+	dstFile, err := os.Create(tmpFileName) //nolint:gosec
 	if err != nil {
 		return fmt.Errorf("error create file: %w", err)
 	}
 
 	_, err = dstFile.Write(data)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+
 	if err != nil {
+		os.Remove(tmpFileName)
 		return fmt.Errorf("error write to file: %w", err)
 	}
 
-	dstFile.Close()
+	if err = os.Rename(tmpFileName, dstFileName); err != nil {
+		os.Remove(tmpFileName)
+		return fmt.Errorf("error write to file: %w", err)
+	}
 
 	return nil
 }
@@ -348,11 +604,24 @@ func writeToFile(dirPkg string, dstFileName string, data []byte) error {
 // Сункция обрабатывает все декларации в папке src
 // результат парсинга складывает в packagesParsed
 func (a *ArGen) parse() error {
+	// Группы полей собираются из всех файлов до разбора моделей, чтобы их можно было подключать в любой модели
+	mixins := map[string]ds.MixinDeclaration{}
+	mixinFiles := map[string]bool{}
+
 	for _, srcFile := range a.srcEntry {
 		if !srcFile.Type().IsRegular() {
 			return fmt.Errorf("error declaration file `%s`. File in model declaration dir must be regular", srcFile.Name())
 		}
 
+		names, err := parser.ParseMixins(filepath.Join(a.src, srcFile.Name()), mixins)
+		if err != nil {
+			return fmt.Errorf("error parse declaration: %w", err)
+		}
+
+		mixinFiles[srcFile.Name()] = len(names) != 0
+	}
+
+	for _, srcFile := range a.srcEntry {
 		srcFileName := filepath.Join(a.src, srcFile.Name())
 		source := srcFile.Name()
 
@@ -364,11 +633,20 @@ func (a *ArGen) parse() error {
 		}
 
 		rc.Namespace.ModuleName = a.modName
+		rc.SourceFile = srcFileName
+		rc.Mixins = mixins
 
 		// Запускаем процесс парсинга
 		if err := parser.Parse(srcFileName, rc); err != nil {
 			return fmt.Errorf("error parse declaration: %w", err)
 		}
+
+		// Файл, в котором объявлены только группы полей, не описывает модель
+		if mixinFiles[source] && rc.Namespace.PublicName == "" {
+			a.packagesLock.Lock()
+			delete(a.packagesParsed, source[:len(source)-3])
+			a.packagesLock.Unlock()
+		}
 	}
 
 	return nil
@@ -165,6 +165,22 @@ func TestArGen_Run(t *testing.T) {
 
 	duplicateInsertResponse := append(responseErrorDuplicate, []byte("Duplicate key")...)
 
+	deleteReq := octopus.PackDelete(2, [][]byte{fieldValue, {}})
+	walkReq := octopus.PackSelectRange(2, 0, 1000, nil)
+
+	deleteBarReq := octopus.PackDelete(2, [][]byte{fieldValue, []byte("bar")})
+	selectByField1Req := octopus.PackSelect(2, 0, 0, 0, [][][]byte{{fieldValue}})
+	barTupleFields := append([]byte{0x04}, fieldValue...)
+	barTupleFields = append(barTupleFields, append([]byte{0x03}, []byte("bar")...)...)
+
+	selectByField1Response := append(responseSuccess, []byte{0x02, 0x00, 0x00, 0x00}...)
+	selectByField1Response = append(selectByField1Response, insertFqTupleSize...)
+	selectByField1Response = append(selectByField1Response, insertTupleCardinality...)
+	selectByField1Response = append(selectByField1Response, insertTupleFields...)
+	selectByField1Response = append(selectByField1Response, []byte{0x0C, 0x00, 0x00, 0x00}...)
+	selectByField1Response = append(selectByField1Response, insertTupleCardinality...)
+	selectByField1Response = append(selectByField1Response, barTupleFields...)
+
 	repositoryName := "foo"
 
 	if err := os.WriteFile(filepath.Join(src, repositoryName+".go"), []byte(textTestPkg), 0600); err != nil {
@@ -248,6 +264,102 @@ func TestArGen_Run(t *testing.T) {
 						octopus.CreateFixture(1, uint8(insertMsg), insertReq, duplicateInsertResponse, nil),
 					},
 				},
+				{
+					testGoMain: `fooRepo := ` + repositoryName + `.New(ctx)
+					fooRepo.SetField1(` + fieldValueStr + `)
+						inserted, err := fooRepo.InsertIfAbsent(ctx)
+						if err != nil || inserted {
+							log.Fatal("Error test insert if absent duplicate", err)
+						}`,
+					fixtures: []octopus.FixtureType{
+						octopus.CreateFixture(1, uint8(insertMsg), insertReq, duplicateInsertResponse, nil),
+					},
+				},
+				{
+					testGoMain: `deleted, failed, err := ` + repositoryName + `.DeleteByPrimaryList(ctx, []` + repositoryName + `.Field1Field2IndexType{{Field1: ` + fieldValueStr + `}})
+						if err != nil || deleted != 1 || len(failed) != 0 {
+							log.Fatal("Error test delete by primary list", deleted, failed, err)
+						}`,
+					fixtures: []octopus.FixtureType{
+						octopus.CreateFixture(1, uint8(octopus.RequestTypeDelete), deleteReq, successInsertResponse, nil),
+					},
+				},
+				{
+					testGoMain: `deleted, err := ` + repositoryName + `.DeleteByField1Part(ctx, ` + fieldValueStr + `)
+						if err != nil || deleted != 2 {
+							log.Fatal("Error test delete by partial index", deleted, err)
+						}`,
+					fixtures: []octopus.FixtureType{
+						octopus.CreateFixture(1, uint8(octopus.RequestTypeSelect), selectByField1Req, selectByField1Response, nil),
+						octopus.CreateFixture(2, uint8(octopus.RequestTypeDelete), deleteReq, successInsertResponse, nil),
+						octopus.CreateFixture(3, uint8(octopus.RequestTypeDelete), deleteBarReq, successInsertResponse, nil),
+					},
+				},
+				{
+					testGoMain: `fooRepo := ` + repositoryName + `.New(ctx)
+					fooRepo.SetField1(` + fieldValueStr + `)
+					fooRepo.SetField2("bar")
+						data, err := fooRepo.MarshalBinary()
+						if err != nil {
+							log.Fatal(err)
+						}
+						fooRestored := ` + repositoryName + `.New(ctx)
+						if err = fooRestored.UnmarshalBinary(data); err != nil {
+							log.Fatal(err)
+						}
+						if fooRestored.GetField1() != fooRepo.GetField1() || fooRestored.GetField2() != fooRepo.GetField2() {
+							log.Fatal("Error test binary round trip", fooRestored.GetField1(), fooRestored.GetField2())
+						}
+						if err = fooRestored.UnmarshalBinary(data[:len(data)-1]); err == nil {
+							log.Fatal("Error test unmarshal truncated data")
+						}`,
+					fixtures: []octopus.FixtureType{},
+				},
+				{
+					testGoMain: `fooRepo := ` + repositoryName + `.New(ctx)
+					fooRepo.SetField1(` + fieldValueStr + `)
+					fooRepo.SetField2("bar")
+						keys := map[` + repositoryName + `.FooPrimaryKey]*` + repositoryName + `.Foo{` + repositoryName + `.KeyOf(fooRepo): fooRepo}
+						if keys[` + repositoryName + `.FooPrimaryKey{Field1: ` + fieldValueStr + `, Field2: "bar"}] != fooRepo {
+							log.Fatal("Error test primary key", ` + repositoryName + `.KeyOf(fooRepo))
+						}`,
+					fixtures: []octopus.FixtureType{},
+				},
+				{
+					testGoMain: `fooFirst := ` + repositoryName + `.New(ctx)
+					fooFirst.SetField1(` + fieldValueStr + `)
+					fooSecond := ` + repositoryName + `.New(ctx)
+					fooSecond.SetField1(` + fieldValueStr + `)
+					fooSecond.SetField2("bar")
+					fooDuplicate := ` + repositoryName + `.New(ctx)
+					fooDuplicate.SetField1(` + fieldValueStr + `)
+						batchErrs := ` + repositoryName + `.ValidateBatch([]*` + repositoryName + `.Foo{fooFirst, fooSecond, fooDuplicate})
+						if len(batchErrs) != 1 || batchErrs[0].Position != 2 || batchErrs[0].Conflict != 0 || batchErrs[0].Err != activerecord.ErrDuplicateKey {
+							log.Fatal("Error test validate batch", batchErrs)
+						}`,
+					fixtures: []octopus.FixtureType{},
+				},
+				{
+					testGoMain: `created, updated, deleted, err := ` + repositoryName + `.Reconcile(ctx, nil)
+						if err != nil || created != 0 || updated != 0 || deleted != 1 {
+							log.Fatal("Error test reconcile", created, updated, deleted, err)
+						}`,
+					fixtures: []octopus.FixtureType{
+						octopus.CreateFixture(1, uint8(octopus.RequestTypeCall), walkReq, successInsertResponse, nil),
+						octopus.CreateFixture(2, uint8(octopus.RequestTypeDelete), deleteReq, successInsertResponse, nil),
+					},
+				},
+				{
+					testGoMain: `fooRepo := ` + repositoryName + `.New(ctx)
+					fooRepo.SetField1(` + fieldValueStr + `)
+						created, updated, deleted, err := ` + repositoryName + `.Reconcile(ctx, []*` + repositoryName + `.Foo{fooRepo})
+						if err != nil || created != 0 || updated != 0 || deleted != 0 {
+							log.Fatal("Error test reconcile unchanged", created, updated, deleted, err)
+						}`,
+					fixtures: []octopus.FixtureType{
+						octopus.CreateFixture(1, uint8(octopus.RequestTypeCall), walkReq, successInsertResponse, nil),
+					},
+				},
 			},
 		},
 	}
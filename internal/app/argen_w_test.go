@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/mailru/activerecord/internal/pkg/ds"
 	"github.com/mailru/activerecord/internal/pkg/testutil"
@@ -566,6 +567,73 @@ func TestArGen_prepareCheck(t *testing.T) {
 	}
 }
 
+func TestArGen_prepareEmbedded(t *testing.T) {
+	newPackage := func(backend string) *ds.RecordPackage {
+		rp := ds.NewRecordPackage()
+		rp.Namespace = ds.NamespaceDeclaration{PackageName: "address", PublicName: "Address", ModuleName: "testmodname/address"}
+		rp.Backends = []string{backend}
+
+		return rp
+	}
+
+	tests := []struct {
+		name    string
+		backend string
+		want    ds.SerializerDeclaration
+		wantErr bool
+	}{
+		{
+			name:    "octopus record",
+			backend: "octopus",
+			want: ds.SerializerDeclaration{
+				Name:        "Address",
+				Pkg:         "testmodname/address",
+				Type:        "*address.Address",
+				ImportName:  "address",
+				Marshaler:   "MarshalEmbedded",
+				Unmarshaler: "UnmarshalEmbedded",
+				Embed:       "address",
+			},
+		},
+		{
+			name:    "unsupported backend",
+			backend: "mock",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := ds.NewRecordPackage()
+			cl.Namespace = ds.NamespaceDeclaration{PackageName: "user", PublicName: "User"}
+
+			if err := cl.AddSerializer(ds.SerializerDeclaration{Name: "Address", Marshaler: "MarshalEmbedded", Unmarshaler: "UnmarshalEmbedded", Embed: "address"}); err != nil {
+				t.Fatalf("can't prepare test data: %s", err)
+			}
+
+			a := &ArGen{
+				packagesParsed: map[string]*ds.RecordPackage{"address": newPackage(tt.backend), "user": cl},
+				packagesLinked: map[string]string{"address": "address", "user": "user"},
+			}
+
+			if err := a.prepareEmbedded(cl); (err != nil) != tt.wantErr {
+				t.Fatalf("prepareEmbedded() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := cl.SerializerMap["Address"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("prepareEmbedded() = %+v, want %+v", got, tt.want)
+			}
+
+			if _, err := cl.FindImport("testmodname/address"); err != nil {
+				t.Errorf("prepareEmbedded() import not added: %s", err)
+			}
+		})
+	}
+}
+
 func Test_writeToFile(t *testing.T) {
 	tempDirs := testutil.InitTmps()
 	defer tempDirs.Defer()
@@ -671,8 +739,9 @@ type TriggersFoo struct {
 			wantErr: false,
 			want: map[string]*ds.RecordPackage{
 				"foo": {
-					Namespace: ds.NamespaceDeclaration{ObjectName: "2", PublicName: "Foo", PackageName: "foo", ModuleName: "testmod"},
-					Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11111"},
+					Namespace:  ds.NamespaceDeclaration{ObjectName: "2", PublicName: "Foo", PackageName: "foo", ModuleName: "testmod"},
+					Server:     ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11111"},
+					SourceFile: filepath.Join(src, repositoryName+".go"),
 					Fields: []ds.FieldDeclaration{
 						{Name: "Field1", Format: "int", PrimaryKey: true, Mutators: []string{}, Size: 5, Serializer: []string{}},
 						{Name: "Field2", Format: "string", PrimaryKey: true, Mutators: []string{}, Size: 5, Serializer: []string{}},
@@ -731,6 +800,7 @@ type TriggersFoo struct {
 					MutatorMap:            map[string]ds.MutatorDeclaration{},
 					ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{},
 					LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
+					Mixins:                map[string]ds.MixinDeclaration{},
 				},
 			},
 		},
@@ -759,3 +829,70 @@ type TriggersFoo struct {
 		})
 	}
 }
+
+func Test_changedFiles(t *testing.T) {
+	now := time.Now()
+	prev := map[string]fileState{
+		"a.go": {modTime: now, size: 1},
+		"b.go": {modTime: now, size: 1},
+		"c.go": {modTime: now, size: 1},
+	}
+	cur := map[string]fileState{
+		"a.go": {modTime: now, size: 1},
+		"b.go": {modTime: now.Add(time.Second), size: 1},
+		"d.go": {modTime: now, size: 1},
+	}
+
+	if got, want := changedFiles(prev, cur), []string{"b.go", "c.go", "d.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("changedFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestWatch(t *testing.T) {
+	tempDirs := testutil.InitTmps()
+	defer tempDirs.Defer()
+
+	src, err := tempDirs.AddTempDir()
+	if err != nil {
+		t.Fatalf("can't initialize dirs: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	runs := make(chan struct{}, 10)
+	done := make(chan error)
+
+	go func() {
+		done <- Watch(ctx, []string{src, filepath.Join(src, "not_exists")}, WatchOptions{Interval: 10 * time.Millisecond, Debounce: 20 * time.Millisecond}, func() error {
+			runs <- struct{}{}
+			return nil
+		})
+	}()
+
+	wait := func(msg string) {
+		select {
+		case <-runs:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Watch() %s", msg)
+		}
+	}
+
+	wait("didn't run initial generation")
+
+	if err := os.WriteFile(filepath.Join(src, "foo.go"), []byte("package foo"), 0600); err != nil {
+		t.Fatalf("can't write declaration: %s", err)
+	}
+
+	wait("didn't regenerate on change")
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Errorf("Watch() error = %v", err)
+	}
+
+	if len(runs) != 0 {
+		t.Errorf("Watch() regenerated %d extra times", len(runs))
+	}
+}
@@ -0,0 +1,200 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// WatchOptions параметры режима наблюдения за файлами
+type WatchOptions struct {
+	// Interval период опроса файлов
+	Interval time.Duration
+	// Debounce время, в течение которого файлы не должны меняться перед запуском генерации.
+	// Позволяет не запускать генерацию на каждое из серии быстрых сохранений
+	Debounce time.Duration
+	// Out вывод сводки по запускам генерации
+	Out io.Writer
+}
+
+// DefaultWatchOptions параметры наблюдения по умолчанию
+var DefaultWatchOptions = WatchOptions{
+	Interval: 500 * time.Millisecond,
+	Debounce: 300 * time.Millisecond,
+	Out:      os.Stdout,
+}
+
+// fileState состояние файла, по изменению которого определяется необходимость перегенерации
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// Watch запускает генерацию run и перезапускает её при каждом изменении файлов в директориях roots
+// (декларации моделей и шаблоны), пока не будет отменён контекст. Изменения определяются опросом
+// времени модификации и размера файлов, поэтому не требуют поддержки уведомлений файловой системы.
+// Генерация запускается, когда файлы не менялись в течение opts.Debounce. Ошибки генерации
+// выводятся в opts.Out и не прерывают наблюдение. Генерацию run каждый раз выполняет новый
+// экземпляр ArGen, так как он хранит состояние одного запуска
+func Watch(ctx context.Context, roots []string, opts WatchOptions, run func() error) error {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchOptions.Interval
+	}
+
+	if opts.Out == nil {
+		opts.Out = io.Discard
+	}
+
+	prev, err := snapshotFiles(roots)
+	if err != nil {
+		return err
+	}
+
+	runWatched(opts.Out, nil, run)
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cur, err := snapshotFiles(roots)
+		if err != nil {
+			fmt.Fprintf(opts.Out, "watch error: %s\n", err)
+			continue
+		}
+
+		changed := changedFiles(prev, cur)
+		if len(changed) == 0 {
+			continue
+		}
+
+		// Ждём, пока файлы перестанут меняться, чтобы не генерировать по наполовину сохранённым файлам
+		for {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(opts.Debounce):
+			}
+
+			next, err := snapshotFiles(roots)
+			if err != nil {
+				break
+			}
+
+			more := changedFiles(cur, next)
+			cur = next
+
+			if len(more) == 0 {
+				break
+			}
+
+			changed = append(changed, more...)
+		}
+
+		prev = cur
+
+		runWatched(opts.Out, changed, run)
+	}
+}
+
+// runWatched запускает генерацию и выводит сводку: изменённые файлы, время генерации или ошибку
+func runWatched(out io.Writer, changed []string, run func() error) {
+	if len(changed) != 0 {
+		fmt.Fprintf(out, "changed: %s\n", strings.Join(uniqueSorted(changed), ", "))
+	}
+
+	start := time.Now()
+
+	if err := run(); err != nil {
+		fmt.Fprintf(out, "generate failed: %s\n", err)
+		return
+	}
+
+	fmt.Fprintf(out, "generated in %s\n", time.Since(start).Round(time.Millisecond))
+}
+
+// snapshotFiles возвращает состояние всех файлов в директориях roots. Отсутствующая директория
+// считается пустой, так как её могут создать позже
+func snapshotFiles(roots []string) (map[string]fileState, error) {
+	ret := map[string]fileState{}
+
+	for _, root := range roots {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if path == root && os.IsNotExist(err) {
+					return filepath.SkipDir
+				}
+
+				return err
+			}
+
+			if d.IsDir() {
+				return nil
+			}
+
+			info, err := d.Info()
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+
+				return err
+			}
+
+			ret[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("can't read dir %s: %w", root, err)
+		}
+	}
+
+	return ret, nil
+}
+
+// changedFiles возвращает созданные, изменённые и удалённые файлы
+func changedFiles(prev, cur map[string]fileState) []string {
+	ret := []string{}
+
+	for path, state := range cur {
+		if prevState, ex := prev[path]; !ex || prevState != state {
+			ret = append(ret, path)
+		}
+	}
+
+	for path := range prev {
+		if _, ex := cur[path]; !ex {
+			ret = append(ret, path)
+		}
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+func uniqueSorted(list []string) []string {
+	sort.Strings(list)
+
+	ret := list[:0]
+
+	for i, s := range list {
+		if i == 0 || s != list[i-1] {
+			ret = append(ret, s)
+		}
+	}
+
+	return ret
+}
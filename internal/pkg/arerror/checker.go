@@ -9,21 +9,76 @@ var ErrCheckBackendUnknown = errors.New("backend unknown")
 var ErrCheckEmptyNamespace = errors.New("empty namespace")
 var ErrCheckPkgBackendToMatch = errors.New("many backends for one class not supported yet")
 var ErrCheckFieldSerializerNotFound = errors.New("serializer not found")
+var ErrCheckFieldSerializerChain = errors.New("serializer chain can't be used with params or in procedure fields")
 var ErrCheckFieldSerializerNotSupported = errors.New("serializer not supported")
 var ErrCheckFieldInvalidFormat = errors.New("invalid format")
 var ErrCheckFieldMutatorConflictPK = errors.New("conflict mutators with primary_key")
 var ErrCheckFieldMutatorConflictSerializer = errors.New("conflict mutators with serializer")
 var ErrCheckFieldMutatorConflictObject = errors.New("conflict mutators with object link")
+var ErrCheckFieldMutatorArity = errors.New("mutator args count not match arity")
 var ErrCheckFieldSerializerConflictObject = errors.New("conflict serializer with object link")
+var ErrCheckFieldSwappableConflictPK = errors.New("conflict swappable with primary_key")
+var ErrCheckFieldSwappableConflictSerializer = errors.New("conflict swappable with serializer")
+var ErrCheckFieldImmutableConflict = errors.New("conflict immutable with mutators, swappable, version, lease or softDelete")
+var ErrCheckFieldTouchConflict = errors.New("touch field must be a not nullable integer, string or time.Time field not changed by other declarations")
+var ErrCheckVersionManyDecl = errors.New("few version fields not supported")
+var ErrCheckVersionConflictPK = errors.New("conflict version with primary_key")
+var ErrCheckVersionConflictMutator = errors.New("conflict version with mutators")
+var ErrCheckFieldArrayConflict = errors.New("array field can't be used in index, with serializer, mutators or nullable")
+var ErrCheckFieldDecimalConflict = errors.New("decimal field can't be used in index or with mutators")
+var ErrCheckFieldTimestampEmpty = errors.New("timestamp storage format required for time.Time field")
+var ErrCheckFieldTimestampConflict = errors.New("time.Time field can't be used in index, with serializer or mutators")
+var ErrCheckFieldDefaultInvalid = errors.New("default value not assignable to field type")
+var ErrCheckFieldDefaultConflict = errors.New("default value can't be used with array or serializer field")
+var ErrCheckFieldEnumInvalid = errors.New("invalid enum value or duplicate enum name")
+var ErrCheckFieldEnumConflict = errors.New("enum field can't be primary key, nullable, with flags, serializer or mutators")
+var ErrCheckFieldTypeOverrideConflict = errors.New("field with custom type can't be primary key, enum, array, nullable, with flags, serializer or mutators")
+var ErrCheckFieldNullableConflict = errors.New("nullable field can't be used in index, with serializer or mutators")
+var ErrCheckFieldValidationInvalid = errors.New("invalid validation rule for field format")
+var ErrCheckFieldValidationConflict = errors.New("validation rules can't be used with array, enum, serializer or required nullable field")
+var ErrCheckValidateFieldsEmpty = errors.New("validate declared without field validation rules")
+var ErrCheckComputedFieldNotFound = errors.New("computed field depends on unknown field")
+var ErrCheckComputedFieldRedefined = errors.New("computed field name conflicts with model field")
+var ErrCheckFieldAPINameDuplicate = errors.New("duplicate api name of field")
+var ErrCheckFieldAPINameConflict = errors.New("api name can't be set for sensitive field")
+var ErrCheckSoftDeleteFieldNotFound = errors.New("soft delete field not found")
+var ErrCheckProtoPkgFieldsEmpty = errors.New("protoPkg declared for model without fields")
+var ErrCheckLeaseProcEmpty = errors.New("leaseProc is empty")
+var ErrCheckLeaseFieldsEmpty = errors.New("lease owner and expire fields required")
+var ErrCheckLeaseFieldsManyDecl = errors.New("few lease fields with same role not supported")
+var ErrCheckLeaseFieldConflictPK = errors.New("conflict lease with primary_key")
+var ErrCheckPayloadDiscriminatorEmpty = errors.New("payload declared without discriminator")
+var ErrCheckPayloadManyDecl = errors.New("few payload or discriminator fields not supported")
+var ErrCheckPayloadConflictSerializer = errors.New("conflict payload with serializer")
 var ErrCheckServerEmpty = errors.New("serverConf and serverHost is empty")
 var ErrCheckPortEmpty = errors.New("serverPort is empty")
 var ErrCheckServerConflict = errors.New("conflict ServerHost and serverConf params")
+var ErrCheckServerPoolConflict = errors.New("connection pool params can't be used with serverConf")
+var ErrCheckServerPoolSize = errors.New("serverMinConns greater than serverMaxConns")
+var ErrCheckServerRetryDelay = errors.New("serverRetryDelay declared without serverRetry")
+var ErrCheckServerCacheTTL = errors.New("cacheTTL declared without cacheSize")
+var ErrCheckServerCacheProc = errors.New("cache can't be used with procedure")
+var ErrCheckAuditProc = errors.New("audit can't be used with procedure")
+var ErrCheckOutboxProc = errors.New("outbox can't be used with procedure")
+var ErrCheckServerReplicasConflict = errors.New("serverReplicas can't be used with serverConf")
+var ErrCheckShardingUnknown = errors.New("sharding type unknown")
+var ErrCheckShardByRing = errors.New("shardBy can be used only with sharding:ring")
+var ErrCheckShardByField = errors.New("shardBy field must be a not nullable primary key field")
+var ErrCheckIndexScatter = errors.New("scatter can be used only with shardBy")
+var ErrCheckBackendFeatureUnsupported = errors.New("feature not supported by backend")
 var ErrCheckFieldIndexEmpty = errors.New("field for index is empty")
+var ErrCheckIndexFilterPrimary = errors.New("primary index can't have filter")
+var ErrCheckIndexFilterField = errors.New("field format can't be used in index filter")
+var ErrCheckIndexFilterValue = errors.New("invalid index filter value")
+var ErrCheckIndexFieldFormat = errors.New("field format can't be used in index part")
 var ErrCheckObjectNotFound = errors.New("linked object not found")
+var ErrCheckFieldEmbedFormat = errors.New("embedded record can be stored only in not nullable string field")
 var ErrCheckFieldTypeNotFound = errors.New("procedure field type not found")
 var ErrCheckFieldsEmpty = errors.New("empty required field declaration")
 var ErrCheckFieldsManyDecl = errors.New("few declarations of fields not supported")
+var ErrCheckProcGroupConflict = errors.New("output group can't contain input params or conflict with procedure field and type names")
 var ErrCheckFieldsOrderDecl = errors.New("incorrect order of fields")
+var ErrCheckNameInvalid = errors.New("name is not a valid go identifier or is a reserved word")
 
 // Описание ошибки декларации пакета
 type ErrCheckPackageDecl struct {
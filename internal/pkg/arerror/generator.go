@@ -7,6 +7,15 @@ var ErrGeneratorBackendNotImplemented = errors.New("backend not implemented")
 var ErrGeneragorGetTmplLine = errors.New("can't get error lines")
 var ErrGeneragorEmptyTmplLine = errors.New("tmpl lines not set")
 var ErrGeneragorErrorLineNotFound = errors.New("template lines not found in error")
+var ErrGeneratorOutdated = errors.New("generated files are outdated")
+var ErrGeneratorDDLFormat = errors.New("field format has no column type")
+var ErrGeneratorProtoFormat = errors.New("field format has no protobuf type")
+var ErrGeneratorImportNotDeclared = errors.New("import required by declaration not declared")
+var ErrGeneratorFileSection = errors.New("invalid file section name")
+var ErrGeneratorIndexFieldNotExist = errors.New("index references field that not exists")
+var ErrGeneratorIndexFieldMismatch = errors.New("index field position mismatch")
+var ErrGeneratorIndexFieldDuplicate = errors.New("field used in index twice")
+var ErrGeneratorDeclarationUnused = errors.New("declaration not used by any field or lifecycle phase")
 
 // Описание ошибки генерации
 type ErrGeneratorPkg struct {
@@ -30,12 +39,49 @@ func (e *ErrGeneratorFile) Error() string {
 	return ErrorBase(e)
 }
 
-// Описание ошибки фаз генерации
+// Описание ошибки отсутствующего импорта: Feature - часть декларации, которой нужен пакет
+type ErrGeneratorImport struct {
+	Feature string
+	Path    string
+	Name    string
+	Err     error
+}
+
+func (e *ErrGeneratorImport) Error() string {
+	return ErrorBase(e)
+}
+
+// Описание ошибки индекса, поле которого не соответствует списку полей модели
+type ErrGeneratorIndex struct {
+	Index string
+	Field string
+	Err   error
+}
+
+func (e *ErrGeneratorIndex) Error() string {
+	return ErrorBase(e)
+}
+
+// Описание ошибки неиспользуемой декларации: Kind - вид декларации (serializer, mutator, trigger), Name - её имя
+type ErrGeneratorUnused struct {
+	Kind string
+	Name string
+	Err  error
+}
+
+func (e *ErrGeneratorUnused) Error() string {
+	return ErrorBase(e)
+}
+
+// Описание ошибки фаз генерации. TmplLines - фрагмент шаблона вокруг строки с ошибкой для вывода пользователю,
+// Line и Column - позиция ошибки в файле шаблона (с 1), 0 - позицию определить не удалось
 type ErrGeneratorPhases struct {
 	Name      string
 	Backend   string
 	Phase     string
 	TmplLines string
+	Line      int `format:"%d"`
+	Column    int `format:"%d"`
 	Err       error
 }
 
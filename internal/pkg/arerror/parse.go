@@ -64,6 +64,18 @@ var ErrParseTagWithValue = errors.New("wrong tag. Flag can't has value")
 
 var ErrParseDocEmptyBoxDeclaration = errors.New("empty declaration box params in doc")
 var ErrParseDocTimeoudDecl = errors.New("invalid timeout declaration")
+var ErrParseDocSlowQueryDecl = errors.New("invalid slow query threshold declaration")
+var ErrParseDocPoolDecl = errors.New("invalid connection pool size declaration")
+var ErrParseDocRetryDecl = errors.New("invalid retry declaration")
+var ErrParseDocCacheDecl = errors.New("invalid cache declaration")
+var ErrParseDocHealthDecl = errors.New("invalid health threshold declaration")
+var ErrParseDocReplicasDecl = errors.New("invalid replicas declaration, want host:port list")
+var ErrParseDocCopyGettersDecl = errors.New("invalid copy getters declaration")
+var ErrParseDocNullableGettersDecl = errors.New("invalid nullable getters declaration")
+var ErrParseDocPreparedDecl = errors.New("invalid prepared declaration")
+var ErrParseDocValidateDecl = errors.New("invalid validate declaration")
+var ErrParseDocTraceDecl = errors.New("invalid trace declaration")
+var ErrParseDocAuditDecl = errors.New("invalid audit declaration")
 var ErrParseDocNamespaceDecl = errors.New("invalid namespace declaration")
 
 // Описание ошибки парсинга поля
@@ -110,6 +122,32 @@ func (e *ErrParseTypeFieldDecl) Error() string {
 	return ErrorBase(e)
 }
 
+// Описание ошибки подключения группы полей (миксина)
+type ErrParseMixinDecl struct {
+	Name string
+	Err  error
+}
+
+func (e *ErrParseMixinDecl) Error() string {
+	return ErrorBase(e)
+}
+
+// Описание ошибки совпадения имён поля группы полей и другого поля модели
+type ErrParseMixinFieldDecl struct {
+	Name  string
+	Field string
+	Other string
+	Err   error
+}
+
+func (e *ErrParseMixinFieldDecl) Error() string {
+	return ErrorBase(e)
+}
+
+var ErrParseMixinNotFound = errors.New("mixin not declared")
+var ErrParseMixinIndex = errors.New("mixin fields can't declare indexes")
+var ErrParseMixinFieldConflict = errors.New("mixin field conflicts with another field")
+
 // Описание ошибки парсинга тегов полей сущности
 type ErrParseTypeFieldTagDecl struct {
 	Name     string
@@ -165,6 +203,7 @@ func (e *ErrParseImportDecl) Error() string {
 }
 
 var ErrParseImportNotFound = errors.New("import not found")
+var ErrParseImportAliasRequired = errors.New("import name can't be derived from path, declare import with alias")
 
 // Описание ошибки парсинга индексов
 type ErrParseTypeIndexDecl struct {
@@ -287,3 +326,28 @@ func (e *ErrParseTriggerDecl) Error() string {
 }
 
 var ErrParseTriggerPackageNotDefined = errors.New("package not defined")
+
+// Описание ошибки парсинга тегов вычисляемых полей
+type ErrParseComputedTagDecl struct {
+	Name     string
+	TagName  string
+	TagValue string
+	Err      error
+}
+
+func (e *ErrParseComputedTagDecl) Error() string {
+	return ErrorBase(e)
+}
+
+// Описание ошибки парсинга вычисляемого поля
+type ErrParseComputedDecl struct {
+	Name string
+	Err  error
+}
+
+func (e *ErrParseComputedDecl) Error() string {
+	return ErrorBase(e)
+}
+
+var ErrParseComputedPackageNotDefined = errors.New("compute function package not defined")
+var ErrParseComputedFieldsNotDefined = errors.New("compute function fields not defined")
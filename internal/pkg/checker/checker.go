@@ -1,8 +1,12 @@
 package checker
 
 import (
+	"go/token"
 	"log"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
@@ -37,7 +41,8 @@ func checkBackend(cl *ds.RecordPackage) error {
 	return nil
 }
 
-// checkLinkedObject проверка существования сущностей на которые ссылаются другие сущности
+// checkLinkedObject проверка существования сущностей на которые ссылаются другие сущности,
+// в том числе встроенных моделей сериализаторов
 func checkLinkedObject(cl *ds.RecordPackage, linkedObjects map[string]string) error {
 	for _, fobj := range cl.FieldsObjectMap {
 		if _, ok := linkedObjects[fobj.ObjectName]; !ok {
@@ -45,6 +50,16 @@ func checkLinkedObject(cl *ds.RecordPackage, linkedObjects map[string]string) er
 		}
 	}
 
+	for _, sd := range cl.SerializerMap {
+		if sd.Embed == "" {
+			continue
+		}
+
+		if _, ok := linkedObjects[sd.Embed]; !ok {
+			return &arerror.ErrCheckPackageLinkedDecl{Pkg: cl.Namespace.PackageName, Object: sd.Embed, Err: arerror.ErrCheckObjectNotFound}
+		}
+	}
+
 	return nil
 }
 
@@ -64,6 +79,7 @@ func checkNamespace(ns ds.NamespaceDeclaration) error {
 // - поля с мутаторами не могут быть сериализованными
 // - поля с мутаторами не могут являться ссылками на другие сущности
 // - сериализуемые поля не могут быть ссылками на другие сущности
+// - поля с атомарной заменой (swappable) не могут быть первичным ключом или сериализованными
 // - есть первичный ключ
 // - имена сущностей на которые ссылаемся на могут пересекаться с именами полей
 //
@@ -77,6 +93,17 @@ func checkFields(cl *ds.RecordPackage) error {
 		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckFieldsOrderDecl}
 	}
 
+	for _, g := range cl.ProcOutGroups {
+		if !g.Fields.Validate() {
+			return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckFieldsOrderDecl}
+		}
+
+		// Для группы генерируются тип строки {Name}{Group} и метод Get{Group}
+		if _, ex := cl.ProcFieldsMap[g.Name]; ex || g.Name == "Params" || g.Name == "List" {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: g.Name, Err: arerror.ErrCheckProcGroupConflict}
+		}
+	}
+
 	primaryFound := false
 
 	octopusAvailFormat := map[octopus.Format]bool{}
@@ -90,10 +117,19 @@ func checkFields(cl *ds.RecordPackage) error {
 		}
 
 		if len(fld.Serializer) > 0 {
-			if _, ex := cl.SerializerMap[fld.Serializer[0]]; len(cl.SerializerMap) == 0 || !ex {
+			sd, ex := cl.SerializerMap[fld.Serializer[0]]
+			if len(cl.SerializerMap) == 0 || !ex {
 				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
 			}
 
+			if len(sd.Chain) > 0 && len(fld.Serializer) > 1 {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerChain}
+			}
+
+			// Встроенная модель упаковывается в строку
+			if sd.Embed != "" && (fld.Format != octopus.String || fld.Nullable || fld.Array) {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldEmbedFormat}
+			}
 		}
 
 		customMutCnt := 0
@@ -118,6 +154,10 @@ func checkFields(cl *ds.RecordPackage) error {
 				if len(md.PartialFields) > 0 && len(fld.Serializer) == 0 {
 					return &arerror.ErrCheckPackageFieldMutatorDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Mutator: m, Err: arerror.ErrParseFieldMutatorTypeHasNotSerializer}
 				}
+
+				if md.Arity > 0 && len(md.Args) != md.Arity {
+					return &arerror.ErrCheckPackageFieldMutatorDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Mutator: m, Err: arerror.ErrCheckFieldMutatorArity}
+				}
 			}
 
 			if fld.PrimaryKey {
@@ -129,6 +169,14 @@ func checkFields(cl *ds.RecordPackage) error {
 			}
 		}
 
+		if fld.Swappable != "" && fld.PrimaryKey {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSwappableConflictPK}
+		}
+
+		if fld.Swappable != "" && len(fld.Serializer) > 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSwappableConflictSerializer}
+		}
+
 		if len(fld.Serializer) > 0 && fld.ObjectLink != "" {
 			return &arerror.ErrCheckPackageFieldMutatorDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerConflictObject}
 		}
@@ -142,15 +190,24 @@ func checkFields(cl *ds.RecordPackage) error {
 		}
 	}
 
-	for _, fld := range cl.ProcOutFields.List() {
+	for _, fld := range cl.ProcResultFields() {
 		if _, ex := octopusAvailFormat[fld.Format]; !ex {
 			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
 		}
 
+		if fld.Group != "" && fld.Type != ds.OUT {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckProcGroupConflict}
+		}
+
 		if len(fld.Serializer) > 0 {
-			if _, ex := cl.SerializerMap[fld.Serializer[0]]; !ex {
+			sd, ex := cl.SerializerMap[fld.Serializer[0]]
+			if !ex {
 				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
 			}
+
+			if len(sd.Chain) > 0 {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerChain}
+			}
 		}
 
 		if fld.Type == 0 {
@@ -169,9 +226,14 @@ func checkFields(cl *ds.RecordPackage) error {
 		}
 
 		if len(fld.Serializer) > 0 {
-			if _, ex := cl.SerializerMap[fld.Serializer[0]]; !ex {
+			sd, ex := cl.SerializerMap[fld.Serializer[0]]
+			if !ex {
 				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
 			}
+
+			if len(sd.Chain) > 0 {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerChain}
+			}
 		}
 
 		if fld.Type == 0 {
@@ -190,35 +252,195 @@ func checkFields(cl *ds.RecordPackage) error {
 	return nil
 }
 
-// Check основная функция, которая запускает процесс проверки
-// Должна вызываться только после окончания процесса парсинга всех деклараций
-func Check(files map[string]*ds.RecordPackage, linkedObjects map[string]string) error {
-	for _, cl := range files {
-		if err := checkBackend(cl); err != nil {
-			return err
+// Допустимые форматы поля окончания аренды, значение хранится в unix time
+var leaseExpireFormat = map[octopus.Format]bool{
+	octopus.Uint32: true,
+	octopus.Uint64: true,
+	octopus.Uint:   true,
+	octopus.Int32:  true,
+	octopus.Int64:  true,
+	octopus.Int:    true,
+}
+
+// checkLease проверка описания аренды записи
+// - процедура аренды и поля владельца и окончания аренды описываются только вместе
+// - поле владельца строковое, поле окончания аренды целочисленное
+// - поля аренды не могут быть первичным ключом или сериализованными
+func checkLease(cl *ds.RecordPackage) error {
+	owner, expire := "", ""
+
+	for _, fld := range cl.Fields {
+		if fld.Lease == "" {
+			continue
 		}
 
-		if err := checkNamespace(cl.Namespace); err != nil {
-			return err
+		if fld.PrimaryKey {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckLeaseFieldConflictPK}
 		}
 
-		if err := checkLinkedObject(cl, linkedObjects); err != nil {
-			return err
+		if len(fld.Serializer) > 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
 		}
 
-		if err := checkFields(cl); err != nil {
-			return err
+		switch fld.Lease {
+		case ds.LeaseOwner:
+			if owner != "" {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckLeaseFieldsManyDecl}
+			}
+
+			if fld.Format != octopus.String {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+			}
+
+			owner = fld.Name
+		case ds.LeaseExpire:
+			if expire != "" {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckLeaseFieldsManyDecl}
+			}
+
+			if _, ex := leaseExpireFormat[fld.Format]; !ex {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+			}
+
+			expire = fld.Name
 		}
+	}
 
-		// Бекендозависимые проверки
-		for _, backend := range cl.Backends {
-			switch backend {
-			case "octopus":
-				if err := checkOctopus(cl); err != nil {
-					return err
+	if owner == "" && expire == "" && cl.LeaseProc == "" {
+		return nil
+	}
+
+	if owner == "" || expire == "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckLeaseFieldsEmpty}
+	}
+
+	if cl.LeaseProc == "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckLeaseProcEmpty}
+	}
+
+	return nil
+}
+
+// Допустимые форматы поля версии записи
+var versionFormat = map[octopus.Format]bool{
+	octopus.Uint32: true,
+	octopus.Uint64: true,
+	octopus.Uint:   true,
+	octopus.Int32:  true,
+	octopus.Int64:  true,
+	octopus.Int:    true,
+}
+
+// checkVersion проверка описания поля версии записи
+// - в сущности не более одного поля версии
+// - поле версии целочисленное, не может быть первичным ключом или сериализованным
+// - изменения мутаторов отправляются отдельными запросами, поэтому мутаторы в сущности с версией не поддерживаются
+func checkVersion(cl *ds.RecordPackage) error {
+	version := ""
+
+	for _, fld := range cl.Fields {
+		if fld.Version == "" {
+			continue
+		}
+
+		if version != "" {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckVersionManyDecl}
+		}
+
+		if fld.PrimaryKey {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckVersionConflictPK}
+		}
+
+		if _, ex := versionFormat[fld.Format]; !ex || len(fld.Serializer) > 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+
+		version = fld.Name
+	}
+
+	if version == "" {
+		return nil
+	}
+
+	for _, fld := range cl.Fields {
+		if len(fld.Mutators) > 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckVersionConflictMutator}
+		}
+	}
+
+	return nil
+}
+
+// checkSoftDelete проверка описания поля с временем удаления записи
+// - поле описано в сущности и хранит unix time в целочисленном формате
+// - поле не может быть первичным ключом, сериализованным или иметь мутаторы
+func checkSoftDelete(cl *ds.RecordPackage) error {
+	if cl.SoftDelete == "" {
+		return nil
+	}
+
+	num, ex := cl.FieldsMap[cl.SoftDelete]
+	if !ex {
+		return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: cl.SoftDelete, Err: arerror.ErrCheckSoftDeleteFieldNotFound}
+	}
+
+	fld := cl.Fields[num]
+
+	if _, ex := leaseExpireFormat[fld.Format]; !ex || fld.PrimaryKey || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 {
+		return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+	}
+
+	return nil
+}
+
+// checkProtoPkg проверка пакета protobuf: функции преобразования генерируются только для моделей с полями
+func checkProtoPkg(cl *ds.RecordPackage) error {
+	if cl.ProtoPkg != "" && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckProtoPkgFieldsEmpty}
+	}
+
+	return nil
+}
+
+// indexFilterFormats форматы полей, которые могут использоваться в условиях фильтра индекса
+var indexFilterFormats = map[octopus.Format]struct{}{
+	octopus.Bool: {}, octopus.String: {}, octopus.UUID: {},
+	octopus.Int8: {}, octopus.Int16: {}, octopus.Int32: {}, octopus.Int64: {}, octopus.Int: {},
+	octopus.Uint8: {}, octopus.Uint16: {}, octopus.Uint32: {}, octopus.Uint64: {}, octopus.Uint: {},
+	octopus.Float32: {}, octopus.Float64: {},
+}
+
+// checkIndexFilter проверка условий частичных индексов
+// - первичный индекс содержит все записи и не может иметь фильтр
+// - в условиях используются скалярные поля без сериализаторов и массивов
+// - значение приводится к формату поля, null допустим только для nullable полей
+func checkIndexFilter(cl *ds.RecordPackage) error {
+	for _, ind := range cl.Indexes {
+		if len(ind.Filter) == 0 {
+			continue
+		}
+
+		if ind.Primary || ind.Num == 0 {
+			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexFilterPrimary}
+		}
+
+		for _, cond := range ind.Filter {
+			fld := cl.Fields[cond.Field]
+
+			if _, ex := indexFilterFormats[fld.Format]; !ex || fld.Array || len(fld.Serializer) > 0 {
+				return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexFilterField}
+			}
+
+			if cond.Value == ds.IndexFilterNull {
+				if !fld.Nullable {
+					return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexFilterValue}
 				}
-			default:
-				return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: backend, Err: arerror.ErrCheckBackendUnknown}
+
+				continue
+			}
+
+			if _, err := fld.Literal(cond.Value); err != nil {
+				return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexFilterValue}
 			}
 		}
 	}
@@ -226,51 +448,958 @@ func Check(files map[string]*ds.RecordPackage, linkedObjects map[string]string)
 	return nil
 }
 
-//nolint:gocognit,gocyclo
-func checkOctopus(cl *ds.RecordPackage) error {
-	if cl.Server.Host == "" && cl.Server.Conf == "" {
-		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerEmpty}
+// checkNullable проверка полей, которые могут хранить NULL
+// - поле не может входить в индекс
+// - поле не может иметь сериализатор, мутаторы или особую роль в модели
+func checkNullable(cl *ds.RecordPackage) error {
+	for num, fld := range cl.Fields {
+		if !fld.Nullable {
+			continue
+		}
+
+		if fld.PrimaryKey || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldNullableConflict}
+		}
+
+		for _, ind := range cl.Indexes {
+			for _, indField := range ind.Fields {
+				if indField == num {
+					return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldNullableConflict}
+				}
+			}
+		}
 	}
 
-	if cl.Server.Host == "" && cl.Server.Port != "" {
-		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckPortEmpty}
+	return nil
+}
+
+// checkDecimal проверка полей с десятичными числами. Значения decimal.Decimal нельзя сравнивать
+// оператором `==`, поэтому такие поля не могут входить в индексы, а атомарные операции для них не реализованы
+func checkDecimal(cl *ds.RecordPackage) error {
+	for num, fld := range cl.Fields {
+		if fld.Format != octopus.Decimal {
+			continue
+		}
+
+		if fld.PrimaryKey || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldDecimalConflict}
+		}
+
+		for _, ind := range cl.Indexes {
+			for _, indField := range ind.Fields {
+				if indField == num {
+					return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldDecimalConflict}
+				}
+			}
+		}
 	}
 
-	if cl.Server.Host != "" && cl.Server.Conf != "" {
-		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerConflict}
+	return nil
+}
+
+// checkTimestamp проверка полей time.Time
+// - для поля указан формат хранения, для полей других форматов формат хранения не указывается
+// - значения time.Time с разными зонами не равны при сравнении оператором `==`, поэтому поле не может
+// входить в индексы, а атомарные операции и сериализаторы для него не применяются
+func checkTimestamp(cl *ds.RecordPackage) error {
+	for num, fld := range cl.Fields {
+		if fld.Format != octopus.Time {
+			if fld.Timestamp != "" {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+			}
+
+			continue
+		}
+
+		if fld.Timestamp == "" {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTimestampEmpty}
+		}
+
+		if fld.PrimaryKey || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTimestampConflict}
+		}
+
+		for _, ind := range cl.Indexes {
+			for _, indField := range ind.Fields {
+				if indField == num {
+					return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTimestampConflict}
+				}
+			}
+		}
 	}
 
-	for _, fl := range cl.Fields {
-		if (fl.Format == "string" || fl.Format == "[]byte") && fl.Size == 0 {
-			log.Printf("Warn: field `%s` declaration. Field with type string or []byte not contain size.", fl.Name)
+	return nil
+}
+
+// arrayElemFormat форматы элементов поля-массива
+var arrayElemFormat = func() map[octopus.Format]bool {
+	ret := map[octopus.Format]bool{octopus.String: true, octopus.Bool: true}
+
+	for _, form := range append(octopus.NumericFormat, octopus.FloatFormat...) {
+		ret[form] = true
+	}
+
+	return ret
+}()
+
+// checkArray проверка полей-массивов
+// - элементы массива имеют базовый формат
+// - массив не может входить в индекс, иметь сериализатор, мутаторы или особую роль в модели
+// - nil срез уже означает отсутствие значения, поэтому nullable для массива не указывается
+func checkArray(cl *ds.RecordPackage) error {
+	for num, fld := range cl.Fields {
+		if !fld.Array {
+			continue
+		}
+
+		if !arrayElemFormat[fld.Format] {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+
+		if fld.PrimaryKey || fld.Nullable || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldArrayConflict}
+		}
+
+		for _, ind := range cl.Indexes {
+			for _, indField := range ind.Fields {
+				if indField == num {
+					return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldArrayConflict}
+				}
+			}
 		}
 	}
 
-	for _, ind := range cl.Indexes {
-		if len(ind.Fields) == 0 {
-			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckFieldIndexEmpty}
+	return nil
+}
+
+// checkEnum проверка перечислений
+// - перечисление описывается для строковых и целочисленных полей
+// - имена констант являются идентификаторами Go, имена и значения не повторяются, значения соответствуют формату поля
+// - поле-перечисление не может быть первичным ключом, иметь сериализатор, мутаторы или особую роль в модели
+func checkEnum(cl *ds.RecordPackage) error {
+	enumFormat := map[octopus.Format]bool{octopus.String: true}
+	for _, form := range octopus.NumericFormat {
+		enumFormat[form] = true
+	}
+
+	for _, fld := range cl.Fields {
+		if len(fld.Enum) == 0 {
+			continue
+		}
+
+		if !enumFormat[fld.Format] || fld.Array {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+
+		if _, ex := cl.FlagMap[fld.Name]; ex || fld.PrimaryKey || fld.Nullable || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldEnumConflict}
+		}
+
+		names := map[string]bool{}
+		values := map[string]bool{}
+
+		for _, v := range fld.Enum {
+			lit, err := fld.Literal(v.Value)
+			if err != nil || !token.IsIdentifier(fld.Name+v.Name) || names[v.Name] || values[lit] {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldEnumInvalid}
+			}
+
+			names[v.Name] = true
+			values[lit] = true
 		}
 	}
 
-	if len(cl.Fields) > 0 {
-		_, err := strconv.ParseInt(cl.Namespace.ObjectName, 10, 64)
-		if err != nil {
-			return &arerror.ErrCheckPackageNamespaceDecl{Pkg: cl.Namespace.PackageName, Name: cl.Namespace.ObjectName, Err: arerror.ErrCheckFieldInvalidFormat}
+	return nil
+}
+
+// checkTypeOverride проверка пользовательских типов полей
+// - пользовательский тип задаётся для строковых, логических и числовых полей, его базовый тип должен совпадать
+// с форматом поля, что проверяется при компиляции сгенерированного кода
+// - поле пользовательского типа не может быть перечислением, первичным ключом, иметь сериализатор, мутаторы или особую роль в модели
+func checkTypeOverride(cl *ds.RecordPackage) error {
+	overrideFormat := map[octopus.Format]bool{octopus.String: true, octopus.Bool: true, octopus.Float32: true, octopus.Float64: true}
+	for _, form := range octopus.NumericFormat {
+		overrideFormat[form] = true
+	}
+
+	for _, fld := range cl.Fields {
+		if fld.TypeOverride.Empty() {
+			continue
+		}
+
+		if !overrideFormat[fld.Format] || fld.Array {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+
+		if _, ex := cl.FlagMap[fld.Name]; ex || len(fld.Enum) > 0 || fld.PrimaryKey || fld.Nullable || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTypeOverrideConflict}
 		}
 	}
 
-	octopusProcAvailFormat := map[octopus.Format]bool{}
-	for _, form := range []octopus.Format{octopus.String, octopus.StringArray, octopus.ByteArray} {
-		octopusProcAvailFormat[form] = true
+	return nil
+}
+
+// checkDefault проверка значений по умолчанию
+// - значение по умолчанию приводится к типу поля
+// - массивы и сериализуемые поля не имеют значения по умолчанию
+func checkDefault(cl *ds.RecordPackage) error {
+	for _, fld := range cl.Fields {
+		if fld.Default == "" {
+			continue
+		}
+
+		if fld.Array || len(fld.Serializer) > 0 || len(fld.Payload) != 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldDefaultConflict}
+		}
+
+		if _, err := fld.DefaultValue(); err != nil {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// checkValidation проверка ограничений на значения полей
+// - границы min и max задаются только для числовых полей, приводятся к типу поля и min не больше max
+// - длина и регулярное выражение задаются только для строковых полей, регулярное выражение компилируется
+// - ограничения не применяются к массивам, перечислениям и сериализуемым полям, required - к nullable полям
+// - проверка перед вставкой и обновлением (validate) включается только при наличии ограничений
+func checkValidation(cl *ds.RecordPackage) error {
+	numericFormat := map[octopus.Format]bool{}
+	for _, form := range append(octopus.NumericFormat, octopus.FloatFormat...) {
+		numericFormat[form] = true
+	}
+
+	validated := false
+
+	for _, fld := range cl.Fields {
+		v := fld.Validation
+		if v.Empty() {
+			continue
+		}
+
+		validated = true
+
+		if fld.Array || len(fld.Enum) > 0 || len(fld.Serializer) > 0 || (v.Required && fld.Nullable) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationConflict}
+		}
+
+		if (v.Min != "" || v.Max != "") && !numericFormat[fld.Format] {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+
+		if (v.MinLen != 0 || v.MaxLen != 0 || v.Regex != "") && fld.Format != octopus.String {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+
+		if _, err := fld.ValidationChecks("v"); err != nil {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: err}
+		}
+
+		if !validBounds(v) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+	}
+
+	if cl.Validate && !validated {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckValidateFieldsEmpty}
 	}
 
+	// Входные параметры процедуры проверяются перед вызовом, значение проверяется до сериализации.
+	// Для параметров-списков допустимы только обязательность и ограничения длины
 	for _, fld := range cl.ProcInFields {
-		if _, ex := octopusProcAvailFormat[fld.Format]; !ex {
-			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		if !fld.Validated() {
+			continue
 		}
 
-		if fld.Format != octopus.String && len(fld.Serializer) == 0 {
-			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
+		v := fld.Validation
+		isList := strings.HasPrefix(string(fld.Format), "[]")
+
+		if len(fld.Serializer) > 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationConflict}
+		}
+
+		if (v.Min != "" || v.Max != "") && !numericFormat[fld.Format] {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+
+		if ((v.MinLen != 0 || v.MaxLen != 0) && fld.Format != octopus.String && !isList) || (v.Regex != "" && fld.Format != octopus.String) || (len(fld.Enum) != 0 && isList) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+
+		if _, err := fld.ValidationChecks("v"); err != nil {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: err}
+		}
+
+		if !validBounds(v) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldValidationInvalid}
+		}
+	}
+
+	return nil
+}
+
+// validBounds проверяет согласованность ограничений: минимум не больше максимума и корректное регулярное выражение
+func validBounds(v ds.Validation) bool {
+	if v.Min != "" && v.Max != "" {
+		minValue, _ := strconv.ParseFloat(v.Min, 64)
+		maxValue, _ := strconv.ParseFloat(v.Max, 64)
+
+		if minValue > maxValue {
+			return false
+		}
+	}
+
+	if v.MaxLen != 0 && v.MinLen > v.MaxLen {
+		return false
+	}
+
+	_, err := regexp.Compile(v.Regex)
+
+	return err == nil
+}
+
+// checkComputed проверка описания вычисляемых полей
+// - имя вычисляемого поля не совпадает с именами полей модели
+// - функция вычисления ссылается только на существующие поля модели
+func checkComputed(cl *ds.RecordPackage) error {
+	for _, fld := range cl.ComputedFields {
+		if _, ex := cl.FieldsMap[fld.Name]; ex {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckComputedFieldRedefined}
+		}
+
+		for _, dep := range fld.Compute.Fields {
+			num, ex := cl.FieldsMap[dep]
+			if !ex || cl.Fields[num].Name != dep {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckComputedFieldNotFound}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkNames проверка имён, из которых формируются идентификаторы сгенерированного кода.
+// Имена полей, параметров процедуры и индексов берутся из декларации на Go и всегда являются идентификаторами,
+// но селекторы и флаги задаются тегами, поэтому могут оказаться зарезервированным словом или начинаться с цифры.
+// Такие имена не экранируются, так как сгенерированные функции должны называться так, как указано в декларации
+func checkNames(cl *ds.RecordPackage) error {
+	for _, fields := range [][]ds.FieldDeclaration{cl.Fields, cl.ComputedFields} {
+		for _, fld := range fields {
+			if !token.IsIdentifier(fld.Name) {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckNameInvalid}
+			}
+		}
+	}
+
+	for _, fld := range cl.ProcInFields {
+		if !token.IsIdentifier(fld.Name) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckNameInvalid}
+		}
+	}
+
+	for _, fld := range cl.ProcOutFields {
+		if !token.IsIdentifier(fld.Name) {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckNameInvalid}
+		}
+	}
+
+	for _, name := range sortedFlagFields(cl.FlagMap) {
+		for _, flag := range cl.FlagMap[name].Flags {
+			if !token.IsIdentifier(flag) {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: name + "." + flag, Err: arerror.ErrCheckNameInvalid}
+			}
+		}
+	}
+
+	for _, ind := range cl.Indexes {
+		if !token.IsIdentifier(ind.Name) || (ind.Selector != "" && !token.IsIdentifier(ind.Selector)) {
+			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckNameInvalid}
+		}
+	}
+
+	return nil
+}
+
+// sortedFlagFields возвращает имена полей с флагами в порядке сортировки, чтобы ошибка не зависела от порядка обхода map
+func sortedFlagFields(flags map[string]ds.FlagDeclaration) []string {
+	ret := make([]string, 0, len(flags))
+	for name := range flags {
+		ret = append(ret, name)
+	}
+
+	sort.Strings(ret)
+
+	return ret
+}
+
+// checkAPIName проверка имён полей в JSON
+// - имя не задаётся для полей с тегом sensitive, так как они не попадают в JSON
+// - имена полей модели и вычисляемых полей в JSON не повторяются
+func checkAPIName(cl *ds.RecordPackage) error {
+	names := map[string]bool{}
+
+	for _, fld := range append(append([]ds.FieldDeclaration{}, cl.Fields...), cl.ComputedFields...) {
+		if fld.Sensitive && fld.APIName != "" && fld.APIName != ds.APINameOmit {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldAPINameConflict}
+		}
+
+		name := fld.JSONName()
+		if name == ds.APINameOmit {
+			continue
+		}
+
+		if names[name] {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldAPINameDuplicate}
+		}
+
+		names[name] = true
+	}
+
+	return nil
+}
+
+// checkPayload проверка описания полиморфного поля
+// - полиморфное поле и дискриминатор описываются только вместе и не более одного в сущности
+// - полиморфное поле строковое и не может иметь собственного сериализатора
+// - для всех значений дискриминатора описаны сериализаторы
+// - значения дискриминатора соответствуют его формату
+func checkPayload(cl *ds.RecordPackage) error {
+	var payload, discriminator *ds.FieldDeclaration
+
+	discriminatorFormat := map[octopus.Format]bool{octopus.String: true}
+	for _, form := range octopus.NumericFormat {
+		discriminatorFormat[form] = true
+	}
+
+	for i, fld := range cl.Fields {
+		if fld.Discriminator {
+			if discriminator != nil {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckPayloadManyDecl}
+			}
+
+			if _, ex := discriminatorFormat[fld.Format]; !ex || len(fld.Serializer) > 0 {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+			}
+
+			discriminator = &cl.Fields[i]
+		}
+
+		if len(fld.Payload) > 0 {
+			if payload != nil {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckPayloadManyDecl}
+			}
+
+			if len(fld.Serializer) > 0 {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckPayloadConflictSerializer}
+			}
+
+			if fld.Format != octopus.String {
+				return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+			}
+
+			payload = &cl.Fields[i]
+		}
+	}
+
+	if payload == nil && discriminator == nil {
+		return nil
+	}
+
+	if payload == nil || discriminator == nil {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckPayloadDiscriminatorEmpty}
+	}
+
+	for value, serializer := range payload.Payload {
+		if _, ex := cl.SerializerMap[serializer]; !ex {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: payload.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
+		}
+
+		if discriminator.Format == octopus.String {
+			continue
+		}
+
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: discriminator.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+	}
+
+	return nil
+}
+
+// checkAudit проверка журнала аудита: изменения полей записываются при Update, которого нет у процедур
+func checkAudit(cl *ds.RecordPackage) error {
+	if cl.Audit && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckAuditProc}
+	}
+
+	return nil
+}
+
+// checkOutbox проверка outbox: события формируются при изменении записи, которого нет у процедур
+func checkOutbox(cl *ds.RecordPackage) error {
+	if cl.Outbox != "" && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckOutboxProc}
+	}
+
+	return nil
+}
+
+// checkShardBy проверка поля шардирования. Шард записи вычисляется по значению поля, поэтому поле должно
+// входить в первичный ключ: тогда операции по первичному ключу направляются в один шард
+func checkShardBy(cl *ds.RecordPackage) error {
+	for _, ind := range cl.Indexes {
+		if ind.Scatter && cl.Server.ShardBy == "" {
+			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexScatter}
+		}
+	}
+
+	if cl.Server.ShardBy == "" {
+		return nil
+	}
+
+	if cl.Server.Sharding != "ring" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckShardByRing}
+	}
+
+	fldNum, ex := cl.FieldsMap[cl.Server.ShardBy]
+	if !ex {
+		return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: cl.Server.ShardBy, Err: arerror.ErrFieldNotExist}
+	}
+
+	fld := cl.Fields[fldNum]
+	if fld.Nullable || fld.Array || len(fld.Serializer) != 0 {
+		return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckShardByField}
+	}
+
+	for _, ind := range cl.Indexes {
+		if !ind.Primary {
+			continue
+		}
+
+		for _, num := range ind.Fields {
+			if num == fldNum {
+				return nil
+			}
+		}
+	}
+
+	return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckShardByField}
+}
+
+// checkImmutable проверка неизменяемых полей: значение такого поля задаётся только при вставке,
+// поэтому поле не может изменяться мутаторами, заменой, версией, арендой или мягким удалением
+func checkImmutable(cl *ds.RecordPackage) error {
+	for _, fld := range cl.Fields {
+		if !fld.Immutable {
+			continue
+		}
+
+		if len(fld.Mutators) != 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldImmutableConflict}
+		}
+	}
+
+	return nil
+}
+
+// checkTouch проверка полей, обновляемых методом Touch: поле получает текущее время,
+// поэтому должно иметь формат, допустимый для значения по умолчанию now(), и не может быть ключом,
+// сериализованным, nullable, массивом, перечислением или изменяться другими механизмами
+func checkTouch(cl *ds.RecordPackage) error {
+	for _, fld := range cl.Fields {
+		if !fld.Touch {
+			continue
+		}
+
+		if fld.PrimaryKey || fld.Immutable || fld.Nullable || fld.Array || len(fld.Enum) > 0 || len(fld.Serializer) > 0 || len(fld.Mutators) > 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 || fld.Name == cl.SoftDelete {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTouchConflict}
+		}
+
+		if _, err := fld.TouchValue(); err != nil {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldTouchConflict}
+		}
+	}
+
+	return nil
+}
+
+// Check основная функция, которая запускает процесс проверки
+// Должна вызываться только после окончания процесса парсинга всех деклараций
+func Check(files map[string]*ds.RecordPackage, linkedObjects map[string]string) error {
+	for _, cl := range files {
+		if err := checkBackend(cl); err != nil {
+			return err
+		}
+
+		if err := checkNamespace(cl.Namespace); err != nil {
+			return err
+		}
+
+		if err := checkLinkedObject(cl, linkedObjects); err != nil {
+			return err
+		}
+
+		if err := checkNames(cl); err != nil {
+			return err
+		}
+
+		if err := checkFields(cl); err != nil {
+			return err
+		}
+
+		if err := checkLease(cl); err != nil {
+			return err
+		}
+
+		if err := checkPayload(cl); err != nil {
+			return err
+		}
+
+		if err := checkVersion(cl); err != nil {
+			return err
+		}
+
+		if err := checkSoftDelete(cl); err != nil {
+			return err
+		}
+
+		if err := checkAudit(cl); err != nil {
+			return err
+		}
+
+		if err := checkOutbox(cl); err != nil {
+			return err
+		}
+
+		if err := checkShardBy(cl); err != nil {
+			return err
+		}
+
+		if err := checkImmutable(cl); err != nil {
+			return err
+		}
+
+		if err := checkNullable(cl); err != nil {
+			return err
+		}
+
+		if err := checkDecimal(cl); err != nil {
+			return err
+		}
+
+		if err := checkTimestamp(cl); err != nil {
+			return err
+		}
+
+		if err := checkArray(cl); err != nil {
+			return err
+		}
+
+		if err := checkEnum(cl); err != nil {
+			return err
+		}
+
+		if err := checkTypeOverride(cl); err != nil {
+			return err
+		}
+
+		if err := checkDefault(cl); err != nil {
+			return err
+		}
+
+		if err := checkTouch(cl); err != nil {
+			return err
+		}
+
+		if err := checkValidation(cl); err != nil {
+			return err
+		}
+
+		if err := checkComputed(cl); err != nil {
+			return err
+		}
+
+		if err := checkAPIName(cl); err != nil {
+			return err
+		}
+
+		if err := checkProtoPkg(cl); err != nil {
+			return err
+		}
+
+		if err := checkIndexFilter(cl); err != nil {
+			return err
+		}
+
+		// Бекендозависимые проверки
+		for _, backend := range cl.Backends {
+			switch backend {
+			case "octopus":
+				if err := checkOctopus(cl); err != nil {
+					return err
+				}
+			case "tarantool2":
+				if err := checkTarantool2(cl); err != nil {
+					return err
+				}
+			case "mock":
+				if err := checkMock(cl); err != nil {
+					return err
+				}
+			case "memory":
+				if err := checkMemory(cl); err != nil {
+					return err
+				}
+			default:
+				return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: backend, Err: arerror.ErrCheckBackendUnknown}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkServer проверка описания сервера
+func checkServer(cl *ds.RecordPackage) error {
+	if cl.Server.Host == "" && cl.Server.Conf == "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerEmpty}
+	}
+
+	if cl.Server.Host == "" && cl.Server.Port != "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckPortEmpty}
+	}
+
+	if cl.Server.Host != "" && cl.Server.Conf != "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerConflict}
+	}
+
+	// Реплики из декларации дополняют сервер, описанный в декларации
+	if cl.Server.Conf != "" && len(cl.Server.Replicas) != 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerReplicasConflict}
+	}
+
+	// Параметры пула применяются только к серверу, описанному в декларации
+	if cl.Server.Conf != "" && (cl.Server.MaxConns != 0 || cl.Server.MinConns != 0 || cl.Server.ConnTimeout != 0) {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerPoolConflict}
+	}
+
+	// По умолчанию пул состоит из одного соединения
+	maxConns := cl.Server.MaxConns
+	if maxConns == 0 {
+		maxConns = 1
+	}
+
+	if cl.Server.MinConns > maxConns {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerPoolSize}
+	}
+
+	if cl.Server.RetryDelay != 0 && cl.Server.Retry == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerRetryDelay}
+	}
+
+	if cl.Server.CacheTTL != 0 && cl.Server.CacheSize == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerCacheTTL}
+	}
+
+	// Кеш хранит записи по первичному ключу, которого у процедуры нет
+	if cl.Server.CacheSize != 0 && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckServerCacheProc}
+	}
+
+	return nil
+}
+
+//nolint:gocognit,gocyclo
+func checkOctopus(cl *ds.RecordPackage) error {
+	if err := checkServer(cl); err != nil {
+		return err
+	}
+
+	if cl.Server.Sharding != "" && cl.Server.Sharding != "ring" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckShardingUnknown}
+	}
+
+	// Повтор запросов при временных ошибках и группы выходных параметров процедур реализованы только для tarantool2.
+	// Outbox требует транзакции из нескольких запросов, которых в протоколе octopus нет, SQL в octopus тоже нет
+	if cl.Server.Retry != 0 || len(cl.ProcOutGroups) != 0 || cl.Outbox != "" || cl.Prepared {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "octopus", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	for _, fl := range cl.Fields {
+		if (fl.Format == "string" || fl.Format == "[]byte") && fl.Size == 0 {
+			log.Printf("Warn: field `%s` declaration. Field with type string or []byte not contain size.", fl.Name)
+		}
+
+		// В тупле octopus нет отдельного значения для NULL и вложенных массивов
+		if fl.Nullable || fl.Array {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fl.Name, Err: arerror.ErrCheckBackendFeatureUnsupported}
+		}
+	}
+
+	for _, ind := range cl.Indexes {
+		if len(ind.Fields) == 0 {
+			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckFieldIndexEmpty}
+		}
+	}
+
+	if len(cl.Fields) > 0 {
+		_, err := strconv.ParseInt(cl.Namespace.ObjectName, 10, 64)
+		if err != nil {
+			return &arerror.ErrCheckPackageNamespaceDecl{Pkg: cl.Namespace.PackageName, Name: cl.Namespace.ObjectName, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+	}
+
+	octopusProcAvailFormat := map[octopus.Format]bool{}
+	for _, form := range []octopus.Format{octopus.String, octopus.StringArray, octopus.ByteArray} {
+		octopusProcAvailFormat[form] = true
+	}
+
+	for _, fld := range cl.ProcInFields {
+		if _, ex := octopusProcAvailFormat[fld.Format]; !ex {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldInvalidFormat}
+		}
+
+		if fld.Format != octopus.String && len(fld.Serializer) == 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckFieldSerializerNotFound}
+		}
+	}
+
+	return nil
+}
+
+// checkTarantool2 проверка декларации для tarantool 2.x. Возможности, которые реализованы
+// только поверх протокола octopus, для этого бекенда не поддерживаются
+func checkTarantool2(cl *ds.RecordPackage) error {
+	if err := checkServer(cl); err != nil {
+		return err
+	}
+
+	if cl.Server.Sharding != "" && cl.Server.Sharding != "ring" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Err: arerror.ErrCheckShardingUnknown}
+	}
+
+	// Шард записи вычисляется по её ключу, у процедуры ключа нет
+	if cl.Server.Sharding != "" && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "tarantool2", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	// Реплики tarantool2 задаются конфигом шардов, параметр serverReplicas используется только для octopus
+	if len(cl.Server.Replicas) != 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "tarantool2", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	// Подготовленные запросы используются только для выборок записей модели
+	if cl.Prepared && len(cl.Fields) == 0 {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "tarantool2", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	if err := checkTarantool2IndexParts(cl); err != nil {
+		return err
+	}
+
+	// Шардирование проверено выше, остальные возможности проверяются как для других бекендов
+	base := *cl
+	base.Server.Sharding = ""
+
+	return checkBaseFeatures(&base, "tarantool2")
+}
+
+// tarantool2IndexFormat форматы полей, для которых в спейсе tarantool можно создать часть индекса
+var tarantool2IndexFormat = map[octopus.Format]bool{
+	octopus.Int8: true, octopus.Int16: true, octopus.Int32: true, octopus.Int64: true, octopus.Int: true,
+	octopus.Uint8: true, octopus.Uint16: true, octopus.Uint32: true, octopus.Uint64: true, octopus.Uint: true,
+	octopus.Float32: true, octopus.Float64: true,
+	octopus.String: true, octopus.Bool: true, octopus.ByteArray: true,
+	octopus.UUID: true, octopus.Decimal: true,
+}
+
+// checkTarantool2IndexParts проверка полей индексов tarantool 2.x: части индекса создаются с типом
+// поля формата спейса, поля с типом array или scalar индексировать нельзя
+func checkTarantool2IndexParts(cl *ds.RecordPackage) error {
+	for _, ind := range cl.Indexes {
+		for _, num := range ind.Fields {
+			fld := cl.Fields[num]
+			if fld.Array || !tarantool2IndexFormat[fld.Format] {
+				return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckIndexFieldFormat}
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkMock проверка модели для бекенда mock, хранящего записи в памяти.
+// Бекенд не обращается к серверу и не поддерживает вызов процедур
+func checkMock(cl *ds.RecordPackage) error {
+	if len(cl.ProcOutFields) != 0 || len(cl.Fields) == 0 || cl.Server.Retry != 0 || cl.Server.CacheSize != 0 || cl.Audit || cl.Outbox != "" || cl.Prepared {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "mock", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	for _, fld := range cl.Fields {
+		if fld.Nullable || fld.Array {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckBackendFeatureUnsupported}
+		}
+	}
+
+	return checkBaseFeatures(cl, "mock")
+}
+
+// checkMemory проверка модели для бекенда memory, хранящего записи в памяти процесса для локальной разработки.
+// В отличие от mock поддерживаются триггеры жизненного цикла и стандартные мутаторы полей, триггеры
+// восстановления тупла, пользовательские мутаторы (процедуры БД) и флаги не поддерживаются
+func checkMemory(cl *ds.RecordPackage) error {
+	if len(cl.ProcOutFields) != 0 || len(cl.Fields) == 0 || cl.Server.Retry != 0 || cl.Server.CacheSize != 0 || len(cl.FlagMap) != 0 || cl.Audit || cl.Outbox != "" || cl.Prepared {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "memory", Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	// Этап заполняется только у триггеров жизненного цикла записи
+	for _, trigger := range cl.TriggerMap {
+		if trigger.Phase == "" {
+			return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: "memory", Err: arerror.ErrCheckBackendFeatureUnsupported}
+		}
+	}
+
+	base := *cl
+	base.TriggerMap = nil
+	base.Fields = make([]ds.FieldDeclaration, 0, len(cl.Fields))
+
+	for _, fld := range cl.Fields {
+		if fld.Nullable || fld.Array {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckBackendFeatureUnsupported}
+		}
+
+		for _, m := range fld.Mutators {
+			if _, ok := cl.MutatorMap[m]; ok {
+				return &arerror.ErrCheckPackageFieldMutatorDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Mutator: m, Err: arerror.ErrCheckBackendFeatureUnsupported}
+			}
+		}
+
+		fld.Mutators = nil
+		base.Fields = append(base.Fields, fld)
+	}
+
+	// Триггеры и мутаторы проверены выше, остальные возможности проверяются как для других бекендов
+	return checkBaseFeatures(&base, "memory")
+}
+
+// checkBaseFeatures проверка, что в модели используются только возможности, поддерживаемые всеми бекендами.
+// Шардирование (кроме tarantool2), параметры пула соединений, трассировка, триггеры, связанные объекты, мутаторы, аренда и полиморфные поля есть только у octopus
+func checkBaseFeatures(cl *ds.RecordPackage, backend string) error {
+	if cl.Server.Sharding != "" || cl.Trace || cl.Server.MaxConns != 0 || cl.Server.MinConns != 0 || cl.Server.ConnTimeout != 0 || len(cl.TriggerMap) != 0 || len(cl.FieldsObjectMap) != 0 || cl.LeaseProc != "" || cl.SoftDelete != "" {
+		return &arerror.ErrCheckPackageDecl{Pkg: cl.Namespace.PackageName, Backend: backend, Err: arerror.ErrCheckBackendFeatureUnsupported}
+	}
+
+	for _, ind := range cl.Indexes {
+		if len(ind.Fields) == 0 {
+			return &arerror.ErrCheckPackageIndexDecl{Pkg: cl.Namespace.PackageName, Index: ind.Name, Err: arerror.ErrCheckFieldIndexEmpty}
+		}
+	}
+
+	for _, fld := range cl.Fields {
+		if len(fld.Mutators) != 0 || fld.Swappable != "" || fld.Version != "" || fld.Lease != "" || fld.Discriminator || len(fld.Payload) != 0 {
+			return &arerror.ErrCheckPackageFieldDecl{Pkg: cl.Namespace.PackageName, Field: fld.Name, Err: arerror.ErrCheckBackendFeatureUnsupported}
 		}
 	}
 
@@ -92,6 +92,25 @@ func TestCheck(t *testing.T) {
 		return
 	}
 
+	rpInvalidSharding := ds.NewRecordPackage()
+	rpInvalidSharding.Backends = []string{"octopus"}
+	rpInvalidSharding.Namespace = ds.NamespaceDeclaration{ObjectName: "0", PackageName: "invsharding", PublicName: "InvalidSharding"}
+	rpInvalidSharding.Server = ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Sharding: "modulo"}
+
+	err = rpInvalidSharding.AddField(ds.FieldDeclaration{
+		Name:       "ID",
+		Format:     octopus.Int,
+		PrimaryKey: true,
+		Mutators:   []string{},
+		Size:       0,
+		Serializer: []string{},
+		ObjectLink: "",
+	})
+	if err != nil {
+		t.Errorf("can't prepare test data: %s", err)
+		return
+	}
+
 	type args struct {
 		files         map[string]*ds.RecordPackage
 		linkedObjects map[string]string
@@ -133,6 +152,14 @@ func TestCheck(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "unknown sharding",
+			args: args{
+				files:         map[string]*ds.RecordPackage{"invalid": rpInvalidSharding},
+				linkedObjects: map[string]string{},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -49,6 +49,13 @@ func Test_checkLinkedObject(t *testing.T) {
 		return
 	}
 
+	rpEmbedded := ds.NewRecordPackage()
+
+	if err := rpEmbedded.AddSerializer(ds.SerializerDeclaration{Name: "Address", Embed: "address"}); err != nil {
+		t.Errorf("can't prepare test data: %s", err)
+		return
+	}
+
 	type args struct {
 		cl            *ds.RecordPackage
 		linkedObjects map[string]string
@@ -62,6 +69,8 @@ func Test_checkLinkedObject(t *testing.T) {
 		{name: "without linked obj", args: args{cl: rp, linkedObjects: map[string]string{}}, wantErr: false},
 		{name: "no linked obj", args: args{cl: rpLinked, linkedObjects: map[string]string{}}, wantErr: true},
 		{name: "normal linked obj", args: args{cl: rpLinked, linkedObjects: map[string]string{"bar": "bar"}}, wantErr: false},
+		{name: "no embedded obj", args: args{cl: rpEmbedded, linkedObjects: map[string]string{"bar": "bar"}}, wantErr: true},
+		{name: "normal embedded obj", args: args{cl: rpEmbedded, linkedObjects: map[string]string{"address": "address"}}, wantErr: false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -198,6 +207,48 @@ func Test_checkFields(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "embedded record in int field",
+			args: args{
+				cl: ds.RecordPackage{
+					Fields: []ds.FieldDeclaration{
+						{
+							Name:       "Foo",
+							Format:     "int",
+							PrimaryKey: true,
+						},
+						{
+							Name:       "Address",
+							Format:     "int",
+							Serializer: []string{"Address"},
+						},
+					},
+					SerializerMap: map[string]ds.SerializerDeclaration{"Address": {Name: "Address", Embed: "address"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "embedded record",
+			args: args{
+				cl: ds.RecordPackage{
+					Fields: []ds.FieldDeclaration{
+						{
+							Name:       "Foo",
+							Format:     "int",
+							PrimaryKey: true,
+						},
+						{
+							Name:       "Address",
+							Format:     "string",
+							Serializer: []string{"Address"},
+						},
+					},
+					SerializerMap: map[string]ds.SerializerDeclaration{"Address": {Name: "Address", Embed: "address"}},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "fields conflict with links",
 			args: args{
@@ -216,6 +267,22 @@ func Test_checkFields(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "swappable and primary",
+			args: args{
+				cl: ds.RecordPackage{
+					Fields: []ds.FieldDeclaration{
+						{
+							Name:       "Foo",
+							Format:     "int",
+							PrimaryKey: true,
+							Swappable:  "foo_swap",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "mutators and primary",
 			args: args{
@@ -370,6 +437,35 @@ func Test_checkFields(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "custom mutator args not match arity",
+			args: args{
+				cl: ds.RecordPackage{
+					Fields: []ds.FieldDeclaration{
+						{
+							Name:       "Pk",
+							Format:     "int",
+							PrimaryKey: true,
+						},
+						{
+							Name:     "Foo",
+							Format:   "string",
+							Mutators: []string{"clamp"},
+						},
+					},
+					MutatorMap: map[string]ds.MutatorDeclaration{
+						"clamp": {
+							Name:   "clamp",
+							Type:   "string",
+							Update: "clamp_max",
+							Args:   []string{"100"},
+							Arity:  2,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -380,194 +476,1679 @@ func Test_checkFields(t *testing.T) {
 	}
 }
 
-func Test_checkProcFields(t *testing.T) {
-	type args struct {
-		cl ds.RecordPackage
-	}
+func Test_checkLease(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+	owner := ds.FieldDeclaration{Name: "Worker", Format: "string", Lease: ds.LeaseOwner}
+	expire := ds.FieldDeclaration{Name: "Until", Format: "int64", Lease: ds.LeaseExpire}
+
 	tests := []struct {
 		name    string
-		args    args
+		cl      ds.RecordPackage
 		wantErr bool
 	}{
 		{
-			name: "empty fields",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{},
-				},
-			},
-			wantErr: true,
+			name:    "without lease",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
 		},
 		{
-			name: "2 fields declaration",
-			args: args{
-				cl: ds.RecordPackage{
-					Fields: []ds.FieldDeclaration{
-						{
-							Name:       "Foo",
-							Format:     "int",
-							PrimaryKey: true,
-						},
-					},
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.INOUT,
-						},
-					},
-				},
-			},
-			wantErr: true,
+			name:    "lease",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, owner, expire}, LeaseProc: "foo_lease"},
+			wantErr: false,
 		},
 		{
-			name: "empty format",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name: "Foo",
-							Type: ds.OUT,
-						},
-					},
-				},
-			},
+			name:    "lease without expire field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, owner}, LeaseProc: "foo_lease"},
 			wantErr: true,
 		},
 		{
-			name: "invalid input format",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.OUT,
-						},
-					},
-					ProcInFields: []ds.ProcFieldDeclaration{
-						{
-							Name:   "Foo",
-							Format: "[]int",
-							Type:   ds.IN,
-						},
-					},
-				},
-			},
+			name:    "lease without proc",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, owner, expire}},
 			wantErr: true,
 		},
 		{
-			name: "invalid output format",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "[]int",
-							Type:   ds.OUT,
-						},
-					},
-				},
-			},
+			name:    "lease proc without fields",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}, LeaseProc: "foo_lease"},
 			wantErr: true,
 		},
 		{
-			name: "type not found",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-						},
-					},
-				},
+			name: "invalid expire format",
+			cl: ds.RecordPackage{
+				Fields:    []ds.FieldDeclaration{pk, owner, {Name: "Until", Format: "string", Lease: ds.LeaseExpire}},
+				LeaseProc: "foo_lease",
 			},
 			wantErr: true,
 		},
 		{
-			name: "incorrect fields order",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-						},
-						2: {
-							Name:   "Bar",
-							Format: "int",
-						},
-					},
-				},
+			name: "lease and primary",
+			cl: ds.RecordPackage{
+				Fields:    []ds.FieldDeclaration{{Name: "Worker", Format: "string", PrimaryKey: true, Lease: ds.LeaseOwner}, expire},
+				LeaseProc: "foo_lease",
 			},
 			wantErr: true,
 		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkLease(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkLease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkVersion(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+	version := ds.FieldDeclaration{Name: "Version", Format: "uint32", Version: "foo_version"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
 		{
-			name: "normal field",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.OUT,
-						},
-					},
-				},
-			},
+			name:    "without version",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
 			wantErr: false,
 		},
 		{
-			name: "normal input field",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcInFields: []ds.ProcFieldDeclaration{
-						{
-							Name:   "Foo",
-							Format: "[]string",
-							Type:   ds.IN,
-						},
-					},
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.OUT,
-						},
-					},
-				},
-			},
+			name:    "version",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, version}},
 			wantErr: false,
 		},
 		{
-			name: "serializer not declared",
-			args: args{
-				cl: ds.RecordPackage{
-					ProcOutFields: ds.ProcFieldDeclarations{
-						0: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.OUT,
-						},
-						1: {
-							Name:   "Foo",
-							Format: "int",
-							Type:   ds.OUT,
-							Serializer: []string{
-								"fser",
-							},
-						},
-					},
-				},
-			},
+			name:    "few versions",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, version, {Name: "Rev", Format: "int64", Version: "foo_version"}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Version", Format: "string", Version: "foo_version"}}},
+			wantErr: true,
+		},
+		{
+			name:    "version and primary",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{{Name: "Version", Format: "int64", PrimaryKey: true, Version: "foo_version"}}},
+			wantErr: true,
+		},
+		{
+			name:    "version and mutators",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, version, {Name: "Cnt", Format: "int64", Mutators: []string{ds.IncMutator}}}},
 			wantErr: true,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := checkFields(&tt.args.cl); (err != nil) != tt.wantErr {
-				t.Errorf("checkFields() error = %v, wantErr %v", err, tt.wantErr)
+			if err := checkVersion(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkSoftDelete(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+	deleted := ds.FieldDeclaration{Name: "DeletedAt", Format: "uint32"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "without soft delete",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "soft delete",
+			cl:      ds.RecordPackage{SoftDelete: "DeletedAt", Fields: []ds.FieldDeclaration{pk, deleted}, FieldsMap: map[string]int{"Foo": 0, "DeletedAt": 1}},
+			wantErr: false,
+		},
+		{
+			name:    "field not found",
+			cl:      ds.RecordPackage{SoftDelete: "DeletedAt", Fields: []ds.FieldDeclaration{pk}, FieldsMap: map[string]int{"Foo": 0}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid format",
+			cl:      ds.RecordPackage{SoftDelete: "DeletedAt", Fields: []ds.FieldDeclaration{pk, {Name: "DeletedAt", Format: "string"}}, FieldsMap: map[string]int{"Foo": 0, "DeletedAt": 1}},
+			wantErr: true,
+		},
+		{
+			name:    "primary key",
+			cl:      ds.RecordPackage{SoftDelete: "Foo", Fields: []ds.FieldDeclaration{{Name: "Foo", Format: "uint32", PrimaryKey: true}}, FieldsMap: map[string]int{"Foo": 0}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkSoftDelete(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkSoftDelete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkAudit(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "model",
+			cl:      ds.RecordPackage{Audit: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "proc without audit",
+			cl:      ds.RecordPackage{ProcOutFields: map[int]ds.ProcFieldDeclaration{0: {Name: "Out", Format: "string", Type: ds.OUT}}},
+			wantErr: false,
+		},
+		{
+			name:    "proc",
+			cl:      ds.RecordPackage{Audit: true, ProcOutFields: map[int]ds.ProcFieldDeclaration{0: {Name: "Out", Format: "string", Type: ds.OUT}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAudit(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkAudit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkOutbox(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "model",
+			cl:      ds.RecordPackage{Outbox: "foo_outbox", Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "proc",
+			cl:      ds.RecordPackage{Outbox: "foo_outbox", ProcOutFields: map[int]ds.ProcFieldDeclaration{0: {Name: "Out", Format: "string", Type: ds.OUT}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkOutbox(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkOutbox() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkShardBy(t *testing.T) {
+	account := ds.FieldDeclaration{Name: "AccountID", Format: "int64", PrimaryKey: true}
+	id := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	email := ds.FieldDeclaration{Name: "Email", Format: "string"}
+	primary := ds.IndexDeclaration{Name: "Primary", Fields: []int{0, 1}, Primary: true, Unique: true}
+	fieldsMap := map[string]int{"AccountID": 0, "ID": 1, "Email": 2}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "without shardBy",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{account, id}, FieldsMap: fieldsMap, Indexes: []ds.IndexDeclaration{primary}},
+			wantErr: false,
+		},
+		{
+			name: "primary key field",
+			cl: ds.RecordPackage{
+				Server:    ds.ServerDeclaration{Sharding: "ring", ShardBy: "AccountID"},
+				Fields:    []ds.FieldDeclaration{account, id, email},
+				FieldsMap: fieldsMap,
+				Indexes:   []ds.IndexDeclaration{primary, {Name: "Email", Fields: []int{2}, Scatter: true}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "without ring",
+			cl: ds.RecordPackage{
+				Server:    ds.ServerDeclaration{ShardBy: "AccountID"},
+				Fields:    []ds.FieldDeclaration{account, id},
+				FieldsMap: fieldsMap,
+				Indexes:   []ds.IndexDeclaration{primary},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown field",
+			cl: ds.RecordPackage{
+				Server:    ds.ServerDeclaration{Sharding: "ring", ShardBy: "UserID"},
+				Fields:    []ds.FieldDeclaration{account, id},
+				FieldsMap: fieldsMap,
+				Indexes:   []ds.IndexDeclaration{primary},
+			},
+			wantErr: true,
+		},
+		{
+			name: "not primary key field",
+			cl: ds.RecordPackage{
+				Server:    ds.ServerDeclaration{Sharding: "ring", ShardBy: "Email"},
+				Fields:    []ds.FieldDeclaration{account, id, email},
+				FieldsMap: fieldsMap,
+				Indexes:   []ds.IndexDeclaration{primary},
+			},
+			wantErr: true,
+		},
+		{
+			name: "scatter without shardBy",
+			cl: ds.RecordPackage{
+				Server:    ds.ServerDeclaration{Sharding: "ring"},
+				Fields:    []ds.FieldDeclaration{account, id, email},
+				FieldsMap: fieldsMap,
+				Indexes:   []ds.IndexDeclaration{primary, {Name: "Email", Fields: []int{2}, Scatter: true}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkShardBy(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkShardBy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkImmutable(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "immutable field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Created", Format: "uint32", Immutable: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "immutable with mutators",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Cnt", Format: "uint32", Immutable: true, Mutators: []string{"inc"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "immutable with swappable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Owner", Format: "string", Immutable: true, Swappable: "swap_owner"}}},
+			wantErr: true,
+		},
+		{
+			name:    "immutable soft delete field",
+			cl:      ds.RecordPackage{SoftDelete: "Deleted", Fields: []ds.FieldDeclaration{pk, {Name: "Deleted", Format: "bool", Immutable: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkImmutable(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkImmutable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkProtoPkg(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "Foo", Format: "int", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "without proto",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "model",
+			cl:      ds.RecordPackage{ProtoPkg: "example.com/pb/foopb", Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "procedure",
+			cl:      ds.RecordPackage{ProtoPkg: "example.com/pb/foopb", ProcOutFields: ds.ProcFieldDeclarations{0: {Name: "Foo", Format: "int"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkProtoPkg(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkProtoPkg() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkNullable(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	nick := ds.FieldDeclaration{Name: "Nick", Format: "string", Nullable: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "nullable field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, nick}, Indexes: []ds.IndexDeclaration{{Name: "ID", Fields: []int{0}, Primary: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "nullable in index",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, nick}, Indexes: []ds.IndexDeclaration{{Name: "Nick", Fields: []int{1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "nullable with serializer",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Nick", Format: "string", Nullable: true, Serializer: []string{"JSON"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "nullable primary key",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{{Name: "ID", Format: "int64", PrimaryKey: true, Nullable: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkNullable(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkNullable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkDecimal(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	amount := ds.FieldDeclaration{Name: "Amount", Format: "decimal.Decimal"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "decimal field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, amount}, Indexes: []ds.IndexDeclaration{{Name: "ID", Fields: []int{0}, Primary: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "decimal in index",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, amount}, Indexes: []ds.IndexDeclaration{{Name: "Amount", Fields: []int{1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "decimal with mutators",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Amount", Format: "decimal.Decimal", Mutators: []string{ds.IncMutator}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDecimal(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkDecimal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkTimestamp(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	created := ds.FieldDeclaration{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnix}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "timestamp field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, created}, Indexes: []ds.IndexDeclaration{{Name: "ID", Fields: []int{0}, Primary: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "storage format not set",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Created", Format: "time.Time"}}},
+			wantErr: true,
+		},
+		{
+			name:    "storage format for int field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Created", Format: "int64", Timestamp: ds.TimestampUnix}}},
+			wantErr: true,
+		},
+		{
+			name:    "timestamp in index",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, created}, Indexes: []ds.IndexDeclaration{{Name: "Created", Fields: []int{1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "timestamp with serializer",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Created", Format: "time.Time", Timestamp: ds.TimestampRFC3339, Serializer: []string{"JSON"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTimestamp(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkTimestamp() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkValidation(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name: "valid rules",
+			cl: ds.RecordPackage{Validate: true, Fields: []ds.FieldDeclaration{
+				pk,
+				{Name: "Age", Format: "int32", Nullable: true, Validation: ds.Validation{Min: "18", Max: "150"}},
+				{Name: "Name", Format: "string", Validation: ds.Validation{Required: true, MinLen: 2, MaxLen: 32, Regex: `^\w+$`}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "validate without rules",
+			cl:      ds.RecordPackage{Validate: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "min for string field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Name", Format: "string", Validation: ds.Validation{Min: "1"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "max not assignable to field type",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Age", Format: "uint8", Validation: ds.Validation{Max: "300"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "min greater than max",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Score", Format: "float64", Validation: ds.Validation{Min: "1.5", Max: "0.5"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "length for int field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Age", Format: "int32", Validation: ds.Validation{MaxLen: 3}}}},
+			wantErr: true,
+		},
+		{
+			name:    "minlen greater than maxlen",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Name", Format: "string", Validation: ds.Validation{MinLen: 5, MaxLen: 3}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Name", Format: "string", Validation: ds.Validation{Regex: "[a-z"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "required nullable field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Name", Format: "string", Nullable: true, Validation: ds.Validation{Required: true}}}},
+			wantErr: true,
+		},
+		{
+			name:    "rules for serialized field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Data", Format: "string", Serializer: []string{"JSON"}, Validation: ds.Validation{Required: true}}}},
+			wantErr: true,
+		},
+		{
+			name: "valid proc param rules",
+			cl: ds.RecordPackage{ProcInFields: []ds.ProcFieldDeclaration{
+				{Name: "Login", Format: "string", Type: ds.IN, Validation: ds.Validation{Required: true, MaxLen: 32}, Enum: []string{"admin", "user"}},
+				{Name: "Limit", Format: "int64", Type: ds.IN, Validation: ds.Validation{Min: "1", Max: "100"}},
+				{Name: "Tags", Format: "[]string", Type: ds.IN, Validation: ds.Validation{Required: true, MaxLen: 10}},
+			}},
+			wantErr: false,
+		},
+		{
+			name:    "proc param enum value not assignable",
+			cl:      ds.RecordPackage{ProcInFields: []ds.ProcFieldDeclaration{{Name: "Limit", Format: "uint8", Type: ds.IN, Enum: []string{"1", "-1"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "proc list param with regex",
+			cl:      ds.RecordPackage{ProcInFields: []ds.ProcFieldDeclaration{{Name: "Tags", Format: "[]string", Type: ds.IN, Validation: ds.Validation{Regex: "^a"}}}},
+			wantErr: true,
+		},
+		{
+			name:    "proc serialized param with rules",
+			cl:      ds.RecordPackage{ProcInFields: []ds.ProcFieldDeclaration{{Name: "Tags", Format: "[]string", Type: ds.IN, Serializer: []string{"JSON"}, Validation: ds.Validation{Required: true}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkValidation(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkValidation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkComputed(t *testing.T) {
+	fields := []ds.FieldDeclaration{
+		{Name: "ID", Format: "int64", PrimaryKey: true},
+		{Name: "First", Format: "string"},
+		{Name: "Last", Format: "string", StorageName: "surname"},
+	}
+	fieldsMap := map[string]int{"ID": 0, "First": 1, "Last": 2, "surname": 2}
+
+	tests := []struct {
+		name     string
+		computed ds.FieldDeclaration
+		wantErr  bool
+	}{
+		{
+			name:     "existing fields",
+			computed: ds.FieldDeclaration{Name: "FullName", Format: "string", Compute: ds.Compute{Func: "FullName", Fields: []string{"First", "Last"}}},
+			wantErr:  false,
+		},
+		{
+			name:     "unknown field",
+			computed: ds.FieldDeclaration{Name: "FullName", Format: "string", Compute: ds.Compute{Func: "FullName", Fields: []string{"First", "Middle"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "storage name instead of field name",
+			computed: ds.FieldDeclaration{Name: "FullName", Format: "string", Compute: ds.Compute{Func: "FullName", Fields: []string{"surname"}}},
+			wantErr:  true,
+		},
+		{
+			name:     "name conflicts with field",
+			computed: ds.FieldDeclaration{Name: "First", Format: "string", Compute: ds.Compute{Func: "FullName", Fields: []string{"Last"}}},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := ds.RecordPackage{Fields: fields, FieldsMap: fieldsMap, ComputedFields: []ds.FieldDeclaration{tt.computed}}
+			if err := checkComputed(&cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkComputed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkNames(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name: "valid names",
+			cl: ds.RecordPackage{
+				Fields:  []ds.FieldDeclaration{pk, {Name: "type_", Format: "string"}},
+				Indexes: []ds.IndexDeclaration{{Name: "ID", Selector: "SelectByID"}},
+				FlagMap: map[string]ds.FlagDeclaration{"Flags": {Name: "Flags", Flags: []string{"Active"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "field type",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "type", Format: "string"}}},
+			wantErr: true,
+		},
+		{
+			name:    "field range",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "range", Format: "string"}}},
+			wantErr: true,
+		},
+		{
+			name:    "field starts with digit",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "1st", Format: "string"}}},
+			wantErr: true,
+		},
+		{
+			name:    "computed field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}, ComputedFields: []ds.FieldDeclaration{{Name: "func", Format: "string"}}},
+			wantErr: true,
+		},
+		{
+			name:    "procedure param",
+			cl:      ds.RecordPackage{ProcInFields: []ds.ProcFieldDeclaration{{Name: "select", Format: "string"}}},
+			wantErr: true,
+		},
+		{
+			name:    "selector",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}, Indexes: []ds.IndexDeclaration{{Name: "ID", Selector: "func"}}},
+			wantErr: true,
+		},
+		{
+			name:    "flag",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}, FlagMap: map[string]ds.FlagDeclaration{"Flags": {Name: "Flags", Flags: []string{"1st"}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkNames(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkNames() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkAPIName(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "api names",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Login", Format: "string", APIName: "login_name"}, {Name: "Hash", Format: "string", APIName: ds.APINameOmit}}},
+			wantErr: false,
+		},
+		{
+			name:    "duplicate api name",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Login", Format: "string", APIName: "id"}}},
+			wantErr: true,
+		},
+		{
+			name:    "computed field with field api name",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UserName", Format: "string"}}, ComputedFields: []ds.FieldDeclaration{{Name: "FullName", Format: "string", APIName: "user_name"}}},
+			wantErr: true,
+		},
+		{
+			name:    "excluded fields with same name",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Password", Format: "string", Sensitive: true}, {Name: "Hash", Format: "string", APIName: ds.APINameOmit}}},
+			wantErr: false,
+		},
+		{
+			name:    "api name for sensitive field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Password", Format: "string", Sensitive: true, APIName: "password"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkAPIName(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkAPIName() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkArray(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	tags := ds.FieldDeclaration{Name: "Tags", Format: "int64", Array: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "array field",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, tags}, Indexes: []ds.IndexDeclaration{{Name: "ID", Fields: []int{0}, Primary: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "array in index",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, tags}, Indexes: []ds.IndexDeclaration{{Name: "Tags", Fields: []int{1}}}},
+			wantErr: true,
+		},
+		{
+			name:    "nullable array",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Tags", Format: "int64", Array: true, Nullable: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "array of uuid",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Refs", Format: "uuid.UUID", Array: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkArray(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkArray() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkDefault(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "default values",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Default: "new"}, {Name: "CreatedAt", Format: "int64", Default: "now()"}}},
+			wantErr: false,
+		},
+		{
+			name:    "default not assignable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Age", Format: "uint8", Default: "old"}}},
+			wantErr: true,
+		},
+		{
+			name:    "default for array",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Tags", Format: "int64", Array: true, Default: "1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "default with serializer",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Attr", Format: "string", Serializer: []string{"AttrJSON"}, Default: "{}"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkDefault(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkDefault() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkTouch(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "touch fields",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UpdatedAt", Format: "int64", Touch: true}, {Name: "Seen", Format: "time.Time", Timestamp: "unix", Touch: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "touch primary key",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{{Name: "ID", Format: "int64", PrimaryKey: true, Touch: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "touch nullable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UpdatedAt", Format: "int64", Nullable: true, Touch: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "touch immutable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UpdatedAt", Format: "int64", Immutable: true, Touch: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "touch with mutator",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UpdatedAt", Format: "int64", Mutators: []string{"inc"}, Touch: true}}},
+			wantErr: true,
+		},
+		{
+			name:    "touch float",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "UpdatedAt", Format: "float64", Touch: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTouch(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkTouch() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkEnum(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "enum values",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}, {Name: "Done", Value: "closed"}}, Default: "new"}, {Name: "Level", Format: "uint8", Enum: []ds.EnumValue{{Name: "Low", Value: "1"}, {Name: "High", Value: "2"}}}}},
+			wantErr: false,
+		},
+		{
+			name:    "enum value not assignable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Level", Format: "uint8", Enum: []ds.EnumValue{{Name: "Low", Value: "low"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate enum value",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}, {Name: "Fresh", Value: "new"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "invalid enum name",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "In progress", Value: "new"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "default not in enum",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}}, Default: "old"}}},
+			wantErr: true,
+		},
+		{
+			name:    "enum for bool",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Flag", Format: "bool", Enum: []ds.EnumValue{{Name: "Yes", Value: "true"}}}}},
+			wantErr: true,
+		},
+		{
+			name:    "enum nullable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Status", Format: "string", Nullable: true, Enum: []ds.EnumValue{{Name: "New", Value: "new"}}}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkEnum(&tt.cl)
+			if err == nil {
+				err = checkDefault(&tt.cl)
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkEnum() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkTypeOverride(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+	email := ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Email", ImportName: "domain"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "string and numeric",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Mail", Format: "string", TypeOverride: email}, {Name: "Price", Format: "int64", TypeOverride: ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Cents", ImportName: "domain"}}}},
+			wantErr: false,
+		},
+		{
+			name:    "uuid",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Token", Format: "uuid.UUID", TypeOverride: email}}},
+			wantErr: true,
+		},
+		{
+			name:    "array",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Mails", Format: "string", Array: true, TypeOverride: email}}},
+			wantErr: true,
+		},
+		{
+			name:    "with enum",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Mail", Format: "string", Enum: []ds.EnumValue{{Name: "Admin", Value: "admin@mail"}}, TypeOverride: email}}},
+			wantErr: true,
+		},
+		{
+			name:    "with serializer",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Mail", Format: "string", Serializer: []string{"JSON"}, TypeOverride: email}}},
+			wantErr: true,
+		},
+		{
+			name:    "primary key",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{{Name: "Mail", Format: "string", PrimaryKey: true, TypeOverride: email}}},
+			wantErr: true,
+		},
+		{
+			name:    "nullable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Mail", Format: "string", Nullable: true, TypeOverride: email}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTypeOverride(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkTypeOverride() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkIndexFilter(t *testing.T) {
+	fields := []ds.FieldDeclaration{
+		{Name: "ID", Format: "int64"},
+		{Name: "State", Format: "uint8"},
+		{Name: "Nick", Format: "string", Nullable: true},
+		{Name: "Data", Format: "string", Serializer: []string{"JSON"}},
+		{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnix},
+	}
+
+	tests := []struct {
+		name    string
+		ind     ds.IndexDeclaration
+		wantErr bool
+	}{
+		{
+			name: "filter",
+			ind:  ds.IndexDeclaration{Name: "State", Num: 1, Fields: []int{1}, Filter: []ds.IndexFilterCond{{Field: 1, Value: "1"}, {Field: 2, Value: "null", Negate: true}}},
+		},
+		{
+			name:    "primary",
+			ind:     ds.IndexDeclaration{Name: "ID", Num: 0, Fields: []int{0}, Primary: true, Filter: []ds.IndexFilterCond{{Field: 1, Value: "1"}}},
+			wantErr: true,
+		},
+		{
+			name:    "value out of range",
+			ind:     ds.IndexDeclaration{Name: "State", Num: 1, Fields: []int{1}, Filter: []ds.IndexFilterCond{{Field: 1, Value: "256"}}},
+			wantErr: true,
+		},
+		{
+			name:    "null for not nullable field",
+			ind:     ds.IndexDeclaration{Name: "State", Num: 1, Fields: []int{1}, Filter: []ds.IndexFilterCond{{Field: 1, Value: "null"}}},
+			wantErr: true,
+		},
+		{
+			name:    "serializer",
+			ind:     ds.IndexDeclaration{Name: "State", Num: 1, Fields: []int{1}, Filter: []ds.IndexFilterCond{{Field: 3, Value: "{}"}}},
+			wantErr: true,
+		},
+		{
+			name:    "time",
+			ind:     ds.IndexDeclaration{Name: "State", Num: 1, Fields: []int{1}, Filter: []ds.IndexFilterCond{{Field: 4, Value: "0"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := &ds.RecordPackage{Fields: fields, Indexes: []ds.IndexDeclaration{tt.ind}}
+
+			if err := checkIndexFilter(cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkIndexFilter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkServer(t *testing.T) {
+	tests := []struct {
+		name    string
+		server  ds.ServerDeclaration
+		wantErr bool
+	}{
+		{
+			name:    "host",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011"},
+			wantErr: false,
+		},
+		{
+			name:    "pool params",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", MaxConns: 8, MinConns: 2, ConnTimeout: 100},
+			wantErr: false,
+		},
+		{
+			name:    "min conns without max conns",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", MinConns: 2},
+			wantErr: true,
+		},
+		{
+			name:    "min conns greater than max conns",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", MaxConns: 2, MinConns: 4},
+			wantErr: true,
+		},
+		{
+			name:    "retry",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Retry: 3, RetryDelay: 20},
+			wantErr: false,
+		},
+		{
+			name:    "retry delay without retry",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", RetryDelay: 20},
+			wantErr: true,
+		},
+		{
+			name:    "cache",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", CacheSize: 1000, CacheTTL: 500},
+			wantErr: false,
+		},
+		{
+			name:    "cache ttl without cache size",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", CacheTTL: 500},
+			wantErr: true,
+		},
+		{
+			name:    "pool params with conf",
+			server:  ds.ServerDeclaration{Conf: "box", MaxConns: 8},
+			wantErr: true,
+		},
+		{
+			name:    "replicas",
+			server:  ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Replicas: []string{"127.0.0.2:11011"}},
+			wantErr: false,
+		},
+		{
+			name:    "replicas with conf",
+			server:  ds.ServerDeclaration{Conf: "box", Replicas: []string{"127.0.0.2:11011"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cl := &ds.RecordPackage{Server: tt.server, Fields: []ds.FieldDeclaration{{Name: "ID", Format: "int64", PrimaryKey: true}}}
+			if err := checkServer(cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkServer() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkPayload(t *testing.T) {
+	serializers := map[string]ds.SerializerDeclaration{"NoteJSON": {Name: "NoteJSON"}}
+	discriminator := ds.FieldDeclaration{Name: "Kind", Format: "uint8", Discriminator: true}
+	payload := ds.FieldDeclaration{Name: "Data", Format: "string", Payload: map[string]string{"1": "NoteJSON"}}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "payload",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{discriminator, payload}, SerializerMap: serializers},
+			wantErr: false,
+		},
+		{
+			name:    "payload without discriminator",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{payload}, SerializerMap: serializers},
+			wantErr: true,
+		},
+		{
+			name:    "payload serializer not declared",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{discriminator, payload}},
+			wantErr: true,
+		},
+		{
+			name: "discriminator value not match format",
+			cl: ds.RecordPackage{
+				Fields:        []ds.FieldDeclaration{discriminator, {Name: "Data", Format: "string", Payload: map[string]string{"note": "NoteJSON"}}},
+				SerializerMap: serializers,
+			},
+			wantErr: true,
+		},
+		{
+			name: "payload with serializer",
+			cl: ds.RecordPackage{
+				Fields:        []ds.FieldDeclaration{discriminator, {Name: "Data", Format: "string", Serializer: []string{"NoteJSON"}, Payload: map[string]string{"1": "NoteJSON"}}},
+				SerializerMap: serializers,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkPayload(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkPayload() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkProcFields(t *testing.T) {
+	type args struct {
+		cl ds.RecordPackage
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name: "empty fields",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "2 fields declaration",
+			args: args{
+				cl: ds.RecordPackage{
+					Fields: []ds.FieldDeclaration{
+						{
+							Name:       "Foo",
+							Format:     "int",
+							PrimaryKey: true,
+						},
+					},
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.INOUT,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty format",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name: "Foo",
+							Type: ds.OUT,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid input format",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.OUT,
+						},
+					},
+					ProcInFields: []ds.ProcFieldDeclaration{
+						{
+							Name:   "Foo",
+							Format: "[]int",
+							Type:   ds.IN,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid output format",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "[]int",
+							Type:   ds.OUT,
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "type not found",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "incorrect fields order",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+						},
+						2: {
+							Name:   "Bar",
+							Format: "int",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "normal field",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.OUT,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "normal input field",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcInFields: []ds.ProcFieldDeclaration{
+						{
+							Name:   "Foo",
+							Format: "[]string",
+							Type:   ds.IN,
+						},
+					},
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.OUT,
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "serializer not declared",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.OUT,
+						},
+						1: {
+							Name:   "Foo",
+							Format: "int",
+							Type:   ds.OUT,
+							Serializer: []string{
+								"fser",
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "output groups",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {Name: "Status", Format: "int", Type: ds.OUT},
+					},
+					ProcOutGroups: []ds.ProcOutGroup{
+						{Name: "Items", Fields: ds.ProcFieldDeclarations{
+							0: {Name: "ItemID", Format: "int", Type: ds.OUT, Group: "Items"},
+						}},
+					},
+					ProcFieldsMap: map[string]int{"Status": 0, "ItemID": 1},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "output group invalid order",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {Name: "Status", Format: "int", Type: ds.OUT},
+					},
+					ProcOutGroups: []ds.ProcOutGroup{
+						{Name: "Items", Fields: ds.ProcFieldDeclarations{
+							1: {Name: "ItemID", Format: "int", Type: ds.OUT, Group: "Items", OrderIndex: 1},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "output group with input param",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {Name: "Status", Format: "int", Type: ds.OUT},
+					},
+					ProcOutGroups: []ds.ProcOutGroup{
+						{Name: "Items", Fields: ds.ProcFieldDeclarations{
+							0: {Name: "ItemID", Format: "int", Type: ds.INOUT, Group: "Items"},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "output group name conflict",
+			args: args{
+				cl: ds.RecordPackage{
+					ProcOutFields: ds.ProcFieldDeclarations{
+						0: {Name: "Status", Format: "int", Type: ds.OUT},
+					},
+					ProcOutGroups: []ds.ProcOutGroup{
+						{Name: "Params", Fields: ds.ProcFieldDeclarations{
+							0: {Name: "ItemID", Format: "int", Type: ds.OUT, Group: "Params"},
+						}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkFields(&tt.args.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkFields() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkTarantool2(t *testing.T) {
+	server := ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500}
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "simple",
+			cl:      ds.RecordPackage{Server: server, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "without server",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name: "sharding ring",
+			cl: ds.RecordPackage{
+				Server: ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500, Sharding: "ring"},
+				Fields: []ds.FieldDeclaration{pk},
+			},
+			wantErr: false,
+		},
+		{
+			name: "sharding unknown",
+			cl: ds.RecordPackage{
+				Server: ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500, Sharding: "mod"},
+				Fields: []ds.FieldDeclaration{pk},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sharding procedure",
+			cl: ds.RecordPackage{
+				Server:        ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500, Sharding: "ring"},
+				ProcOutFields: ds.ProcFieldDeclarations{0: {Name: "Out", Format: "string", Type: ds.OUT}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "pool params",
+			cl: ds.RecordPackage{
+				Server: ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500, MaxConns: 8},
+				Fields: []ds.FieldDeclaration{pk},
+			},
+			wantErr: true,
+		},
+		{
+			name: "replicas",
+			cl: ds.RecordPackage{
+				Server: ds.ServerDeclaration{Host: "127.0.0.1", Port: "3301", Timeout: 500, Replicas: []string{"127.0.0.2:3301"}},
+				Fields: []ds.FieldDeclaration{pk},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mutators",
+			cl: ds.RecordPackage{
+				Server: server,
+				Fields: []ds.FieldDeclaration{pk, {Name: "Cnt", Format: "int64", Mutators: []string{ds.IncMutator}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "index without fields",
+			cl: ds.RecordPackage{
+				Server:  server,
+				Fields:  []ds.FieldDeclaration{pk},
+				Indexes: []ds.IndexDeclaration{{Name: "Empty"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "index on bytes",
+			cl: ds.RecordPackage{
+				Server:  server,
+				Fields:  []ds.FieldDeclaration{pk, {Name: "Hash", Format: "[]byte"}},
+				Indexes: []ds.IndexDeclaration{{Name: "Hash", Fields: []int{1}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "index on string array",
+			cl: ds.RecordPackage{
+				Server:  server,
+				Fields:  []ds.FieldDeclaration{pk, {Name: "Tags", Format: "[]string"}},
+				Indexes: []ds.IndexDeclaration{{Name: "Tags", Fields: []int{1}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "index on array",
+			cl: ds.RecordPackage{
+				Server:  server,
+				Fields:  []ds.FieldDeclaration{pk, {Name: "Codes", Format: "string", Array: true}},
+				Indexes: []ds.IndexDeclaration{{Name: "Codes", Fields: []int{1}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "nullable",
+			cl:      ds.RecordPackage{Server: server, Fields: []ds.FieldDeclaration{pk, {Name: "Nick", Format: "string", Nullable: true}}},
+			wantErr: false,
+		},
+		{
+			name:    "outbox",
+			cl:      ds.RecordPackage{Server: server, Outbox: "foo_outbox", Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "prepared",
+			cl:      ds.RecordPackage{Server: server, Prepared: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name: "prepared procedure",
+			cl: ds.RecordPackage{
+				Server:        server,
+				Prepared:      true,
+				ProcOutFields: ds.ProcFieldDeclarations{0: {Name: "Out", Format: "string", Type: ds.OUT}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkTarantool2(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkTarantool2() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkMock(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "simple",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name:    "without fields",
+			cl:      ds.RecordPackage{},
+			wantErr: true,
+		},
+		{
+			name: "proc",
+			cl: ds.RecordPackage{
+				ProcOutFields: map[int]ds.ProcFieldDeclaration{0: {Name: "Out", Format: "string", Type: ds.OUT}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "trace",
+			cl:      ds.RecordPackage{Trace: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "retry",
+			cl:      ds.RecordPackage{Server: ds.ServerDeclaration{Retry: 3}, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "prepared",
+			cl:      ds.RecordPackage{Prepared: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "cache",
+			cl:      ds.RecordPackage{Server: ds.ServerDeclaration{CacheSize: 100}, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "audit",
+			cl:      ds.RecordPackage{Audit: true, Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name:    "outbox",
+			cl:      ds.RecordPackage{Outbox: "foo_outbox", Fields: []ds.FieldDeclaration{pk}},
+			wantErr: true,
+		},
+		{
+			name: "trigger",
+			cl: ds.RecordPackage{
+				Fields:     []ds.FieldDeclaration{pk},
+				TriggerMap: map[string]ds.TriggerDeclaration{"RepairTuple": {Name: "RepairTuple"}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "nullable",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk, {Name: "Nick", Format: "string", Nullable: true}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMock(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkMock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_checkMemory(t *testing.T) {
+	pk := ds.FieldDeclaration{Name: "ID", Format: "int64", PrimaryKey: true}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantErr bool
+	}{
+		{
+			name:    "simple",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}},
+			wantErr: false,
+		},
+		{
+			name: "lifecycle trigger and mutator",
+			cl: ds.RecordPackage{
+				Fields:     []ds.FieldDeclaration{pk, {Name: "Cnt", Format: "uint32", Mutators: []string{ds.IncMutator}}},
+				TriggerMap: map[string]ds.TriggerDeclaration{"BeforeInsert": {Name: "BeforeInsert", Phase: "BeforeInsert"}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "repair trigger",
+			cl: ds.RecordPackage{
+				Fields:     []ds.FieldDeclaration{pk},
+				TriggerMap: map[string]ds.TriggerDeclaration{"RepairTuple": {Name: "RepairTuple"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom mutator",
+			cl: ds.RecordPackage{
+				Fields:     []ds.FieldDeclaration{pk, {Name: "Score", Format: "string", Mutators: []string{"Clamp"}}},
+				MutatorMap: map[string]ds.MutatorDeclaration{"Clamp": {Name: "Clamp", Update: "clamp_max"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "flags",
+			cl: ds.RecordPackage{
+				Fields:  []ds.FieldDeclaration{pk, {Name: "Status", Format: "uint32", Mutators: []string{ds.SetBitMutator, ds.ClearBitMutator}}},
+				FlagMap: map[string]ds.FlagDeclaration{"Status": {Name: "Status", Flags: []string{"Active"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name:    "without fields",
+			cl:      ds.RecordPackage{},
+			wantErr: true,
+		},
+		{
+			name:    "lease",
+			cl:      ds.RecordPackage{Fields: []ds.FieldDeclaration{pk}, LeaseProc: "lease"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMemory(&tt.cl); (err != nil) != tt.wantErr {
+				t.Errorf("checkMemory() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -2,11 +2,17 @@ package ds
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
 	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/pkg/iproto/util/text"
 	"github.com/mailru/activerecord/pkg/octopus"
 )
 
@@ -19,6 +25,7 @@ type AppInfo struct {
 	buildOS      string
 	buildCommit  string
 	generateTime string
+	sourceFile   string
 }
 
 // Конструктор для AppInfo
@@ -50,11 +57,43 @@ func (i *AppInfo) WithBuildCommit(commit string) *AppInfo {
 	return i
 }
 
+// WithGenerateTime возвращает копию описания с временем генерации generateTime вместо времени создания описания
+func (i AppInfo) WithGenerateTime(generateTime string) AppInfo {
+	i.generateTime = generateTime
+	return i
+}
+
+// WithSourceFile возвращает копию описания с путём к файлу декларации, из которого сгенерирован пакет
+func (i AppInfo) WithSourceFile(sourceFile string) AppInfo {
+	i.sourceFile = sourceFile
+	return i
+}
+
 // Строковое представление версии генератора
-func (i *AppInfo) String() string {
+func (i AppInfo) String() string {
 	return fmt.Sprintf("%s@%s (Commit: %s)", i.appName, i.version, i.buildCommit)
 }
 
+// Version версия генератора
+func (i AppInfo) Version() string {
+	return i.version
+}
+
+// Revision коммит, из которого собран генератор
+func (i AppInfo) Revision() string {
+	return i.buildCommit
+}
+
+// GeneratedAt время запуска генерации в формате RFC3339
+func (i AppInfo) GeneratedAt() string {
+	return i.generateTime
+}
+
+// SourceFile путь к файлу декларации, пустой для файлов, собранных из нескольких деклараций
+func (i AppInfo) SourceFile() string {
+	return i.sourceFile
+}
+
 // Структура для описания неймспейса сущности
 type NamespaceDeclaration struct {
 	ObjectName  string
@@ -68,6 +107,20 @@ type NamespaceDeclaration struct {
 type ServerDeclaration struct {
 	Timeout          int64
 	Host, Port, Conf string
+	Sharding         string // Способ распределения записей по шардам кластера
+	ShardBy          string // Поле, по значению которого выбирается шард записи, по умолчанию шард выбирается по первичному ключу
+	SlowQuery        int64  // Порог медленного запроса в миллисекундах, при превышении вызывается SlowQueryHook
+	MaxConns         int64  // Максимальное количество соединений в пуле, 0 - размер пула по умолчанию
+	MinConns         int64  // Количество соединений, устанавливаемых при создании пула, 0 - одно соединение
+	ConnTimeout      int64  // Таймаут установки соединения в миллисекундах, 0 - используется serverTimeout
+	Retry            int64  // Количество повторов запроса при временных ошибках, 0 - без повторов
+	RetryDelay       int64  // Пауза перед первым повтором в миллисекундах, 0 - пауза по умолчанию
+	CacheSize        int64  // Количество записей в кеше выборки по первичному ключу, 0 - кеш не используется
+	CacheTTL         int64  // Время жизни записи в кеше в миллисекундах, 0 - без ограничения
+	HealthFailures   int64  // Количество неудачных запросов подряд, после которого хранилище считается деградировавшим
+	HealthLatency    int64  // Время выполнения запроса в миллисекундах, выше которого запрос считается неудачным, 0 - не учитывается
+	// Адреса реплик сервера в формате host:port, на которые направляются запросы на чтение
+	Replicas []string
 }
 
 type ImportPackage struct {
@@ -82,7 +135,7 @@ type RecordPackage struct {
 	Server                ServerDeclaration                    // Описание сервера
 	Namespace             NamespaceDeclaration                 // Описание неймспейса/таблицы
 	Fields                []FieldDeclaration                   // Описание полей, важна последовательность для некоторых хранилищ
-	FieldsMap             map[string]int                       // Обратный индекс от имен к полям
+	FieldsMap             map[string]int                       // Обратный индекс от имен к полям, содержит имена полей модели и имена в хранилище
 	FieldsObjectMap       map[string]FieldObject               // Обратный индекс по имени для ссылок на другие сущности
 	Indexes               []IndexDeclaration                   // Список индексов, важна последовательность для некоторых хранилищ
 	IndexMap              map[string]int                       // Обратный индекс от имён для индексов
@@ -94,9 +147,23 @@ type RecordPackage struct {
 	FlagMap               map[string]FlagDeclaration           // Список флагов используемых в полях сущности
 	ProcInFields          []ProcFieldDeclaration               // Описание входных параметров процедуры, важна последовательность
 	ProcOutFields         ProcFieldDeclarations                // Описание выходных параметров процедуры, важна последовательность
+	ProcOutGroups         []ProcOutGroup                       // Именованные группы выходных параметров процедуры, важна последовательность
 	ProcFieldsMap         map[string]int                       // Обратный индекс от имен
 	LinkedStructsMap      map[string]LinkedPackageDeclaration  // Описание пакетов связанных типов
 	ImportStructFieldsMap map[string][]PartialFieldDeclaration // Описаний структур импортируемых полей сущности
+	ComputedFields        []FieldDeclaration                   // Описание вычисляемых полей, не хранящихся в БД
+	LeaseProc             string                               // Имя lua-процедуры захвата и освобождения аренды записи
+	SoftDelete            string                               // Имя поля с временем удаления записи, при его наличии записи не удаляются, а помечаются удалёнными
+	CopyGetters           bool                                 // Признак генерации геттеров, возвращающих копии значений ссылочных типов
+	NullableGetters       bool                                 // Признак генерации методов {Field}OrZero и {Field}Value для nullable полей
+	Validate              bool                                 // Признак проверки ограничений полей методом Validate перед вставкой и обновлением
+	Trace                 bool                                 // Признак генерации span-ов трассировки для запросов к БД
+	Audit                 bool                                 // Признак записи изменений полей в журнал аудита при Update
+	Prepared              bool                                 // Признак выборок по уникальным индексам подготовленными SQL-запросами (tarantool2)
+	Outbox                string                               // Имя неймспейса outbox, в который в одной транзакции с изменением записи добавляется событие
+	ProtoPkg              string                               // Путь импорта пакета с сообщениями protobuf для генерации функций преобразования
+	SourceFile            string                               // Путь к файлу декларации
+	Mixins                map[string]MixinDeclaration          // Группы полей, которые можно подключить в модель встраиванием
 }
 
 func NewImportPackage() ImportPackage {
@@ -151,15 +218,39 @@ type IndexField struct {
 
 // Тип для описания индекса
 type IndexDeclaration struct {
-	Name      string                // Имя индекса
-	Num       uint8                 // Номер индекса в описании спейса
-	Selector  string                // Название функции селектора
-	Fields    []int                 // Список номеров полей участвующих в индексе (последовательность имеет значение)
-	FieldsMap map[string]IndexField // Обратный индекс по именам полей (используется для выявления дублей)
-	Primary   bool                  // Признак того, что индекс является первичным ключом
-	Unique    bool                  // Признак того, что индекс является уникальным
-	Type      string                // Тип индекса, для индексов по одному полю простой тип, для составных индексов собственный тип
-	Partial   bool                  // Признак того, что индекс частичный
+	Name       string                // Имя индекса
+	Num        uint8                 // Номер индекса в описании спейса
+	Selector   string                // Название функции селектора
+	Fields     []int                 // Список номеров полей участвующих в индексе (последовательность имеет значение)
+	FieldsMap  map[string]IndexField // Обратный индекс по именам полей (используется для выявления дублей)
+	Primary    bool                  // Признак того, что индекс является первичным ключом
+	Unique     bool                  // Признак того, что индекс является уникальным
+	Type       string                // Тип индекса, для индексов по одному полю простой тип, для составных индексов собственный тип
+	Partial    bool                  // Признак того, что индекс частичный
+	Projection []int                 // Список номеров полей, возвращаемых проекцией индекса
+	Filter     []IndexFilterCond     // Условия, которым удовлетворяют записи в индексе (объединяются через AND)
+	Scatter    bool                  // Признак того, что выборки без ключа шардирования выполняются во всех шардах
+}
+
+// FieldOrder направление сортировки поля с номером fieldNum в индексе
+func (i IndexDeclaration) FieldOrder(fieldNum int) IndexOrder {
+	for _, indField := range i.FieldsMap {
+		if indField.IndField == fieldNum {
+			return indField.Order
+		}
+	}
+
+	return IndexOrderAsc
+}
+
+// IndexFilterNull значение условия индекса, означающее отсутствие значения у nullable поля
+const IndexFilterNull = "null"
+
+// IndexFilterCond условие фильтра индекса: значение поля равно (или не равно) значению из декларации
+type IndexFilterCond struct {
+	Field  int    // Номер поля в описании модели
+	Value  string // Значение в декларации или IndexFilterNull
+	Negate bool   // Условие на неравенство
 }
 
 // Serializer Сериализаторы для поля
@@ -167,13 +258,160 @@ type Serializer []string
 
 // FieldDeclaration Тип описывающий поле в сущности
 type FieldDeclaration struct {
-	Name       string         // Название поля
-	Format     octopus.Format // формат поля
-	PrimaryKey bool           // участвует ли поле в первичном ключе (при изменении таких полей необходимо делать delete + insert вместо update)
-	Mutators   []string       // список мутаторов (атомарных действий на уровне БД)
-	Size       int64          // Размер поля, используется только для строковых значений
-	Serializer Serializer     // Сериализаторы для поля
-	ObjectLink string         // является ли поле ссылкой на другую сущность
+	Name          string            // Название поля
+	Format        octopus.Format    // формат поля
+	PrimaryKey    bool              // участвует ли поле в первичном ключе (при изменении таких полей необходимо делать delete + insert вместо update)
+	Mutators      []string          // список мутаторов (атомарных действий на уровне БД)
+	Size          int64             // Размер поля, используется только для строковых значений
+	Serializer    Serializer        // Сериализаторы для поля
+	ObjectLink    string            // является ли поле ссылкой на другую сущность
+	Swappable     string            // Имя lua-процедуры для атомарной условной замены значения поля
+	Version       string            // Имя lua-процедуры замены записи при совпадении версии (поле хранит версию записи)
+	Lease         string            // Роль поля в аренде записи (владелец или время окончания аренды)
+	Discriminator bool              // Признак того, что значение поля определяет тип полиморфного поля
+	Payload       map[string]string // Сериализаторы полиморфного поля по значениям дискриминатора
+	Nullable      bool              // Поле может хранить NULL, в модели представлено указателем
+	Array         bool              // Поле хранит массив значений формата Format, в модели представлено срезом
+	Default       string            // Значение по умолчанию, устанавливается при вставке, если поле не заполнено
+	StorageName   string            // Имя поля в хранилище, если оно отличается от имени поля модели
+	Enum          []EnumValue       // Допустимые значения поля, для поля формируется отдельный тип-перечисление
+	Sensitive     bool              // Значение поля не выводится в логи операций
+	Immutable     bool              // Значение поля нельзя изменить после вставки записи
+	Touch         bool              // Поле получает текущее время при вызове Touch
+	APIName       string            // Имя поля в JSON, если оно отличается от имени по умолчанию, "-" исключает поле из JSON
+	Timestamp     string            // Формат хранения поля time.Time
+	Validation    Validation        // Ограничения на значение поля, проверяемые методом Validate модели
+	Compute       Compute           // Описание функции вычисления для вычисляемого поля
+	TypeOverride  TypeOverride      // Пользовательский тип поля в модели вместо базового типа формата
+	Mixin         string            // Имя группы полей, из которой подключено поле
+}
+
+// MixinDeclaration группа полей (миксин), общая для нескольких моделей. Поля группы вставляются
+// в модель на место встраивания группы в структуру Fields*
+type MixinDeclaration struct {
+	Name   string             // Имя группы полей
+	Fields []FieldDeclaration // Описание полей группы, важна последовательность
+}
+
+// TypeOverride пользовательский тип поля модели. Базовый тип пользовательского типа
+// должен совпадать с форматом поля, при упаковке и распаковке значение приводится к формату
+type TypeOverride struct {
+	Pkg        string // Путь импорта пакета с типом
+	Type       string // Имя типа в пакете
+	ImportName string // Имя, под которым пакет импортируется в сгенерированный код
+}
+
+// Empty возвращает признак того, что пользовательский тип не задан
+func (t TypeOverride) Empty() bool {
+	return t.Type == ""
+}
+
+// Compute описание функции вычисляемого поля. Вычисляемое поле не хранится в БД,
+// его значение формируется функцией из значений других полей модели
+type Compute struct {
+	Pkg        string   // Пакет функции
+	Func       string   // Имя функции
+	ImportName string   // Симлинк для импорта пакета
+	Fields     []string // Поля модели, значения которых передаются в функцию
+}
+
+// Validation ограничения на значение поля
+type Validation struct {
+	Required bool   // Значение поля должно отличаться от нулевого
+	Min, Max string // Границы значения числового поля включительно, пустая строка - без ограничения
+	MinLen   int64  // Минимальная длина строкового поля в символах
+	MaxLen   int64  // Максимальная длина строкового поля в символах, 0 - без ограничения
+	Regex    string // Регулярное выражение, которому должно соответствовать значение строкового поля
+}
+
+// Empty возвращает признак того, что ограничения не заданы
+func (v Validation) Empty() bool {
+	return v == Validation{}
+}
+
+// EnumValue значение перечисления
+type EnumValue struct {
+	Name  string // Имя константы, к нему добавляется имя поля
+	Value string // Значение в хранилище
+}
+
+// ExternalFormats типы полей из сторонних пакетов и пакеты, в которых они описаны
+var ExternalFormats = map[octopus.Format]string{
+	octopus.UUID:    "github.com/google/uuid",
+	octopus.Decimal: "github.com/shopspring/decimal",
+	octopus.Time:    "time",
+}
+
+// Форматы хранения полей time.Time
+const (
+	TimestampUnix    = "unix"    // целое число секунд unix time
+	TimestampUnixMs  = "unix_ms" // целое число миллисекунд unix time
+	TimestampRFC3339 = "rfc3339" // строка в формате RFC 3339
+)
+
+// timestampPackFormat форматы упаковки полей time.Time по форматам хранения
+var timestampPackFormat = map[string]octopus.Format{
+	TimestampUnix:    octopus.TimeUnix,
+	TimestampUnixMs:  octopus.TimeUnixMs,
+	TimestampRFC3339: octopus.TimeRFC3339,
+}
+
+// ValidTimestamp проверяет, что формат хранения поля time.Time поддерживается
+func ValidTimestamp(timestamp string) bool {
+	_, ok := timestampPackFormat[timestamp]
+
+	return ok
+}
+
+// PackFormat формат, по которому выбирается упаковщик поля. Для полей time.Time
+// упаковщик зависит от формата хранения, для остальных совпадает с форматом поля
+func (f FieldDeclaration) PackFormat() octopus.Format {
+	if f.Format == octopus.Time {
+		return timestampPackFormat[f.Timestamp]
+	}
+
+	return f.Format
+}
+
+// APINameOmit значение тега api, исключающее поле из JSON
+const APINameOmit = "-"
+
+// JSONName имя поля в JSON: заданное тегом api или имя поля модели в snake_case.
+// Поля с тегом sensitive и исключённые тегом api в JSON не попадают, для них возвращается APINameOmit
+func (f FieldDeclaration) JSONName() string {
+	if f.Sensitive || f.APIName == APINameOmit {
+		return APINameOmit
+	}
+
+	if f.APIName != "" {
+		return f.APIName
+	}
+
+	return text.ToSnakeCase(f.Name)
+}
+
+// EnumType имя типа-перечисления поля
+func (f FieldDeclaration) EnumType() string {
+	return f.Name + "Enum"
+}
+
+// NamedType возвращает имя именованного типа поля в модели: типа-перечисления или
+// пользовательского типа. Для полей базового типа возвращается пустая строка
+func (f FieldDeclaration) NamedType() string {
+	if len(f.Enum) > 0 {
+		return f.EnumType()
+	}
+
+	if !f.TypeOverride.Empty() {
+		return f.TypeOverride.ImportName + "." + f.TypeOverride.Type
+	}
+
+	return ""
+}
+
+// EnumLiteral возвращает значение перечисления в виде литерала на Go
+func (f FieldDeclaration) EnumLiteral(v EnumValue) (string, error) {
+	return f.Literal(v.Value)
 }
 
 // Name возвращает имя сериализатора, если он установлен, иначе пустую строку
@@ -195,6 +433,206 @@ func (s Serializer) Params() string {
 	return ""
 }
 
+// DefaultNow значение по умолчанию, вычисляемое как текущее время в момент вставки
+const DefaultNow = "now()"
+
+// formatBitSize разрядность числовых форматов, используется при разборе значений по умолчанию
+var formatBitSize = map[octopus.Format]int{
+	octopus.Int8: 8, octopus.Int16: 16, octopus.Int32: 32, octopus.Int64: 64, octopus.Int: 0,
+	octopus.Uint8: 8, octopus.Uint16: 16, octopus.Uint32: 32, octopus.Uint64: 64, octopus.Uint: 0,
+	octopus.Float32: 32, octopus.Float64: 64,
+}
+
+// DefaultValue возвращает выражение на Go, вычисляющее значение поля по умолчанию.
+// Литерал разбирается в соответствии с форматом поля, поэтому неприводимое к типу поля значение
+// обнаруживается при генерации. Значение now() означает текущее время: unix-время для целочисленных
+// полей, время в формате RFC3339 для строковых и time.Now() для полей time.Time. Значение поля time.Time
+// задаётся в формате RFC3339. Для перечисления значение должно входить в список
+// допустимых и подставляется соответствующей константой. Для поля пользовательского типа
+// значение приводится к этому типу
+func (f FieldDeclaration) DefaultValue() (string, error) {
+	if len(f.Enum) > 0 {
+		for _, v := range f.Enum {
+			if v.Value == f.Default {
+				return f.Name + v.Name, nil
+			}
+		}
+
+		return "", arerror.ErrCheckFieldDefaultInvalid
+	}
+
+	value, err := f.defaultFormatValue()
+	if err != nil || f.TypeOverride.Empty() {
+		return value, err
+	}
+
+	return f.NamedType() + "(" + value + ")", nil
+}
+
+// TouchValue возвращает выражение на Go, вычисляющее текущее время в формате поля, как значение
+// по умолчанию now(). Используется для полей с тегом touch
+func (f FieldDeclaration) TouchValue() (string, error) {
+	f.Default = DefaultNow
+
+	return f.DefaultValue()
+}
+
+// defaultFormatValue возвращает выражение значения по умолчанию в формате поля
+func (f FieldDeclaration) defaultFormatValue() (string, error) {
+	if f.Default == DefaultNow {
+		switch f.Format {
+		case octopus.Int, octopus.Int64, octopus.Uint, octopus.Uint32, octopus.Uint64:
+			return string(f.Format) + "(time.Now().Unix())", nil
+		case octopus.String:
+			return "time.Now().Format(time.RFC3339)", nil
+		case octopus.Time:
+			return "time.Now()", nil
+		default:
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+	}
+
+	lit, err := f.Literal(f.Default)
+	if err != nil {
+		return "", err
+	}
+
+	if _, ex := formatBitSize[f.Format]; ex {
+		return string(f.Format) + "(" + lit + ")", nil
+	}
+
+	return lit, nil
+}
+
+// Literal возвращает значение value, записанное в декларации, в виде литерала на Go.
+// Значение разбирается в соответствии с форматом поля, числа возвращаются нетипизированными литералами
+func (f FieldDeclaration) Literal(value string) (string, error) {
+	switch f.Format {
+	case octopus.String:
+		return strconv.Quote(value), nil
+	case octopus.Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return strconv.FormatBool(v), nil
+	case octopus.UUID:
+		if _, err := uuid.Parse(value); err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return "uuid.MustParse(" + strconv.Quote(value) + ")", nil
+	case octopus.Decimal:
+		if _, err := decimal.NewFromString(value); err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return "decimal.RequireFromString(" + strconv.Quote(value) + ")", nil
+	case octopus.Time:
+		v, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return fmt.Sprintf("time.Unix(%d, %d).UTC()", v.Unix(), v.Nanosecond()), nil
+	case octopus.Float32, octopus.Float64:
+		v, err := strconv.ParseFloat(value, formatBitSize[f.Format])
+		if err != nil || math.IsInf(v, 0) || math.IsNaN(v) {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return strconv.FormatFloat(v, 'g', -1, formatBitSize[f.Format]), nil
+	case octopus.Uint8, octopus.Uint16, octopus.Uint32, octopus.Uint64, octopus.Uint:
+		v, err := strconv.ParseUint(value, 10, formatBitSize[f.Format])
+		if err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return strconv.FormatUint(v, 10), nil
+	case octopus.Int8, octopus.Int16, octopus.Int32, octopus.Int64, octopus.Int:
+		v, err := strconv.ParseInt(value, 10, formatBitSize[f.Format])
+		if err != nil {
+			return "", arerror.ErrCheckFieldDefaultInvalid
+		}
+
+		return strconv.FormatInt(v, 10), nil
+	default:
+		return "", arerror.ErrCheckFieldDefaultInvalid
+	}
+}
+
+// DefaultUnset возвращает условие на Go, при котором значение varname считается незаполненным
+// и заменяется значением по умолчанию: nil для nullable полей, иначе нулевое значение формата
+func (f FieldDeclaration) DefaultUnset(varname string) string {
+	if f.Nullable {
+		return varname + " == nil"
+	}
+
+	switch f.Format {
+	case octopus.String:
+		return varname + ` == ""`
+	case octopus.Bool:
+		return "!" + varname
+	case octopus.UUID:
+		return varname + " == uuid.Nil"
+	case octopus.Decimal, octopus.Time:
+		return varname + ".IsZero()"
+	default:
+		return varname + " == 0"
+	}
+}
+
+// ValidationCheck проверка ограничения на значение поля
+type ValidationCheck struct {
+	Cond string // Условие на Go, истинное при нарушении ограничения
+	Rule string // Описание ограничения, попадает в ошибку проверки
+}
+
+// ValidationRegexVar имя переменной пакета со скомпилированным регулярным выражением поля
+func (f FieldDeclaration) ValidationRegexVar() string {
+	return "validate" + f.Name + "Regex"
+}
+
+// ValidationChecks возвращает проверки ограничений поля для значения varname в порядке
+// required, min, max, minlen, maxlen, regex. Для nullable поля varname должен быть разыменованным
+// значением, проверка на nil выполняется отдельно
+func (f FieldDeclaration) ValidationChecks(varname string) ([]ValidationCheck, error) {
+	v := f.Validation
+	ret := []ValidationCheck{}
+
+	if v.Required {
+		ret = append(ret, ValidationCheck{Cond: f.DefaultUnset(varname), Rule: "required"})
+	}
+
+	for _, bound := range []struct{ value, op, rule string }{{v.Min, "<", "min"}, {v.Max, ">", "max"}} {
+		if bound.value == "" {
+			continue
+		}
+
+		lit, err := f.Literal(bound.value)
+		if err != nil {
+			return nil, arerror.ErrCheckFieldValidationInvalid
+		}
+
+		ret = append(ret, ValidationCheck{Cond: varname + " " + bound.op + " " + lit, Rule: bound.rule + " " + bound.value})
+	}
+
+	if v.MinLen > 0 {
+		ret = append(ret, ValidationCheck{Cond: fmt.Sprintf("utf8.RuneCountInString(%s) < %d", varname, v.MinLen), Rule: fmt.Sprintf("minlen %d", v.MinLen)})
+	}
+
+	if v.MaxLen > 0 {
+		ret = append(ret, ValidationCheck{Cond: fmt.Sprintf("utf8.RuneCountInString(%s) > %d", varname, v.MaxLen), Rule: fmt.Sprintf("maxlen %d", v.MaxLen)})
+	}
+
+	if v.Regex != "" {
+		ret = append(ret, ValidationCheck{Cond: "!" + f.ValidationRegexVar() + ".MatchString(" + varname + ")", Rule: "regex " + v.Regex})
+	}
+
+	return ret, nil
+}
+
 const (
 	ProcInputParam  = "input"
 	ProcOutputParam = "output"
@@ -230,6 +668,78 @@ type ProcFieldDeclaration struct {
 	Size       int64             // Размер поля, используется только для строковых значений
 	Serializer Serializer        // Сериализатора для поля
 	OrderIndex int               // Порядковый номер параметра в сигнатуре вызова процедуры
+	Group      string            // Имя группы выходных параметров, пустое для основного тупла ответа
+	Validation Validation        // Ограничения на значение входного параметра, проверяемые перед вызовом процедуры
+	Enum       []string          // Допустимые значения входного параметра
+}
+
+// Validated возвращает признак того, что для входного параметра заданы ограничения на значение
+func (p ProcFieldDeclaration) Validated() bool {
+	return !p.Validation.Empty() || len(p.Enum) != 0
+}
+
+// ValidationRegexVar имя переменной пакета со скомпилированным регулярным выражением параметра
+func (p ProcFieldDeclaration) ValidationRegexVar() string {
+	return p.field().ValidationRegexVar()
+}
+
+// ValidationChecks возвращает проверки ограничений входного параметра для значения varname в порядке
+// required, min, max, minlen, maxlen, regex, enum. Для параметров-списков обязательность означает
+// значение отличное от nil, а длина считается в элементах списка
+func (p ProcFieldDeclaration) ValidationChecks(varname string) ([]ValidationCheck, error) {
+	v := p.Validation
+
+	if strings.HasPrefix(string(p.Format), "[]") {
+		ret := []ValidationCheck{}
+
+		if v.Required {
+			ret = append(ret, ValidationCheck{Cond: varname + " == nil", Rule: "required"})
+		}
+
+		if v.MinLen > 0 {
+			ret = append(ret, ValidationCheck{Cond: fmt.Sprintf("len(%s) < %d", varname, v.MinLen), Rule: fmt.Sprintf("minlen %d", v.MinLen)})
+		}
+
+		if v.MaxLen > 0 {
+			ret = append(ret, ValidationCheck{Cond: fmt.Sprintf("len(%s) > %d", varname, v.MaxLen), Rule: fmt.Sprintf("maxlen %d", v.MaxLen)})
+		}
+
+		return ret, nil
+	}
+
+	ret, err := p.field().ValidationChecks(varname)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(p.Enum) != 0 {
+		conds := make([]string, 0, len(p.Enum))
+
+		for _, value := range p.Enum {
+			lit, err := p.field().Literal(value)
+			if err != nil {
+				return nil, arerror.ErrCheckFieldValidationInvalid
+			}
+
+			conds = append(conds, varname+" != "+lit)
+		}
+
+		ret = append(ret, ValidationCheck{Cond: strings.Join(conds, " && "), Rule: "enum " + strings.Join(p.Enum, ",")})
+	}
+
+	return ret, nil
+}
+
+// field описание параметра как поля модели, для переиспользования проверок значения
+func (p ProcFieldDeclaration) field() FieldDeclaration {
+	return FieldDeclaration{Name: p.Name, Format: p.Format, Validation: p.Validation}
+}
+
+// ProcOutGroup именованная группа выходных параметров процедуры. Для каждой группы процедура
+// возвращает отдельный набор строк, следующий в ответе после основного тупла
+type ProcOutGroup struct {
+	Name   string                // Название группы
+	Fields ProcFieldDeclarations // Поля строки группы, OrderIndex - номер поля в тупле строки
 }
 
 // ProcFieldDeclarations Индекс порядкового значения полей процедуры
@@ -275,6 +785,12 @@ func (pfd ProcFieldDeclarations) Validate() bool {
 	return maxIdx < len(pfd)
 }
 
+// Константы описывающие роли полей в аренде записи
+const (
+	LeaseOwner  string = "owner"  // владелец аренды (только строковые поля)
+	LeaseExpire string = "expire" // время окончания аренды в unix time (только целочисленные поля)
+)
+
 // Константы описывающие мутаторы для поля
 const (
 	IncMutator      string = "inc"       // инкремент (только для числовых типов)
@@ -319,31 +835,80 @@ type FieldObject struct {
 
 // Структура описывающая сериализатор
 type SerializerDeclaration struct {
-	Name        string // имя
-	Pkg         string // Пакет для импорта
-	Type        string // Тип данных
-	ImportName  string // Симлинк для импорта
-	Marshaler   string // Имя функции маршалера
-	Unmarshaler string // Имя функции анмаршаллера
+	Name        string   // имя
+	Pkg         string   // Пакет для импорта
+	Type        string   // Тип данных
+	ImportName  string   // Симлинк для импорта
+	Marshaler   string   // Имя функции маршалера
+	Unmarshaler string   // Имя функции анмаршаллера
+	Chain       []string // Цепочка сериализаторов, применяемых последовательно
+	Embed       string   // Пакет модели, запись которой хранится в поле (встроенная модель)
+}
+
+// MarshalFunc возвращает имя функции маршалера для вызова из сгенерированного кода.
+// Функции цепочки сериализаторов генерируются в пакете модели и вызываются без импорта
+func (s SerializerDeclaration) MarshalFunc() string {
+	if len(s.Chain) > 0 {
+		return "marshal" + s.Name
+	}
+
+	return s.ImportName + "." + s.Marshaler
+}
+
+// UnmarshalFunc возвращает имя функции анмаршалера для вызова из сгенерированного кода
+func (s SerializerDeclaration) UnmarshalFunc() string {
+	if len(s.Chain) > 0 {
+		return "unmarshal" + s.Name
+	}
+
+	return s.ImportName + "." + s.Unmarshaler
 }
 
 // MutatorDeclaration Структура описывающая мутатор
 type MutatorDeclaration struct {
-	Name          string // имя
-	Pkg           string // Пакет для импорта
-	Type          string // Тип данных
-	ImportName    string // Симлинк для импорта
-	Update        string // Имя функции для параметров обновления
-	Replace       string // Имя функции для параметров замены
+	Name          string   // имя
+	Pkg           string   // Пакет для импорта
+	Type          string   // Тип данных
+	ImportName    string   // Симлинк для импорта
+	Update        string   // Имя функции для параметров обновления
+	Replace       string   // Имя функции для параметров замены
+	Args          []string // Константные аргументы, передаваемые в функцию/процедуру после значения поля
+	Arity         int      // Ожидаемое количество константных аргументов, 0 - количество не проверяется
 	PartialFields []PartialFieldDeclaration
 }
 
+// Params возвращает константные аргументы мутатора в виде строковых литералов
+// для подстановки в конец списка аргументов вызова функции/процедуры
+func (m MutatorDeclaration) Params() string {
+	ret := ""
+	for _, arg := range m.Args {
+		ret += ", " + strconv.Quote(arg)
+	}
+
+	return ret
+}
+
 // Структура описывающая дополнительный импорты
 type ImportDeclaration struct {
 	Path       string // Путь к пакету
 	ImportName string // Симлинк для пакета при импорте
 }
 
+// PkgName возвращает имя, под которым пакет доступен в сгенерированном коде:
+// симлинк, если он задан, иначе имя пакета из пути
+func (i ImportDeclaration) PkgName() string {
+	if i.ImportName != "" {
+		return i.ImportName
+	}
+
+	name, err := getImportName(i.Path)
+	if err != nil {
+		return ""
+	}
+
+	return name
+}
+
 // Структура описывающая триггеры
 type TriggerDeclaration struct {
 	Name       string          // Имя
@@ -351,12 +916,40 @@ type TriggerDeclaration struct {
 	Func       string          // Имя функции
 	ImportName string          // Симлинк для импорта пакета
 	Params     map[string]bool // Параметры передаваемые в функцию
+	Phase      string          // Этап жизненного цикла записи, на котором вызывается триггер
+	Priority   int             // Приоритет вызова среди триггеров одного этапа, меньшие вызываются раньше
 }
 
 // Структура описывающая флаги для поля
 type FlagDeclaration struct {
-	Name  string   // Имя
-	Flags []string // Список имён флагов
+	Name      string         // Имя
+	Flags     []string       // Список имён флагов
+	Bits      []int          // Номера битов флагов, в том же порядке, что и имена
+	Format    octopus.Format // Формат поля, в котором хранятся флаги
+	Exclusive [][]string     // Группы взаимоисключающих флагов
+}
+
+// ExclusiveWith возвращает флаги, которые должны сниматься при установке флага flag
+func (f FlagDeclaration) ExclusiveWith(flag string) []string {
+	for _, group := range f.Exclusive {
+		for _, name := range group {
+			if name != flag {
+				continue
+			}
+
+			others := make([]string, 0, len(group)-1)
+
+			for _, other := range group {
+				if other != flag {
+					others = append(others, other)
+				}
+			}
+
+			return others
+		}
+	}
+
+	return nil
 }
 
 type PartialFieldDeclaration struct {
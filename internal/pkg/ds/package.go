@@ -2,7 +2,10 @@ package ds
 
 import (
 	"errors"
+	"go/token"
+	"path"
 	"regexp"
+	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 )
@@ -20,11 +23,45 @@ func getImportName(path string) (string, error) {
 	return matched[1], nil
 }
 
+// versionElemRx последний элемент пути модуля с major версией: github.com/foo/bar/v2
+var versionElemRx = regexp.MustCompile(`^v[0-9]+$`)
+
+// ImportNameFromPath возвращает имя пакета для использования в коде, выведенное из пути импорта так же,
+// как его обычно выбирают авторы пакетов: элемент версии vN пропускается, префикс "go-" и суффикс "-go"
+// отбрасываются, символы, недопустимые в идентификаторе, удаляются. Если допустимое имя получить
+// не удалось, возвращается false
+func ImportNameFromPath(importPath string) (string, bool) {
+	name := path.Base(importPath)
+	if versionElemRx.MatchString(name) && path.Dir(importPath) != "." {
+		name = path.Base(path.Dir(importPath))
+	}
+
+	name = strings.TrimSuffix(strings.TrimPrefix(name, "go-"), "-go")
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+
+		return -1
+	}, name)
+
+	return name, token.IsIdentifier(name)
+}
+
 // Добавление нового поля в результирующий пакет
 func (rc *RecordPackage) AddField(f FieldDeclaration) error {
 	// Проверка на то, что имя не дублируется
-	if _, ex := rc.FieldsMap[f.Name]; ex {
-		return &arerror.ErrParseTypeFieldDecl{Name: f.Name, FieldType: string(f.Format), Err: arerror.ErrRedefined}
+	if num, ex := rc.FieldsMap[f.Name]; ex {
+		return rc.fieldRedefinedErr(f, num, f.Name)
+	}
+
+	// Имя в хранилище так же не должно совпадать с именами других полей
+	if f.StorageName != "" && f.StorageName != f.Name {
+		if num, ex := rc.FieldsMap[f.StorageName]; ex {
+			return rc.fieldRedefinedErr(f, num, f.StorageName)
+		}
+
+		rc.FieldsMap[f.StorageName] = len(rc.Fields)
 	}
 
 	// добавляем поле и не забываем про обратны индекс
@@ -34,6 +71,27 @@ func (rc *RecordPackage) AddField(f FieldDeclaration) error {
 	return nil
 }
 
+// fieldRedefinedErr ошибка совпадения имени name поля f с именем поля под номером num.
+// Если одно из полей подключено из группы полей, в ошибке указываются оба поля
+func (rc *RecordPackage) fieldRedefinedErr(f FieldDeclaration, num int, name string) error {
+	other := rc.Fields[num]
+
+	if f.Mixin == "" && other.Mixin == "" {
+		return &arerror.ErrParseTypeFieldDecl{Name: name, FieldType: string(f.Format), Err: arerror.ErrRedefined}
+	}
+
+	return &arerror.ErrParseMixinFieldDecl{Name: name, Field: fieldSource(f), Other: fieldSource(other), Err: arerror.ErrParseMixinFieldConflict}
+}
+
+// fieldSource описание поля для ошибок с указанием группы полей, из которой оно подключено
+func fieldSource(f FieldDeclaration) string {
+	if f.Mixin != "" {
+		return "Mixin" + f.Mixin + "." + f.Name
+	}
+
+	return f.Name
+}
+
 // Добавление нового параметра процедуры в результирующий пакет
 func (rc *RecordPackage) AddProcField(f ProcFieldDeclaration) error {
 	// Проверка на то, что имя не дублируется
@@ -49,7 +107,12 @@ func (rc *RecordPackage) AddProcField(f ProcFieldDeclaration) error {
 	}
 	// добавляем поле в выходные параметры
 	if f.Type == OUT || f.Type == INOUT {
-		if err := rc.ProcOutFields.Add(f); err != nil {
+		fields := rc.ProcOutFields
+		if f.Group != "" {
+			fields = rc.procOutGroup(f.Group).Fields
+		}
+
+		if err := fields.Add(f); err != nil {
 			return &arerror.ErrParseTypeFieldDecl{Name: f.Name, FieldType: f.Type.String(), Err: err}
 		}
 	}
@@ -57,6 +120,46 @@ func (rc *RecordPackage) AddProcField(f ProcFieldDeclaration) error {
 	return nil
 }
 
+// procOutGroup возвращает группу выходных параметров процедуры, при отсутствии группа добавляется в конец списка
+func (rc *RecordPackage) procOutGroup(name string) *ProcOutGroup {
+	for i := range rc.ProcOutGroups {
+		if rc.ProcOutGroups[i].Name == name {
+			return &rc.ProcOutGroups[i]
+		}
+	}
+
+	rc.ProcOutGroups = append(rc.ProcOutGroups, ProcOutGroup{Name: name, Fields: ProcFieldDeclarations{}})
+
+	return &rc.ProcOutGroups[len(rc.ProcOutGroups)-1]
+}
+
+// NextProcOutIndex порядковый номер следующего выходного параметра группы,
+// для пустого имени группы - порядковый номер в основном тупле ответа
+func (rc *RecordPackage) NextProcOutIndex(group string) int {
+	if group == "" {
+		return len(rc.ProcOutFields)
+	}
+
+	for _, g := range rc.ProcOutGroups {
+		if g.Name == group {
+			return len(g.Fields)
+		}
+	}
+
+	return 0
+}
+
+// ProcResultFields список всех выходных параметров процедуры: основного тупла ответа и затем всех групп
+func (rc *RecordPackage) ProcResultFields() []ProcFieldDeclaration {
+	ret := rc.ProcOutFields.List()
+
+	for _, g := range rc.ProcOutGroups {
+		ret = append(ret, g.Fields.List()...)
+	}
+
+	return ret
+}
+
 // Добавление нового ссылочного поля
 func (rc *RecordPackage) AddFieldObject(fo FieldObject) error {
 	if _, ex := rc.FieldsObjectMap[fo.Name]; ex {
@@ -182,6 +285,19 @@ func (rc *ImportPackage) FindOrAddImport(path, importName string) (ImportDeclara
 	return imp, nil
 }
 
+// Добавление нового вычисляемого поля в результирующий пакет
+func (rc *RecordPackage) AddComputedField(f FieldDeclaration) error {
+	for _, computed := range rc.ComputedFields {
+		if computed.Name == f.Name {
+			return &arerror.ErrParseComputedDecl{Name: f.Name, Err: arerror.ErrRedefined}
+		}
+	}
+
+	rc.ComputedFields = append(rc.ComputedFields, f)
+
+	return nil
+}
+
 func (rc *RecordPackage) AddTrigger(t TriggerDeclaration) error {
 	if _, ex := rc.TriggerMap[t.Name]; ex {
 		return &arerror.ErrParseTriggerDecl{Name: t.Name, Err: arerror.ErrRedefined}
@@ -227,6 +227,31 @@ func TestRecordPackage_FindImportByPkg(t *testing.T) {
 	}
 }
 
+func TestImportNameFromPath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOk bool
+	}{
+		{path: "github.com/foo/domain", want: "domain", wantOk: true},
+		{path: "github.com/foo/domain/v2", want: "domain", wantOk: true},
+		{path: "github.com/foo/go-domain", want: "domain", wantOk: true},
+		{path: "github.com/foo/domain-go", want: "domain", wantOk: true},
+		{path: "github.com/foo/my-domain", want: "mydomain", wantOk: true},
+		{path: "gopkg.in/yaml.v3", want: "yamlv3", wantOk: true},
+		{path: "v2", want: "v2", wantOk: true},
+		{path: "github.com/foo/123", want: "123", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, ok := ds.ImportNameFromPath(tt.path)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ImportNameFromPath() = %s, %v, want %s, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
 func TestRecordClass_AddField(t *testing.T) {
 	type args struct {
 		f ds.FieldDeclaration
@@ -244,6 +269,9 @@ func TestRecordClass_AddField(t *testing.T) {
 		{name: "dupField", fields: rc, args: args{f: ds.FieldDeclaration{Name: "bla"}}, wantErr: true},
 		{name: "anyField", fields: rc, args: args{f: ds.FieldDeclaration{Name: "bla1"}}, wantErr: false},
 		{name: "CaseField", fields: rc, args: args{f: ds.FieldDeclaration{Name: "Bla"}}, wantErr: false},
+		{name: "storageField", fields: rc, args: args{f: ds.FieldDeclaration{Name: "UserID", StorageName: "uid"}}, wantErr: false},
+		{name: "dupStorageName", fields: rc, args: args{f: ds.FieldDeclaration{Name: "OwnerID", StorageName: "bla"}}, wantErr: true},
+		{name: "fieldAsStorageName", fields: rc, args: args{f: ds.FieldDeclaration{Name: "uid"}}, wantErr: true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -252,6 +280,10 @@ func TestRecordClass_AddField(t *testing.T) {
 			}
 		})
 	}
+
+	if rc.FieldsMap["uid"] != rc.FieldsMap["UserID"] {
+		t.Errorf("RecordClass.AddField() storage name resolved to field %d, want %d", rc.FieldsMap["uid"], rc.FieldsMap["UserID"])
+	}
 }
 
 func TestRecordClass_AddFieldObject(t *testing.T) {
@@ -358,3 +390,116 @@ func TestRecordClass_AddFlag(t *testing.T) {
 		})
 	}
 }
+
+func TestFieldDeclaration_DefaultValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   ds.FieldDeclaration
+		want    string
+		wantErr bool
+	}{
+		{name: "string", field: ds.FieldDeclaration{Format: "string", Default: "new"}, want: `"new"`},
+		{name: "string now", field: ds.FieldDeclaration{Format: "string", Default: "now()"}, want: "time.Now().Format(time.RFC3339)"},
+		{name: "int now", field: ds.FieldDeclaration{Format: "uint32", Default: "now()"}, want: "uint32(time.Now().Unix())"},
+		{name: "bool now", field: ds.FieldDeclaration{Format: "bool", Default: "now()"}, wantErr: true},
+		{name: "int", field: ds.FieldDeclaration{Format: "int16", Default: "-010"}, want: "int16(-10)"},
+		{name: "int overflow", field: ds.FieldDeclaration{Format: "int8", Default: "200"}, wantErr: true},
+		{name: "uint negative", field: ds.FieldDeclaration{Format: "uint64", Default: "-1"}, wantErr: true},
+		{name: "float", field: ds.FieldDeclaration{Format: "float64", Default: "1.5"}, want: "float64(1.5)"},
+		{name: "float inf", field: ds.FieldDeclaration{Format: "float64", Default: "Inf"}, wantErr: true},
+		{name: "bool", field: ds.FieldDeclaration{Format: "bool", Default: "true"}, want: "true"},
+		{name: "bool invalid", field: ds.FieldDeclaration{Format: "bool", Default: "yes"}, wantErr: true},
+		{name: "uuid", field: ds.FieldDeclaration{Format: "uuid.UUID", Default: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"}, want: `uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")`},
+		{name: "uuid invalid", field: ds.FieldDeclaration{Format: "uuid.UUID", Default: "new"}, wantErr: true},
+		{name: "decimal", field: ds.FieldDeclaration{Format: "decimal.Decimal", Default: "0.10"}, want: `decimal.RequireFromString("0.10")`},
+		{name: "decimal invalid", field: ds.FieldDeclaration{Format: "decimal.Decimal", Default: "ten"}, wantErr: true},
+		{name: "time", field: ds.FieldDeclaration{Format: "time.Time", Default: "2023-05-17T10:30:15.5+03:00"}, want: `time.Unix(1684308615, 500000000).UTC()`},
+		{name: "time now", field: ds.FieldDeclaration{Format: "time.Time", Default: "now()"}, want: `time.Now()`},
+		{name: "time invalid", field: ds.FieldDeclaration{Format: "time.Time", Default: "2023-05-17"}, wantErr: true},
+		{name: "bytes", field: ds.FieldDeclaration{Format: "[]byte", Default: "abc"}, wantErr: true},
+		{name: "enum", field: ds.FieldDeclaration{Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}}, Default: "new"}, want: "StatusNew"},
+		{name: "enum unknown", field: ds.FieldDeclaration{Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}}, Default: "old"}, wantErr: true},
+		{name: "type override", field: ds.FieldDeclaration{Name: "Price", Format: "int64", TypeOverride: ds.TypeOverride{Type: "Cents", ImportName: "domain"}, Default: "100"}, want: "domain.Cents(int64(100))"},
+		{name: "type override invalid", field: ds.FieldDeclaration{Name: "Price", Format: "int64", TypeOverride: ds.TypeOverride{Type: "Cents", ImportName: "domain"}, Default: "free"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.field.DefaultValue()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FieldDeclaration.DefaultValue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if got != tt.want {
+				t.Errorf("FieldDeclaration.DefaultValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFieldDeclaration_ValidationChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   ds.FieldDeclaration
+		want    []ds.ValidationCheck
+		wantErr bool
+	}{
+		{
+			name:  "numeric bounds",
+			field: ds.FieldDeclaration{Name: "Age", Format: "int32", Validation: ds.Validation{Required: true, Min: "18", Max: "150"}},
+			want: []ds.ValidationCheck{
+				{Cond: "v == 0", Rule: "required"},
+				{Cond: "v < 18", Rule: "min 18"},
+				{Cond: "v > 150", Rule: "max 150"},
+			},
+		},
+		{
+			name:  "string length and regex",
+			field: ds.FieldDeclaration{Name: "Name", Format: "string", Validation: ds.Validation{MinLen: 2, MaxLen: 32, Regex: "^[a-z]+$"}},
+			want: []ds.ValidationCheck{
+				{Cond: "utf8.RuneCountInString(v) < 2", Rule: "minlen 2"},
+				{Cond: "utf8.RuneCountInString(v) > 32", Rule: "maxlen 32"},
+				{Cond: "!validateNameRegex.MatchString(v)", Rule: "regex ^[a-z]+$"},
+			},
+		},
+		{
+			name:    "bound overflow",
+			field:   ds.FieldDeclaration{Name: "Age", Format: "int8", Validation: ds.Validation{Max: "200"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.field.ValidationChecks("v")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FieldDeclaration.ValidationChecks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FieldDeclaration.ValidationChecks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAppInfo_WithSourceFile(t *testing.T) {
+	info := *ds.NewAppInfo().WithVersion("v1.2.0").WithBuildCommit("abc123")
+	withSource := info.WithSourceFile("repository/declaration/foo.go")
+
+	if withSource.SourceFile() != "repository/declaration/foo.go" {
+		t.Errorf("AppInfo.SourceFile() = %v, want %v", withSource.SourceFile(), "repository/declaration/foo.go")
+	}
+
+	if info.SourceFile() != "" {
+		t.Errorf("AppInfo.WithSourceFile() modified original: %v", info.SourceFile())
+	}
+
+	if withSource.Version() != "v1.2.0" || withSource.Revision() != "abc123" || withSource.GeneratedAt() != info.GeneratedAt() {
+		t.Errorf("AppInfo.WithSourceFile() = %+v, want copy of %+v", withSource, info)
+	}
+
+	if withSource.String() != "argen@v1.2.0 (Commit: abc123)" {
+		t.Errorf("AppInfo.String() = %v", withSource.String())
+	}
+}
@@ -0,0 +1,217 @@
+package generator
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/iproto/util/text"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+const ddlDisclaimer = `-- Code generated by argen. DO NOT EDIT.
+-- This DDL was generated from the model declaration.
+`
+
+// ddlTypes соответствие формата поля типу колонки
+var ddlTypes = map[octopus.Format]string{
+	octopus.Int8:        "SMALLINT",
+	octopus.Uint8:       "SMALLINT",
+	octopus.Int16:       "SMALLINT",
+	octopus.Uint16:      "INTEGER",
+	octopus.Int32:       "INTEGER",
+	octopus.Uint32:      "BIGINT",
+	octopus.Int64:       "BIGINT",
+	octopus.Int:         "BIGINT",
+	octopus.Uint64:      "NUMERIC(20)",
+	octopus.Uint:        "NUMERIC(20)",
+	octopus.Bool:        "BOOLEAN",
+	octopus.Float32:     "REAL",
+	octopus.Float64:     "DOUBLE PRECISION",
+	octopus.String:      "TEXT",
+	octopus.StringArray: "TEXT[]",
+	octopus.ByteArray:   "BYTEA",
+	octopus.UUID:        "UUID",
+	octopus.Decimal:     "NUMERIC",
+	octopus.TimeUnix:    "BIGINT",
+	octopus.TimeUnixMs:  "BIGINT",
+	octopus.TimeRFC3339: "TEXT",
+}
+
+// ddlType возвращает тип колонки для поля. Для строк с ограничением размера используется VARCHAR,
+// для полей-массивов - массив типа элемента, для полей time.Time тип определяется форматом хранения
+func ddlType(fld ds.FieldDeclaration) (string, error) {
+	ret, ok := ddlTypes[fld.PackFormat()]
+	if !ok {
+		return "", fmt.Errorf("%w: field `%s` has format `%s`", arerror.ErrGeneratorDDLFormat, fld.Name, fld.Format)
+	}
+
+	if fld.Format == octopus.String && fld.Size > 0 {
+		ret = fmt.Sprintf("VARCHAR(%d)", fld.Size)
+	}
+
+	if fld.Array {
+		ret += "[]"
+	}
+
+	return ret, nil
+}
+
+// GenerateDDL генерирует описание таблицы для модели: CREATE TABLE с колонками по полям декларации,
+// первичным ключом по первичному индексу и CREATE INDEX для остальных индексов, кроме частичных.
+// Для индексов с фильтром формируется условие WHERE.
+// Поля сериализуются в базе в исходном формате, поэтому тип колонки определяется форматом поля.
+// Колонки полей без признака nullable описываются как NOT NULL, значения по умолчанию подставляются моделью при вставке и в DDL не описываются.
+// Для процедур описание не генерируется и возвращается false
+func GenerateDDL(cl ds.RecordPackage) (GenerateFile, bool, error) {
+	if len(cl.Fields) == 0 {
+		return GenerateFile{}, false, nil
+	}
+
+	table := cl.Namespace.ObjectName
+	filename := cl.Namespace.PackageName + ".sql"
+	columns := make([]string, 0, len(cl.Fields)+1)
+
+	for _, fld := range cl.Fields {
+		colType, err := ddlType(fld)
+		if err != nil {
+			return GenerateFile{}, false, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Filename: filename, Err: err}
+		}
+
+		notNull := " NOT NULL"
+		if fld.Nullable {
+			notNull = ""
+		}
+
+		columns = append(columns, fmt.Sprintf("\t%s %s%s", ddlIdent(ddlColumn(fld)), colType, notNull))
+	}
+
+	primary := -1
+
+	for num, ind := range cl.Indexes {
+		if ind.Primary {
+			primary = num
+			break
+		}
+	}
+
+	if primary == -1 && len(cl.Indexes) > 0 {
+		primary = 0
+	}
+
+	if primary != -1 {
+		columns = append(columns, fmt.Sprintf("\tPRIMARY KEY (%s)", ddlIndexColumns(cl, cl.Indexes[primary], false)))
+	}
+
+	ddl := strings.Builder{}
+
+	ddl.WriteString(ddlDisclaimer)
+	ddl.WriteString("\n")
+	fmt.Fprintf(&ddl, "CREATE TABLE %s (\n%s\n);\n", ddlIdent(table), strings.Join(columns, ",\n"))
+
+	for num, ind := range cl.Indexes {
+		// Частичный индекс использует префикс составного индекса, отдельный индекс для него не нужен
+		if num == primary || ind.Partial {
+			continue
+		}
+
+		unique := ""
+		if ind.Unique {
+			unique = "UNIQUE "
+		}
+
+		indName := ddlIdent(table + "_" + text.ToSnakeCase(ind.Name) + "_idx")
+
+		where := ""
+		if len(ind.Filter) != 0 {
+			where = " WHERE " + ddlIndexFilter(cl, ind)
+		}
+
+		fmt.Fprintf(&ddl, "\nCREATE %sINDEX %s ON %s (%s)%s;\n", unique, indName, ddlIdent(table), ddlIndexColumns(cl, ind, true), where)
+	}
+
+	return GenerateFile{
+		Data: []byte(ddl.String()),
+		Name: filename,
+		Dir:  cl.Namespace.PackageName,
+	}, true, nil
+}
+
+// ddlColumn имя колонки поля: имя в хранилище, если оно задано, иначе имя поля модели в snake_case
+func ddlColumn(fld ds.FieldDeclaration) string {
+	if fld.StorageName != "" {
+		return fld.StorageName
+	}
+
+	return text.ToSnakeCase(fld.Name)
+}
+
+// ddlIndexColumns список колонок индекса через запятую. Если ordered, для полей, отсортированных
+// в индексе в обратном направлении, указывается DESC (в описании первичного ключа направление не задаётся)
+func ddlIndexColumns(cl ds.RecordPackage, ind ds.IndexDeclaration, ordered bool) string {
+	cols := make([]string, 0, len(ind.Fields))
+
+	for _, fieldNum := range ind.Fields {
+		col := ddlIdent(ddlColumn(cl.Fields[fieldNum]))
+		if ordered && ind.FieldOrder(fieldNum) == ds.IndexOrderDesc {
+			col += " DESC"
+		}
+
+		cols = append(cols, col)
+	}
+
+	return strings.Join(cols, ", ")
+}
+
+// ddlIndexFilter условие WHERE индекса с фильтром. Значения в декларации проверены при разборе модели
+func ddlIndexFilter(cl ds.RecordPackage, ind ds.IndexDeclaration) string {
+	conds := make([]string, 0, len(ind.Filter))
+
+	for _, cond := range ind.Filter {
+		fld := cl.Fields[cond.Field]
+		column := ddlIdent(ddlColumn(fld))
+
+		if cond.Value == ds.IndexFilterNull {
+			if cond.Negate {
+				conds = append(conds, column+" IS NOT NULL")
+			} else {
+				conds = append(conds, column+" IS NULL")
+			}
+
+			continue
+		}
+
+		op := " = "
+		if cond.Negate {
+			op = " <> "
+		}
+
+		conds = append(conds, column+op+ddlLiteral(fld, cond.Value))
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// ddlLiteral значение поля в виде литерала SQL: строки и UUID в кавычках, логические значения TRUE и FALSE
+func ddlLiteral(fld ds.FieldDeclaration, value string) string {
+	switch fld.Format {
+	case octopus.String, octopus.UUID:
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	case octopus.Bool:
+		if v, err := strconv.ParseBool(value); err == nil && v {
+			return "TRUE"
+		}
+
+		return "FALSE"
+	default:
+		return value
+	}
+}
+
+// ddlIdent экранирует идентификатор. Имена неймспейсов в octopus являются номерами,
+// поэтому без кавычек они не могут быть именами таблиц
+func ddlIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
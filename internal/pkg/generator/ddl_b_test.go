@@ -0,0 +1,133 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+func TestGenerateDDL(t *testing.T) {
+	namespace := ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantOk  bool
+		want    string
+		wantErr error
+	}{
+		{
+			name: "table with indexes",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				Fields: []ds.FieldDeclaration{
+					{Name: "ID", Format: "int64", PrimaryKey: true},
+					{Name: "UserName", Format: "string", Size: 32},
+					{Name: "Tags", Format: "string", Serializer: []string{"JSON"}},
+					{Name: "Score", Format: "uint64"},
+					{Name: "Nick", Format: "string", Size: 16, Nullable: true},
+					{Name: "Token", Format: "uuid.UUID"},
+					{Name: "Amount", Format: "decimal.Decimal"},
+					{Name: "Labels", Format: "string", Array: true},
+					{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnixMs},
+					{Name: "Updated", Format: "time.Time", Timestamp: ds.TimestampRFC3339},
+				},
+				Indexes: []ds.IndexDeclaration{
+					{Name: "ID", Fields: []int{0}, Primary: true, Unique: true},
+					{Name: "UserName", Fields: []int{1}, Unique: true},
+					{Name: "NameScore", Fields: []int{1, 3}, FieldsMap: map[string]ds.IndexField{"UserName": {IndField: 1}, "Score": {IndField: 3, Order: ds.IndexOrderDesc}}},
+					{Name: "NamePart", Fields: []int{1}, Partial: true},
+					{Name: "Token", Fields: []int{5}, Filter: []ds.IndexFilterCond{{Field: 4, Value: "null", Negate: true}, {Field: 1, Value: "o'neil", Negate: true}, {Field: 3, Value: "0"}}},
+				},
+			},
+			wantOk: true,
+			want: `-- Code generated by argen. DO NOT EDIT.
+-- This DDL was generated from the model declaration.
+
+CREATE TABLE "users" (
+	"id" BIGINT NOT NULL,
+	"user_name" VARCHAR(32) NOT NULL,
+	"tags" TEXT NOT NULL,
+	"score" NUMERIC(20) NOT NULL,
+	"nick" VARCHAR(16),
+	"token" UUID NOT NULL,
+	"amount" NUMERIC NOT NULL,
+	"labels" TEXT[] NOT NULL,
+	"created" BIGINT NOT NULL,
+	"updated" TEXT NOT NULL,
+	PRIMARY KEY ("id")
+);
+
+CREATE UNIQUE INDEX "users_user_name_idx" ON "users" ("user_name");
+
+CREATE INDEX "users_name_score_idx" ON "users" ("user_name", "score" DESC);
+
+CREATE INDEX "users_token_idx" ON "users" ("token") WHERE "nick" IS NOT NULL AND "user_name" <> 'o''neil' AND "score" = 0;
+`,
+		},
+		{
+			name: "storage name",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				Fields: []ds.FieldDeclaration{
+					{Name: "UserID", Format: "int64", PrimaryKey: true, StorageName: "uid"},
+					{Name: "Name", Format: "string", Size: 32},
+				},
+				Indexes: []ds.IndexDeclaration{
+					{Name: "UserID", Fields: []int{0}, Primary: true, Unique: true},
+				},
+			},
+			wantOk: true,
+			want: `-- Code generated by argen. DO NOT EDIT.
+-- This DDL was generated from the model declaration.
+
+CREATE TABLE "users" (
+	"uid" BIGINT NOT NULL,
+	"name" VARCHAR(32) NOT NULL,
+	PRIMARY KEY ("uid")
+);
+`,
+		},
+		{
+			name: "procedure",
+			cl:   ds.RecordPackage{Namespace: namespace},
+		},
+		{
+			name: "unknown format",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				Fields:    []ds.FieldDeclaration{{Name: "ID", Format: "complex64"}},
+			},
+			wantErr: arerror.ErrGeneratorDDLFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := GenerateDDL(tt.cl)
+			if !errors.Is(err, tt.wantErr) {
+				var errFile *arerror.ErrGeneratorFile
+				if !errors.As(err, &errFile) || !errors.Is(errFile.Err, tt.wantErr) {
+					t.Fatalf("GenerateDDL() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("GenerateDDL() ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if !ok {
+				return
+			}
+
+			if got.Name != "foo.sql" || got.Dir != "foo" || got.Backend != "" {
+				t.Errorf("GenerateDDL() file = %s/%s (%s), want foo/foo.sql", got.Dir, got.Name, got.Backend)
+			}
+
+			if string(got.Data) != tt.want {
+				t.Errorf("GenerateDDL() = %s, want %s", got.Data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Строки дисклеймера с информацией о генераторе, они меняются при смене версии argen
+// и при каждом запуске генерации и не должны учитываться при сравнении
+var generateInfoRx = regexp.MustCompile(`(?m)^// (Generate info|Version|Revision|Generated at): .*$`)
+
+// normalizeGenerated убирает из сгенерированного файла информацию, не влияющую на код
+func normalizeGenerated(data []byte) string {
+	return generateInfoRx.ReplaceAllString(string(data), "// $1:")
+}
+
+// DiffFile сравнивает существующий файл с результатом генерации. Возвращает unified diff
+// и признак того, что файл изменится
+func DiffFile(existing []byte, file GenerateFile) (string, bool, error) {
+	oldData := normalizeGenerated(existing)
+	newData := normalizeGenerated(file.Data)
+
+	if oldData == newData {
+		return "", false, nil
+	}
+
+	path := filepath.Join(file.Dir, file.Name)
+
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(oldData),
+		B:        difflib.SplitLines(newData),
+		FromFile: path,
+		ToFile:   path,
+		Context:  3,
+	})
+	if err != nil {
+		return "", false, fmt.Errorf("can't diff %s: %w", path, err)
+	}
+
+	return diff, true, nil
+}
+
+// DiffAll сравнивает результат генерации с файлами в директории root.
+// Возвращает diff для изменившихся и новых файлов, ключ - путь к файлу
+func DiffAll(files []GenerateFile, root string) (map[string]string, error) {
+	ret := map[string]string{}
+
+	for _, file := range files {
+		path := filepath.Join(root, file.Dir, file.Name)
+
+		existing, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("can't read %s: %w", path, err)
+		}
+
+		diff, changed, err := DiffFile(existing, file)
+		if err != nil {
+			return nil, err
+		}
+
+		if changed {
+			ret[path] = diff
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,53 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiffAll(t *testing.T) {
+	root := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(root, "foo"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	same := "// Generate info: argen@v1.0.0\n// Version: v1.0.0\n// Generated at: 2024-01-01T00:00:00Z\npackage foo\n\nvar A = 1\n"
+	changed := "// Generate info: argen@v1.0.0\npackage foo\n\nvar B = 1\n"
+
+	for name, data := range map[string]string{"same.go": same, "changed.go": changed} {
+		if err := os.WriteFile(filepath.Join(root, "foo", name), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files := []GenerateFile{
+		{Dir: "foo", Name: "same.go", Data: []byte("// Generate info: argen@v1.1.0 (Commit: abc)\n// Version: v1.1.0\n// Generated at: 2024-02-01T00:00:00Z\npackage foo\n\nvar A = 1\n")},
+		{Dir: "foo", Name: "changed.go", Data: []byte("// Generate info: argen@v1.0.0\npackage foo\n\nvar B = 2\n")},
+		{Dir: "foo", Name: "new.go", Data: []byte("package foo\n")},
+	}
+
+	got, err := DiffAll(files, root)
+	if err != nil {
+		t.Fatalf("DiffAll() error = %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("DiffAll() = %v, want diffs for changed.go and new.go", got)
+	}
+
+	if _, ok := got[filepath.Join(root, "foo", "same.go")]; ok {
+		t.Errorf("DiffAll() same.go must not differ when only generate info changed")
+	}
+
+	diff := got[filepath.Join(root, "foo", "changed.go")]
+	if !strings.Contains(diff, "-var B = 1\n") || !strings.Contains(diff, "+var B = 2\n") {
+		t.Errorf("DiffAll() changed.go diff = %s", diff)
+	}
+
+	if !strings.Contains(got[filepath.Join(root, "foo", "new.go")], "+package foo\n") {
+		t.Errorf("DiffAll() new.go diff = %s", got[filepath.Join(root, "foo", "new.go")])
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"bytes"
 	_ "embed"
+	"fmt"
 	"io"
 	"strings"
 	"text/template"
@@ -11,6 +12,7 @@ import (
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
 	"github.com/mailru/activerecord/pkg/iproto/util/text"
+	"github.com/mailru/activerecord/pkg/octopus"
 )
 
 type FixturePkgData struct {
@@ -20,6 +22,7 @@ type FixturePkgData struct {
 	FieldList        []ds.FieldDeclaration
 	FieldMap         map[string]int
 	FieldObject      map[string]ds.FieldObject
+	LinkedObject     map[string]ds.RecordPackage
 	ProcInFieldList  []ds.ProcFieldDeclaration
 	ProcOutFieldList []ds.ProcFieldDeclaration
 	Container        ds.NamespaceDeclaration
@@ -27,15 +30,16 @@ type FixturePkgData struct {
 	Serializers      map[string]ds.SerializerDeclaration
 	Mutators         map[string]ds.MutatorDeclaration
 	Imports          []ds.ImportDeclaration
-	AppInfo          string
+	Flags            map[string]ds.FlagDeclaration
+	AppInfo          ds.AppInfo
 }
 
-func generateFixture(params FixturePkgData) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+func generateFixture(params FixturePkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
 	fixtureWriter := bytes.Buffer{}
 
 	fixtureFile := bufio.NewWriter(&fixtureWriter)
 
-	err := GenerateFixtureTmpl(fixtureFile, params)
+	err := GenerateFixtureTmpl(fixtureFile, params, opts.header(), opts.template("octopus/fixturestore", tmpl))
 	if err != nil {
 		return nil, err
 	}
@@ -52,28 +56,93 @@ func generateFixture(params FixturePkgData) (map[string]bytes.Buffer, *arerror.E
 //go:embed tmpl/octopus/fixturestore.tmpl
 var tmpl string
 
-func GenerateFixtureTmpl(dstFile io.Writer, params FixturePkgData) *arerror.ErrGeneratorPhases {
-	templatePackage, err := template.New(TemplateName).Funcs(templateFuncs).Funcs(OctopusTemplateFuncs).Parse(disclaimer + tmpl)
+func GenerateFixtureTmpl(dstFile io.Writer, params FixturePkgData, header, tmpl string) *arerror.ErrGeneratorPhases {
+	templatePackage, err := parseTmpl("fixture", header+tmpl, funcs, templateFuncs, OctopusTemplateFuncs)
 	if err != nil {
-		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(disclaimer+tmpl, "\n"), err.Error())
+		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(header+tmpl, "\n"), err.Error())
 		if errgetline != nil {
 			tmplLines = errgetline.Error()
 		}
 
-		return &arerror.ErrGeneratorPhases{Backend: "fixture", Phase: "parse", TmplLines: tmplLines, Err: err}
+		line, column := getTmplErrorPos(header, tmpl, err.Error())
+
+		return &arerror.ErrGeneratorPhases{Backend: "fixture", Phase: "parse", TmplLines: tmplLines, Line: line, Column: column, Err: err}
 	}
 
 	err = templatePackage.Execute(dstFile, params)
 	if err != nil {
-		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(disclaimer+tmpl, "\n"), err.Error())
+		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(header+tmpl, "\n"), err.Error())
 		if errgetline != nil {
 			tmplLines = errgetline.Error()
 		}
 
-		return &arerror.ErrGeneratorPhases{Backend: "fixture", Phase: "execute", TmplLines: tmplLines, Err: err}
+		line, column := getTmplErrorPos(header, tmpl, err.Error())
+
+		return &arerror.ErrGeneratorPhases{Backend: "fixture", Phase: "execute", TmplLines: tmplLines, Line: line, Column: column, Err: err}
 	}
 
 	return nil
 }
 
-var templateFuncs = template.FuncMap{"snakeCase": text.ToSnakeCase, "split": strings.Split}
+var templateFuncs = template.FuncMap{"snakeCase": text.ToSnakeCase, "split": strings.Split, "randomValue": randomValue}
+
+// randomStringSize длина случайной строки для строковых полей без заданного размера
+const randomStringSize = 16
+
+// randomValue возвращает выражение, вычисляющее случайное значение поля fld генератором rnd,
+// для фабрики записей Random<Model> в сторе фикстур. Для перечислений выбирается одно из допустимых значений,
+// значение поля пользовательского типа приводится к этому типу.
+// Для неизвестного формата возвращается пустая строка, такое поле не заполняется
+func randomValue(pkg string, fld ds.FieldDeclaration) string {
+	var value string
+
+	switch {
+	case len(fld.Enum) > 0:
+		values := make([]string, 0, len(fld.Enum))
+		for _, v := range fld.Enum {
+			values = append(values, pkg+"."+fld.Name+v.Name)
+		}
+
+		value = fmt.Sprintf("[]%s.%s{%s}[rnd.Intn(%d)]", pkg, fld.EnumType(), strings.Join(values, ", "), len(values))
+	case fld.Format == octopus.Bool:
+		value = "rnd.Intn(2) == 1"
+	case fld.Format == octopus.Uint8, fld.Format == octopus.Uint16, fld.Format == octopus.Uint32, fld.Format == octopus.Uint64, fld.Format == octopus.Uint:
+		value = fmt.Sprintf("%s(rnd.Uint64())", fld.Format)
+	case fld.Format == octopus.Int8, fld.Format == octopus.Int16, fld.Format == octopus.Int32, fld.Format == octopus.Int64, fld.Format == octopus.Int:
+		value = fmt.Sprintf("%s(rnd.Int63())", fld.Format)
+	case fld.Format == octopus.Float32:
+		value = "float32(rnd.Float64() * 1000)"
+	case fld.Format == octopus.Float64:
+		value = "rnd.Float64() * 1000"
+	case fld.Format == octopus.String, fld.Format == octopus.ByteArray:
+		size := int64(randomStringSize)
+		if fld.Size > 0 && fld.Size < size {
+			size = fld.Size
+		}
+
+		value = fmt.Sprintf("activerecord.RandomString(rnd, %d)", size)
+		if fld.Format == octopus.ByteArray {
+			value = "[]byte(" + value + ")"
+		}
+	case fld.Format == octopus.StringArray:
+		value = fmt.Sprintf("[]string{activerecord.RandomString(rnd, %d)}", randomStringSize)
+	case fld.Format == octopus.UUID:
+		value = "uuid.Must(uuid.NewRandomFromReader(rnd))"
+	case fld.Format == octopus.Decimal:
+		value = "decimal.New(rnd.Int63n(1000000), -2)"
+	case fld.Format == octopus.Time:
+		value = "time.Unix(rnd.Int63n(1<<32), 0).UTC()"
+	default:
+		return ""
+	}
+
+	if fld.Array {
+		value = fmt.Sprintf("[]%s{%s}", fld.Format, value)
+	}
+
+	if !fld.TypeOverride.Empty() {
+		value = fld.NamedType() + "(" + value + ")"
+	}
+
+	return value
+}
@@ -79,7 +79,12 @@ func TestGenerateFixture(t *testing.T) {
 							ObjectLink: "",
 						},
 					},
-					FieldObject: map[string]ds.FieldObject{},
+					FieldObject: map[string]ds.FieldObject{
+						"Owner": {Name: "Owner", Key: "Code", ObjectName: "owner", Field: "Code", Unique: true},
+					},
+					LinkedObject: map[string]ds.RecordPackage{
+						"owner": {Namespace: ds.NamespaceDeclaration{PackageName: "owner", PublicName: "Owner"}},
+					},
 					Container:   ds.NamespaceDeclaration{ObjectName: "0", PublicName: "Testmodel", PackageName: "testmodel"},
 					Serializers: map[string]ds.SerializerDeclaration{},
 					Mutators: map[string]ds.MutatorDeclaration{
@@ -107,6 +112,15 @@ func TestGenerateFixture(t *testing.T) {
 					`var giftFixtures []*gift.Gift`,
 					`func initGift() {`,
 					`func GetUpdateMutatorAnyFixtureById(ctx context.Context, Id string) (fxt octopus.FixtureType) {`,
+					`func RandomGift(seed int64) *gift.Gift {`,
+					`rnd := rand.New(rand.NewSource(seed))`,
+					`if err := obj.SetId(activerecord.RandomString(rnd, 16)); err != nil {`,
+					`if err := obj.SetInv(rnd.Intn(2) == 1); err != nil {`,
+					`func LoadGiftFromFile(path string) ([]*gift.Gift, error) {`,
+					`case "inv":`,
+					`func GiftForOwner(parent *owner.Owner, children ...*gift.Gift) []*gift.Gift {`,
+					`if err := child.SetCode(parent.GetCode()); err != nil {`,
+					`if err := field.Decode(&ft.Inv); err != nil {`,
 				},
 			},
 		},
@@ -201,7 +215,7 @@ func TestGenerateFixture(t *testing.T) {
 						},
 					},
 					Imports: []ds.ImportDeclaration{},
-					AppInfo: "",
+					AppInfo: ds.AppInfo{},
 				},
 			},
 			wantStr: map[string][]string{
@@ -222,7 +236,7 @@ func TestGenerateFixture(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ret, got := generateFixture(tt.args.params)
+			ret, got := generateFixture(tt.args.params, Options{})
 			if got != tt.want {
 				t.Errorf("GenerateFixture() = %v, want %v", got, tt.want)
 			}
@@ -243,3 +257,54 @@ func TestGenerateFixture(t *testing.T) {
 		})
 	}
 }
+
+func Test_randomValue(t *testing.T) {
+	tests := []struct {
+		name string
+		fld  ds.FieldDeclaration
+		want string
+	}{
+		{
+			name: "int",
+			fld:  ds.FieldDeclaration{Name: "ID", Format: "int32"},
+			want: "int32(rnd.Int63())",
+		},
+		{
+			name: "sized string",
+			fld:  ds.FieldDeclaration{Name: "Code", Format: "string", Size: 4},
+			want: "activerecord.RandomString(rnd, 4)",
+		},
+		{
+			name: "enum",
+			fld:  ds.FieldDeclaration{Name: "Kind", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}, {Name: "Done", Value: "closed"}}},
+			want: "[]foo.KindEnum{foo.KindNew, foo.KindDone}[rnd.Intn(2)]",
+		},
+		{
+			name: "type override",
+			fld:  ds.FieldDeclaration{Name: "Mail", Format: "string", TypeOverride: ds.TypeOverride{Type: "Email", ImportName: "domain"}},
+			want: "domain.Email(activerecord.RandomString(rnd, 16))",
+		},
+		{
+			name: "array",
+			fld:  ds.FieldDeclaration{Name: "Scores", Format: "float64", Array: true},
+			want: "[]float64{rnd.Float64() * 1000}",
+		},
+		{
+			name: "time",
+			fld:  ds.FieldDeclaration{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnix},
+			want: "time.Unix(rnd.Int63n(1<<32), 0).UTC()",
+		},
+		{
+			name: "unknown format",
+			fld:  ds.FieldDeclaration{Name: "Data", Format: "map[string]any"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := randomValue("foo", tt.fld); got != tt.want {
+				t.Errorf("randomValue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
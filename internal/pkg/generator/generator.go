@@ -3,14 +3,24 @@ package generator
 import (
 	"bufio"
 	"bytes"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
+	"go/format"
 	"io"
+	"io/fs"
 	"log"
+	"os"
+	"path/filepath"
 	"regexp"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"text/template"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	"golang.org/x/tools/imports"
@@ -19,24 +29,40 @@ import (
 	"github.com/mailru/activerecord/internal/pkg/ds"
 )
 
-const disclaimer string = `// Code generated by argen. DO NOT EDIT.
+// disclaimer заголовок сгенерированных файлов. Заголовок можно переопределить шаблоном `disclaimer`
+const disclaimer string = disclaimerNotice + `//
+// Generate info: {{ .AppInfo }}
+// Version: {{ .AppInfo.Version }}
+// Revision: {{ .AppInfo.Revision }}
+// Generated at: {{ .AppInfo.GeneratedAt }}` + disclaimerSource
+
+// stableDisclaimer заголовок без версии генератора и времени генерации, см. Options.StableHeader
+const stableDisclaimer string = disclaimerNotice + disclaimerSource
+
+const disclaimerNotice string = `// Code generated by argen. DO NOT EDIT.
 // This code was generated from a template.
 //
 // Manual changes to this file may cause unexpected behavior in your application.
 // Manual changes to this file will be overwritten if the code is regenerated.
-//
-// Generate info: {{ .AppInfo }}
+`
+
+const disclaimerSource string = `
+{{- with .AppInfo.SourceFile }}
+// Source file: {{ . }}
+{{- end }}
 `
 
 type PkgData struct {
 	ARPkg            string
 	ARPkgTitle       string
 	FieldList        []ds.FieldDeclaration
+	ComputedFields   []ds.FieldDeclaration
 	FieldMap         map[string]int
 	FieldObject      map[string]ds.FieldObject
 	LinkedObject     map[string]ds.RecordPackage
 	ProcInFieldList  []ds.ProcFieldDeclaration
 	ProcOutFieldList []ds.ProcFieldDeclaration
+	ProcOutGroups    []ds.ProcOutGroup
 	Server           ds.ServerDeclaration
 	Container        ds.NamespaceDeclaration
 	Indexes          []ds.IndexDeclaration
@@ -45,53 +71,812 @@ type PkgData struct {
 	Imports          []ds.ImportDeclaration
 	Triggers         map[string]ds.TriggerDeclaration
 	Flags            map[string]ds.FlagDeclaration
-	AppInfo          string
+	LeaseProc        string
+	SoftDelete       string
+	CopyGetters      bool
+	NullableGetters  bool
+	Validate         bool
+	Trace            bool
+	Audit            bool
+	Outbox           string
+	Prepared         bool
+	AppInfo          ds.AppInfo
+	Backend          string
+}
+
+// ConstructorTriggers возвращает триггеры BeforeInsert, которые вызывает конструктор New<Model>.
+// Триггеры жизненного цикла вызывают только записи бекендов octopus и memory
+func (p PkgData) ConstructorTriggers() []ds.TriggerDeclaration {
+	if p.Backend != "octopus" && p.Backend != "memory" {
+		return nil
+	}
+
+	return p.PhaseTriggers("BeforeInsert")
+}
+
+// PhaseTriggers возвращает триггеры этапа жизненного цикла phase в порядке вызова:
+// по возрастанию приоритета, при равном приоритете по имени триггера
+func (p PkgData) PhaseTriggers(phase string) []ds.TriggerDeclaration {
+	ret := []ds.TriggerDeclaration{}
+
+	for _, trigger := range p.Triggers {
+		if trigger.Phase == phase {
+			ret = append(ret, trigger)
+		}
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Priority != ret[j].Priority {
+			return ret[i].Priority < ret[j].Priority
+		}
+
+		return ret[i].Name < ret[j].Name
+	})
+
+	return ret
+}
+
+// requiredImport пакет, который используется в сгенерированном коде и должен быть объявлен в Imports
+type requiredImport struct {
+	Feature string // Часть декларации, которой нужен пакет
+	Path    string // Путь к пакету
+	Name    string // Имя, под которым пакет используется в шаблонах, пустое - имя не важно
+}
+
+// requiredImports возвращает пакеты, необходимые полям, сериализаторам, мутаторам, триггерам
+// и вычисляемым полям модели
+func (p PkgData) requiredImports() []requiredImport {
+	ret := []requiredImport{}
+
+	for _, fld := range p.FieldList {
+		if path, ex := ds.ExternalFormats[fld.Format]; ex {
+			ret = append(ret, requiredImport{Feature: "field " + fld.Name, Path: path})
+		}
+
+		if !fld.TypeOverride.Empty() {
+			ret = append(ret, requiredImport{Feature: "field " + fld.Name, Path: fld.TypeOverride.Pkg, Name: fld.TypeOverride.ImportName})
+		}
+	}
+
+	for _, fld := range p.ComputedFields {
+		ret = append(ret, requiredImport{Feature: "computed " + fld.Name, Path: fld.Compute.Pkg, Name: fld.Compute.ImportName})
+	}
+
+	for _, name := range sortedKeys(p.Serializers) {
+		// Функции цепочки сериализаторов генерируются в пакете модели
+		if s := p.Serializers[name]; len(s.Chain) == 0 {
+			ret = append(ret, requiredImport{Feature: "serializer " + name, Path: s.Pkg, Name: s.ImportName})
+		}
+	}
+
+	for _, name := range sortedKeys(p.Mutators) {
+		if m := p.Mutators[name]; m.Pkg != "" {
+			ret = append(ret, requiredImport{Feature: "mutator " + name, Path: m.Pkg, Name: m.ImportName})
+		}
+	}
+
+	for _, name := range sortedKeys(p.Triggers) {
+		t := p.Triggers[name]
+		ret = append(ret, requiredImport{Feature: "trigger " + name, Path: t.Pkg, Name: t.ImportName})
+	}
+
+	return ret
+}
+
+// checkImports проверяет до выполнения шаблонов, что все необходимые декларации пакеты объявлены
+// в Imports под используемыми в шаблонах именами. Иначе goimports может подставить пакет
+// из другого модуля или завершиться с невнятной ошибкой
+func (p PkgData) checkImports(backend string) *arerror.ErrGeneratorPhases {
+	for _, req := range p.requiredImports() {
+		declared := false
+
+		for _, imp := range p.Imports {
+			if imp.Path == req.Path && (req.Name == "" || imp.PkgName() == req.Name) {
+				declared = true
+				break
+			}
+		}
+
+		if !declared {
+			return &arerror.ErrGeneratorPhases{Backend: backend, Phase: "imports", Err: &arerror.ErrGeneratorImport{Feature: req.Feature, Path: req.Path, Name: req.Name, Err: arerror.ErrGeneratorImportNotDeclared}}
+		}
+	}
+
+	return nil
+}
+
+// checkIndexes проверяет до выполнения шаблонов, что поля индексов есть в модели, а номера полей в описании
+// индекса совпадают с их позициями в списке полей. Иначе рассинхронизация декларации после переименования
+// или перестановки полей проявится только неверными результатами выборок
+func (p PkgData) checkIndexes(backend string) *arerror.ErrGeneratorPhases {
+	indexErr := func(ind ds.IndexDeclaration, field string, err error) *arerror.ErrGeneratorPhases {
+		return &arerror.ErrGeneratorPhases{Backend: backend, Phase: "indexes", Err: &arerror.ErrGeneratorIndex{Index: ind.Name, Field: field, Err: err}}
+	}
+
+	for _, ind := range p.Indexes {
+		used := map[int]bool{}
+
+		for _, num := range ind.Fields {
+			if num < 0 || num >= len(p.FieldList) {
+				return indexErr(ind, "#"+strconv.Itoa(num), arerror.ErrGeneratorIndexFieldNotExist)
+			}
+
+			name := p.FieldList[num].Name
+
+			if used[num] {
+				return indexErr(ind, name, arerror.ErrGeneratorIndexFieldDuplicate)
+			}
+
+			used[num] = true
+
+			if len(ind.FieldsMap) == 0 {
+				continue
+			}
+
+			part, ok := ind.FieldsMap[name]
+			if !ok && p.FieldList[num].StorageName != "" {
+				part, ok = ind.FieldsMap[p.FieldList[num].StorageName]
+			}
+
+			if !ok || part.IndField != num {
+				return indexErr(ind, name, arerror.ErrGeneratorIndexFieldMismatch)
+			}
+		}
+
+		names := make([]string, 0, len(ind.FieldsMap))
+		for name := range ind.FieldsMap {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+
+		for _, name := range names {
+			num := p.fieldNum(name)
+			if num < 0 {
+				return indexErr(ind, name, arerror.ErrGeneratorIndexFieldNotExist)
+			}
+
+			if ind.FieldsMap[name].IndField != num || !used[num] {
+				return indexErr(ind, name, arerror.ErrGeneratorIndexFieldMismatch)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkUnused выводит предупреждения о сериализаторах, мутаторах и триггерах, которые объявлены в модели,
+// но не используются ни одним полем или этапом жизненного цикла и поэтому ни на что не влияют. В строгом
+// режиме (Options.StrictUnused) первая неиспользуемая декларация завершает генерацию ошибкой
+func (p PkgData) checkUnused(backend string, opts Options) *arerror.ErrGeneratorPhases {
+	for _, unused := range p.unusedDeclarations() {
+		if opts.StrictUnused {
+			return &arerror.ErrGeneratorPhases{Backend: backend, Phase: "unused", Err: unused}
+		}
+
+		opts.logf("Warn: %s `%s` of %s declared but not used by any field or lifecycle phase", unused.Kind, unused.Name, p.ARPkgTitle)
+	}
+
+	return nil
+}
+
+// unusedDeclarations возвращает неиспользуемые декларации в порядке: сериализаторы, мутаторы, триггеры,
+// внутри вида - по имени. Сериализатор используется, если на него ссылается поле модели, параметр процедуры,
+// полиморфное поле или цепочка используемого сериализатора. Триггер используется, если он вызывается
+// на этапе жизненного цикла или восстанавливает тупл (RepairTuple)
+func (p PkgData) unusedDeclarations() []*arerror.ErrGeneratorUnused {
+	usedSerializers := map[string]bool{}
+
+	var useSerializer func(name string)
+	useSerializer = func(name string) {
+		if name == "" || usedSerializers[name] {
+			return
+		}
+
+		usedSerializers[name] = true
+
+		for _, link := range p.Serializers[name].Chain {
+			useSerializer(link)
+		}
+	}
+
+	usedMutators := map[string]bool{}
+
+	for _, fields := range [][]ds.FieldDeclaration{p.FieldList, p.ComputedFields} {
+		for _, fld := range fields {
+			useSerializer(fld.Serializer.Name())
+
+			for _, name := range fld.Payload {
+				useSerializer(name)
+			}
+
+			for _, m := range fld.Mutators {
+				usedMutators[m] = true
+			}
+		}
+	}
+
+	for _, fields := range [][]ds.ProcFieldDeclaration{p.ProcInFieldList, p.ProcOutFieldList} {
+		for _, fld := range fields {
+			useSerializer(fld.Serializer.Name())
+		}
+	}
+
+	ret := []*arerror.ErrGeneratorUnused{}
+
+	for _, name := range sortedKeys(p.Serializers) {
+		if !usedSerializers[name] {
+			ret = append(ret, &arerror.ErrGeneratorUnused{Kind: "serializer", Name: name, Err: arerror.ErrGeneratorDeclarationUnused})
+		}
+	}
+
+	for _, name := range sortedKeys(p.Mutators) {
+		if !usedMutators[name] {
+			ret = append(ret, &arerror.ErrGeneratorUnused{Kind: "mutator", Name: name, Err: arerror.ErrGeneratorDeclarationUnused})
+		}
+	}
+
+	for _, name := range sortedKeys(p.Triggers) {
+		if trigger := p.Triggers[name]; trigger.Phase == "" && trigger.Name != "RepairTuple" {
+			ret = append(ret, &arerror.ErrGeneratorUnused{Kind: "trigger", Name: name, Err: arerror.ErrGeneratorDeclarationUnused})
+		}
+	}
+
+	return ret
+}
+
+// fieldNum возвращает номер поля с именем или именем в хранилище name в списке полей модели или -1,
+// если поля нет. Если задан обратный индекс FieldMap, номер в нём должен совпадать с позицией поля
+func (p PkgData) fieldNum(name string) int {
+	for num, fld := range p.FieldList {
+		if fld.Name != name && fld.StorageName != name {
+			continue
+		}
+
+		if mapped, ok := p.FieldMap[name]; p.FieldMap != nil && (!ok || mapped != num) {
+			return -1
+		}
+
+		return num
+	}
+
+	return -1
+}
+
+// DefaultFields возвращает поля, для которых задано значение по умолчанию
+func (p PkgData) DefaultFields() []ds.FieldDeclaration {
+	ret := []ds.FieldDeclaration{}
+
+	for _, fld := range p.FieldList {
+		if fld.Default != "" {
+			ret = append(ret, fld)
+		}
+	}
+
+	return ret
+}
+
+// TouchFields возвращает поля, которые обновляет метод Touch: поля с тегом touch и поле версии записи
+func (p PkgData) TouchFields() []ds.FieldDeclaration {
+	ret := []ds.FieldDeclaration{}
+
+	for _, fld := range p.FieldList {
+		if fld.Touch || fld.Version != "" {
+			ret = append(ret, fld)
+		}
+	}
+
+	return ret
+}
+
+// ConstructorFields возвращает поля, которые передаются параметрами в конструктор New{Model}: поля первичного
+// ключа и поля, которые не могут быть NULL и не имеют значения по умолчанию. Поля, значения которых
+// устанавливаются библиотекой (версия, аренда, время удаления) или флагами, в конструктор не передаются
+func (p PkgData) ConstructorFields() []ds.FieldDeclaration {
+	ret := []ds.FieldDeclaration{}
+
+	for _, fld := range p.FieldList {
+		if _, ex := p.Flags[fld.Name]; !fld.PrimaryKey && (fld.Nullable || fld.Default != "" || fld.Version != "" || fld.Lease != "" || fld.Name == p.SoftDelete || ex) {
+			continue
+		}
+
+		ret = append(ret, fld)
+	}
+
+	return ret
+}
+
+// ValidatedFields возвращает поля, для которых заданы ограничения на значение
+func (p PkgData) ValidatedFields() []ds.FieldDeclaration {
+	ret := []ds.FieldDeclaration{}
+
+	for _, fld := range p.FieldList {
+		if !fld.Validation.Empty() {
+			ret = append(ret, fld)
+		}
+	}
+
+	return ret
+}
+
+// ValidatedProcParams возвращает входные параметры процедуры, для которых заданы ограничения на значение
+func (p PkgData) ValidatedProcParams() []ds.ProcFieldDeclaration {
+	ret := []ds.ProcFieldDeclaration{}
+
+	for _, fld := range p.ProcInFieldList {
+		if fld.Validated() {
+			ret = append(ret, fld)
+		}
+	}
+
+	return ret
+}
+
+// SensitiveIndex возвращает признак того, что в индекс входит поле с тегом sensitive,
+// значения ключей такого индекса не выводятся в логи операций
+func (p PkgData) SensitiveIndex(ind ds.IndexDeclaration) bool {
+	for _, num := range ind.Fields {
+		if p.FieldList[num].Sensitive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SelectorDoc комментарий к селектору name, выбирающему записи по одному ключу param индекса ind
+func (p PkgData) SelectorDoc(name, param string, ind ds.IndexDeclaration) string {
+	ret := name + " выборка по ключу " + param + " " + p.selectorIndex(ind) + ". "
+
+	if ind.Unique {
+		ret += "Возвращает не более одной записи, если записи нет - nil."
+	} else {
+		ret += "Возвращает записи в пределах limiter, если записей нет - пустой список."
+	}
+
+	return docComment(ret + p.selectorConds(ind))
+}
+
+// SelectorListDoc комментарий к селектору name, выбирающему записи по списку ключей индекса ind
+func (p PkgData) SelectorListDoc(name string, ind ds.IndexDeclaration) string {
+	ret := name + " выборка по списку ключей keys " + p.selectorIndex(ind) + ". "
+
+	if ind.Unique {
+		ret += "Возвращает не более одной записи на каждый ключ, ключи без записей пропускаются."
+	} else {
+		ret += "Возвращает записи всех ключей в пределах limiter, ключи без записей пропускаются."
+	}
+
+	return docComment(ret + p.selectorConds(ind))
+}
+
+// selectorIndex описание индекса в комментарии к селектору: вид индекса и поля, из которых состоит его ключ
+func (p PkgData) selectorIndex(ind ds.IndexDeclaration) string {
+	kind := "индекса"
+
+	switch {
+	case ind.Primary:
+		kind = "первичного индекса"
+	case ind.Partial:
+		kind = "части индекса"
+	case ind.Unique:
+		kind = "уникального индекса"
+	}
+
+	fields := make([]string, 0, len(ind.Fields))
+	for _, num := range ind.Fields {
+		fields = append(fields, p.FieldList[num].Name)
+	}
+
+	fieldsDecl := "поле "
+	if len(fields) > 1 {
+		fieldsDecl = "поля "
+	}
+
+	return kind + " " + ind.Name + " (" + fieldsDecl + strings.Join(fields, ", ") + ")"
+}
+
+// selectorConds окончание комментария к селектору: условия отбора записей и ошибки
+func (p PkgData) selectorConds(ind ds.IndexDeclaration) string {
+	ret := ""
+
+	if len(ind.Filter) > 0 {
+		ret += " Возвращаются только записи, удовлетворяющие условию " + indexFilterDecl(p.FieldList, ind)
+
+		// Записи, помеченные удалёнными, отбрасываются после лимита, поэтому для softDelete это не выполняется
+		if !ind.Unique && p.SoftDelete == "" {
+			ret += ", смещение и лимит limiter применяются к ним"
+		}
+
+		ret += "."
+	}
+
+	if p.SoftDelete != "" {
+		ret += " Записи, помеченные удалёнными (" + p.SoftDelete + "), не возвращаются."
+	}
+
+	return ret + " Отсутствие записей ошибкой не считается, ошибка возвращается, если выборку не удалось выполнить"
+}
+
+// ShardField поле, по значению которого выбирается шард записи (см. ds.ServerDeclaration.ShardBy).
+// Если поле не задано, возвращается пустое описание
+func (p PkgData) ShardField() ds.FieldDeclaration {
+	fldNum, ex := p.FieldMap[p.Server.ShardBy]
+	if p.Server.ShardBy == "" || !ex {
+		return ds.FieldDeclaration{}
+	}
+
+	return p.FieldList[fldNum]
+}
+
+// ShardKeyPos возвращает позицию поля шардирования в ключе индекса. Если поле шардирования
+// не задано или не входит в индекс, возвращается -1
+func (p PkgData) ShardKeyPos(ind ds.IndexDeclaration) int {
+	fldNum, ex := p.FieldMap[p.Server.ShardBy]
+	if p.Server.ShardBy == "" || !ex {
+		return -1
+	}
+
+	for pos, num := range ind.Fields {
+		if num == fldNum {
+			return pos
+		}
+	}
+
+	return -1
+}
+
+// ProcResultFields список всех выходных параметров процедуры: основного тупла ответа и затем всех групп
+func (p PkgData) ProcResultFields() []ds.ProcFieldDeclaration {
+	ret := append([]ds.ProcFieldDeclaration{}, p.ProcOutFieldList...)
+
+	for _, g := range p.ProcOutGroups {
+		ret = append(ret, g.Fields.List()...)
+	}
+
+	return ret
+}
+
+// SchemaVersion версия схемы хранилища модели: хеш описания полей и индексов (для процедур - параметров),
+// влияющих на формат данных в хранилище. Для одинаковых деклараций версия не меняется между запусками,
+// а изменение, например, валидации или значений по умолчанию версию не меняет
+func (p PkgData) SchemaVersion() string {
+	hash := sha256.New()
+
+	for _, fld := range p.FieldList {
+		fmt.Fprintf(hash, "field %s %s %s %d %t %t %t\n", fld.Name, fld.StorageName, fld.Format, fld.Size, fld.PrimaryKey, fld.Nullable, fld.Array)
+	}
+
+	for _, ind := range p.Indexes {
+		if !ind.Partial {
+			fmt.Fprintf(hash, "index %s %d %v %t %t\n", ind.Name, ind.Num, ind.Fields, ind.Primary, ind.Unique)
+		}
+	}
+
+	for _, fld := range p.ProcInFieldList {
+		fmt.Fprintf(hash, "param %s %s %d\n", fld.Name, fld.Format, fld.OrderIndex)
+	}
+
+	for _, fld := range p.ProcResultFields() {
+		fmt.Fprintf(hash, "result %s %s %s %d\n", fld.Name, fld.Format, fld.Group, fld.OrderIndex)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))[:16]
 }
 
-func NewPkgData(appInfo string, cl ds.RecordPackage) PkgData {
+func NewPkgData(appInfo ds.AppInfo, cl ds.RecordPackage) PkgData {
 	return PkgData{
 		ARPkg:            cl.Namespace.PackageName,
 		ARPkgTitle:       cl.Namespace.PublicName,
 		Indexes:          cl.Indexes,
 		FieldList:        cl.Fields,
+		ComputedFields:   cl.ComputedFields,
 		FieldMap:         cl.FieldsMap,
 		ProcInFieldList:  cl.ProcInFields,
 		ProcOutFieldList: cl.ProcOutFields.List(),
+		ProcOutGroups:    cl.ProcOutGroups,
 		FieldObject:      cl.FieldsObjectMap,
 		Server:           cl.Server,
 		Container:        cl.Namespace,
 		Serializers:      cl.SerializerMap,
 		Mutators:         cl.MutatorMap,
-		Imports:          cl.Imports,
+		Imports:          uniqueImports(cl.Imports),
 		Triggers:         cl.TriggerMap,
 		Flags:            cl.FlagMap,
-		AppInfo:          appInfo,
+		LeaseProc:        cl.LeaseProc,
+		SoftDelete:       cl.SoftDelete,
+		CopyGetters:      cl.CopyGetters,
+		NullableGetters:  cl.NullableGetters,
+		Validate:         cl.Validate,
+		Trace:            cl.Trace,
+		Audit:            cl.Audit,
+		Outbox:           cl.Outbox,
+		Prepared:         cl.Prepared,
+		AppInfo:          appInfo.WithSourceFile(cl.SourceFile),
 	}
 }
 
+// uniqueImports возвращает импорты без повторов, отсортированные по пути и имени импорта.
+// Импорты добавляются несколькими фичами декларации, поэтому один пакет может встречаться
+// несколько раз, а порядок зависит от порядка обхода. Исходный срез не изменяется
+func uniqueImports(imports []ds.ImportDeclaration) []ds.ImportDeclaration {
+	ret := make([]ds.ImportDeclaration, 0, len(imports))
+	seen := make(map[ds.ImportDeclaration]struct{}, len(imports))
+
+	for _, imp := range imports {
+		if _, ok := seen[imp]; ok {
+			continue
+		}
+
+		seen[imp] = struct{}{}
+		ret = append(ret, imp)
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Path != ret[j].Path {
+			return ret[i].Path < ret[j].Path
+		}
+
+		return ret[i].ImportName < ret[j].ImportName
+	})
+
+	return ret
+}
+
 const TemplateName = `ARPkgTemplate`
 
+// Options параметры форматирования сгенерированных файлов
+type Options struct {
+	// LocalPrefix список префиксов пакетов через запятую, импорты которых
+	// группируются отдельно после сторонних пакетов (аналог `goimports -local`)
+	LocalPrefix string
+	// TemplateOverrides шаблоны, заменяющие встроенные. Ключ - путь к шаблону
+	// относительно директории tmpl без расширения, например `octopus/main` или `meta`
+	TemplateOverrides map[string]string
+	// TypeCheck включает проверку типов в сгенерированных пакетах. Проверка медленная,
+	// поэтому по умолчанию выключена
+	TypeCheck bool
+	// DDL включает генерацию описания таблицы `<package>.sql` для каждой модели
+	DDL bool
+	// Proto включает генерацию описания модели в формате protobuf `<package>.proto` для каждой модели
+	Proto bool
+	// StableHeader убирает из заголовка сгенерированных файлов версию и ревизию генератора и время генерации,
+	// поэтому файлы не меняются при повторной генерации тем же кодом. Не действует, если заголовок переопределён
+	StableHeader bool
+	// StrictUnused завершает генерацию ошибкой, если в модели есть сериализаторы, мутаторы или триггеры,
+	// которые не используются. По умолчанию о них выводятся предупреждения в Logger
+	StrictUnused bool
+	// Logger вывод сообщений генератора. Если не задан, сообщения выводятся стандартным логгером пакета log
+	Logger Logger
+}
+
+// Logger интерфейс вывода сообщений генератора, ему удовлетворяет, например, *log.Logger.
+// Для подавления сообщений можно передать log.New(io.Discard, "", 0)
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// logf выводит сообщение генератора в логгер из опций или в стандартный логгер
+func (o Options) logf(format string, v ...any) {
+	if o.Logger != nil {
+		o.Logger.Printf(format, v...)
+		return
+	}
+
+	log.Printf(format, v...)
+}
+
+// template возвращает шаблон с именем name с учётом переопределений
+func (o Options) template(name, def string) string {
+	if tmpl, ok := o.TemplateOverrides[name]; ok {
+		return tmpl
+	}
+
+	return def
+}
+
+// header возвращает шаблон заголовка сгенерированных файлов с учётом переопределения `disclaimer`
+func (o Options) header() string {
+	if o.StableHeader {
+		return o.template("disclaimer", stableDisclaimer)
+	}
+
+	return o.template("disclaimer", disclaimer)
+}
+
+// LoadTemplateOverrides загружает шаблоны `*.tmpl` из директории dir для переопределения встроенных.
+// Имя шаблона строится по пути к файлу относительно dir без расширения
+func LoadTemplateOverrides(dir string) (map[string]string, error) {
+	ret := map[string]string{}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || filepath.Ext(path) != ".tmpl" {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		ret[filepath.ToSlash(strings.TrimSuffix(rel, ".tmpl"))] = string(data)
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't load templates from %s: %w", dir, err)
+	}
+
+	return ret, nil
+}
+
+// importsLock защищает imports.LocalPrefix, которая в goimports задаётся глобально для пакета
+var importsLock sync.RWMutex
+
+// importsProcess форматирование с расстановкой импортов, переменная для подмены в тестах
+var importsProcess = imports.Process
+
+// processImports форматирует сгенерированный файл и расставляет импорты с учётом opts.
+// Пока префикс не меняется, файлы форматируются параллельно. Если goimports не смог обработать файл,
+// например из-за ещё не сгенерированного пакета, на который есть ссылка, то файл только форматируется gofmt.
+// В этом случае возвращается ошибка goimports, только если и gofmt не смог обработать файл
+func processImports(filename string, src []byte, opts Options) ([]byte, error) {
+	ret, err := processImportsLocal(filename, src, opts)
+	if err == nil {
+		return ret, nil
+	}
+
+	ret, errFmt := format.Source(src)
+	if errFmt != nil {
+		return nil, err
+	}
+
+	opts.logf("WARN: goimports failed for %s, file formatted with gofmt: %s", filename, err)
+
+	return ret, nil
+}
+
+func processImportsLocal(filename string, src []byte, opts Options) ([]byte, error) {
+	importsLock.RLock()
+
+	if imports.LocalPrefix == opts.LocalPrefix {
+		defer importsLock.RUnlock()
+
+		return importsProcess(filename, src, nil)
+	}
+
+	importsLock.RUnlock()
+
+	importsLock.Lock()
+	defer importsLock.Unlock()
+
+	imports.LocalPrefix = opts.LocalPrefix
+
+	return importsProcess(filename, src, nil)
+}
+
 type GenerateFile struct {
 	Data    []byte
 	Name    string
 	Dir     string
 	Backend string
+	Input   string // Хеш входных данных, заполняется при инкрементальной генерации, см. InputHash
 }
 
 type MetaData struct {
 	Namespaces []*ds.RecordPackage
-	AppInfo    string
+	AppInfo    ds.AppInfo
+}
+
+// HasTarantool2 возвращает признак наличия моделей tarantool2, для которых в пакете репозитория
+// формируется API транзакций
+func (m MetaData) HasTarantool2() bool {
+	for _, ns := range m.Namespaces {
+		if len(ns.Fields) > 0 && len(ns.Backends) > 0 && ns.Backends[0] == "tarantool2" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// MemoryNamespaces возвращает модели бекенда memory, хранилища которых сохраняются и загружаются
+// функциями пакета репозитория
+func (m MetaData) MemoryNamespaces() []*ds.RecordPackage {
+	ret := []*ds.RecordPackage{}
+
+	for _, ns := range m.Namespaces {
+		if len(ns.Fields) > 0 && len(ns.Backends) > 0 && ns.Backends[0] == "memory" {
+			ret = append(ret, ns)
+		}
+	}
+
+	return ret
 }
 
 //nolint:revive
 //go:embed tmpl/meta.tmpl
 var MetaTmpl string
 
-func GenerateMeta(params MetaData) ([]GenerateFile, *arerror.ErrGeneratorFile) {
+// serializerTmpl общие для всех бекендов функции цепочек сериализаторов
+//
+//go:embed tmpl/serializer.tmpl
+var serializerTmpl string
+
+// defaultsTmpl общая для всех бекендов функция установки значений по умолчанию
+//
+//go:embed tmpl/defaults.tmpl
+var defaultsTmpl string
+
+// enumTmpl общие для всех бекендов типы-перечисления полей
+//
+//go:embed tmpl/enum.tmpl
+var enumTmpl string
+
+// validateTmpl общий для всех бекендов метод проверки ограничений полей
+//
+//go:embed tmpl/validate.tmpl
+var validateTmpl string
+
+// primaryKeyTmpl общий для всех бекендов типизированный первичный ключ
+//
+//go:embed tmpl/primarykey.tmpl
+var primaryKeyTmpl string
+
+// computedTmpl общие для всех бекендов методы вычисляемых полей
+//
+//go:embed tmpl/computed.tmpl
+var computedTmpl string
+
+// jsonTmpl общие для всех бекендов методы преобразования записи в JSON
+//
+//go:embed tmpl/json.tmpl
+var jsonTmpl string
+
+// cloneTmpl общее для всех бекендов копирование полей записи ссылочных типов в методе Clone
+//
+//go:embed tmpl/clone.tmpl
+var cloneTmpl string
+
+// equalTmpl общие для всех бекендов методы сравнения записей
+//
+//go:embed tmpl/equal.tmpl
+var equalTmpl string
+
+// auditTmpl общие для бекендов с журналом аудита методы формирования записей журнала
+//
+//go:embed tmpl/audit.tmpl
+var auditTmpl string
+
+// importTmpl общий для всех бекендов импорт записей пакетами через InsertBatch
+//
+//go:embed tmpl/import.tmpl
+var importTmpl string
+
+// repositoryTmpl общие для всех бекендов методы выборок записи для интерфейса репозитория
+//
+//go:embed tmpl/repository.tmpl
+var repositoryTmpl string
+
+// filterTmpl общая для бекендов с хранилищем выборка по индексам с условием
+//
+//go:embed tmpl/filter.tmpl
+var filterTmpl string
+
+func GenerateMeta(params MetaData, opts Options) ([]GenerateFile, *arerror.ErrGeneratorFile) {
 	metaWriter := bytes.Buffer{}
 	metaFile := bufio.NewWriter(&metaWriter)
 
-	if err := GenerateByTmpl(metaFile, params, "meta", MetaTmpl); err != nil {
+	if err := GenerateByTmpl(metaFile, params, "meta", opts.header(), opts.template("meta", MetaTmpl)); err != nil {
 		return nil, &arerror.ErrGeneratorFile{Name: "repository.go", Backend: "meta", Filename: "repository.go", Err: err}
 	}
 
@@ -107,7 +892,7 @@ func GenerateMeta(params MetaData) ([]GenerateFile, *arerror.ErrGeneratorFile) {
 
 	var err error
 
-	genRes.Data, err = imports.Process("", genData, nil)
+	genRes.Data, err = processImports(genRes.Name, genData, opts)
 	if err != nil {
 		return nil, &arerror.ErrGeneratorFile{Name: "repository.go", Backend: "meta", Filename: genRes.Name, Err: ErrorLine(err, string(genData))}
 	}
@@ -115,31 +900,155 @@ func GenerateMeta(params MetaData) ([]GenerateFile, *arerror.ErrGeneratorFile) {
 	return []GenerateFile{genRes}, nil
 }
 
-func GenerateByTmpl(dstFile io.Writer, params any, name, tmpl string) *arerror.ErrGeneratorPhases {
-	templatePackage, err := template.New(TemplateName).Funcs(funcs).Funcs(OctopusTemplateFuncs).Parse(disclaimer + tmpl)
+// tmplCacheKey ключ кеша разобранных шаблонов. Один и тот же текст может разбираться с разным набором функций
+type tmplCacheKey struct {
+	kind string
+	text string
+}
+
+// parsedTemplates кеш разобранных шаблонов. Разбор шаблона не зависит от параметров генерации,
+// поэтому при генерации большого количества пакетов каждый шаблон разбирается один раз
+var parsedTemplates sync.Map
+
+// parseTmpl возвращает разобранный шаблон из кеша или разбирает его. Шаблоны с ошибкой разбора не кешируются,
+// поэтому ошибка возвращается при каждом вызове
+func parseTmpl(kind, text string, funcMaps ...template.FuncMap) (*template.Template, error) {
+	key := tmplCacheKey{kind: kind, text: text}
+
+	if cached, ok := parsedTemplates.Load(key); ok {
+		return cached.(*template.Template), nil
+	}
+
+	templatePackage := template.New(TemplateName)
+	for _, fm := range funcMaps {
+		templatePackage = templatePackage.Funcs(fm)
+	}
+
+	templatePackage, err := templatePackage.Parse(text)
 	if err != nil {
-		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(disclaimer+tmpl, "\n"), err.Error())
-		if errgetline != nil {
-			tmplLines = errgetline.Error()
+		return nil, err
+	}
+
+	cached, _ := parsedTemplates.LoadOrStore(key, templatePackage)
+
+	return cached.(*template.Template), nil
+}
+
+// GenerateByTmpl генерирует файл по шаблону tmpl, файл начинается с заголовка по шаблону header
+func GenerateByTmpl(dstFile io.Writer, params any, name, header, tmpl string) *arerror.ErrGeneratorPhases {
+	templatePackage, genErr := parseGeneratorTmpl(name, header, tmpl)
+	if genErr != nil {
+		return genErr
+	}
+
+	if err := templatePackage.Execute(dstFile, params); err != nil {
+		return tmplExecuteError(name, header, tmpl, err)
+	}
+
+	return nil
+}
+
+// FileSectionPrefix префикс имени шаблона, содержимое которого выносится в отдельный файл.
+// Шаблон {{ define "file:select" }} основного файла octopus формирует файл octopus_select.go
+const FileSectionPrefix = "file:"
+
+// GenerateFilesByTmpl генерирует по шаблону основной файл с ключом key и по отдельному файлу
+// с ключом key_<name> на каждую секцию шаблона {{ define "file:<name>" }}. Секции не попадают
+// в основной файл, поэтому шаблоны без секций формируют, как и раньше, один файл.
+// Каждый файл секции начинается с того же заголовка header, что и основной, объявление пакета
+// должно быть в самой секции, импорты добавляются при обработке файла
+func GenerateFilesByTmpl(key string, params any, name, header, tmpl string) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	templatePackage, genErr := parseGeneratorTmpl(name, header, tmpl)
+	if genErr != nil {
+		return nil, genErr
+	}
+
+	ret := map[string]bytes.Buffer{}
+
+	mainWriter := bytes.Buffer{}
+	if err := templatePackage.Execute(&mainWriter, params); err != nil {
+		return nil, tmplExecuteError(name, header, tmpl, err)
+	}
+
+	ret[key] = mainWriter
+
+	sections := []string{}
+
+	for _, t := range templatePackage.Templates() {
+		if strings.HasPrefix(t.Name(), FileSectionPrefix) {
+			sections = append(sections, t.Name())
+		}
+	}
+
+	if len(sections) == 0 {
+		return ret, nil
+	}
+
+	sort.Strings(sections)
+
+	disclaimerPackage, err := parseTmpl("disclaimer", header, funcs)
+	if err != nil {
+		return nil, &arerror.ErrGeneratorPhases{Backend: name, Phase: "parse", Err: err}
+	}
+
+	for _, section := range sections {
+		sectionName := strings.TrimPrefix(section, FileSectionPrefix)
+		if !fileSectionRx.MatchString(sectionName) {
+			return nil, &arerror.ErrGeneratorPhases{Backend: name, Phase: "parse", Err: fmt.Errorf("%w: %q", arerror.ErrGeneratorFileSection, section)}
+		}
+
+		sectionWriter := bytes.Buffer{}
+
+		if err := disclaimerPackage.Execute(&sectionWriter, params); err != nil {
+			return nil, &arerror.ErrGeneratorPhases{Backend: name, Phase: "execute", Err: err}
+		}
+
+		if err := templatePackage.ExecuteTemplate(&sectionWriter, section, params); err != nil {
+			return nil, tmplExecuteError(name, header, tmpl, err)
 		}
 
-		return &arerror.ErrGeneratorPhases{Backend: name, Phase: "parse", TmplLines: tmplLines, Err: err}
+		ret[key+"_"+sectionName] = sectionWriter
 	}
 
-	err = templatePackage.Execute(dstFile, params)
+	return ret, nil
+}
+
+// fileSectionRx допустимое имя секции файла, используется в имени файла
+var fileSectionRx = regexp.MustCompile(`^[a-z0-9][a-z0-9_]*$`)
+
+// parseGeneratorTmpl разбирает шаблон пакета вместе с заголовком и общими шаблонами
+func parseGeneratorTmpl(name, header, tmpl string) (*template.Template, *arerror.ErrGeneratorPhases) {
+	templatePackage, err := parseTmpl("generator", header+tmpl+serializerTmpl+defaultsTmpl+enumTmpl+validateTmpl+primaryKeyTmpl+computedTmpl+jsonTmpl+cloneTmpl+equalTmpl+auditTmpl+importTmpl+repositoryTmpl+filterTmpl, funcs, OctopusTemplateFuncs, TarantoolTemplateFuncs)
 	if err != nil {
-		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(disclaimer+tmpl, "\n"), err.Error())
+		tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(header+tmpl, "\n"), err.Error())
 		if errgetline != nil {
 			tmplLines = errgetline.Error()
 		}
 
-		return &arerror.ErrGeneratorPhases{Backend: name, Phase: "execute", TmplLines: tmplLines, Err: err}
+		line, column := getTmplErrorPos(header, tmpl, err.Error())
+
+		return nil, &arerror.ErrGeneratorPhases{Backend: name, Phase: "parse", TmplLines: tmplLines, Line: line, Column: column, Err: err}
 	}
 
-	return nil
+	return templatePackage, nil
+}
+
+// tmplExecuteError формирует ошибку выполнения шаблона с фрагментом и позицией ошибки в шаблоне
+func tmplExecuteError(name, header, tmpl string, err error) *arerror.ErrGeneratorPhases {
+	tmplLines, errgetline := getTmplErrorLine(strings.SplitAfter(header+tmpl, "\n"), err.Error())
+	if errgetline != nil {
+		tmplLines = errgetline.Error()
+	}
+
+	line, column := getTmplErrorPos(header, tmpl, err.Error())
+
+	return &arerror.ErrGeneratorPhases{Backend: name, Phase: "execute", TmplLines: tmplLines, Line: line, Column: column, Err: err}
 }
 
-func Generate(appInfo string, cl ds.RecordPackage, linkObject map[string]ds.RecordPackage) (ret []GenerateFile, err error) {
+func Generate(appInfo ds.AppInfo, cl ds.RecordPackage, linkObject map[string]ds.RecordPackage, opts Options) (ret []GenerateFile, err error) {
+	// Файлы записи первого бекенда, с которыми проверяются типы в функциях преобразования protobuf
+	modelEnd := 0
+
 	for _, backend := range cl.Backends {
 		var generated map[string]bytes.Buffer
 
@@ -150,18 +1059,52 @@ func Generate(appInfo string, cl ds.RecordPackage, linkObject map[string]ds.Reco
 			params := NewPkgData(appInfo, cl)
 			params.LinkedObject = linkObject
 
-			log.Printf("Generate package (%v)", cl)
+			opts.logf("Generate package (%v)", cl)
 
 			var err *arerror.ErrGeneratorPhases
 
-			generated, err = GenerateOctopus(params)
+			generated, err = GenerateOctopus(params, opts)
 			if err != nil {
 				err.Name = cl.Namespace.PublicName
 				return nil, err
 			}
-		case "tarantool16":
-			fallthrough
 		case "tarantool2":
+			params := NewPkgData(appInfo, cl)
+
+			opts.logf("Generate package (%v)", cl)
+
+			var err *arerror.ErrGeneratorPhases
+
+			generated, err = GenerateTarantool2(params, opts)
+			if err != nil {
+				err.Name = cl.Namespace.PublicName
+				return nil, err
+			}
+		case "mock":
+			params := NewPkgData(appInfo, cl)
+
+			opts.logf("Generate package (%v)", cl)
+
+			var err *arerror.ErrGeneratorPhases
+
+			generated, err = GenerateMock(params, opts)
+			if err != nil {
+				err.Name = cl.Namespace.PublicName
+				return nil, err
+			}
+		case "memory":
+			params := NewPkgData(appInfo, cl)
+
+			opts.logf("Generate package (%v)", cl)
+
+			var err *arerror.ErrGeneratorPhases
+
+			generated, err = GenerateMemory(params, opts)
+			if err != nil {
+				err.Name = cl.Namespace.PublicName
+				return nil, err
+			}
+		case "tarantool16":
 			return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Backend: backend, Err: arerror.ErrGeneratorBackendNotImplemented}
 		case "postgres":
 			return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Backend: backend, Err: arerror.ErrGeneratorBackendNotImplemented}
@@ -169,7 +1112,10 @@ func Generate(appInfo string, cl ds.RecordPackage, linkObject map[string]ds.Reco
 			return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Backend: backend, Err: arerror.ErrGeneratorBackendUnknown}
 		}
 
-		for name, data := range generated {
+		backendStart := len(ret)
+
+		for _, name := range sortedKeys(generated) {
+			data := generated[name]
 			genRes := GenerateFile{
 				Dir:     cl.Namespace.PackageName,
 				Name:    name + ".go",
@@ -178,19 +1124,142 @@ func Generate(appInfo string, cl ds.RecordPackage, linkObject map[string]ds.Reco
 
 			genData := data.Bytes()
 
-			genRes.Data, err = imports.Process("", genData, nil)
+			genRes.Data, err = processImports(genRes.Name, genData, opts)
 			if err != nil {
 				return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Backend: backend, Filename: genRes.Name, Err: ErrorLine(err, string(genData))}
 			}
 
 			ret = append(ret, genRes)
 		}
+
+		if opts.TypeCheck {
+			if err := typeCheck(cl.Namespace.PublicName, backend, ret[backendStart:]); err != nil {
+				return nil, err
+			}
+		}
+
+		if modelEnd == 0 {
+			modelEnd = len(ret)
+		}
+	}
+
+	// Функции преобразования в сообщения protobuf используют только геттеры и сеттеры модели, поэтому не зависят от бекенда
+	conv, ok, err := GenerateProtoConv(appInfo, cl, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if ok {
+		genRes := GenerateFile{Dir: cl.Namespace.PackageName, Name: "protoconv.go"}
+
+		genRes.Data, err = processImports(genRes.Name, []byte(conv), opts)
+		if err != nil {
+			return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Filename: genRes.Name, Err: ErrorLine(err, conv)}
+		}
+
+		if opts.TypeCheck {
+			if err := typeCheck(cl.Namespace.PublicName, "proto", append(ret[:modelEnd:modelEnd], genRes)); err != nil {
+				return nil, err
+			}
+		}
+
+		ret = append(ret, genRes)
+	}
+
+	if opts.DDL {
+		// Описание таблицы не зависит от бекенда и не является go-файлом, поэтому imports.Process не нужен
+		ddl, ok, err := GenerateDDL(cl)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			ret = append(ret, ddl)
+		}
+	}
+
+	if opts.Proto {
+		// Описание protobuf не является go-файлом, поэтому imports.Process не нужен
+		proto, ok, err := GenerateProto(cl)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			ret = append(ret, proto)
+		}
+	}
+
+	return ret, nil
+}
+
+// GenerateConcurrency ограничение количества пакетов, генерируемых одновременно в GenerateAll.
+// Если значение не положительное, то используется количество процессоров
+var GenerateConcurrency = runtime.NumCPU()
+
+// GenerateAll генерирует пакеты для всех переданных моделей параллельно. Результат упорядочен
+// по публичному имени модели и имени файла и не зависит от порядка завершения генерации.
+// При ошибках генерации возвращается первая ошибка в этом же порядке
+func GenerateAll(appInfo ds.AppInfo, packages []ds.RecordPackage, link map[string]ds.RecordPackage, opts Options) ([]GenerateFile, error) {
+	sorted := make([]ds.RecordPackage, len(packages))
+	copy(sorted, packages)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Namespace.PublicName < sorted[j].Namespace.PublicName
+	})
+
+	workers := GenerateConcurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	if workers > len(sorted) {
+		workers = len(sorted)
+	}
+
+	results := make([][]GenerateFile, len(sorted))
+	errs := make([]error, len(sorted))
+	jobs := make(chan int)
+	wg := sync.WaitGroup{}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for num := range jobs {
+				results[num], errs[num] = Generate(appInfo, sorted[num], link, opts)
+			}
+		}()
+	}
+
+	for num := range sorted {
+		jobs <- num
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	ret := []GenerateFile{}
+
+	for num, files := range results {
+		if errs[num] != nil {
+			return nil, errs[num]
+		}
+
+		sort.Slice(files, func(i, j int) bool {
+			return files[i].Name < files[j].Name
+		})
+
+		ret = append(ret, files...)
 	}
 
 	return ret, nil
 }
 
-var errImportsRx = regexp.MustCompile(`^(\d+):(\d+):`)
+// Позиция ошибки форматирования в формате `[filename:]line:column:`
+var errImportsRx = regexp.MustCompile(`^(?:[^:\s]+\.go:)?(\d+):(\d+):`)
 
 func ErrorLine(errIn error, genData string) error {
 	findErr := errImportsRx.FindStringSubmatch(errIn.Error())
@@ -202,7 +1271,7 @@ func ErrorLine(errIn error, genData string) error {
 
 		lines := strings.Split(genData, "\n")
 
-		if len(lines) < lineNum {
+		if lineNum < 1 || len(lines) < lineNum {
 			return errors.Wrap(errIn, fmt.Sprintf("line num %d not found (total %d)", lineNum, len(lines)))
 		}
 
@@ -213,21 +1282,33 @@ func ErrorLine(errIn error, genData string) error {
 			return errors.Wrap(errIn, "can't unparse error byte num in line: "+line)
 		}
 
-		if len(line) < byteNum {
+		if byteNum < 1 || len(line) < byteNum {
 			return errors.Wrap(errIn, "byte num not found in line: "+line)
 		}
 
-		return errors.Wrap(errIn, "\n"+strings.Trim(lines[lineNum-2], "\t")+"\n"+strings.Trim(line, "\t")+"\n"+strings.Repeat(" ", byteNum-1)+"^^^^^"+"\n"+strings.Trim(lines[lineNum], "\t"))
+		// Соседние строки для контекста, у первой и последней строки соседа может не быть
+		prevLine, nextLine := "", ""
+
+		if lineNum >= 2 {
+			prevLine = lines[lineNum-2]
+		}
+
+		if lineNum < len(lines) {
+			nextLine = lines[lineNum]
+		}
+
+		// Позиция указателя считается в символах, а не в байтах, с учётом обрезанных в начале строки табуляций
+		column := utf8.RuneCountInString(strings.TrimLeft(line[:byteNum-1], "\t"))
+
+		return errors.Wrap(errIn, "\n"+strings.Trim(prevLine, "\t")+"\n"+strings.Trim(line, "\t")+"\n"+strings.Repeat(" ", column)+"^^^^^"+"\n"+strings.Trim(nextLine, "\t"))
 	}
 
 	return errors.Wrap(errIn, "cant parse error message")
 }
 
-func GenerateFixture(appInfo string, cl ds.RecordPackage, pkg string, pkgFixture string) ([]GenerateFile, error) {
+func GenerateFixture(appInfo ds.AppInfo, cl ds.RecordPackage, linkObject map[string]ds.RecordPackage, pkg string, pkgFixture string, opts Options) ([]GenerateFile, error) {
 	var generated map[string]bytes.Buffer
 
-	ret := make([]GenerateFile, 0, 1)
-
 	params := FixturePkgData{
 		FixturePkg:       pkgFixture,
 		ARPkg:            pkg,
@@ -235,6 +1316,7 @@ func GenerateFixture(appInfo string, cl ds.RecordPackage, pkg string, pkgFixture
 		FieldList:        cl.Fields,
 		FieldMap:         cl.FieldsMap,
 		FieldObject:      cl.FieldsObjectMap,
+		LinkedObject:     linkObject,
 		ProcInFieldList:  cl.ProcInFields,
 		ProcOutFieldList: cl.ProcOutFields.List(),
 		Container:        cl.Namespace,
@@ -242,28 +1324,38 @@ func GenerateFixture(appInfo string, cl ds.RecordPackage, pkg string, pkgFixture
 		Serializers:      cl.SerializerMap,
 		Mutators:         cl.MutatorMap,
 		Imports:          cl.Imports,
-		AppInfo:          appInfo,
+		Flags:            cl.FlagMap,
+		AppInfo:          appInfo.WithSourceFile(cl.SourceFile),
 	}
 
-	log.Printf("Generate package (%v)", cl)
+	opts.logf("Generate package (%v)", cl)
 
 	var err *arerror.ErrGeneratorPhases
 
-	generated, err = generateFixture(params)
+	generated, err = generateFixture(params, opts)
 	if err != nil {
 		err.Name = cl.Namespace.PublicName
 		return nil, err
 	}
 
-	for _, data := range generated {
+	return fixtureFiles(cl, pkgFixture, generated, opts)
+}
+
+// fixtureFiles формирует файлы пакета фикстур. Фикстуры всех пакетов генерируются в одну директорию,
+// поэтому имя файла строится из имени пакета и ключа сгенерированного буфера
+func fixtureFiles(cl ds.RecordPackage, pkgFixture string, generated map[string]bytes.Buffer, opts Options) ([]GenerateFile, error) {
+	ret := make([]GenerateFile, 0, len(generated))
+
+	for _, name := range sortedKeys(generated) {
+		data := generated[name]
 		genRes := GenerateFile{
 			Dir:  pkgFixture,
-			Name: cl.Namespace.PackageName + "_gen.go",
+			Name: fixtureFileName(cl.Namespace.PackageName, name),
 		}
 
 		genData := data.Bytes()
 
-		dataImp, err := imports.Process("", genData, nil)
+		dataImp, err := processImports(genRes.Name, genData, opts)
 		if err != nil {
 			return nil, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Backend: "fixture", Filename: genRes.Name, Err: ErrorLine(err, string(genData))}
 		}
@@ -274,3 +1366,12 @@ func GenerateFixture(appInfo string, cl ds.RecordPackage, pkg string, pkgFixture
 
 	return ret, nil
 }
+
+// fixtureFileName возвращает имя файла фикстур. Для основного буфера "fixture" имя не содержит ключа
+func fixtureFileName(pkg, key string) string {
+	if key == "fixture" {
+		return pkg + "_gen.go"
+	}
+
+	return pkg + "_" + key + "_gen.go"
+}
@@ -1,16 +1,27 @@
 package generator
 
 import (
+	"bytes"
+	"errors"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 
+	"golang.org/x/tools/imports"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
 	"github.com/mailru/activerecord/internal/pkg/testutil"
 )
 
 func TestGenerate(t *testing.T) {
 	type args struct {
-		appInfo      string
+		appInfo      ds.AppInfo
 		cl           ds.RecordPackage
 		linkedObject map[string]ds.RecordPackage
 	}
@@ -23,7 +34,7 @@ func TestGenerate(t *testing.T) {
 		{
 			name: "Filename",
 			args: args{
-				appInfo: testutil.TestAppInfo.String(),
+				appInfo: testutil.TestAppInfo,
 				cl: ds.RecordPackage{
 					Server: ds.ServerDeclaration{
 						Host:    "127.0.0.1",
@@ -89,12 +100,30 @@ func TestGenerate(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotRet, err := Generate(tt.args.appInfo, tt.args.cl, tt.args.linkedObject)
+			var logBuf bytes.Buffer
+
+			gotRet, err := Generate(tt.args.appInfo, tt.args.cl, tt.args.linkedObject, Options{Logger: log.New(&logBuf, "", 0)})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Generate() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
+			if !strings.HasPrefix(logBuf.String(), "Generate package (") {
+				t.Errorf("Generate() log = %q, want generate package message", logBuf.String())
+			}
+
+			// Повторная генерация должна давать те же файлы в том же порядке
+			for i := 0; i < 5; i++ {
+				againRet, err := Generate(tt.args.appInfo, tt.args.cl, tt.args.linkedObject, Options{Logger: log.New(&bytes.Buffer{}, "", 0)})
+				if err != nil {
+					t.Fatalf("Generate() repeat error = %v", err)
+				}
+
+				if !reflect.DeepEqual(againRet, gotRet) {
+					t.Fatalf("Generate() is not deterministic: %v != %v", fileNames(againRet), fileNames(gotRet))
+				}
+			}
+
 			// Testing in backend specific tests
 			for iGotRet := range gotRet {
 				gotRet[iGotRet].Data = []byte{}
@@ -111,6 +140,158 @@ func TestGenerate(t *testing.T) {
 	}
 }
 
+func TestGenerateAll(t *testing.T) {
+	recordPackage := func(name string, backend string) ds.RecordPackage {
+		return ds.RecordPackage{
+			Server:    ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Timeout: 500},
+			Namespace: ds.NamespaceDeclaration{ObjectName: "5", PublicName: name, PackageName: strings.ToLower(name)},
+			Backends:  []string{backend},
+			Fields: []ds.FieldDeclaration{
+				{Name: "Field1", Format: "int", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			},
+			FieldsMap:       map[string]int{"Field1": 0},
+			FieldsObjectMap: map[string]ds.FieldObject{},
+			Indexes: []ds.IndexDeclaration{
+				{Name: "Field1", Num: 0, Selector: "SelectByField1", Fields: []int{0}, Primary: true, Unique: true, Type: "int"},
+			},
+			ImportPackage: ds.NewImportPackage(),
+			SerializerMap: map[string]ds.SerializerDeclaration{},
+			TriggerMap:    map[string]ds.TriggerDeclaration{},
+			FlagMap:       map[string]ds.FlagDeclaration{},
+		}
+	}
+
+	t.Run("deterministic order", func(t *testing.T) {
+		packages := []ds.RecordPackage{
+			recordPackage("Foo", "octopus"),
+			recordPackage("Baz", "tarantool2"),
+			recordPackage("Bar", "octopus"),
+		}
+
+		got, err := GenerateAll(testutil.TestAppInfo, packages, map[string]ds.RecordPackage{}, Options{})
+		if err != nil {
+			t.Fatalf("GenerateAll() error = %v", err)
+		}
+
+		names := make([]string, 0, len(got))
+		for _, file := range got {
+			names = append(names, file.Dir+"/"+file.Name)
+		}
+
+		want := []string{
+			"bar/fixture.go", "bar/mock.go", "bar/octopus.go",
+			"baz/tarantool.go",
+			"foo/fixture.go", "foo/mock.go", "foo/octopus.go",
+		}
+
+		if !reflect.DeepEqual(names, want) {
+			t.Errorf("GenerateAll() = %v, want %v", names, want)
+		}
+	})
+
+	t.Run("stable header", func(t *testing.T) {
+		packages := []ds.RecordPackage{recordPackage("Foo", "octopus"), recordPackage("Baz", "tarantool2")}
+		link := map[string]ds.RecordPackage{}
+
+		for _, stable := range []bool{false, true} {
+			first, err := GenerateAll(testutil.TestAppInfo.WithGenerateTime("2024-01-01T10:00:00Z"), packages, link, Options{StableHeader: stable})
+			if err != nil {
+				t.Fatalf("GenerateAll() error = %v", err)
+			}
+
+			second, err := GenerateAll(testutil.TestAppInfo.WithGenerateTime("2024-01-01T10:00:01Z"), packages, link, Options{StableHeader: stable})
+			if err != nil {
+				t.Fatalf("GenerateAll() error = %v", err)
+			}
+
+			for i := range first {
+				if equal := bytes.Equal(first[i].Data, second[i].Data); equal != stable {
+					t.Errorf("GenerateAll() StableHeader = %v, files %s equal = %v", stable, first[i].Name, equal)
+				}
+
+				if stable && bytes.Contains(first[i].Data, []byte("Generated at:")) {
+					t.Errorf("GenerateAll() StableHeader = %v, file %s contains generate time", stable, first[i].Name)
+				}
+			}
+		}
+	})
+
+	t.Run("disclaimer override", func(t *testing.T) {
+		opts := Options{TemplateOverrides: map[string]string{"disclaimer": "// Code generated by argen {{ .AppInfo.Version }}. DO NOT EDIT.\n"}}
+
+		got, err := GenerateAll(testutil.TestAppInfo, []ds.RecordPackage{recordPackage("Baz", "tarantool2")}, map[string]ds.RecordPackage{}, opts)
+		if err != nil {
+			t.Fatalf("GenerateAll() error = %v", err)
+		}
+
+		if !bytes.HasPrefix(got[0].Data, []byte("// Code generated by argen 1.0. DO NOT EDIT.\n")) {
+			t.Errorf("GenerateAll() = %s, want overridden disclaimer", got[0].Data)
+		}
+	})
+
+	t.Run("first error", func(t *testing.T) {
+		packages := []ds.RecordPackage{
+			recordPackage("Foo", "postgres"),
+			recordPackage("Bar", "unknown"),
+		}
+
+		_, err := GenerateAll(testutil.TestAppInfo, packages, map[string]ds.RecordPackage{}, Options{})
+
+		var fileErr *arerror.ErrGeneratorFile
+		if !errors.As(err, &fileErr) || fileErr.Err != arerror.ErrGeneratorBackendUnknown {
+			t.Errorf("GenerateAll() error = %v, want %v", err, arerror.ErrGeneratorBackendUnknown)
+		}
+	})
+}
+
+func TestGenerateMeta(t *testing.T) {
+	namespaces := []*ds.RecordPackage{
+		{
+			Namespace: ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+			Backends:  []string{"tarantool2"},
+			Fields:    []ds.FieldDeclaration{{Name: "ID", Format: "int", PrimaryKey: true}},
+		},
+		{
+			Namespace: ds.NamespaceDeclaration{ObjectName: "foo_proc", PublicName: "FooProc", PackageName: "fooproc"},
+			Backends:  []string{"tarantool2"},
+		},
+	}
+
+	got, err := GenerateMeta(MetaData{Namespaces: namespaces, AppInfo: testutil.TestAppInfo}, Options{})
+	if err != nil {
+		t.Fatalf("GenerateMeta() error = %v", err)
+	}
+
+	data := string(got[0].Data)
+
+	for _, want := range []string{
+		`// Generate info: argen@1.0 (Commit: nocommit)`,
+		`// Version: 1.0`,
+		`// Revision: nocommit`,
+		`// Generated at: ` + testutil.TestAppInfo.GeneratedAt(),
+		`type FooRepository interface {`,
+		"type FooRepository interface {\n\tfoo.FooSelectors\n",
+		`InsertOrReplace(ctx context.Context) error`,
+		`var _ FooRepository = (*foo.Foo)(nil)`,
+		`DeleteTx(ctx context.Context, tx *Tx) error`,
+		`type Tx = tarantool.Tx`,
+		`func WithTx(ctx context.Context, fn func(tx *Tx) error) error {`,
+		`func Shutdown(ctx context.Context) error {`,
+		`func WithShard(ctx context.Context, shard int) context.Context {`,
+		`return activerecord.WithMasterRead(ctx)`,
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("GenerateMeta() generated code doesn't contain %s", want)
+		}
+	}
+
+	for _, notWant := range []string{`InsertIfAbsent`, `FooProcRepository`, `// Source file:`} {
+		if strings.Contains(data, notWant) {
+			t.Errorf("GenerateMeta() generated code contains %s", notWant)
+		}
+	}
+}
+
 func filesByName(files []GenerateFile) map[string]GenerateFile {
 	ret := make(map[string]GenerateFile, len(files))
 	for _, file := range files {
@@ -118,3 +299,923 @@ func filesByName(files []GenerateFile) map[string]GenerateFile {
 	}
 	return ret
 }
+
+// goTestGenerated записывает сгенерированные файлы и тест testFile из testdata во временный пакет
+// и выполняет в нём go test с аргументами args. Возвращает вывод go test
+func goTestGenerated(t *testing.T, generated map[string]bytes.Buffer, testFile string, args ...string) []byte {
+	t.Helper()
+
+	files := map[string][]byte{}
+
+	for name, buff := range generated {
+		src, err := processImports(name+".go", buff.Bytes(), Options{})
+		if err != nil {
+			t.Fatalf("processImports() error = %v", err)
+		}
+
+		files[name+".go"] = src
+	}
+
+	test, err := os.ReadFile(filepath.Join("testdata", testFile))
+	if err != nil {
+		t.Fatalf("can't read test: %v", err)
+	}
+
+	files[testFile] = test
+
+	// Каталоги с префиксом "_" не попадают в шаблон ./..., поэтому пакет не мешает остальным проверкам модуля
+	dir, err := os.MkdirTemp(".", "_generated")
+	if err != nil {
+		t.Fatalf("can't create package dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("can't write %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command("go", append(append([]string{"test"}, args...), ".")...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("go test error = %v\n%s", err, out)
+	}
+
+	return out
+}
+
+func fileNames(files []GenerateFile) []string {
+	ret := make([]string, 0, len(files))
+	for _, file := range files {
+		ret = append(ret, file.Name)
+	}
+	return ret
+}
+
+func Test_sortedKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		m    any
+		want []string
+	}{
+		{name: "buffers", m: map[string]bytes.Buffer{"octopus": {}, "mock": {}, "fixture": {}}, want: []string{"fixture", "mock", "octopus"}},
+		{name: "empty", m: map[string]int{}, want: []string{}},
+		{name: "not string keys", m: map[int]string{1: "a"}, want: nil},
+		{name: "not map", m: []string{"a"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sortedKeys(tt.m); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sortedKeys() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_indexFilter(t *testing.T) {
+	fields := []ds.FieldDeclaration{
+		{Name: "State", Format: "uint8"},
+		{Name: "Active", Format: "bool"},
+		{Name: "Nick", Format: "string", Nullable: true},
+		{Name: "Blocked", Format: "bool", Nullable: true},
+	}
+
+	tests := []struct {
+		name     string
+		filter   []ds.IndexFilterCond
+		want     string
+		wantDecl string
+	}{
+		{
+			name:     "equal",
+			filter:   []ds.IndexFilterCond{{Field: 0, Value: "1"}},
+			want:     "obj.GetState() == 1",
+			wantDecl: "State=1",
+		},
+		{
+			name:     "bool",
+			filter:   []ds.IndexFilterCond{{Field: 1, Value: "true"}, {Field: 1, Value: "true", Negate: true}},
+			want:     "obj.GetActive() && !obj.GetActive()",
+			wantDecl: "Active=true,Active!=true",
+		},
+		{
+			name:     "nullable",
+			filter:   []ds.IndexFilterCond{{Field: 2, Value: "null", Negate: true}, {Field: 2, Value: "guest", Negate: true}, {Field: 3, Value: "false"}},
+			want:     `obj.GetNick() != nil && (obj.GetNick() != nil && *obj.GetNick() != "guest") && (obj.GetBlocked() != nil && !*obj.GetBlocked())`,
+			wantDecl: "Nick!=null,Nick!=guest,Blocked=false",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ind := ds.IndexDeclaration{Name: "Filtered", Filter: tt.filter}
+
+			got, err := indexFilter(fields, ind, "obj")
+			if err != nil {
+				t.Fatalf("indexFilter() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("indexFilter() = %s, want %s", got, tt.want)
+			}
+
+			if got := indexFilterDecl(fields, ind); got != tt.wantDecl {
+				t.Errorf("indexFilterDecl() = %s, want %s", got, tt.wantDecl)
+			}
+		})
+	}
+}
+
+func Test_orderKind(t *testing.T) {
+	tests := []struct {
+		name string
+		fld  ds.FieldDeclaration
+		want string
+	}{
+		{name: "int", fld: ds.FieldDeclaration{Format: "int64"}, want: orderOrdered},
+		{name: "string", fld: ds.FieldDeclaration{Format: "string"}, want: orderOrdered},
+		{name: "enum", fld: ds.FieldDeclaration{Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}}}, want: orderOrdered},
+		{name: "bool", fld: ds.FieldDeclaration{Format: "bool"}, want: orderBool},
+		{name: "time", fld: ds.FieldDeclaration{Format: "time.Time"}, want: orderTime},
+		{name: "decimal", fld: ds.FieldDeclaration{Format: "decimal.Decimal"}, want: orderDecimal},
+		{name: "uuid", fld: ds.FieldDeclaration{Format: "uuid.UUID"}, want: orderUUID},
+		{name: "bytes", fld: ds.FieldDeclaration{Format: "[]byte"}, want: orderOther},
+		{name: "serialized", fld: ds.FieldDeclaration{Format: "string", Serializer: []string{"JSON"}}, want: orderOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := orderKind(tt.fld); got != tt.want {
+				t.Errorf("orderKind() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_zeroValueOf(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{goType: "string", want: `""`},
+		{goType: "rune", want: "0"},
+		{goType: "*Params", want: "nil"},
+		{goType: "[]int", want: "nil"},
+		{goType: "map[string]any", want: "nil"},
+		{goType: "func() error", want: "nil"},
+		{goType: "chan int", want: "nil"},
+		{goType: "interface{ Valid() bool }", want: "nil"},
+		{goType: "any", want: "nil"},
+		{goType: "[16]byte", want: "[16]byte{}"},
+		{goType: "struct{ A int }", want: "struct{ A int }{}"},
+		{goType: "ds.Params", want: "*new(ds.Params)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			if got := zeroValueOf(tt.goType); got != tt.want {
+				t.Errorf("zeroValueOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewPkgDataImports(t *testing.T) {
+	cl := ds.NewRecordPackage()
+	cl.Imports = []ds.ImportDeclaration{
+		{Path: "github.com/foo/stage", ImportName: "hooks"},
+		{Path: "github.com/foo/serializer"},
+		{Path: "github.com/foo/stage", ImportName: "hooks"},
+		{Path: "github.com/foo/serializer", ImportName: "ser"},
+	}
+
+	got := NewPkgData(ds.AppInfo{}, *cl).Imports
+	want := []ds.ImportDeclaration{
+		{Path: "github.com/foo/serializer"},
+		{Path: "github.com/foo/serializer", ImportName: "ser"},
+		{Path: "github.com/foo/stage", ImportName: "hooks"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NewPkgData() Imports = %v, want %v", got, want)
+	}
+
+	if len(cl.Imports) != 4 || cl.Imports[0].Path != "github.com/foo/stage" {
+		t.Errorf("NewPkgData() changed declaration imports: %v", cl.Imports)
+	}
+}
+
+func Test_fixtureFiles(t *testing.T) {
+	cl := ds.RecordPackage{Namespace: ds.NamespaceDeclaration{PackageName: "foo", PublicName: "Foo"}}
+
+	generated := map[string]bytes.Buffer{
+		"fixture": *bytes.NewBufferString("package fixture\n\nvar A = 1\n"),
+		"extra":   *bytes.NewBufferString("package fixture\n\nvar B = 2\n"),
+	}
+
+	got, err := fixtureFiles(cl, "fixture", generated, Options{})
+	if err != nil {
+		t.Fatalf("fixtureFiles() error = %v", err)
+	}
+
+	names := make([]string, 0, len(got))
+
+	for _, f := range got {
+		if f.Dir != "fixture" || len(f.Data) == 0 {
+			t.Errorf("fixtureFiles() invalid file %+v", f)
+		}
+
+		names = append(names, f.Name)
+	}
+
+	sort.Strings(names)
+
+	if want := []string{"foo_extra_gen.go", "foo_gen.go"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("fixtureFiles() names = %v, want %v", names, want)
+	}
+}
+
+func BenchmarkGenerateOctopus(b *testing.B) {
+	const namespaces = 200
+
+	params := NewPkgData(testutil.TestAppInfo, ds.RecordPackage{
+		Server:    ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Timeout: 500},
+		Namespace: ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Bar", PackageName: "bar"},
+		Backends:  []string{"octopus"},
+		Fields: []ds.FieldDeclaration{
+			{Name: "Field1", Format: "int", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldsMap:       map[string]int{"Field1": 0},
+		FieldsObjectMap: map[string]ds.FieldObject{},
+		Indexes: []ds.IndexDeclaration{
+			{Name: "Field1", Num: 0, Selector: "SelectByField1", Fields: []int{0}, Primary: true, Unique: true, Type: "int"},
+		},
+		ImportPackage: ds.NewImportPackage(),
+		SerializerMap: map[string]ds.SerializerDeclaration{},
+		TriggerMap:    map[string]ds.TriggerDeclaration{},
+		FlagMap:       map[string]ds.FlagDeclaration{},
+	})
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for ns := 0; ns < namespaces; ns++ {
+			if _, err := GenerateOctopus(params, Options{}); err != nil {
+				b.Fatalf("GenerateOctopus() error = %v", err)
+			}
+		}
+	}
+}
+
+func Test_parseTmpl(t *testing.T) {
+	first, err := parseTmpl("test", "{{ .Name }}")
+	if err != nil {
+		t.Fatalf("parseTmpl() error = %v", err)
+	}
+
+	second, err := parseTmpl("test", "{{ .Name }}")
+	if err != nil {
+		t.Fatalf("parseTmpl() error = %v", err)
+	}
+
+	if first != second {
+		t.Errorf("parseTmpl() template not cached")
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := parseTmpl("test", "{{ .Name "); err == nil {
+			t.Errorf("parseTmpl() expect parse error on call %d", i)
+		}
+	}
+}
+
+func TestErrorLine(t *testing.T) {
+	genData := "package foo\n\nfunc Foo() {\n\treturn 1 +\n}\n"
+
+	_, err := imports.Process("foo_gen.go", []byte(genData), nil)
+	if err == nil {
+		t.Fatalf("imports.Process() expect error")
+	}
+
+	if !strings.HasPrefix(err.Error(), "foo_gen.go:") {
+		t.Errorf("imports.Process() error = %v, want filename prefix", err)
+	}
+
+	got := ErrorLine(err, genData).Error()
+	if !strings.Contains(got, "^^^^^") || !strings.Contains(got, "return 1 +") {
+		t.Errorf("ErrorLine() = %v, want context of error line", got)
+	}
+}
+
+func TestErrorLineBoundary(t *testing.T) {
+	tests := []struct {
+		name    string
+		errIn   error
+		genData string
+		want    string
+	}{
+		{
+			name:    "first line",
+			errIn:   errors.New("foo_gen.go:1:1: expected 'package', found pkg"),
+			genData: "pkg foo\n\nvar A = 1",
+			want:    "\n\npkg foo\n^^^^^\n",
+		},
+		{
+			name:    "last line",
+			errIn:   errors.New("foo_gen.go:3:5: expected declaration"),
+			genData: "package foo\n\nvar A",
+			want:    "\n\nvar A\n    ^^^^^\n",
+		},
+		{
+			name:    "multibyte before column",
+			errIn:   errors.New("foo_gen.go:2:31: expected ';', found bar"),
+			genData: "package foo\n\tvar A = \"поле\" // имя bar\n",
+			want:    "\npackage foo\nvar A = \"поле\" // имя bar\n                      ^^^^^\n",
+		},
+		{
+			name:    "line out of range",
+			errIn:   errors.New("4:1: unexpected EOF"),
+			genData: "package foo",
+			want:    "line num 4 not found (total 1)",
+		},
+		{
+			name:    "zero line",
+			errIn:   errors.New("0:1: unexpected EOF"),
+			genData: "package foo",
+			want:    "line num 0 not found (total 1)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ErrorLine(tt.errIn, tt.genData).Error()
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("ErrorLine() = %q, want prefix %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateByTmplErrorPos(t *testing.T) {
+	tests := []struct {
+		name       string
+		tmpl       string
+		phase      string
+		wantLine   int
+		wantColumn int
+	}{
+		{
+			name:     "parse",
+			tmpl:     "package foo\n\n{{ end }}\n",
+			phase:    "parse",
+			wantLine: 3,
+		},
+		{
+			name:       "execute",
+			tmpl:       "package foo\n\nvar A = {{ .ARPkg.Name }}\n",
+			phase:      "execute",
+			wantLine:   3,
+			wantColumn: 18,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			got := GenerateByTmpl(&buf, PkgData{ARPkg: "foo"}, "octopus", disclaimer, tt.tmpl)
+			if got == nil {
+				t.Fatalf("GenerateByTmpl() expect error")
+			}
+
+			if got.Phase != tt.phase || got.Line != tt.wantLine || got.Column != tt.wantColumn {
+				t.Errorf("GenerateByTmpl() phase = %s, pos = %d:%d, want %s, %d:%d", got.Phase, got.Line, got.Column, tt.phase, tt.wantLine, tt.wantColumn)
+			}
+
+			if got.TmplLines == "" {
+				t.Errorf("GenerateByTmpl() TmplLines is empty")
+			}
+		})
+	}
+
+	if line, column := getTmplErrorPos(disclaimer, "package foo\n", "template: "+TemplateName+":200:5: executing"); line != 0 || column != 0 {
+		t.Errorf("getTmplErrorPos() = %d:%d for line out of template, want 0:0", line, column)
+	}
+}
+
+func TestGenerateFilesByTmpl(t *testing.T) {
+	params := PkgData{ARPkg: "foo"}
+
+	t.Run("single file", func(t *testing.T) {
+		got, err := GenerateFilesByTmpl("octopus", params, "octopus", disclaimer, "package {{ .ARPkg }}\n")
+		if err != nil {
+			t.Fatalf("GenerateFilesByTmpl() error = %v", err)
+		}
+
+		if keys := sortedKeys(got); !reflect.DeepEqual(keys, []string{"octopus"}) {
+			t.Errorf("GenerateFilesByTmpl() keys = %v, want [octopus]", keys)
+		}
+	})
+
+	t.Run("sections", func(t *testing.T) {
+		tmpl := "package {{ .ARPkg }}\n\nvar Main = 1\n" +
+			"{{ define \"file:select\" }}package {{ .ARPkg }}\n\nvar Select = 1\n{{ end }}" +
+			"{{ define \"file:write\" }}package {{ .ARPkg }}\n\nvar Write = 1\n{{ end }}"
+
+		got, err := GenerateFilesByTmpl("octopus", params, "octopus", disclaimer, tmpl)
+		if err != nil {
+			t.Fatalf("GenerateFilesByTmpl() error = %v", err)
+		}
+
+		if keys := sortedKeys(got); !reflect.DeepEqual(keys, []string{"octopus", "octopus_select", "octopus_write"}) {
+			t.Fatalf("GenerateFilesByTmpl() keys = %v, want [octopus octopus_select octopus_write]", keys)
+		}
+
+		mainFile := got["octopus"]
+		if strings.Contains(mainFile.String(), "Select") {
+			t.Errorf("GenerateFilesByTmpl() main file contains section:\n%s", mainFile.String())
+		}
+
+		sel := got["octopus_select"]
+		if !strings.HasPrefix(sel.String(), "// Code generated by argen. DO NOT EDIT.") || !strings.Contains(sel.String(), "package foo\n\nvar Select = 1") {
+			t.Errorf("GenerateFilesByTmpl() section file:\n%s", sel.String())
+		}
+	})
+
+	t.Run("invalid section name", func(t *testing.T) {
+		_, err := GenerateFilesByTmpl("octopus", params, "octopus", disclaimer, "package foo\n{{ define \"file:Select\" }}{{ end }}")
+		if err == nil || !errors.Is(err.Err, arerror.ErrGeneratorFileSection) {
+			t.Errorf("GenerateFilesByTmpl() error = %v, want %v", err, arerror.ErrGeneratorFileSection)
+		}
+	})
+}
+
+func TestPkgData_checkImports(t *testing.T) {
+	serializerPkg := "github.com/mailru/activerecord/pkg/serializer"
+	serializers := map[string]ds.SerializerDeclaration{
+		"NoteJSON": {Name: "NoteJSON", Pkg: serializerPkg, Type: "[]string", ImportName: "serializerNoteJSON", Marshaler: "JSONMarshal", Unmarshaler: "JSONUnmarshal"},
+		"Packed":   {Name: "Packed", Type: "[]string", Chain: []string{"NoteJSON"}},
+	}
+
+	tests := []struct {
+		name   string
+		params PkgData
+		want   *arerror.ErrGeneratorPhases
+	}{
+		{
+			name: "all declared",
+			params: PkgData{
+				FieldList:   []ds.FieldDeclaration{{Name: "Token", Format: "uuid.UUID"}, {Name: "Tags", Format: "string", Serializer: []string{"NoteJSON"}}},
+				Serializers: serializers,
+				Imports:     []ds.ImportDeclaration{{Path: "github.com/google/uuid"}, {Path: serializerPkg, ImportName: "serializerNoteJSON"}},
+			},
+		},
+		{
+			name: "serializer import omitted",
+			params: PkgData{
+				FieldList:   []ds.FieldDeclaration{{Name: "Tags", Format: "string", Serializer: []string{"NoteJSON"}}},
+				Serializers: serializers,
+				Imports:     []ds.ImportDeclaration{},
+			},
+			want: &arerror.ErrGeneratorPhases{Backend: "octopus", Phase: "imports", Err: &arerror.ErrGeneratorImport{Feature: "serializer NoteJSON", Path: serializerPkg, Name: "serializerNoteJSON", Err: arerror.ErrGeneratorImportNotDeclared}},
+		},
+		{
+			name: "serializer imported with another name",
+			params: PkgData{
+				Serializers: serializers,
+				Imports:     []ds.ImportDeclaration{{Path: serializerPkg}},
+			},
+			want: &arerror.ErrGeneratorPhases{Backend: "octopus", Phase: "imports", Err: &arerror.ErrGeneratorImport{Feature: "serializer NoteJSON", Path: serializerPkg, Name: "serializerNoteJSON", Err: arerror.ErrGeneratorImportNotDeclared}},
+		},
+		{
+			name: "field format import omitted",
+			params: PkgData{
+				FieldList: []ds.FieldDeclaration{{Name: "Amount", Format: "decimal.Decimal"}},
+				Imports:   []ds.ImportDeclaration{},
+			},
+			want: &arerror.ErrGeneratorPhases{Backend: "octopus", Phase: "imports", Err: &arerror.ErrGeneratorImport{Feature: "field Amount", Path: "github.com/shopspring/decimal", Err: arerror.ErrGeneratorImportNotDeclared}},
+		},
+		{
+			name: "type override import by package name",
+			params: PkgData{
+				FieldList: []ds.FieldDeclaration{{Name: "Mail", Format: "string", TypeOverride: ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Email", ImportName: "domain"}}},
+				Imports:   []ds.ImportDeclaration{{Path: "github.com/foo/domain"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.params.checkImports("octopus"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PkgData.checkImports() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPkgData_checkIndexes(t *testing.T) {
+	fields := []ds.FieldDeclaration{{Name: "ID", Format: "int"}, {Name: "Name", Format: "string", StorageName: "title"}}
+	indexErr := func(index, field string, err error) *arerror.ErrGeneratorPhases {
+		return &arerror.ErrGeneratorPhases{Backend: "octopus", Phase: "indexes", Err: &arerror.ErrGeneratorIndex{Index: index, Field: field, Err: err}}
+	}
+
+	tests := []struct {
+		name  string
+		index ds.IndexDeclaration
+		want  *arerror.ErrGeneratorPhases
+	}{
+		{
+			name:  "consistent",
+			index: ds.IndexDeclaration{Name: "IDName", Fields: []int{0, 1}, FieldsMap: map[string]ds.IndexField{"ID": {IndField: 0}, "Name": {IndField: 1}}},
+		},
+		{
+			name:  "field by storage name",
+			index: ds.IndexDeclaration{Name: "Title", Fields: []int{1}, FieldsMap: map[string]ds.IndexField{"title": {IndField: 1}}},
+		},
+		{
+			name:  "field not exists",
+			index: ds.IndexDeclaration{Name: "ByGhost", Fields: []int{1}, FieldsMap: map[string]ds.IndexField{"Ghost": {IndField: 1}}},
+			want:  indexErr("ByGhost", "Name", arerror.ErrGeneratorIndexFieldMismatch),
+		},
+		{
+			name:  "field out of range",
+			index: ds.IndexDeclaration{Name: "ByGhost", Fields: []int{2}},
+			want:  indexErr("ByGhost", "#2", arerror.ErrGeneratorIndexFieldNotExist),
+		},
+		{
+			name:  "fields map only",
+			index: ds.IndexDeclaration{Name: "ByGhost", Fields: []int{}, FieldsMap: map[string]ds.IndexField{"Ghost": {IndField: 0}}},
+			want:  indexErr("ByGhost", "Ghost", arerror.ErrGeneratorIndexFieldNotExist),
+		},
+		{
+			name:  "position mismatch",
+			index: ds.IndexDeclaration{Name: "Name", Fields: []int{1}, FieldsMap: map[string]ds.IndexField{"Name": {IndField: 0}}},
+			want:  indexErr("Name", "Name", arerror.ErrGeneratorIndexFieldMismatch),
+		},
+		{
+			name:  "duplicate field",
+			index: ds.IndexDeclaration{Name: "NameName", Fields: []int{1, 1}},
+			want:  indexErr("NameName", "Name", arerror.ErrGeneratorIndexFieldDuplicate),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := PkgData{FieldList: fields, Indexes: []ds.IndexDeclaration{tt.index}}
+
+			if got := params.checkIndexes("octopus"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("PkgData.checkIndexes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPkgData_checkUnused(t *testing.T) {
+	params := PkgData{
+		ARPkgTitle: "Foo",
+		FieldList: []ds.FieldDeclaration{
+			{Name: "Tags", Format: "string", Serializer: []string{"Packed"}, Mutators: []string{"Append"}},
+			{Name: "Data", Format: "string", Payload: map[string]string{"note": "Note"}},
+		},
+		Serializers: map[string]ds.SerializerDeclaration{
+			"JSON":   {Name: "JSON"},
+			"Packed": {Name: "Packed", Chain: []string{"JSON"}},
+			"Note":   {Name: "Note"},
+			"Legacy": {Name: "Legacy"},
+		},
+		Mutators: map[string]ds.MutatorDeclaration{
+			"Append": {Name: "Append"},
+			"Remove": {Name: "Remove"},
+		},
+		Triggers: map[string]ds.TriggerDeclaration{
+			"RepairTuple":        {Name: "RepairTuple"},
+			"Audit":              {Name: "Audit", Phase: "AfterInsert"},
+			"DublicateUniqTuple": {Name: "DublicateUniqTuple"},
+		},
+	}
+
+	var logBuf bytes.Buffer
+
+	if err := params.checkUnused("octopus", Options{Logger: log.New(&logBuf, "", 0)}); err != nil {
+		t.Fatalf("PkgData.checkUnused() error = %v", err)
+	}
+
+	wantLog := "Warn: serializer `Legacy` of Foo declared but not used by any field or lifecycle phase\n" +
+		"Warn: mutator `Remove` of Foo declared but not used by any field or lifecycle phase\n" +
+		"Warn: trigger `DublicateUniqTuple` of Foo declared but not used by any field or lifecycle phase\n"
+
+	if logBuf.String() != wantLog {
+		t.Errorf("PkgData.checkUnused() log = %q, want %q", logBuf.String(), wantLog)
+	}
+
+	want := &arerror.ErrGeneratorPhases{Backend: "octopus", Phase: "unused", Err: &arerror.ErrGeneratorUnused{Kind: "serializer", Name: "Legacy", Err: arerror.ErrGeneratorDeclarationUnused}}
+
+	if got := params.checkUnused("octopus", Options{StrictUnused: true}); !reflect.DeepEqual(got, want) {
+		t.Errorf("PkgData.checkUnused() strict = %v, want %v", got, want)
+	}
+}
+
+func TestPkgData_SchemaVersion(t *testing.T) {
+	pkgData := func(fields ...ds.FieldDeclaration) PkgData {
+		return PkgData{
+			FieldList: fields,
+			Indexes:   []ds.IndexDeclaration{{Name: "ID", Fields: []int{0}, Primary: true, Unique: true}},
+		}
+	}
+
+	base := pkgData(ds.FieldDeclaration{Name: "ID", Format: "int", PrimaryKey: true}).SchemaVersion()
+
+	if len(base) != 16 {
+		t.Errorf("SchemaVersion() = %s, want 16 hex chars", base)
+	}
+
+	if got := pkgData(ds.FieldDeclaration{Name: "ID", Format: "int", PrimaryKey: true, Validation: ds.Validation{Required: true}}).SchemaVersion(); got != base {
+		t.Errorf("SchemaVersion() changed by validation: %s != %s", got, base)
+	}
+
+	if got := pkgData(ds.FieldDeclaration{Name: "ID", Format: "int", PrimaryKey: true}, ds.FieldDeclaration{Name: "Email", Format: "string"}).SchemaVersion(); got == base {
+		t.Errorf("SchemaVersion() not changed by new field")
+	}
+
+	if got := pkgData(ds.FieldDeclaration{Name: "ID", Format: "int", PrimaryKey: true, StorageName: "user_id"}).SchemaVersion(); got == base {
+		t.Errorf("SchemaVersion() not changed by storage name")
+	}
+}
+
+func TestPkgData_SelectorDoc(t *testing.T) {
+	fields := []ds.FieldDeclaration{{Name: "ID", Format: "int"}, {Name: "Owner", Format: "int"}, {Name: "Kind", Format: "string"}, {Name: "Active", Format: "bool"}}
+
+	tests := []struct {
+		name       string
+		softDelete string
+		index      ds.IndexDeclaration
+		want       string
+		wantList   string
+	}{
+		{
+			name:  "primary",
+			index: ds.IndexDeclaration{Name: "ID", Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true},
+			want: "// SelectByID выборка по ключу key первичного индекса ID (поле ID). Возвращает не более одной записи, если записи нет -\n" +
+				"// nil. Отсутствие записей ошибкой не считается, ошибка возвращается, если выборку не удалось выполнить",
+			wantList: "// SelectByIDs выборка по списку ключей keys первичного индекса ID (поле ID). Возвращает не более одной записи на каждый\n" +
+				"// ключ, ключи без записей пропускаются. Отсутствие записей ошибкой не считается, ошибка возвращается, если выборку не\n" +
+				"// удалось выполнить",
+		},
+		{
+			name:  "filtered",
+			index: ds.IndexDeclaration{Name: "OwnerKind", Selector: "SelectByOwnerKind", Fields: []int{1, 2}, Filter: []ds.IndexFilterCond{{Field: 3, Value: "true"}}},
+			want: "// SelectByOwnerKind выборка по ключу key индекса OwnerKind (поля Owner, Kind). Возвращает записи в пределах limiter,\n" +
+				"// если записей нет - пустой список. Возвращаются только записи, удовлетворяющие условию Active=true, смещение и лимит\n" +
+				"// limiter применяются к ним. Отсутствие записей ошибкой не считается, ошибка возвращается, если выборку не удалось\n" +
+				"// выполнить",
+			wantList: "// SelectByOwnerKinds выборка по списку ключей keys индекса OwnerKind (поля Owner, Kind). Возвращает записи всех ключей\n" +
+				"// в пределах limiter, ключи без записей пропускаются. Возвращаются только записи, удовлетворяющие условию Active=true,\n" +
+				"// смещение и лимит limiter применяются к ним. Отсутствие записей ошибкой не считается, ошибка возвращается, если\n" +
+				"// выборку не удалось выполнить",
+		},
+		{
+			name:       "filtered with soft delete",
+			softDelete: "DeletedAt",
+			index:      ds.IndexDeclaration{Name: "OwnerKind", Selector: "SelectByOwnerKind", Fields: []int{1, 2}, Filter: []ds.IndexFilterCond{{Field: 3, Value: "true"}}},
+			want: "// SelectByOwnerKind выборка по ключу key индекса OwnerKind (поля Owner, Kind). Возвращает записи в пределах limiter,\n" +
+				"// если записей нет - пустой список. Возвращаются только записи, удовлетворяющие условию Active=true. Записи, помеченные\n" +
+				"// удалёнными (DeletedAt), не возвращаются. Отсутствие записей ошибкой не считается, ошибка возвращается, если выборку\n" +
+				"// не удалось выполнить",
+			wantList: "// SelectByOwnerKinds выборка по списку ключей keys индекса OwnerKind (поля Owner, Kind). Возвращает записи всех ключей\n" +
+				"// в пределах limiter, ключи без записей пропускаются. Возвращаются только записи, удовлетворяющие условию Active=true.\n" +
+				"// Записи, помеченные удалёнными (DeletedAt), не возвращаются. Отсутствие записей ошибкой не считается, ошибка\n" +
+				"// возвращается, если выборку не удалось выполнить",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := PkgData{FieldList: fields, SoftDelete: tt.softDelete}
+
+			if got := p.SelectorDoc(tt.index.Selector, "key", tt.index); got != tt.want {
+				t.Errorf("SelectorDoc() = %s, want %s", got, tt.want)
+			}
+
+			if got := p.SelectorListDoc(tt.index.Selector+"s", tt.index); got != tt.wantList {
+				t.Errorf("SelectorListDoc() = %s, want %s", got, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestGenerateFilteredIndex(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+			{Name: "Owner", Num: 1, Selector: "SelectByOwner", Fields: []int{1}, Type: "int64", Filter: []ds.IndexFilterCond{{Field: 2, Value: "true"}}},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Owner", Format: "int64", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Active", Format: "bool", Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldMap:    map[string]int{"ID": 0, "Owner": 1, "Active": 2},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{},
+		Triggers:    map[string]ds.TriggerDeclaration{},
+		Flags:       map[string]ds.FlagDeclaration{},
+	}
+
+	tests := []struct {
+		name     string
+		generate func(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases)
+		file     string
+		want     []string
+	}{
+		{
+			name:     "octopus",
+			generate: GenerateOctopus,
+			file:     "octopus",
+			want: []string{
+				"res, err := selectFiltered(limiter, func(page activerecord.SelectorLimiter) ([]*Foo, error) {\n\t\treturn selectBox(ctx, 1, keysPacked, page)\n\t}, filterOwner)",
+				"selected, err := SelectByOwner(ctx, key, activerecord.NewLimiter(1))",
+				"func selectFiltered(limiter activerecord.SelectorLimiter, fetch func(page activerecord.SelectorLimiter) ([]*Foo, error), filter func(records []*Foo) []*Foo) ([]*Foo, error) {",
+			},
+		},
+		{
+			name:     "tarantool",
+			generate: GenerateTarantool2,
+			file:     "tarantool",
+			want: []string{
+				"return selectBox(ctx, 1, tarantool.IterEq, keysPacked, page)",
+				"return selectBoxIn(ctx, 1, keysPacked, page)",
+				"return selectBox(ctx, 1, tarantool.IterReq, [][]any{keyPacked}, page)",
+				"res, err = selectFiltered(activerecord.NewLimitOffset(q.limit, q.offset), fetch, q.filter)",
+				"selected, err := SelectByOwner(ctx, key, activerecord.NewLimiter(1))",
+				"func selectFiltered(",
+			},
+		},
+		{
+			name:     "mock",
+			generate: GenerateMock,
+			file:     "mock",
+			want: []string{
+				"return reflect.DeepEqual(obj.keyOwner(), key) && obj.GetActive()",
+				"return ok && obj.GetActive()",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := tt.generate(params, Options{})
+			if err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+
+			buff := ret[tt.file]
+			data := buff.String()
+
+			for _, want := range tt.want {
+				if !strings.Contains(data, want) {
+					t.Errorf("generated code doesn't contain %s", want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateOctopusImportOmitted(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Tags", Format: "string", Mutators: []string{}, Serializer: []string{"NoteJSON"}},
+		},
+		Serializers: map[string]ds.SerializerDeclaration{
+			"NoteJSON": {Name: "NoteJSON", Pkg: "github.com/mailru/activerecord/pkg/serializer", Type: "[]string", ImportName: "serializerNoteJSON", Marshaler: "JSONMarshal", Unmarshaler: "JSONUnmarshal"},
+		},
+		Imports: []ds.ImportDeclaration{},
+	}
+
+	_, err := GenerateOctopus(params, Options{})
+	if err == nil {
+		t.Fatal("GenerateOctopus() error expected")
+	}
+
+	if err.Phase != "imports" || !strings.Contains(err.Error(), "serializer NoteJSON") {
+		t.Errorf("GenerateOctopus() error = %v, want missing import of serializer NoteJSON", err)
+	}
+}
+
+func Test_processImports(t *testing.T) {
+	src := []byte("package foo\n\nimport (\n\t\"example.com/local/bar\"\n\t\"fmt\"\n\t\"github.com/pkg/errors\"\n)\n\nvar _ = fmt.Sprint(bar.A, errors.New)\n")
+
+	tests := []struct {
+		name string
+		opts Options
+		want string
+	}{
+		{
+			name: "with local prefix",
+			opts: Options{LocalPrefix: "example.com/local"},
+			want: "import (\n\t\"fmt\"\n\n\t\"github.com/pkg/errors\"\n\n\t\"example.com/local/bar\"\n)",
+		},
+		{
+			name: "without local prefix",
+			opts: Options{},
+			want: "import (\n\t\"fmt\"\n\n\t\"example.com/local/bar\"\n\t\"github.com/pkg/errors\"\n)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := processImports("foo_gen.go", src, tt.opts)
+			if err != nil {
+				t.Fatalf("processImports() error = %v", err)
+			}
+
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("processImports() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_processImportsFallback(t *testing.T) {
+	errImports := errors.New("can't resolve import")
+
+	importsProcess = func(string, []byte, *imports.Options) ([]byte, error) {
+		return nil, errImports
+	}
+	defer func() { importsProcess = imports.Process }()
+
+	var logBuf bytes.Buffer
+
+	got, err := processImports("foo_gen.go", []byte("package foo\nvar  A  =  1\n"), Options{Logger: log.New(&logBuf, "", 0)})
+	if err != nil {
+		t.Fatalf("processImports() error = %v", err)
+	}
+
+	if !strings.Contains(logBuf.String(), "goimports failed for foo_gen.go") {
+		t.Errorf("processImports() log = %q, want goimports warning", logBuf.String())
+	}
+
+	if string(got) != "package foo\n\nvar A = 1\n" {
+		t.Errorf("processImports() = %q, want gofmt output", got)
+	}
+
+	if _, err = processImports("foo_gen.go", []byte("package foo\nvar A =\n"), Options{}); err != errImports {
+		t.Errorf("processImports() error = %v, want %v", err, errImports)
+	}
+}
+
+func TestLoadTemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := os.MkdirAll(filepath.Join(dir, "octopus"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "octopus", "mock.tmpl"), []byte("package {{ .ARPkg }}\n\n// custom mock\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a template"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	overrides, err := LoadTemplateOverrides(dir)
+	if err != nil {
+		t.Fatalf("LoadTemplateOverrides() error = %v", err)
+	}
+
+	if len(overrides) != 1 || overrides["octopus/mock"] == "" {
+		t.Fatalf("LoadTemplateOverrides() = %v, want only octopus/mock", overrides)
+	}
+
+	params := PkgData{
+		ARPkg:       "foo",
+		ARPkgTitle:  "Foo",
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "proc", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		ProcOutFieldList: []ds.ProcFieldDeclaration{
+			{Name: "Output", Format: "string", Type: ds.OUT, Serializer: []string{}},
+		},
+	}
+
+	ret, genErr := GenerateOctopus(params, Options{TemplateOverrides: overrides})
+	if genErr != nil {
+		t.Fatalf("GenerateOctopus() error = %v", genErr)
+	}
+
+	mock := ret["mock"]
+	if !strings.Contains(mock.String(), "// custom mock") {
+		t.Errorf("GenerateOctopus() mock = %s, want overridden template", mock.String())
+	}
+
+	main := ret["octopus"]
+	if !strings.Contains(main.String(), "func Call(") {
+		t.Errorf("GenerateOctopus() octopus not generated by embedded template")
+	}
+
+	if _, err := LoadTemplateOverrides(filepath.Join(dir, "notexists")); err == nil {
+		t.Errorf("LoadTemplateOverrides() expect error for not exists dir")
+	}
+}
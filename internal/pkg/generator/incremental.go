@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+// IncrementalResult результат инкрементальной генерации
+type IncrementalResult struct {
+	Files []GenerateFile // Файлы пакетов, декларации которых изменились, их нужно записать
+	Kept  []ManifestFile // Файлы неизменившихся пакетов из предыдущего манифеста, они остаются на диске без изменений
+	Stale []ManifestFile // Файлы из предыдущего манифеста, которые больше не генерируются и должны быть удалены
+}
+
+// InputHash хеш входных данных генерации пакета: версии генератора, декларации пакета и деклараций пакетов,
+// на которые он ссылается. Пакет нужно перегенерировать, если хеш изменился
+func InputHash(appInfo ds.AppInfo, cl ds.RecordPackage, link map[string]ds.RecordPackage) (string, error) {
+	hash := sha256.New()
+
+	fmt.Fprintf(hash, "%s@%s\n", appInfo.Version(), appInfo.Revision())
+
+	enc := json.NewEncoder(hash)
+
+	if err := enc.Encode(cl); err != nil {
+		return "", fmt.Errorf("can't hash declaration of package `%s`: %w", cl.Namespace.PackageName, err)
+	}
+
+	linked := make([]string, 0, len(cl.FieldsObjectMap))
+
+	for _, fo := range cl.FieldsObjectMap {
+		linked = append(linked, fo.ObjectName)
+	}
+
+	sort.Strings(linked)
+
+	for _, name := range linked {
+		if err := enc.Encode(link[name]); err != nil {
+			return "", fmt.Errorf("can't hash linked object `%s` of package `%s`: %w", name, cl.Namespace.PackageName, err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// GenerateIncremental генерирует только пакеты, входные данные которых (см. InputHash) изменились
+// по сравнению с предыдущим манифестом prev. Пакет генерируется заново и в случае, если изменилась декларация
+// пакета, на который он ссылается. Файлы манифеста без хеша входных данных (мета-информация, фикстуры
+// и манифесты предыдущих версий генератора) не учитываются, поэтому пакеты без хеша генерируются заново.
+// Директории файлов в prev должны быть указаны так же, как их формирует Generate, то есть относительно
+// каталога генерации. Изменение параметров генерации opts не отслеживается и требует полной генерации
+func GenerateIncremental(appInfo ds.AppInfo, prev []ManifestFile, packages []ds.RecordPackage, link map[string]ds.RecordPackage, opts Options) (*IncrementalResult, error) {
+	prevByDir := map[string][]ManifestFile{}
+
+	for _, file := range prev {
+		if file.Input != "" {
+			prevByDir[file.Dir] = append(prevByDir[file.Dir], file)
+		}
+	}
+
+	ret := &IncrementalResult{}
+	changed := make([]ds.RecordPackage, 0, len(packages))
+	hashes := map[string]string{}
+
+	for _, cl := range packages {
+		dir := cl.Namespace.PackageName
+
+		hash, err := InputHash(appInfo, cl, link)
+		if err != nil {
+			return nil, err
+		}
+
+		hashes[dir] = hash
+
+		if unchanged(prevByDir[dir], hash) {
+			ret.Kept = append(ret.Kept, prevByDir[dir]...)
+			delete(prevByDir, dir)
+
+			continue
+		}
+
+		changed = append(changed, cl)
+	}
+
+	files, err := GenerateAll(appInfo, changed, link, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	generated := map[string]bool{}
+
+	for i := range files {
+		files[i].Input = hashes[files[i].Dir]
+		generated[files[i].Dir+"/"+files[i].Name] = true
+	}
+
+	ret.Files = files
+
+	for _, dirFiles := range prevByDir {
+		for _, file := range dirFiles {
+			if !generated[file.Dir+"/"+file.Name] {
+				ret.Stale = append(ret.Stale, file)
+			}
+		}
+	}
+
+	sortManifest(ret.Kept)
+	sortManifest(ret.Stale)
+
+	return ret, nil
+}
+
+// unchanged проверяет, что все файлы пакета из предыдущего манифеста сгенерированы из тех же входных данных
+func unchanged(prev []ManifestFile, hash string) bool {
+	if len(prev) == 0 {
+		return false
+	}
+
+	for _, file := range prev {
+		if file.Input != hash {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,140 @@
+package generator
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/testutil"
+)
+
+func incrementalPackage(name string, timeout int64) ds.RecordPackage {
+	return ds.RecordPackage{
+		Server:    ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Timeout: timeout},
+		Namespace: ds.NamespaceDeclaration{ObjectName: strings.ToLower(name), PublicName: name, PackageName: strings.ToLower(name)},
+		Backends:  []string{"tarantool2"},
+		Fields: []ds.FieldDeclaration{
+			{Name: "Field1", Format: "int", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldsMap:       map[string]int{"Field1": 0},
+		FieldsObjectMap: map[string]ds.FieldObject{},
+		Indexes: []ds.IndexDeclaration{
+			{Name: "Field1", Num: 0, Selector: "SelectByField1", Fields: []int{0}, Primary: true, Unique: true, Type: "int"},
+		},
+		ImportPackage: ds.NewImportPackage(),
+		SerializerMap: map[string]ds.SerializerDeclaration{},
+		TriggerMap:    map[string]ds.TriggerDeclaration{},
+		FlagMap:       map[string]ds.FlagDeclaration{},
+	}
+}
+
+func manifestPaths(files []ManifestFile) []string {
+	ret := []string{}
+
+	for _, file := range files {
+		ret = append(ret, file.Dir+"/"+file.Name)
+	}
+
+	return ret
+}
+
+func generatedPaths(files []GenerateFile) []string {
+	return manifestPaths(Manifest(files))
+}
+
+func TestGenerateIncremental(t *testing.T) {
+	link := map[string]ds.RecordPackage{}
+
+	first, err := GenerateIncremental(testutil.TestAppInfo, nil, []ds.RecordPackage{
+		incrementalPackage("Foo", 500),
+		incrementalPackage("Bar", 500),
+	}, link, Options{})
+	if err != nil {
+		t.Fatalf("GenerateIncremental() error = %v", err)
+	}
+
+	if got, want := generatedPaths(first.Files), []string{"bar/tarantool.go", "foo/tarantool.go"}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("GenerateIncremental() files = %v, want %v", got, want)
+	}
+
+	prev := Manifest(first.Files)
+	for _, file := range prev {
+		if file.Input == "" {
+			t.Fatalf("GenerateIncremental() file %s/%s without input hash", file.Dir, file.Name)
+		}
+	}
+
+	prev = append(prev,
+		ManifestFile{Dir: "", Name: "repository.go", Backend: "meta"},
+		ManifestFile{Dir: "bar", Name: "old.go", Backend: "tarantool2", Input: prev[0].Input},
+		ManifestFile{Dir: "qux", Name: "tarantool.go", Backend: "tarantool2", Input: "deleted"},
+	)
+
+	tests := []struct {
+		name      string
+		packages  []ds.RecordPackage
+		wantFiles []string
+		wantKept  []string
+		wantStale []string
+	}{
+		{
+			name:      "nothing changed",
+			packages:  []ds.RecordPackage{incrementalPackage("Foo", 500), incrementalPackage("Bar", 500)},
+			wantFiles: []string{},
+			wantKept:  []string{"bar/old.go", "bar/tarantool.go", "foo/tarantool.go"},
+			wantStale: []string{"qux/tarantool.go"},
+		},
+		{
+			name:      "declaration changed",
+			packages:  []ds.RecordPackage{incrementalPackage("Foo", 500), incrementalPackage("Bar", 1000)},
+			wantFiles: []string{"bar/tarantool.go"},
+			wantKept:  []string{"foo/tarantool.go"},
+			wantStale: []string{"bar/old.go", "qux/tarantool.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GenerateIncremental(testutil.TestAppInfo, prev, tt.packages, link, Options{})
+			if err != nil {
+				t.Fatalf("GenerateIncremental() error = %v", err)
+			}
+
+			if files := generatedPaths(got.Files); !reflect.DeepEqual(files, tt.wantFiles) {
+				t.Errorf("GenerateIncremental() files = %v, want %v", files, tt.wantFiles)
+			}
+
+			if kept := manifestPaths(got.Kept); !reflect.DeepEqual(kept, tt.wantKept) {
+				t.Errorf("GenerateIncremental() kept = %v, want %v", kept, tt.wantKept)
+			}
+
+			if stale := manifestPaths(got.Stale); !reflect.DeepEqual(stale, tt.wantStale) {
+				t.Errorf("GenerateIncremental() stale = %v, want %v", stale, tt.wantStale)
+			}
+		})
+	}
+}
+
+func TestInputHash(t *testing.T) {
+	foo := incrementalPackage("Foo", 500)
+	foo.FieldsObjectMap = map[string]ds.FieldObject{"Bar": {Name: "Bar", Key: "Field1", ObjectName: "bar", Field: "Field1"}}
+
+	hash := func(link map[string]ds.RecordPackage) string {
+		h, err := InputHash(testutil.TestAppInfo, foo, link)
+		if err != nil {
+			t.Fatalf("InputHash() error = %v", err)
+		}
+
+		return h
+	}
+
+	base := hash(map[string]ds.RecordPackage{"bar": incrementalPackage("Bar", 500)})
+
+	if got := hash(map[string]ds.RecordPackage{"bar": incrementalPackage("Bar", 500), "baz": incrementalPackage("Baz", 1)}); got != base {
+		t.Errorf("InputHash() changed by unrelated package")
+	}
+
+	if got := hash(map[string]ds.RecordPackage{"bar": incrementalPackage("Bar", 1000)}); got == base {
+		t.Errorf("InputHash() not changed by linked object declaration")
+	}
+}
@@ -0,0 +1,79 @@
+package generator
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ManifestFile описание сгенерированного файла в манифесте
+type ManifestFile struct {
+	Dir     string `json:"dir"`
+	Name    string `json:"name"`
+	Backend string `json:"backend"`
+	Hash    string `json:"sha256"`
+	Input   string `json:"input,omitempty"` // Хеш входных данных, из которых сгенерирован файл, см. InputHash
+}
+
+// Manifest формирует описание сгенерированных файлов. Файлы упорядочены по директории и имени,
+// а хеш считается только по содержимому файла без информации о генераторе и времени генерации,
+// поэтому для одинаковых входных данных манифест не меняется
+func Manifest(files []GenerateFile) []ManifestFile {
+	ret := make([]ManifestFile, 0, len(files))
+
+	for _, file := range files {
+		hash := sha256.Sum256([]byte(normalizeGenerated(file.Data)))
+
+		ret = append(ret, ManifestFile{
+			Dir:     file.Dir,
+			Name:    file.Name,
+			Backend: file.Backend,
+			Hash:    hex.EncodeToString(hash[:]),
+			Input:   file.Input,
+		})
+	}
+
+	sortManifest(ret)
+
+	return ret
+}
+
+func sortManifest(files []ManifestFile) {
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].Dir != files[j].Dir {
+			return files[i].Dir < files[j].Dir
+		}
+
+		return files[i].Name < files[j].Name
+	})
+}
+
+// WriteManifest записывает манифест сгенерированных файлов в формате JSON. Файлы kept, оставшиеся
+// без изменений при инкрементальной генерации, добавляются в манифест как есть
+func WriteManifest(files []GenerateFile, w io.Writer, kept ...ManifestFile) error {
+	manifest := append(Manifest(files), kept...)
+	sortManifest(manifest)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	if err := enc.Encode(manifest); err != nil {
+		return fmt.Errorf("can't write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// ReadManifest читает манифест, записанный WriteManifest
+func ReadManifest(r io.Reader) ([]ManifestFile, error) {
+	manifest := []ManifestFile{}
+
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("can't read manifest: %w", err)
+	}
+
+	return manifest, nil
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteManifest(t *testing.T) {
+	files := []GenerateFile{
+		{Dir: "foo", Name: "octopus.go", Backend: "octopus", Data: []byte("package foo\n")},
+		{Dir: "", Name: "repository.go", Backend: "meta", Data: []byte("package repository\n")},
+		{Dir: "foo", Name: "mock.go", Backend: "octopus", Data: []byte("package foo\n")},
+	}
+
+	want := `[
+  {
+    "dir": "",
+    "name": "repository.go",
+    "backend": "meta",
+    "sha256": "8058c39d036b24fe24b844213ca2b25831934b606816e18b3fdb52f012c0db80"
+  },
+  {
+    "dir": "foo",
+    "name": "mock.go",
+    "backend": "octopus",
+    "sha256": "1b63a92736f126a00f521c0ef804e67d0cf949b5ff790d6d4c3a4b7681da8d21"
+  },
+  {
+    "dir": "foo",
+    "name": "octopus.go",
+    "backend": "octopus",
+    "sha256": "1b63a92736f126a00f521c0ef804e67d0cf949b5ff790d6d4c3a4b7681da8d21"
+  }
+]
+`
+
+	buf := bytes.Buffer{}
+
+	if err := WriteManifest(files, &buf); err != nil {
+		t.Fatalf("WriteManifest() error = %v", err)
+	}
+
+	if buf.String() != want {
+		t.Errorf("WriteManifest() = %s, want %s", buf.String(), want)
+	}
+}
@@ -0,0 +1,45 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+)
+
+//nolint:revive
+//go:embed tmpl/mock/main.tmpl
+var MockRootRepositoryTmpl string
+
+// GenerateMock генерация пакета, хранящего записи в памяти процесса.
+// Сгенерированный пакет имеет такой же набор функций, как и пакет для octopus,
+// и используется в тестах вместо реальной базы
+func GenerateMock(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	return generateInMemory(params, "mock", opts)
+}
+
+// GenerateMemory генерация пакета для локальной разработки без базы. Записи хранятся в памяти процесса,
+// как для mock, дополнительно вызываются триггеры жизненного цикла, генерируются стандартные мутаторы полей,
+// а хранилище сохраняется в JSON файл функцией SaveStore и загружается из него функцией LoadStore
+func GenerateMemory(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	return generateInMemory(params, "memory", opts)
+}
+
+// generateInMemory генерация пакета с хранилищем в памяти процесса по общему шаблону бекендов mock и memory
+func generateInMemory(params PkgData, backend string, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	if err := params.checkImports(backend); err != nil {
+		return nil, err
+	}
+
+	if err := params.checkIndexes(backend); err != nil {
+		return nil, err
+	}
+
+	if err := params.checkUnused(backend, opts); err != nil {
+		return nil, err
+	}
+
+	params.Backend = backend
+
+	return GenerateFilesByTmpl(backend, params, backend, opts.header(), opts.template("mock/main", MockRootRepositoryTmpl))
+}
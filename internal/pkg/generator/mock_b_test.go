@@ -0,0 +1,202 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+func TestGenerateMock(t *testing.T) {
+	type args struct {
+		params PkgData
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    *arerror.ErrGeneratorPhases
+		wantStr []string
+	}{
+		{
+			name: "fieldsPkg",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      "foo",
+					ARPkgTitle: "Foo",
+					Indexes: []ds.IndexDeclaration{
+						{
+							Name:     "ID",
+							Num:      0,
+							Selector: "SelectByID",
+							Fields:   []int{0},
+							Type:     "int64",
+							Primary:  true,
+							Unique:   true,
+						},
+						{
+							Name:     "NameTags",
+							Num:      1,
+							Selector: "SelectByNameTags",
+							Fields:   []int{1, 2},
+							Type:     "NameTagsIndexType",
+						},
+					},
+					FieldList: []ds.FieldDeclaration{
+						{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+						{Name: "Name", Format: "string", Size: 32, Serializer: []string{}},
+						{Name: "Tags", Format: "string", Serializer: []string{"NoteJSON"}},
+					},
+					ComputedFields: []ds.FieldDeclaration{
+						{Name: "Title", Format: "string", Compute: ds.Compute{Pkg: "github.com/mailru/activerecord/notexistsfolder/compute", Func: "Title", ImportName: "computeTitle", Fields: []string{"Name", "Tags"}}},
+					},
+					Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+					Serializers: map[string]ds.SerializerDeclaration{
+						"NoteJSON": {
+							Name:        "NoteJSON",
+							Pkg:         "github.com/mailru/activerecord/pkg/serializer",
+							Type:        "[]string",
+							ImportName:  "serializerNoteJSON",
+							Marshaler:   "JSONMarshal",
+							Unmarshaler: "JSONUnmarshal",
+						},
+					},
+					Imports: []ds.ImportDeclaration{
+						{Path: "github.com/mailru/activerecord/notexistsfolder/compute", ImportName: "computeTitle"},
+						{Path: "github.com/mailru/activerecord/pkg/serializer", ImportName: "serializerNoteJSON"},
+					},
+				},
+			},
+			wantStr: []string{
+				`package foo`,
+				`fieldTags []string`,
+				`func ResetStore() {`,
+				// Записи упорядочиваются сравнением значений полей первичного ключа, а не их строкового представления
+				"func lessPrimary(a, b *Foo) bool {\n\tif a.fieldID != b.fieldID {\n\t\treturn a.fieldID < b.fieldID\n\t}\n\n\treturn false\n}",
+				`sort.Slice(records, func(i, j int) bool { return lessPrimary(records[i], records[j]) })`,
+				`for _, obj := range sortedRecords() {`,
+				`func ExportAll(ctx context.Context, fn func(obj *Foo) error) error {`,
+				`func ImportAll(ctx context.Context, records <-chan *Foo) error {`,
+				`max length of field 'Foo.Name' is '%d' (received '%d')", 32, len(Name))`,
+				`packedTags, err := serializerNoteJSON.JSONMarshal(obj.fieldTags)`,
+				`if err = serializerNoteJSON.JSONUnmarshal(packedTags, &np.fieldTags); err != nil {`,
+				`type NameTagsIndexType struct {`,
+				`func SelectByNameTags(ctx context.Context, key NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`func SelectByNameTagsWithLimit(ctx context.Context, key NameTagsIndexType, limit, offset uint32) ([]*Foo, error) {`,
+				`func Upsert(ctx context.Context, obj *Foo) error {`,
+				"// Upsert добавляет запись obj в хранилище или заменяет запись с тем же первичным ключом всеми полями obj\nfunc Upsert(",
+				`func InsertBatch(ctx context.Context, records []*Foo) error {`,
+				`func SelectByNameTagsCount(ctx context.Context, key NameTagsIndexType) (uint32, error) {`,
+				`func ExistsByNameTags(ctx context.Context, key NameTagsIndexType) (bool, error) {`,
+				`func SelectByID(ctx context.Context, key int64) (*Foo, error) {`,
+				`func SelectByPrimary(ctx context.Context, pk int64) (*Foo, error) {`,
+				`activerecord.ErrDuplicateKey`,
+				`func GetOrCreateByID(ctx context.Context, key int64, newRecord *Foo) (*Foo, bool, error) {`,
+				`if !errors.Is(insertErr, activerecord.ErrDuplicateKey) {`,
+				`func DeleteByNameTags(ctx context.Context, key NameTagsIndexType) (int, error) {`,
+				`type FooPrimaryKey struct {`,
+				`func KeyOf(obj *Foo) FooPrimaryKey {`,
+				`obj.markChanged("Name")`,
+				`func (obj *Foo) Changed() []string {`,
+				`if !obj.changed["Name"] {`,
+				`np.fieldName = stored.fieldName`,
+				`func (obj *Foo) GetTitle() string {`,
+				`return computeTitle.Title(obj.GetName(), obj.GetTags())`,
+				`func SelectByKey(ctx context.Context, key FooPrimaryKey) (*Foo, error) {`,
+				`return SelectByPrimary(ctx, key.ID)`,
+				`func SelectByPrimaryKeysOrdered(ctx context.Context, keys []FooPrimaryKey) ([]*Foo, error) {`,
+				`pks = append(pks, key.ID)`,
+				`func (obj *Foo) Delete(ctx context.Context) error {`,
+				`func UpdateReturning(ctx context.Context, obj *Foo) (*Foo, error) {`,
+				`return fmt.Errorf("can't update not exists object: %w", activerecord.ErrNotFound)`,
+				`return fmt.Errorf("can't insert already exists object: %w", activerecord.ErrConflict)`,
+				`Backend:   "mock",`,
+				`type FooSelectors interface {`,
+				`SelectByNameTagsIn(ctx context.Context, values []NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error)`,
+				"func (obj *Foo) SelectByID(ctx context.Context, key int64) (*Foo, error) {\n\treturn SelectByID(ctx, key)\n}",
+				"func (obj *Foo) SelectByNameTagss(ctx context.Context, keys []NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error) {\n\treturn SelectByNameTagss(ctx, keys, limiter)\n}",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, got := GenerateMock(tt.args.params, Options{})
+			if got != tt.want {
+				t.Errorf("GenerateMock() = %v, want %v", got, tt.want)
+			}
+
+			buff, ex := ret["mock"]
+			if !ex {
+				t.Errorf("GenerateMock() mock not generated")
+				return
+			}
+
+			for _, substr := range tt.wantStr {
+				if !strings.Contains(buff.String(), substr) {
+					t.Errorf("GenerateMock() = %v, want %v", buff.String(), substr)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateMemory(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Cnt", Format: "uint32", Serializer: []string{}, Mutators: []string{ds.IncMutator, ds.DecMutator}},
+		},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+		Triggers: map[string]ds.TriggerDeclaration{
+			"BeforeInsert": {Name: "BeforeInsert", Pkg: "github.com/mailru/activerecord/notexistsfolder/hooks", Func: "BeforeInsert", ImportName: "hooks", Phase: "BeforeInsert"},
+			"AfterDelete":  {Name: "AfterDelete", Pkg: "github.com/mailru/activerecord/notexistsfolder/hooks", Func: "AfterDelete", ImportName: "hooks", Phase: "AfterDelete"},
+		},
+		Imports: []ds.ImportDeclaration{
+			{Path: "github.com/mailru/activerecord/notexistsfolder/hooks", ImportName: "hooks"},
+		},
+	}
+
+	ret, err := GenerateMemory(params, Options{})
+	if err != nil {
+		t.Fatalf("GenerateMemory() error = %v", err)
+	}
+
+	buff, ex := ret["memory"]
+	if !ex {
+		t.Fatalf("GenerateMemory() memory not generated")
+	}
+
+	for _, substr := range []string{
+		`func (obj *Foo) insertReplace(ctx context.Context, mustAbsent bool) error {`,
+		`if err := hooks.BeforeInsert(ctx, obj); err != nil {`,
+		`if err := obj.beforeInsert(ctx); err != nil {`,
+		"obj.changed[name] = true\n\tobj.normalized = false",
+		"\tobj.normalized = true\n\n\treturn obj, nil",
+		`return obj.insertReplace(ctx, true)`,
+		`func (obj *Foo) doDelete(ctx context.Context) error {`,
+		`hooks.AfterDelete(ctx, obj)`,
+		`func (obj *Foo) IncCnt(mutArg uint32) error {`,
+		`if obj.fieldCnt < mutArg {`,
+		`obj.markChanged("Cnt")`,
+		`func SaveStore(path string) error {`,
+		`func LoadStore(path string) error {`,
+		`Cnt uint32 ` + "`json:\"Cnt\"`",
+		`Backend:   "memory",`,
+	} {
+		if !strings.Contains(buff.String(), substr) {
+			t.Errorf("GenerateMemory() = %v, want %v", buff.String(), substr)
+		}
+	}
+
+	mock, _ := GenerateMock(params, Options{})
+	if got := mock["mock"]; strings.Contains(got.String(), "insertReplace") || strings.Contains(got.String(), "IncCnt") || strings.Contains(got.String(), "normalized") {
+		t.Errorf("GenerateMock() generated triggers or mutators")
+	}
+}
@@ -1,7 +1,6 @@
 package generator
 
 import (
-	"bufio"
 	"bytes"
 	_ "embed"
 	"log"
@@ -28,45 +27,61 @@ var OctopusRootRepositoryTmpl string
 //go:embed tmpl/octopus/fixture.tmpl
 var OctopusFixtureRepositoryTmpl string
 
-var funcs = template.FuncMap{"snakeCase": text.ToSnakeCase}
-
-func GenerateOctopus(params PkgData) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
-	octopusWriter := bytes.Buffer{}
-	mockWriter := bytes.Buffer{}
-	fixtureWriter := bytes.Buffer{}
+// funcs общие функции для всех шаблонов генерации
+var funcs = template.FuncMap{
+	"snakeCase":   text.ToSnakeCase,
+	"camelCase":   text.ToCamelCase,
+	"pascalCase":  text.ToPascalCase,
+	"pluralize":   text.Pluralize,
+	"singularize": text.Singularize,
+	"reverse": func(list []string) []string {
+		ret := make([]string, 0, len(list))
+		for i := len(list) - 1; i >= 0; i-- {
+			ret = append(ret, list[i])
+		}
 
-	octopusFile := bufio.NewWriter(&octopusWriter)
+		return ret
+	},
+	"sortedKeys":      sortedKeys,
+	"indexFilter":     indexFilter,
+	"indexFilterDecl": indexFilterDecl,
+	"zeroValueOf":     zeroValueOf,
+	"orderKind":       orderKind,
+}
 
-	//TODO возможно имеет смысл разделить большой шаблон OctopusRootRepositoryTmpl для удобства поддержки
-	err := GenerateByTmpl(octopusFile, params, "octopus", OctopusRootRepositoryTmpl)
-	if err != nil {
+func GenerateOctopus(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	if err := params.checkImports("octopus"); err != nil {
 		return nil, err
 	}
 
-	octopusFile.Flush()
-
-	mockFile := bufio.NewWriter(&mockWriter)
+	if err := params.checkIndexes("octopus"); err != nil {
+		return nil, err
+	}
 
-	err = GenerateByTmpl(mockFile, params, "octopus", OctopusMockRepositoryTmpl)
-	if err != nil {
+	if err := params.checkUnused("octopus", opts); err != nil {
 		return nil, err
 	}
 
-	mockFile.Flush()
+	params.Backend = "octopus"
 
-	fixtureFile := bufio.NewWriter(&fixtureWriter)
+	ret := map[string]bytes.Buffer{}
 
-	err = GenerateByTmpl(fixtureFile, params, "octopus", OctopusFixtureRepositoryTmpl)
-	if err != nil {
-		return nil, err
+	//TODO возможно имеет смысл разделить большой шаблон OctopusRootRepositoryTmpl для удобства поддержки
+	files := []struct{ key, tmpl, def string }{
+		{key: "octopus", tmpl: "octopus/main", def: OctopusRootRepositoryTmpl},
+		{key: "mock", tmpl: "octopus/mock", def: OctopusMockRepositoryTmpl},
+		{key: "fixture", tmpl: "octopus/fixture", def: OctopusFixtureRepositoryTmpl},
 	}
 
-	fixtureFile.Flush()
+	for _, f := range files {
+		generated, err := GenerateFilesByTmpl(f.key, params, "octopus", opts.header(), opts.template(f.tmpl, f.def))
+		if err != nil {
+			return nil, err
+		}
 
-	ret := map[string]bytes.Buffer{
-		"octopus": octopusWriter,
-		"mock":    mockWriter,
-		"fixture": fixtureWriter,
+		for key, data := range generated {
+			ret[key] = data
+		}
 	}
 
 	return ret, nil
@@ -179,6 +194,12 @@ func (p OctopusFormatParam) DefaultValue() string {
 		return fname + "([]byte{}, 0, iproto.ModeDefault)"
 	} else if strings.HasPrefix(p.Name, "String") {
 		return fname + `([]byte{}, "", iproto.ModeDefault)`
+	} else if p.Name == "UUID" {
+		return fname + "([]byte{}, uuid.Nil, iproto.ModeDefault)"
+	} else if p.Name == "Decimal" {
+		return fname + "([]byte{}, decimal.Zero, iproto.ModeDefault)"
+	} else if strings.HasPrefix(p.Name, "Time") {
+		return fname + "([]byte{}, time.Time{}, iproto.ModeDefault)"
 	} else {
 		return "can't detect type"
 	}
@@ -237,20 +258,25 @@ type OctopusMutatorParam struct {
 }
 
 var OctopusFormatMapper = map[octopus.Format]OctopusFormatParam{
-	octopus.Bool:    {Name: "Uint8", len: 2, convstr: "strconv.FormatBool(%%)", packConvFunc: "octopus.BoolToUint", UnpackConvFunc: "octopus.UintToBool", unpackType: "uint8"},
-	octopus.Uint8:   {Name: "Uint8", len: 2, convstr: "strconv.FormatUint(uint64(%%), 10)"},
-	octopus.Uint16:  {Name: "Uint16", len: 3, convstr: "strconv.FormatUint(uint64(%%), 10)"},
-	octopus.Uint32:  {Name: "Uint32", len: 5, convstr: "strconv.FormatUint(uint64(%%), 10)"},
-	octopus.Uint64:  {Name: "Uint64", len: 9, convstr: "strconv.FormatUint(%%, 10)"},
-	octopus.Uint:    {Name: "Uint32", len: 5, convstr: "strconv.FormatUint(uint64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "uint"},
-	octopus.Int8:    {Name: "Uint8", len: 2, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint8", UnpackConvFunc: "int8", minValue: "math.MinInt8", maxValue: "math.MaxInt8"},
-	octopus.Int16:   {Name: "Uint16", len: 3, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint16", UnpackConvFunc: "int16", minValue: "math.MinInt16", maxValue: "math.MaxInt16"},
-	octopus.Int32:   {Name: "Uint32", len: 5, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "int32", minValue: "math.MinInt32", maxValue: "math.MaxInt32"},
-	octopus.Int64:   {Name: "Uint64", len: 9, convstr: "strconv.FormatInt(%%, 10)", packConvFunc: "uint64", UnpackConvFunc: "int64", minValue: "math.MinInt64", maxValue: "math.MaxInt64"},
-	octopus.Int:     {Name: "Uint32", len: 5, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "int", minValue: "math.MinInt32", maxValue: "math.MaxInt32"},
-	octopus.Float32: {Name: "Uint32", len: 5, convstr: "strconv.FormatFloat(%%, 32)", packConvFunc: "math.Float32bits", UnpackConvFunc: "math.Float32frombits", unpackType: "uint32", minValue: "math.MinFloat32", maxValue: "math.MaxFloat32"},
-	octopus.Float64: {Name: "Uint64", len: 9, convstr: "strconv.FormatFloat(%%, 64)", packConvFunc: "math.Float64bits", UnpackConvFunc: "math.Float64frombits", unpackType: "uint64", minValue: "math.MinFloat64", maxValue: "math.MaxFloat64"},
-	octopus.String:  {Name: "String", convstr: " %% ", lenFunc: octopus.ByteLen, packFunc: "octopus.PackString", unpackFunc: "octopus.UnpackString", minValue: "0", maxValue: "4096", unpackType: "string"}}
+	octopus.Bool:        {Name: "Uint8", len: 2, convstr: "strconv.FormatBool(%%)", packConvFunc: "octopus.BoolToUint", UnpackConvFunc: "octopus.UintToBool", unpackType: "uint8"},
+	octopus.Uint8:       {Name: "Uint8", len: 2, convstr: "strconv.FormatUint(uint64(%%), 10)"},
+	octopus.Uint16:      {Name: "Uint16", len: 3, convstr: "strconv.FormatUint(uint64(%%), 10)"},
+	octopus.Uint32:      {Name: "Uint32", len: 5, convstr: "strconv.FormatUint(uint64(%%), 10)"},
+	octopus.Uint64:      {Name: "Uint64", len: 9, convstr: "strconv.FormatUint(%%, 10)"},
+	octopus.Uint:        {Name: "Uint32", len: 5, convstr: "strconv.FormatUint(uint64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "uint"},
+	octopus.Int8:        {Name: "Uint8", len: 2, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint8", UnpackConvFunc: "int8", minValue: "math.MinInt8", maxValue: "math.MaxInt8"},
+	octopus.Int16:       {Name: "Uint16", len: 3, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint16", UnpackConvFunc: "int16", minValue: "math.MinInt16", maxValue: "math.MaxInt16"},
+	octopus.Int32:       {Name: "Uint32", len: 5, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "int32", minValue: "math.MinInt32", maxValue: "math.MaxInt32"},
+	octopus.Int64:       {Name: "Uint64", len: 9, convstr: "strconv.FormatInt(%%, 10)", packConvFunc: "uint64", UnpackConvFunc: "int64", minValue: "math.MinInt64", maxValue: "math.MaxInt64"},
+	octopus.Int:         {Name: "Uint32", len: 5, convstr: "strconv.FormatInt(int64(%%), 10)", packConvFunc: "uint32", UnpackConvFunc: "int", minValue: "math.MinInt32", maxValue: "math.MaxInt32"},
+	octopus.Float32:     {Name: "Uint32", len: 5, convstr: "strconv.FormatFloat(%%, 32)", packConvFunc: "math.Float32bits", UnpackConvFunc: "math.Float32frombits", unpackType: "uint32", minValue: "math.MinFloat32", maxValue: "math.MaxFloat32"},
+	octopus.Float64:     {Name: "Uint64", len: 9, convstr: "strconv.FormatFloat(%%, 64)", packConvFunc: "math.Float64bits", UnpackConvFunc: "math.Float64frombits", unpackType: "uint64", minValue: "math.MinFloat64", maxValue: "math.MaxFloat64"},
+	octopus.String:      {Name: "String", convstr: " %% ", lenFunc: octopus.ByteLen, packFunc: "octopus.PackString", unpackFunc: "octopus.UnpackString", minValue: "0", maxValue: "4096", unpackType: "string"},
+	octopus.UUID:        {Name: "UUID", len: 17, convstr: "%%.String()", packFunc: "octopus.PackUUID", unpackFunc: "octopus.UnpackUUID"},
+	octopus.Decimal:     {Name: "Decimal", convstr: "%%.String()", lenFunc: octopus.ByteLen, packFunc: "octopus.PackDecimal", unpackFunc: "octopus.UnpackDecimal"},
+	octopus.TimeUnix:    {Name: "TimeUnix", len: 9, convstr: "%%.Format(time.RFC3339Nano)", packFunc: "octopus.PackTimeUnix", unpackFunc: "octopus.UnpackTimeUnix"},
+	octopus.TimeUnixMs:  {Name: "TimeUnixMs", len: 9, convstr: "%%.Format(time.RFC3339Nano)", packFunc: "octopus.PackTimeUnixMs", unpackFunc: "octopus.UnpackTimeUnixMs"},
+	octopus.TimeRFC3339: {Name: "TimeRFC3339", convstr: "%%.Format(time.RFC3339Nano)", lenFunc: octopus.ByteLen, packFunc: "octopus.PackTimeRFC3339", unpackFunc: "octopus.UnpackTimeRFC3339"}}
 
 var OctopusMutatorMapper = map[string]OctopusMutatorParam{
 	ds.IncMutator:      {Name: "Inc", AvailableType: octopus.NumericFormat},
@@ -1,11 +1,13 @@
 package generator
 
 import (
+	"bytes"
 	"strings"
 	"testing"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/octopus"
 )
 
 func TestGenerateOctopus(t *testing.T) {
@@ -41,6 +43,7 @@ func TestGenerateOctopus(t *testing.T) {
 							},
 							Primary: true,
 							Unique:  true,
+							Type:    "int",
 						},
 						{
 							Name:     "Field2",
@@ -48,10 +51,12 @@ func TestGenerateOctopus(t *testing.T) {
 							Selector: "SelectByField2",
 							Fields:   []int{1},
 							FieldsMap: map[string]ds.IndexField{
-								"Field1": {IndField: 1, Order: 0},
+								"Field2": {IndField: 1, Order: 0},
 							},
-							Primary: false,
-							Unique:  false,
+							Primary:    false,
+							Unique:     false,
+							Projection: []int{0, 2},
+							Type:       "bool",
 						},
 					},
 					FieldList: []ds.FieldDeclaration{
@@ -70,6 +75,8 @@ func TestGenerateOctopus(t *testing.T) {
 							Mutators:   []string{},
 							Serializer: []string{},
 							ObjectLink: "",
+							Swappable:  "foo_swap",
+							Sensitive:  true,
 						},
 						{
 							Name:       "Fs",
@@ -79,11 +86,55 @@ func TestGenerateOctopus(t *testing.T) {
 							Serializer: []string{},
 							ObjectLink: "",
 						},
+						{
+							Name:       "Worker",
+							Format:     "string",
+							Mutators:   []string{},
+							Serializer: []string{},
+							Lease:      ds.LeaseOwner,
+						},
+						{
+							Name:       "Until",
+							Format:     "int64",
+							Mutators:   []string{},
+							Serializer: []string{},
+							Lease:      ds.LeaseExpire,
+						},
+						{
+							Name:          "Kind",
+							Format:        "string",
+							Mutators:      []string{},
+							Serializer:    []string{},
+							Discriminator: true,
+						},
+						{
+							Name:       "Data",
+							Format:     "string",
+							Mutators:   []string{},
+							Serializer: []string{},
+							Payload:    map[string]string{"note": "NoteJSON"},
+						},
+						{
+							Name:       "Tags",
+							Format:     "string",
+							Mutators:   []string{},
+							Serializer: []string{"NoteJSON"},
+						},
 					},
 					FieldObject: map[string]ds.FieldObject{},
-					Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+					Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011", Sharding: "ring", SlowQuery: 200, MaxConns: 16, MinConns: 4, ConnTimeout: 100, CacheSize: 100},
+					Trace:       true,
 					Container:   ds.NamespaceDeclaration{ObjectName: "2", PublicName: "Testmodel", PackageName: "testmodel"},
-					Serializers: map[string]ds.SerializerDeclaration{},
+					Serializers: map[string]ds.SerializerDeclaration{
+						"NoteJSON": {
+							Name:        "NoteJSON",
+							Pkg:         "github.com/mailru/activerecord/pkg/serializer",
+							Type:        "[]string",
+							ImportName:  "serializerNoteJSON",
+							Marshaler:   "JSONMarshal",
+							Unmarshaler: "JSONUnmarshal",
+						},
+					},
 					Mutators: map[string]ds.MutatorDeclaration{
 						"FsMutator": {
 							Name:       "FsMutator",
@@ -92,6 +143,8 @@ func TestGenerateOctopus(t *testing.T) {
 							ImportName: "mutatorFooMutatorField",
 							Update:     "updateFunc",
 							Replace:    "replaceFunc",
+							Args:       []string{"5"},
+							Arity:      1,
 							PartialFields: []ds.PartialFieldDeclaration{
 								{Name: "Bar", Type: "ds.AppInfo"},
 								{Name: "BeerData", Type: "[]Beer"},
@@ -99,28 +152,124 @@ func TestGenerateOctopus(t *testing.T) {
 							},
 						},
 					},
-					Imports:  []ds.ImportDeclaration{},
-					Triggers: map[string]ds.TriggerDeclaration{},
-					Flags:    map[string]ds.FlagDeclaration{},
+					Imports: []ds.ImportDeclaration{
+						{Path: "github.com/mailru/activerecord/pkg/serializer", ImportName: "serializerNoteJSON"},
+						{Path: "github.com/mailru/activerecord/internal/pkg/conv", ImportName: "mutatorFooMutatorField"},
+						{Path: "github.com/mailru/activerecord/internal/pkg/hooks", ImportName: "triggerHooks"},
+					},
+					Triggers: map[string]ds.TriggerDeclaration{
+						"BeforeUpdate": {Name: "BeforeUpdate", Func: "CheckUpdate", ImportName: "triggerHooks", Pkg: "github.com/mailru/activerecord/internal/pkg/hooks", Phase: "BeforeUpdate"},
+						"AfterUpdate":  {Name: "AfterUpdate", Func: "NotifyUpdate", ImportName: "triggerHooks", Pkg: "github.com/mailru/activerecord/internal/pkg/hooks", Phase: "AfterUpdate"},
+					},
+					Flags:       map[string]ds.FlagDeclaration{},
+					LeaseProc:   "foo_lease",
+					CopyGetters: true,
 				},
 			},
 			wantStr: map[string][]string{
 				"octopus": {
 					`Code generated by argen. DO NOT EDIT.`,
+					`if shard, ok := activerecord.ShardNumFromContext(ctx); ok {`,
+					`shardKeys[shard] = keysPacked`,
+					`func UpdateReturning(ctx context.Context, obj *Foo) (*Foo, error) {`,
+					`return SelectByPrimary(activerecord.WithMasterRead(ctx), obj.Primary())`,
+					`firstShard, shardCnt = shard, shard+1`,
+					`octopus.WithTimeout(time.Millisecond * 500, time.Millisecond * 100),`,
+					`octopus.WithPoolSize(16),`,
+					`octopus.WithMinPoolSize(4),`,
+					`ctx, span := activerecord.StartSpan(ctx, "Foo.select", activerecord.SpanAttrs{Space: "2", Index: indexnum})`,
+					`observeQuery(ctx, activerecord.QueryLabels{Storage: "octopus", Entity: "Foo", Namespace: "2", Method: "select", Index: indexName(indexnum)}, started, err)`,
+					`var health = activerecord.NewHealth(activerecord.DefaultHealthFailures, 0)`,
+					`func HealthState() activerecord.HealthState {`,
+					`health.ObservePing(err)`,
+					`health.Observe(err, time.Since(started))`,
+					`res, err := selectFromBox(ctx, indexnum, keysPacked, limiter)`,
+					`err := obj.updateInBox(ctx)`,
+					`activerecord.LogQuery(ctx, activerecord.QueryLabels{Storage: "octopus", Entity: "Foo", Method: "SelectByField1s", Index: "Field1"}, keys, err)`,
+					`activerecord.LogQuery(ctx, activerecord.QueryLabels{Storage: "octopus", Entity: "Foo", Method: "SelectByField2s", Index: "Field2"}, activerecord.RedactedValue, err)`,
+					`activerecord.LogQuery(ctx, activerecord.QueryLabels{Storage: "octopus", Entity: "Foo", Method: "update"}, obj.Primary(), err)`,
+					`"Field1: " + fmt.Sprint(obj.fieldField1),`,
+					`"Field2: " + activerecord.RedactedValue,`,
+					`func (obj *Foo) GoString() string {`,
+					`err := obj.deleteFromBox(ctx)`,
+					`err := obj.insertReplaceInBox(ctx, insertMode)`,
+					`func GetOrCreateByField1(ctx context.Context, key int, newRecord *Foo) (*Foo, bool, error) {`,
+					`func (obj *Foo) Changed() []string {`,
+					`changed[op.Field] = true`,
+					`if err = newRecord.SetField1(key); err != nil {`,
+					`if !errors.Is(insertErr, octopus.ErrDuplicate) {`,
+					`func DeleteByField2(ctx context.Context, key bool) (int, error) {`,
+					`selected, err := SelectByField2(ctx, key, activerecord.EmptyLimiter())`,
+					`err := obj.Delete(ctx)`,
 					`func (obj *Foo) insertReplace(ctx context.Context, insertMode octopus.InsertMode) error {`,
 					`func (obj *Foo) InsertOrReplace(ctx context.Context) error {`,
+					`func (obj *Foo) InsertIfAbsent(ctx context.Context) (bool, error) {`,
 					`func (obj *Foo) Replace(ctx context.Context) error {`,
 					`func (obj *Foo) Insert(ctx context.Context) error {`,
 					`func (obj *Foo) Update(ctx context.Context) error {`,
+					`if err := triggerHooks.CheckUpdate(ctx, obj); err != nil {`,
+					`return fmt.Errorf("trigger BeforeUpdate: %w", err)`,
+					`triggerHooks.NotifyUpdate(ctx, obj)`,
+					`func (obj *Foo) doUpdate(ctx context.Context) error {`,
 					`func (obj *Foo) Delete(ctx context.Context) error {`,
 					`func (obj *Foo) packPk() ([][]byte, error) {`,
-					`func (obj *Foo) Equal (anotherObjI any) bool {`,
+					`func (obj *Foo) Equal(other *Foo) bool {`,
 					`func (obj *Foo) PrimaryString() string {`,
 					`func selectBox (ctx context.Context, indexnum uint32, keysPacked [][][]byte, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+					`func selectBoxShard (ctx context.Context, shard int, indexnum uint32, keysPacked [][][]byte, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+					`func shardByPk(ctx context.Context, pk [][]byte) (int, error) {`,
+					`func (obj *Foo) masterBox(ctx context.Context) (*octopus.Connection, error) {`,
+					`slowQueryThreshold = 200 * time.Millisecond`,
+					`defer activerecord.SlowQuery("Foo.select", time.Now(), slowQueryThreshold, keysPacked)`,
+					`defer activerecord.SlowQuery("Foo.update", time.Now(), slowQueryThreshold, obj.primaryLog())`,
 					`func (obj *Foo) SetField1(Field1 int) error {`,
+					`type FooField2Projection struct {`,
+					`func SwapField2(ctx context.Context, key int, from, to bool) (bool, error) {`,
+					`octopus.CallLua(ctx, connection, "foo_swap", args...)`,
+					`func Lease(ctx context.Context, key int, owner string, ttl time.Duration) (*Foo, bool, error) {`,
+					`func Release(ctx context.Context, key int, owner string) (bool, error) {`,
+					`octopus.CallLua(ctx, connection, "foo_lease", args...)`,
+					`func CountByField2(ctx context.Context, maxKeys ...int) (map[bool]uint64, error) {`,
+					`err := walkAll(ctx, func(tuple octopus.TupleData) error {`,
+					`err = walkAll(ctx, func(tuple octopus.TupleData) error {`,
+					`w := octopus.PackSelectRange(namespace, 0, countPageSize, after)`,
+					`if num == 0 && after != nil && tuplePkEqual(tuple, after) {`,
+					`return bytes.Equal(tuple.Data[0], key[0])`,
+					`func (obj *Foo) Payload() (any, error) {`,
+					`func (obj *Foo) MarshalBinary() ([]byte, error) {`,
+					`func MarshalEmbedded(obj *Foo) (string, error) {`,
+					`func UnmarshalEmbedded(data string, obj **Foo) error {`,
+					`func (obj *Foo) GetTags() []string {`,
+					`copy(ret, obj.fieldTags)`,
+					`func (obj *Foo) UnmarshalBinary(data []byte) error {`,
+					`var primaryCache = activerecord.NewCache(100, 0)`,
+					`if data, ok := primaryCache.Get(key); ok {`,
+					`primaryCache.Set(key, data, version)`,
+					`invalidateCache(ctx, obj.Primary())`,
+					`func DeleteByPrimaryList(ctx context.Context, keys []int) (int, []int, error) {`,
+					`func ValidateBatch(records []*Foo) []activerecord.BatchError {`,
+					`func Reconcile(ctx context.Context, desired []*Foo) (created, updated, deleted int, err error) {`,
+					`func Schema() activerecord.SchemaDescriptor {`,
+					`const SchemaVersion = "`,
+					`Mutators:   []string{"inc"},`,
+					`case "note":`,
+					`serializerNoteJSON.JSONUnmarshal(obj.GetData(), &svar)`,
+					`return nil, &activerecord.DiscriminatorError{Entity: "Foo", Value: obj.GetKind()}`,
+					`func SelectByField2Projection(ctx context.Context, keys []bool, limiter activerecord.SelectorLimiter) ([]FooField2Projection, error) {`,
+					`func SelectByField2WithLimit(ctx context.Context, key bool, limit, offset uint32) ([]*Foo, error) {`,
+					`return SelectByField2(ctx, key, activerecord.NewLimitOffset(limit, offset))`,
+					`func SelectByField2In(ctx context.Context, values []bool, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+					`return SelectByField2s(ctx, keys, limiter)`,
+					`func SelectByField2Count(ctx context.Context, key bool) (uint32, error) {`,
+					`func UpdateByField2(ctx context.Context, key bool, ops FooUpdateOps) (int, error) {`,
+					`type FooUpdateOps struct {`,
+					"\tWorker *string\n",
+					`if err := obj.SetWorker(*ops.Worker); err != nil {`,
+					`err = walkIndex(ctx, 1, keysPacked[0], func(tuple octopus.TupleData) error {`,
 					`func (obj *Foo) GetField1() int {`,
 					`type Mutators struct {`,
 					`newObj.FsMutator.OpFunc`,
+					`append(append([]string{obj.PrimaryString()}, mutatorArgs...), "5")...)`,
 					`newObj.FsMutator.PartialFields`,
 					`func (obj *Foo) SetFsMutatorBar(Bar ds.AppInfo) error {`,
 					`func (obj *Foo) SetFsMutatorBeerData(BeerData []Beer) error {`,
@@ -143,8 +292,8 @@ func TestGenerateOctopus(t *testing.T) {
 					`func (obj *Foo) MockInsertOrReplace(ctx context.Context) []byte {`,
 					`func (obj *Foo) MockUpdate(ctx context.Context) []byte {`,
 					`func (obj *Foo) RepoSelector(ctx context.Context) (any, error) {`,
-					`func SelectByField1MockerLogger(keys [], res FooList) func() (activerecord.MockerLogger, error) {`,
-					`func (obj *Foo) MockSelectByField1sRequest(ctx context.Context, keys [], ) []byte {`,
+					`func SelectByField1MockerLogger(keys []int, res FooList) func() (activerecord.MockerLogger, error) {`,
+					`func (obj *Foo) MockSelectByField1sRequest(ctx context.Context, keys []int, ) []byte {`,
 					`func (obj *Foo) MockSelectResponse() ([][]byte, error) {`,
 					`func (obj *Foo) MockMutatorFsMutatorUpdate(ctx context.Context) [][]byte {`,
 				},
@@ -181,16 +330,20 @@ func TestGenerateOctopus(t *testing.T) {
 					Imports:     []ds.ImportDeclaration{},
 					Triggers:    map[string]ds.TriggerDeclaration{},
 					Flags:       map[string]ds.FlagDeclaration{},
-					AppInfo:     "",
+					AppInfo:     ds.AppInfo{},
 				},
 			},
 			wantStr: map[string][]string{
 				"octopus": {
 					`Code generated by argen. DO NOT EDIT.`,
 					`func (obj *Foo) GetOutput() string {`,
+					`octopus.WithPoolSize(octopus.DefaultPoolSize),`,
 					`func Call(ctx context.Context) (*Foo, error)`,
 					`func TupleToStruct(ctx context.Context, tuple octopus.TupleData) (*Foo, error) {`,
 					`procName string = "simpleProc"`,
+					`observeQuery(ctx, activerecord.QueryLabels{Storage: "octopus", Entity: "Foo", Namespace: procName, Method: "call_proc"}, started, err)`,
+					`func HealthState() activerecord.HealthState {`,
+					`if _, err := octopus.Box(ctx, 0, activerecord.ReplicaOrMasterInstanceType, "arcfg", nil); err != nil {`,
 					`type Foo struct {`,
 					`type FooParams struct {`,
 					`package ` + packageName,
@@ -260,10 +413,10 @@ func TestGenerateOctopus(t *testing.T) {
 							Unmarshaler: "OutputUnmarshal",
 						},
 					},
-					Imports:  []ds.ImportDeclaration{},
+					Imports:  []ds.ImportDeclaration{{Path: "github.com/mailru/activerecord/pkg/serializer", ImportName: "serializerOutput"}},
 					Triggers: map[string]ds.TriggerDeclaration{},
 					Flags:    map[string]ds.FlagDeclaration{},
-					AppInfo:  "",
+					AppInfo:  ds.AppInfo{},
 				},
 			},
 			wantStr: map[string][]string{
@@ -294,11 +447,82 @@ func TestGenerateOctopus(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "compositePk",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      packageName,
+					ARPkgTitle: "Foo",
+					Indexes: []ds.IndexDeclaration{
+						{
+							Name:     "AccountShard",
+							Num:      0,
+							Selector: "SelectByAccountShard",
+							Fields:   []int{0, 1},
+							FieldsMap: map[string]ds.IndexField{
+								"AccountID": {IndField: 0, Order: 0},
+								"Shard":     {IndField: 1, Order: 0},
+							},
+							Primary: true,
+							Unique:  true,
+							Type:    "AccountShardIndexType",
+						},
+					},
+					FieldList: []ds.FieldDeclaration{
+						{Name: "AccountID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+						{Name: "Shard", Format: "int32", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+						{Name: "Name", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+						{Name: "Version", Format: "uint32", Mutators: []string{}, Serializer: []string{}, Version: "foo_version"},
+						{Name: "DeletedAt", Format: "uint32", Mutators: []string{}, Serializer: []string{}},
+					},
+					SoftDelete:  "DeletedAt",
+					FieldObject: map[string]ds.FieldObject{},
+					Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+					Container:   ds.NamespaceDeclaration{ObjectName: namespaceStr, PublicName: "Foo", PackageName: packageName},
+					Serializers: map[string]ds.SerializerDeclaration{},
+					Mutators:    map[string]ds.MutatorDeclaration{},
+					Imports:     []ds.ImportDeclaration{},
+				},
+			},
+			wantStr: map[string][]string{
+				"octopus": {
+					`type AccountShardIndexType struct {`,
+					`func (obj *Foo) Primary() AccountShardIndexType {`,
+					`func SelectByPrimaryFields(ctx context.Context, AccountID int64, Shard int32) (*Foo, error) {`,
+					`return SelectByPrimary(ctx, AccountShardIndexType{`,
+					`keysField = append(keysField, iproto.PackUint64([]byte{}, uint64(key.AccountID), iproto.ModeDefault))`,
+					`keysField = append(keysField, iproto.PackUint32([]byte{}, uint32(key.Shard), iproto.ModeDefault))`,
+					`data, err = packAccountID([]byte{}, obj.GetAccountID())`,
+					`data, err = packShard([]byte{}, obj.GetShard())`,
+					`func Upsert(ctx context.Context, obj *Foo) error {`,
+					"// Выполняется одним запросом insert с флагом замены без предварительной выборки, как InsertOrReplace\nfunc Upsert(",
+					`func InsertBatch(ctx context.Context, records []*Foo) error {`,
+					`args := []string{strconv.FormatUint(uint64(namespace), 10), strconv.Itoa(3), string(versionPacked)}`,
+					`data, err = packVersion([]byte{}, version+1)`,
+					`tuples, err := octopus.CallLua(ctx, connection, "foo_version", args...)`,
+					`return &activerecord.VersionConflictError{Entity: "Foo", PK: obj.primaryLog(), Version: version}`,
+					`err := obj.Insert(ctx)`,
+					`func SelectByAccountShardsWithDeleted(ctx context.Context, keys []AccountShardIndexType) ([]*Foo, error) {`,
+					`func SelectByAccountShardWithDeleted(ctx context.Context, key AccountShardIndexType) (*Foo, error) {`,
+					`func SelectByPrimaryKeys(ctx context.Context, keys []FooPrimaryKey) ([]*Foo, error) {`,
+					`pks = append(pks, AccountShardIndexType(key))`,
+					`return SelectByAccountShards(ctx, pks)`,
+					`return notDeleted(res), nil`,
+					`if err := obj.SetDeletedAt(uint32(time.Now().Unix())); err != nil {`,
+					`func (obj *Foo) HardDelete(ctx context.Context) error {`,
+					`func ExistsByAccountShard(ctx context.Context, key AccountShardIndexType) (bool, error) {`,
+					`return selected != nil, nil`,
+					`func Ping(ctx context.Context) error {`,
+					`octopus.PackSelect(namespace, 0, 0, 0, [][][]byte{})`,
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			ret, got := GenerateOctopus(tt.args.params)
+			ret, got := GenerateOctopus(tt.args.params, Options{})
 			if got != tt.want {
 				t.Errorf("GenerateOctopus() = %v, want %v", got, tt.want)
 			}
@@ -319,3 +543,590 @@ func TestGenerateOctopus(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateOctopusTriggerOrder(t *testing.T) {
+	hooks := "github.com/mailru/activerecord/internal/pkg/hooks"
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Name", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{{Path: hooks, ImportName: "triggerHooks"}},
+		Triggers: map[string]ds.TriggerDeclaration{
+			"Normalize": {Name: "Normalize", Func: "Normalize", ImportName: "triggerHooks", Pkg: hooks, Phase: "BeforeInsert", Priority: 2},
+			"Validate":  {Name: "Validate", Func: "Validate", ImportName: "triggerHooks", Pkg: hooks, Phase: "BeforeInsert", Priority: 1},
+			"Audit":     {Name: "Audit", Func: "Audit", ImportName: "triggerHooks", Pkg: hooks, Phase: "BeforeInsert", Priority: 2},
+		},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	wantOrder := []string{
+		`triggerHooks.Validate(ctx, obj)`,
+		`triggerHooks.Audit(ctx, obj)`,
+		`triggerHooks.Normalize(ctx, obj)`,
+		`obj.doInsertReplace(ctx, insertMode)`,
+	}
+
+	prev := -1
+
+	for _, call := range wantOrder {
+		pos := strings.Index(buff.String(), call)
+		if pos <= prev {
+			t.Errorf("GenerateOctopus() call %s at %d, want after %d", call, pos, prev)
+		}
+
+		prev = pos
+	}
+
+	for _, want := range []string{
+		`normalized bool`,
+		"if obj.normalized {\n\t\tobj.normalized = false\n\n\t\treturn nil\n\t}",
+		"if err := obj.beforeInsert(ctx); err != nil {\n\t\treturn nil, err\n\t}\n\n\tobj.normalized = true",
+		"obj.normalized = false\n\tobj.fieldName = Name",
+	} {
+		if !strings.Contains(buff.String(), want) {
+			t.Errorf("GenerateOctopus() = %v, want %v", buff.String(), want)
+		}
+	}
+}
+
+func TestGenerateOctopusFlags(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Status", Format: "uint32", Mutators: []string{ds.SetBitMutator, ds.ClearBitMutator}, Serializer: []string{}},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{},
+		Triggers:    map[string]ds.TriggerDeclaration{},
+		Flags: map[string]ds.FlagDeclaration{
+			"Status": {
+				Name:      "Status",
+				Flags:     []string{"Active", "Closed", "Deleted"},
+				Bits:      []int{0, 1, 7},
+				Format:    "uint32",
+				Exclusive: [][]string{{"Active", "Closed"}},
+			},
+		},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	for _, want := range []string{
+		`StatusActiveFlag uint32 = 1 << 0`,
+		`StatusDeletedFlag uint32 = 1 << 7`,
+		`func (obj *Foo) HasStatusDeleted() bool {`,
+		`func (obj *Foo) SetStatusDeleted() error {`,
+		`func (obj *Foo) ClearStatusDeleted() error {`,
+		`func (obj *Foo) FlagsString() string {`,
+		`names = append(names, "Status.Deleted")`,
+		`if err := obj.ClearBitStatus(StatusClosedFlag); err != nil {`,
+		`if mask := obj.GetStatus() & (StatusActiveFlag | StatusClosedFlag); mask&(mask-1) != 0 {`,
+		`return fmt.Errorf("%w: Status Active,Closed", activerecord.ErrExclusiveFlags)`,
+	} {
+		if !strings.Contains(buff.String(), want) {
+			t.Errorf("GenerateOctopus() generated code doesn't contain %s", want)
+		}
+	}
+}
+
+func TestGenerateOctopusExternalFormat(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "uuid.UUID"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "uuid.UUID", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Name", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}, Default: "now()"},
+			{Name: "Amount", Format: "decimal.Decimal", Mutators: []string{}, Serializer: []string{}, Default: "1.5"},
+			{Name: "Status", Format: "string", Mutators: []string{}, Serializer: []string{}, Enum: []ds.EnumValue{{Name: "New", Value: "new"}, {Name: "Done", Value: "closed"}}, Default: "new"},
+			{Name: "Price", Format: "int64", Mutators: []string{}, Serializer: []string{}, TypeOverride: ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Cents", ImportName: "domain"}},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{{Path: "github.com/google/uuid"}, {Path: "github.com/shopspring/decimal"}, {Path: "github.com/foo/domain"}},
+		Triggers:    map[string]ds.TriggerDeclaration{},
+		Flags:       map[string]ds.FlagDeclaration{},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	for _, want := range []string{
+		`"github.com/google/uuid"`,
+		`func UnpackID(r *bytes.Reader) (ret uuid.UUID, errRet error) {`,
+		`err := octopus.UnpackUUID(r, &ID, iproto.ModeDefault)`,
+		`return octopus.PackUUID(w, pvar, iproto.ModeDefault), nil`,
+		`func SelectByID(ctx context.Context, key uuid.UUID) (*Foo, error) {`,
+		`obj.GetID().String(),`,
+		`func UnpackAmount(r *bytes.Reader) (ret decimal.Decimal, errRet error) {`,
+		`err := octopus.UnpackDecimal(r, &Amount, iproto.ModeDefault)`,
+		`return octopus.PackDecimal(w, pvar, iproto.ModeDefault), nil`,
+		`obj.applyDefaults()`,
+		`if obj.fieldName == "" {`,
+		`obj.fieldName = time.Now().Format(time.RFC3339)`,
+		`if obj.fieldAmount.IsZero() {`,
+		`obj.fieldAmount = decimal.RequireFromString("1.5")`,
+		`func NewFoo(ctx context.Context, ID uuid.UUID, Price domain.Cents) (*Foo, error) {`,
+		`if err := obj.SetPrice(Price); err != nil {`,
+		`type StatusEnum string`,
+		`StatusDone StatusEnum = "closed"`,
+		`func (e StatusEnum) Valid() bool {`,
+		`func UnpackStatus(r *bytes.Reader) (ret StatusEnum, errRet error) {`,
+		`return nil, &activerecord.EnumValueError{Entity: "Foo", Field: "Status", Value: Status}`,
+		`obj.fieldStatus = StatusNew`,
+		`"github.com/foo/domain"`,
+		`func (obj *Foo) GetPrice() domain.Cents {`,
+		`func (obj *Foo) SetPrice(Price domain.Cents) error {`,
+		`(int64(Price))`,
+		`svar := domain.Cents(bvar)`,
+		`var _ = (*int64)((*domain.Cents)(nil))`,
+	} {
+		if !strings.Contains(buff.String(), want) {
+			t.Errorf("GenerateOctopus() generated code doesn't contain %s", want)
+		}
+	}
+}
+
+func TestGenerateOctopusPreload(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "OwnerID", Format: "int64", Mutators: []string{}, Serializer: []string{}, ObjectLink: "Owner"},
+		},
+		FieldMap: map[string]int{"ID": 0, "OwnerID": 1},
+		FieldObject: map[string]ds.FieldObject{
+			"Owner": {Name: "Owner", Key: "ID", ObjectName: "user", Field: "OwnerID", Unique: true},
+			"Items": {Name: "Items", Key: "FooID", ObjectName: "item", Field: "ID", Unique: false},
+		},
+		LinkedObject: map[string]ds.RecordPackage{
+			"user": {Namespace: ds.NamespaceDeclaration{PublicName: "User", PackageName: "user"}},
+			"item": {Namespace: ds.NamespaceDeclaration{PublicName: "Item", PackageName: "item"}},
+		},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{},
+		Triggers:    map[string]ds.TriggerDeclaration{},
+		Flags:       map[string]ds.FlagDeclaration{},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	for _, want := range []string{
+		`func PreloadOwner(ctx context.Context, records []*Foo) error {`,
+		`key := r.GetOwnerID()`,
+		`linked, err := user.SelectByIDs(ctx, keys)`,
+		`linkedMap := make(map[int64]*user.User, len(linked))`,
+		`r.BaseField.Objects["Owner"] = []octopus.ModelStruct{linkedMap[r.GetOwnerID()]}`,
+		`func PreloadItems(ctx context.Context, records []*Foo) error {`,
+		`page, err := item.SelectByFooIDs(ctx, keys, activerecord.NewLimitOffset(pageSize, offset))`,
+		`if len(page) < int(pageSize) {`,
+		`linkedMap[l.GetFooID()] = append(linkedMap[l.GetFooID()], l)`,
+		`r.BaseField.Objects["Items"] = linkedMap[r.GetID()]`,
+	} {
+		if !strings.Contains(buff.String(), want) {
+			t.Errorf("GenerateOctopus() generated code doesn't contain %s", want)
+		}
+	}
+}
+
+func TestGenerateOctopusTouch(t *testing.T) {
+	hooks := "github.com/mailru/activerecord/internal/pkg/hooks"
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Name", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Version", Format: "uint32", Mutators: []string{}, Serializer: []string{}, Version: "ver_replace"},
+			{Name: "UpdatedAt", Format: "uint32", Mutators: []string{}, Serializer: []string{}, Touch: true},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{{Path: hooks, ImportName: "triggerHooks"}},
+		Triggers: map[string]ds.TriggerDeclaration{
+			"AfterUpdate": {Name: "AfterUpdate", Func: "AfterUpdate", ImportName: "triggerHooks", Pkg: hooks, Phase: "AfterUpdate"},
+		},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	data := buff.String()
+
+	for _, want := range []string{
+		"func Touch(ctx context.Context, key FooPrimaryKey) error {",
+		"return fmt.Errorf(\"can't touch not exists object: %w\", activerecord.ErrNotFound)",
+		"triggerHooks.AfterUpdate(ctx, touched)",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("GenerateOctopus() Touch not contains %s", want)
+		}
+	}
+
+	// В Touch изменяются только поле версии и поля с тегом touch
+	_, ops, found := strings.Cut(data, "func touchOps() ([]octopus.Ops, error) {")
+	if !found {
+		t.Fatalf("GenerateOctopus() touchOps not generated")
+	}
+
+	ops, _, _ = strings.Cut(ops, "\n}\n")
+
+	for _, want := range []string{
+		"octopus.Ops{Field: 2, Op: octopus.OpAdd, Value: iproto.PackUint32([]byte{}, 1, iproto.ModeDefault)}",
+		"packUpdatedAt([]byte{}, uint32(time.Now().Unix()))",
+		"octopus.Ops{Field: 3, Op: octopus.OpSet, Value: dataUpdatedAt}",
+	} {
+		if !strings.Contains(ops, want) {
+			t.Errorf("GenerateOctopus() touchOps = %v, want %s", ops, want)
+		}
+	}
+
+	if cnt := strings.Count(ops, "octopus.Ops{Field:"); cnt != 2 {
+		t.Errorf("GenerateOctopus() touchOps has %d ops, want 2", cnt)
+	}
+
+	// Приращение версии упаковывается по формату поля версии
+	versionOps := []struct {
+		format octopus.Format
+		want   string
+	}{
+		{format: octopus.Uint64, want: "octopus.Ops{Field: 2, Op: octopus.OpAdd, Value: iproto.PackUint64([]byte{}, 1, iproto.ModeDefault)}"},
+		{format: octopus.Int64, want: "octopus.Ops{Field: 2, Op: octopus.OpAdd, Value: iproto.PackUint64([]byte{}, uint64(1), iproto.ModeDefault)}"},
+		{format: octopus.Int, want: "octopus.Ops{Field: 2, Op: octopus.OpAdd, Value: iproto.PackUint32([]byte{}, uint32(1), iproto.ModeDefault)}"},
+	}
+
+	for _, tt := range versionOps {
+		params.FieldList[2].Format = tt.format
+
+		ret, got = GenerateOctopus(params, Options{})
+		if got != nil {
+			t.Fatalf("GenerateOctopus() version %s = %v", tt.format, got)
+		}
+
+		buff = ret["octopus"]
+		if data = buff.String(); !strings.Contains(data, tt.want) {
+			t.Errorf("GenerateOctopus() version %s touchOps not contains %s", tt.format, tt.want)
+		}
+	}
+}
+
+func TestGenerateOctopusAuditRepaired(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Name", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{},
+		Triggers:    map[string]ds.TriggerDeclaration{},
+		Audit:       true,
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff, ex := ret["octopus"]
+	if !ex {
+		t.Fatalf("GenerateOctopus() octopus not generated")
+	}
+
+	_, update, found := strings.Cut(buff.String(), "func (obj *Foo) updateInBox(ctx context.Context) error {")
+	if !found {
+		t.Fatalf("GenerateOctopus() updateInBox not generated")
+	}
+
+	update, _, _ = strings.Cut(update, "\n}\n")
+
+	// Записи аудита формируются до Replace и отправляются только после его успешного выполнения
+	want := "\tif obj.BaseField.Repaired {\n" +
+		"\t\tmetricStatCnt.Inc(ctx, \"update_repaired\", 1)\n" +
+		"\t\tlogger.Debug(ctx, \"\", obj.primaryLog(), \"Flag 'Repaired' is true! Insert instead Update\")\n\n" +
+		"\t\tif err := obj.Replace(ctx); err != nil {\n\t\t\treturn err\n\t\t}\n\n" +
+		"\t\tactiverecord.Audit(ctx, audit)\n\n" +
+		"\t\treturn nil\n\t}"
+	if !strings.Contains(update, want) {
+		t.Errorf("GenerateOctopus() updateInBox = %v, want %s", update, want)
+	}
+
+	if strings.Index(update, "audit := obj.auditRecords(ctx)") > strings.Index(update, "if obj.BaseField.Repaired {") {
+		t.Errorf("GenerateOctopus() audit records are computed after Repaired branch")
+	}
+
+	if cnt := strings.Count(update, "activerecord.Audit(ctx, audit)"); cnt != 2 {
+		t.Errorf("GenerateOctopus() updateInBox emits audit %d times, want 2", cnt)
+	}
+}
+
+func TestGenerateOctopusSoftDelete(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+			{Name: "Owner", Num: 1, Selector: "SelectByOwner", Fields: []int{1}, Type: "int64"},
+			{Name: "Code", Num: 2, Selector: "SelectByCode", Fields: []int{2}, Unique: true, Type: "string"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Owner", Format: "int64", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Code", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+			{Name: "DeletedAt", Format: "uint32", Mutators: []string{}, Serializer: []string{}},
+		},
+		SoftDelete:  "DeletedAt",
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{},
+		Mutators:    map[string]ds.MutatorDeclaration{},
+		Imports:     []ds.ImportDeclaration{},
+	}
+
+	ret, got := GenerateOctopus(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateOctopus() = %v", got)
+	}
+
+	buff := ret["octopus"]
+	data := buff.String()
+
+	// Каждая выборка, на которую опирается функция, не возвращает записи, помеченные удалёнными
+	tests := []struct {
+		name    string
+		fn      string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "delete by primary list",
+			fn:      "func DeleteByPrimaryList(ctx context.Context, keys []int64) (int, []int64, error) {",
+			want:    []string{"selected, err := SelectByIDs(ctx, keys)", "err := obj.Delete(ctx)", "failed = append(failed, obj.Primary())"},
+			notWant: []string{"deleteBox(", "HardDelete"},
+		},
+		{
+			name: "deleted tuple",
+			fn:   "func tupleDeleted(tuple octopus.TupleData) (bool, error) {",
+			want: []string{"if tuple.Cnt <= 3 {", "val, err := UnpackDeletedAt(bytes.NewReader(tuple.Data[3]))", "return val != 0, nil"},
+		},
+		{
+			name: "count",
+			fn:   "func SelectByOwnerCount(ctx context.Context, key int64) (uint32, error) {",
+			want: []string{"deleted, err := tupleDeleted(tuple)\n\t\tif err != nil || deleted {\n\t\t\treturn err\n\t\t}"},
+		},
+		{
+			name: "count by field",
+			fn:   "func CountByOwner(ctx context.Context, maxKeys ...int) (map[int64]uint64, error) {",
+			want: []string{"if deleted, err := tupleDeleted(tuple); err != nil || deleted {"},
+		},
+		{
+			name: "reconcile",
+			fn:   "func Reconcile(ctx context.Context, desired []*Foo) (created, updated, deleted int, err error) {",
+			want: []string{"if deleted, err := tupleDeleted(tuple); err != nil || deleted {"},
+		},
+		{
+			name: "get or create",
+			fn:   "func GetOrCreateByCode(ctx context.Context, key string, newRecord *Foo) (*Foo, bool, error) {",
+			want: []string{
+				"selected, err := SelectByCodeWithDeleted(ctx, key)",
+				"selected, err = SelectByCodeWithDeleted(ctx, key)",
+				"if selected != nil && selected.GetDeletedAt() != 0 {",
+				"if selected.GetDeletedAt() != 0 {",
+			},
+		},
+		{
+			name: "update by index",
+			fn:   "func UpdateByOwner(ctx context.Context, key int64, ops FooUpdateOps) (int, error) {",
+			want: []string{"selected, err := SelectByOwner(ctx, key, activerecord.EmptyLimiter())"},
+		},
+		{
+			name: "select by primary keys",
+			fn:   "func SelectByPrimaryKeys(ctx context.Context, keys []FooPrimaryKey) ([]*Foo, error) {",
+			want: []string{"return SelectByIDs(ctx, pks)"},
+		},
+		{
+			name:    "delete by index",
+			fn:      "func DeleteByOwner(ctx context.Context, key int64) (int, error) {",
+			want:    []string{"selected, err := SelectByOwner(ctx, key, activerecord.EmptyLimiter())", "err := obj.Delete(ctx)"},
+			notWant: []string{"WithDeleted", "HardDelete"},
+		},
+		{
+			name: "select list",
+			fn:   "func SelectByIDs(ctx context.Context, keys []int64) ([]*Foo, error) {",
+			want: []string{"res, err := SelectByIDsWithDeleted(ctx, keys)", "return notDeleted(res), nil"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, body, found := strings.Cut(data, tt.fn)
+			if !found {
+				t.Fatalf("GenerateOctopus() %s not generated", tt.fn)
+			}
+
+			body, _, _ = strings.Cut(body, "\n}\n")
+
+			for _, want := range tt.want {
+				if !strings.Contains(body, want) {
+					t.Errorf("GenerateOctopus() %s = %v, want %s", tt.fn, body, want)
+				}
+			}
+
+			for _, notWant := range tt.notWant {
+				if strings.Contains(body, notWant) {
+					t.Errorf("GenerateOctopus() %s = %v, not want %s", tt.fn, body, notWant)
+				}
+			}
+		})
+	}
+}
+
+// binaryRoundTripParams модель с полями всех форматов для проверки MarshalBinary и UnmarshalBinary
+// тестом testdata/binary_roundtrip_test.go. Поле Nick может хранить NULL только в tarantool2
+func binaryRoundTripParams(nullable bool) PkgData {
+	return PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Primary: true, Unique: true, Type: "int64"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Ref", Format: "uuid.UUID", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Amount", Format: "decimal.Decimal", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnix, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Updated", Format: "time.Time", Timestamp: ds.TimestampUnixMs, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Expires", Format: "time.Time", Timestamp: ds.TimestampRFC3339, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Nick", Format: "string", Nullable: nullable, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Status", Format: "string", Enum: []ds.EnumValue{{Name: "New", Value: "new"}, {Name: "Done", Value: "closed"}}, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Tags", Format: "string", Mutators: []string{}, Serializer: []string{"TagsJSON"}},
+			{Name: "Score", Format: "float64", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Active", Format: "bool", Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldObject: map[string]ds.FieldObject{},
+		Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11011"},
+		Container:   ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Serializers: map[string]ds.SerializerDeclaration{
+			"TagsJSON": {Name: "TagsJSON", Pkg: "github.com/mailru/activerecord/pkg/serializer", Type: "[]string", ImportName: "serializerTagsJSON", Marshaler: "JSONMarshal", Unmarshaler: "JSONUnmarshal"},
+		},
+		Mutators: map[string]ds.MutatorDeclaration{},
+		Imports: []ds.ImportDeclaration{
+			{Path: "github.com/google/uuid"},
+			{Path: "github.com/shopspring/decimal"},
+			{Path: "time"},
+			{Path: "github.com/mailru/activerecord/pkg/serializer", ImportName: "serializerTagsJSON"},
+		},
+		Triggers: map[string]ds.TriggerDeclaration{},
+		Flags:    map[string]ds.FlagDeclaration{},
+	}
+}
+
+func TestGenerateBinaryRoundTrip(t *testing.T) {
+	if testing.Short() {
+		t.Skip("test of generated package is skipped in short mode")
+	}
+
+	tests := []struct {
+		name     string
+		generate func(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases)
+		nullable bool
+	}{
+		{name: "octopus", generate: GenerateOctopus},
+		{name: "tarantool2", generate: GenerateTarantool2, nullable: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, err := tt.generate(binaryRoundTripParams(tt.nullable), Options{})
+			if err != nil {
+				t.Fatalf("generate error = %v", err)
+			}
+
+			goTestGenerated(t, ret, "binary_roundtrip_test.go", "-run", "TestBinaryRoundTrip", "-v")
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/iproto/util/text"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+const protoDisclaimer = `// Code generated by argen. DO NOT EDIT.
+// This proto was generated from the model declaration.
+`
+
+const protoTimestamp = "google.protobuf.Timestamp"
+
+// protoTypes соответствие формата поля и типа go скалярному типу protobuf
+var protoTypes = map[string]string{
+	"int8":            "int32",
+	"int16":           "int32",
+	"int32":           "int32",
+	"int64":           "int64",
+	"int":             "int64",
+	"uint8":           "uint32",
+	"uint16":          "uint32",
+	"uint32":          "uint32",
+	"uint64":          "uint64",
+	"uint":            "uint64",
+	"bool":            "bool",
+	"float32":         "float",
+	"float64":         "double",
+	"string":          "string",
+	"[]byte":          "bytes",
+	"uuid.UUID":       "string",
+	"decimal.Decimal": "string",
+	"time.Time":       protoTimestamp,
+}
+
+// protoFile описание генерируемого proto-файла
+type protoFile struct {
+	cl       ds.RecordPackage
+	imports  map[string]bool
+	enums    []string
+	messages []string
+	nested   map[string]string // Имя вложенного сообщения -> тип go структуры
+	named    map[string]string // Именованный тип поля модели -> тип protobuf
+}
+
+// newProtoFile описание proto-файла модели. Именованные типы полей модели (перечисления и пользовательские типы)
+// описываются в protobuf перечислением и типом базового формата соответственно
+func newProtoFile(cl ds.RecordPackage) *protoFile {
+	pf := &protoFile{cl: cl, imports: map[string]bool{}, nested: map[string]string{}, named: map[string]string{}}
+
+	for _, fld := range cl.Fields {
+		switch {
+		case len(fld.Enum) != 0:
+			pf.named[fld.EnumType()] = pf.enumName(fld)
+		case !fld.TypeOverride.Empty():
+			if typ, ok := protoTypes[string(fld.Format)]; ok {
+				pf.named[fld.NamedType()] = typ
+			}
+		}
+	}
+
+	return pf
+}
+
+// GenerateProto генерирует описание модели в формате protobuf (proto3): сообщение с именем модели,
+// поля которого соответствуют полям декларации. Номер поля в сообщении равен позиции поля в декларации,
+// поэтому при добавлении полей в конец декларации описание остаётся совместимым. Для полей-перечислений
+// формируются enum, для сериализованных полей, структура которых известна из декларации импорта,
+// вложенные сообщения, остальные сериализованные поля описываются как bytes.
+// Для процедур описание не генерируется и возвращается false
+func GenerateProto(cl ds.RecordPackage) (GenerateFile, bool, error) {
+	if len(cl.Fields) == 0 {
+		return GenerateFile{}, false, nil
+	}
+
+	filename := cl.Namespace.PackageName + ".proto"
+	pf := newProtoFile(cl)
+
+	fields := make([]string, 0, len(cl.Fields))
+
+	for _, fld := range cl.Fields {
+		field, err := pf.field(fld)
+		if err != nil {
+			return GenerateFile{}, false, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Filename: filename, Err: err}
+		}
+
+		fields = append(fields, field)
+	}
+
+	proto := strings.Builder{}
+
+	proto.WriteString(protoDisclaimer)
+	proto.WriteString("\nsyntax = \"proto3\";\n\n")
+	fmt.Fprintf(&proto, "package %s;\n", cl.Namespace.PackageName)
+
+	if cl.ProtoPkg != "" {
+		fmt.Fprintf(&proto, "\noption go_package = %q;\n", cl.ProtoPkg)
+	}
+
+	if len(pf.imports) != 0 {
+		proto.WriteString("\n")
+
+		for _, imp := range sortedKeys(pf.imports) {
+			fmt.Fprintf(&proto, "import %q;\n", imp)
+		}
+	}
+
+	for _, enum := range pf.enums {
+		proto.WriteString("\n" + enum)
+	}
+
+	fmt.Fprintf(&proto, "\nmessage %s {\n%s}\n", cl.Namespace.PublicName, strings.Join(fields, ""))
+
+	for _, msg := range pf.messages {
+		proto.WriteString("\n" + msg)
+	}
+
+	return GenerateFile{
+		Data: []byte(proto.String()),
+		Name: filename,
+		Dir:  cl.Namespace.PackageName,
+	}, true, nil
+}
+
+// field описание поля модели в сообщении
+func (pf *protoFile) field(fld ds.FieldDeclaration) (string, error) {
+	num := pf.cl.FieldsMap[fld.Name] + 1
+	name := text.ToSnakeCase(fld.Name)
+
+	var (
+		typ string
+		err error
+	)
+
+	switch {
+	case len(fld.Enum) != 0:
+		typ = pf.enum(fld)
+	case len(fld.Serializer) != 0:
+		typ, err = pf.serialized(fld)
+	case fld.Format == octopus.StringArray:
+		return fmt.Sprintf("\trepeated string %s = %d;\n", name, num), nil
+	default:
+		typ, err = pf.scalar(string(fld.Format))
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("%w: field `%s` has format `%s`", err, fld.Name, fld.Format)
+	}
+
+	label := ""
+
+	switch {
+	case fld.Array:
+		label = "repeated "
+	case fld.Nullable:
+		label = "optional "
+	}
+
+	return fmt.Sprintf("\t%s%s %s = %d;\n", label, typ, name, num), nil
+}
+
+// scalar тип protobuf для формата поля или типа go
+func (pf *protoFile) scalar(goType string) (string, error) {
+	typ, ok := protoTypes[goType]
+	if !ok {
+		return "", arerror.ErrGeneratorProtoFormat
+	}
+
+	if typ == protoTimestamp {
+		pf.imports["google/protobuf/timestamp.proto"] = true
+	}
+
+	return typ, nil
+}
+
+// enum описание перечисления для поля. Нулевое значение в proto3 обязательно,
+// поэтому оно зарезервировано под незаполненное значение, а значения декларации нумеруются с 1
+func (pf *protoFile) enum(fld ds.FieldDeclaration) string {
+	name := pf.enumName(fld)
+
+	enum := strings.Builder{}
+
+	fmt.Fprintf(&enum, "enum %s {\n\t%s = 0;\n", name, protoEnumValue(name, protoEnumUnspecified))
+
+	for num, v := range fld.Enum {
+		fmt.Fprintf(&enum, "\t%s = %d;\n", protoEnumValue(name, v.Name), num+1)
+	}
+
+	enum.WriteString("}\n")
+
+	pf.enums = append(pf.enums, enum.String())
+
+	return name
+}
+
+// enumName имя перечисления protobuf для поля модели
+func (pf *protoFile) enumName(fld ds.FieldDeclaration) string {
+	return pf.cl.Namespace.PublicName + fld.Name
+}
+
+// protoEnumUnspecified имя нулевого значения перечисления
+const protoEnumUnspecified = "Unspecified"
+
+// protoEnumValue имя значения перечисления protobuf. Значения перечислений proto3 находятся в области видимости пакета,
+// поэтому имя значения начинается с имени перечисления
+func protoEnumValue(enum, value string) string {
+	return strings.ToUpper(text.ToSnakeCase(enum)) + "_" + strings.ToUpper(text.ToSnakeCase(value))
+}
+
+// serialized тип сериализованного поля: вложенное сообщение, если структура типа сериализатора
+// описана в декларации импорта, иначе значение в формате хранения
+func (pf *protoFile) serialized(fld ds.FieldDeclaration) (string, error) {
+	serializer := pf.cl.SerializerMap[fld.Serializer[0]]
+
+	if msg, ok := pf.message(strings.TrimPrefix(serializer.Type, "*")); ok {
+		return msg, nil
+	}
+
+	return "bytes", nil
+}
+
+// message вложенное сообщение для структуры goType, описанной в декларации импорта.
+// Поля сообщения нумеруются по порядку полей структуры
+func (pf *protoFile) message(goType string) (string, bool) {
+	partial, ok := pf.cl.ImportStructFieldsMap[goType]
+	if !ok {
+		return "", false
+	}
+
+	name := goType[strings.LastIndex(goType, ".")+1:]
+	if _, ok := pf.nested[name]; ok {
+		return name, true
+	}
+
+	pf.nested[name] = goType
+
+	msg := strings.Builder{}
+
+	fmt.Fprintf(&msg, "message %s {\n", name)
+
+	for num, fld := range partial {
+		label, typ := pf.partialType(fld.Type)
+		fmt.Fprintf(&msg, "\t%s%s %s = %d;\n", label, typ, text.ToSnakeCase(fld.Name), num+1)
+	}
+
+	msg.WriteString("}\n")
+
+	pf.messages = append(pf.messages, msg.String())
+
+	return name, true
+}
+
+// partialType тип поля вложенного сообщения. Указатели становятся optional, срезы - repeated,
+// map со скалярными ключами - map, вложенные структуры из декларации импорта - сообщениями.
+// Остальные типы и сочетания, которые нельзя описать в protobuf (срез срезов, map срезов), описываются как bytes
+func (pf *protoFile) partialType(goType string) (string, string) {
+	switch {
+	case goType == "[]byte":
+	case strings.HasPrefix(goType, "*"):
+		label, typ := pf.partialType(goType[1:])
+		if label != "" {
+			return label, typ
+		}
+
+		return "optional ", typ
+	case strings.HasPrefix(goType, "[]"):
+		label, typ := pf.partialType(goType[2:])
+		if label != "" || strings.HasPrefix(typ, "map<") {
+			return "", "bytes"
+		}
+
+		return "repeated ", typ
+	case strings.HasPrefix(goType, "map["):
+		key, val, _ := strings.Cut(goType[4:], "]")
+
+		keyType, err := pf.scalar(key)
+		if err != nil || keyType == protoTimestamp || keyType == "float" || keyType == "double" || keyType == "bytes" {
+			return "", "bytes"
+		}
+
+		label, valType := pf.partialType(val)
+		if label == "repeated " || strings.HasPrefix(valType, "map<") {
+			return "", "bytes"
+		}
+
+		return "", "map<" + keyType + ", " + valType + ">"
+	}
+
+	if typ, ok := pf.named[goType]; ok {
+		return "", typ
+	}
+
+	if typ, err := pf.scalar(goType); err == nil {
+		return "", typ
+	}
+
+	if msg, ok := pf.message(goType); ok {
+		return "", msg
+	}
+
+	return "", "bytes"
+}
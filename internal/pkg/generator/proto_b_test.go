@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+func TestGenerateProto(t *testing.T) {
+	namespace := ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantOk  bool
+		want    string
+		wantErr error
+	}{
+		{
+			name: "message",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				ProtoPkg:  "example.com/pb/foopb",
+				Fields: []ds.FieldDeclaration{
+					{Name: "ID", Format: "int64", PrimaryKey: true},
+					{Name: "UserName", Format: "string", Size: 32, StorageName: "uname"},
+					{Name: "Status", Format: "uint8", Enum: []ds.EnumValue{{Name: "Active", Value: "1"}, {Name: "Blocked", Value: "2"}}},
+					{Name: "Nick", Format: "string", Nullable: true},
+					{Name: "Labels", Format: "string", Array: true},
+					{Name: "Tags", Format: "[]string"},
+					{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnixMs},
+					{Name: "Token", Format: "uuid.UUID"},
+					{Name: "Profile", Format: "string", Serializer: []string{"Profile"}},
+					{Name: "Raw", Format: "string", Serializer: []string{"Raw"}},
+				},
+				FieldsMap: map[string]int{
+					"ID": 0, "UserName": 1, "uname": 1, "Status": 2, "Nick": 3, "Labels": 4,
+					"Tags": 5, "Created": 6, "Token": 7, "Profile": 8, "Raw": 9,
+				},
+				SerializerMap: map[string]ds.SerializerDeclaration{
+					"Profile": {Name: "Profile", Type: "*ds.Profile"},
+					"Raw":     {Name: "Raw", Type: "map[string]any"},
+				},
+				ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{
+					"ds.Profile": {{Name: "Age", Type: "int"}, {Name: "Emails", Type: "[]string"}, {Name: "Attrs", Type: "map[string]ds.Attr"}, {Name: "Grid", Type: "[][]int"}},
+					"ds.Attr":    {{Name: "Value", Type: "*string"}},
+				},
+			},
+			wantOk: true,
+			want: `// Code generated by argen. DO NOT EDIT.
+// This proto was generated from the model declaration.
+
+syntax = "proto3";
+
+package foo;
+
+option go_package = "example.com/pb/foopb";
+
+import "google/protobuf/timestamp.proto";
+
+enum FooStatus {
+	FOO_STATUS_UNSPECIFIED = 0;
+	FOO_STATUS_ACTIVE = 1;
+	FOO_STATUS_BLOCKED = 2;
+}
+
+message Foo {
+	int64 id = 1;
+	string user_name = 2;
+	FooStatus status = 3;
+	optional string nick = 4;
+	repeated string labels = 5;
+	repeated string tags = 6;
+	google.protobuf.Timestamp created = 7;
+	string token = 8;
+	Profile profile = 9;
+	bytes raw = 10;
+}
+
+message Attr {
+	optional string value = 1;
+}
+
+message Profile {
+	int64 age = 1;
+	repeated string emails = 2;
+	map<string, Attr> attrs = 3;
+	bytes grid = 4;
+}
+`,
+		},
+		{
+			name: "procedure",
+			cl:   ds.RecordPackage{Namespace: namespace},
+		},
+		{
+			name: "unknown format",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				Fields:    []ds.FieldDeclaration{{Name: "ID", Format: "complex64"}},
+				FieldsMap: map[string]int{"ID": 0},
+			},
+			wantErr: arerror.ErrGeneratorProtoFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := GenerateProto(tt.cl)
+			if !errors.Is(err, tt.wantErr) {
+				var errFile *arerror.ErrGeneratorFile
+				if !errors.As(err, &errFile) || !errors.Is(errFile.Err, tt.wantErr) {
+					t.Fatalf("GenerateProto() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("GenerateProto() ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if !ok {
+				return
+			}
+
+			if got.Name != "foo.proto" || got.Dir != "foo" || got.Backend != "" {
+				t.Errorf("GenerateProto() file = %s/%s (%s), want foo/foo.proto", got.Dir, got.Name, got.Backend)
+			}
+
+			if string(got.Data) != tt.want {
+				t.Errorf("GenerateProto() = %s, want %s", got.Data, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,631 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/iproto/util/text"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+// protoConv построитель данных шаблона protoconv.tmpl: функций преобразования записи модели
+// в сообщение protobuf и обратно. Типы сообщений и полей соответствуют описанию, которое генерирует GenerateProto
+type protoConv struct {
+	pf    *protoFile
+	pb    string                         // Имя импорта пакета с сообщениями protobuf
+	enums map[string]ds.FieldDeclaration // Имя перечисления protobuf -> поле модели
+	base  map[string]string              // Пользовательский тип поля модели -> формат поля
+	vars  int
+}
+
+// ProtoConvData данные шаблона файла protoconv.go
+type ProtoConvData struct {
+	AppInfo  ds.AppInfo
+	Package  string
+	Name     string // Имя модели и сообщения protobuf
+	PB       string // Имя импорта пакета с сообщениями protobuf
+	ProtoPkg string
+	Imports  []ds.ImportDeclaration
+	To       []*protoStep // Заполнение полей сообщения в ToProto
+	From     []*protoStep // Установка полей записи в <Model>FromProto
+	Enums    []protoEnumConv
+	Messages []protoMessageConv
+}
+
+// protoEnumConv функции преобразования значений поля-перечисления
+type protoEnumConv struct {
+	Field       string // Имя поля модели
+	Type        string // Тип перечисления поля модели
+	Enum        string // Тип перечисления protobuf
+	Cases       []protoEnumCase
+	Unspecified string // Нулевое значение перечисления protobuf
+	Default     string // Значение поля для нулевого значения перечисления, если объявлено значение по умолчанию
+}
+
+// protoEnumCase соответствие значения перечисления поля модели значению перечисления protobuf
+type protoEnumCase struct {
+	Value string
+	Proto string
+}
+
+// protoMessageConv функции преобразования структуры, описанной в декларации импорта, во вложенное сообщение и обратно
+type protoMessageConv struct {
+	Name   string // Имя вложенного сообщения
+	GoType string
+	To     []*protoStep
+	From   []*protoStep
+}
+
+// Виды шагов преобразования значения, код каждого вида формирует шаблон protoconv.tmpl
+const (
+	protoStepAssign      = "assign"      // Присваивание выражения
+	protoStepCall        = "call"        // Вызов функции преобразования, которая возвращает ошибку
+	protoStepJSON        = "json"        // Разбор значения в формате JSON
+	protoStepPointer     = "pointer"     // Преобразование значения, на которое ссылается указатель
+	protoStepSlice       = "slice"       // Поэлементное преобразование среза
+	protoStepMap         = "map"         // Поэлементное преобразование словаря
+	protoStepSet         = "set"         // Установка поля записи сеттером
+	protoStepUnserialize = "unserialize" // Десериализация значения и установка сериализованного поля записи
+)
+
+// protoStep шаг преобразования значения между полем записи и полем сообщения protobuf
+type protoStep struct {
+	Kind  string
+	Dst   string     // Куда присваивается результат
+	Src   string     // Преобразуемое значение
+	Cond  string     // Условие, при котором выполняется присваивание или вызов
+	Var   string     // Локальная переменная с результатом вызова или со значением Src
+	Func  string     // Функция преобразования
+	Expr  string     // Присваиваемое выражение или аргументы функции десериализации
+	Type  string     // Тип создаваемого среза или словаря, тип десериализованного значения
+	Index string     // Переменная ключа в цикле по срезу или словарю
+	Item  string     // Переменная элемента в цикле по срезу или словарю
+	Key   protoValue // Ключ словаря
+	Value protoValue // Значение указателя, элемент среза или словаря, значение для сеттера
+	Elem  *protoStep // Преобразование значения указателя без временной переменной
+	Name  string     // Имя поля модели, которое устанавливается сеттером
+	Field string     // Поле в тексте ошибки преобразования
+	Zero  string     // Значение, которое возвращается вместе с ошибкой
+}
+
+// Block признак шага, код которого занимает несколько строк. Такие шаги отделяются пустыми строками
+func (s *protoStep) Block() bool {
+	return s.Kind != protoStepAssign || s.Cond != ""
+}
+
+// protoValue значение, которое использует шаг преобразования: выражение
+// или временная переменная, заполненная шагом Conv либо инициализированная выражением
+type protoValue struct {
+	Var  string
+	Type string
+	Expr string
+	Conv *protoStep
+}
+
+// Ref значение в коде
+func (v protoValue) Ref() string {
+	if v.Var != "" {
+		return v.Var
+	}
+
+	return v.Expr
+}
+
+// ProtoConvTmpl шаблон файла protoconv.go с функциями преобразования записи в сообщение protobuf и обратно
+//
+//go:embed tmpl/protoconv.tmpl
+var ProtoConvTmpl string
+
+// GenerateProtoConv генерирует файл protoconv.go с методом ToProto записи и функцией <Model>FromProto,
+// преобразующими запись в сообщение protobuf из пакета cl.ProtoPkg и обратно.
+// Файл генерируется только для моделей с объявленным пакетом protobuf, для остальных возвращается false
+func GenerateProtoConv(appInfo ds.AppInfo, cl ds.RecordPackage, opts Options) (string, bool, error) {
+	if cl.ProtoPkg == "" || len(cl.Fields) == 0 {
+		return "", false, nil
+	}
+
+	pc := protoConv{
+		pf:    newProtoFile(cl),
+		pb:    cl.Namespace.PackageName + "pb",
+		enums: map[string]ds.FieldDeclaration{},
+		base:  map[string]string{},
+	}
+
+	for _, fld := range cl.Fields {
+		if _, err := pc.pf.field(fld); err != nil {
+			return "", false, &arerror.ErrGeneratorFile{Name: cl.Namespace.PublicName, Filename: "protoconv.go", Err: err}
+		}
+
+		switch {
+		case len(fld.Enum) != 0:
+			pc.enums[pc.pf.enumName(fld)] = fld
+		case !fld.TypeOverride.Empty():
+			pc.base[fld.NamedType()] = string(fld.Format)
+		}
+	}
+
+	params := ProtoConvData{
+		AppInfo:  appInfo.WithSourceFile(cl.SourceFile),
+		Package:  cl.Namespace.PackageName,
+		Name:     cl.Namespace.PublicName,
+		PB:       pc.pb,
+		ProtoPkg: cl.ProtoPkg,
+	}
+
+	// Типы полей вложенных структур описаны с импортами файлов, в которых объявлены структуры.
+	// Неиспользуемые импорты удаляются при форматировании файла
+	imports := append([]ds.ImportDeclaration{}, cl.Imports...)
+	for _, pkg := range sortedKeys(cl.LinkedStructsMap) {
+		imports = append(imports, cl.LinkedStructsMap[pkg].Import.Imports...)
+	}
+
+	seen := map[string]bool{"time": true}
+
+	for _, imp := range imports {
+		if !seen[imp.Path] {
+			seen[imp.Path] = true
+			params.Imports = append(params.Imports, imp)
+		}
+	}
+
+	for _, fld := range cl.Fields {
+		params.To = append(params.To, pc.fieldToProto(fld))
+	}
+
+	for _, fld := range cl.Fields {
+		params.From = append(params.From, pc.fieldFromProto(fld))
+	}
+
+	for _, fld := range cl.Fields {
+		if len(fld.Enum) != 0 {
+			params.Enums = append(params.Enums, pc.enumConv(fld))
+		}
+	}
+
+	for _, msg := range sortedKeys(pc.pf.nested) {
+		params.Messages = append(params.Messages, pc.messageConv(msg, pc.pf.nested[msg]))
+	}
+
+	w := bytes.Buffer{}
+
+	if err := GenerateByTmpl(&w, params, "protoconv", opts.header(), opts.template("protoconv", ProtoConvTmpl)); err != nil {
+		err.Name = cl.Namespace.PublicName
+		return "", false, err
+	}
+
+	return w.String(), true, nil
+}
+
+// fieldToProto заполнение поля сообщения значением поля записи
+func (pc *protoConv) fieldToProto(fld ds.FieldDeclaration) *protoStep {
+	dst := "msg." + protoGoName(text.ToSnakeCase(fld.Name))
+	src := "obj.Get" + fld.Name + "()"
+	field := pc.pf.cl.Namespace.PublicName + "." + fld.Name
+
+	if goType, ok := pc.serializedMessage(fld); ok {
+		return pc.to(dst, src, goType, field, "nil")
+	}
+
+	if len(fld.Serializer) != 0 {
+		v := pc.newVar()
+		serializer := pc.pf.cl.SerializerMap[fld.Serializer.Name()]
+
+		return &protoStep{Kind: protoStepCall, Var: v, Func: serializer.MarshalFunc(), Src: fld.Serializer.Params() + src, Dst: dst, Expr: "[]byte(" + v + ")", Field: field, Zero: "nil"}
+	}
+
+	return pc.to(dst, src, pc.fieldType(fld), field, "nil")
+}
+
+// fieldFromProto установка значения поля записи из поля сообщения
+func (pc *protoConv) fieldFromProto(fld ds.FieldDeclaration) *protoStep {
+	src := "msg." + protoGoName(text.ToSnakeCase(fld.Name))
+	field := pc.pf.cl.Namespace.PublicName + "." + fld.Name
+
+	if goType, ok := pc.serializedMessage(fld); ok {
+		v := pc.newVar()
+
+		return &protoStep{Kind: protoStepSet, Name: fld.Name, Field: field, Value: protoValue{Var: v, Type: goType, Conv: pc.from(v, src, goType, field, "nil")}}
+	}
+
+	if len(fld.Serializer) != 0 {
+		v := pc.newVar()
+		serializer := pc.pf.cl.SerializerMap[fld.Serializer.Name()]
+
+		return &protoStep{
+			Kind:  protoStepUnserialize,
+			Src:   src,
+			Var:   v,
+			Type:  serializer.Type,
+			Func:  serializer.UnmarshalFunc(),
+			Expr:  fld.Serializer.Params() + string(fld.Format) + "(" + src + ")",
+			Name:  fld.Name,
+			Field: field,
+		}
+	}
+
+	return &protoStep{Kind: protoStepSet, Name: fld.Name, Field: field, Value: pc.fromValue(src, pc.fieldType(fld), field, "nil")}
+}
+
+// fieldType тип значения поля записи, которое возвращает геттер
+func (pc *protoConv) fieldType(fld ds.FieldDeclaration) string {
+	goType := string(fld.Format)
+	if fld.NamedType() != "" {
+		goType = fld.NamedType()
+	}
+
+	switch {
+	case fld.Nullable:
+		return "*" + goType
+	case fld.Array:
+		return "[]" + goType
+	}
+
+	return goType
+}
+
+// serializedMessage тип сериализованного поля, которое описано в protobuf вложенным сообщением
+func (pc *protoConv) serializedMessage(fld ds.FieldDeclaration) (string, bool) {
+	if len(fld.Serializer) == 0 {
+		return "", false
+	}
+
+	serializer := pc.pf.cl.SerializerMap[fld.Serializer.Name()]
+	if _, ok := pc.pf.cl.ImportStructFieldsMap[strings.TrimPrefix(serializer.Type, "*")]; !ok {
+		return "", false
+	}
+
+	return serializer.Type, true
+}
+
+// enumConv функции преобразования значений поля-перечисления. Нулевое значение перечисления protobuf
+// означает незаполненное поле и преобразуется в значение по умолчанию, если оно объявлено
+func (pc *protoConv) enumConv(fld ds.FieldDeclaration) protoEnumConv {
+	enum := pc.pf.enumName(fld)
+	value := func(v string) string {
+		return pc.pb + "." + enum + "_" + protoEnumValue(enum, v)
+	}
+
+	ret := protoEnumConv{
+		Field:       fld.Name,
+		Type:        fld.EnumType(),
+		Enum:        pc.pb + "." + enum,
+		Unspecified: value(protoEnumUnspecified),
+	}
+
+	for _, v := range fld.Enum {
+		ret.Cases = append(ret.Cases, protoEnumCase{Value: fld.Name + v.Name, Proto: value(v.Name)})
+	}
+
+	if def, err := fld.DefaultValue(); fld.Default != "" && err == nil {
+		ret.Default = def
+	}
+
+	return ret
+}
+
+// messageConv функции преобразования структуры, описанной в декларации импорта, во вложенное сообщение и обратно
+func (pc *protoConv) messageConv(msg, goType string) protoMessageConv {
+	ret := protoMessageConv{Name: msg, GoType: goType}
+	partial := pc.pf.cl.ImportStructFieldsMap[goType]
+
+	for _, fld := range partial {
+		ret.To = append(ret.To, pc.to("msg."+protoGoName(text.ToSnakeCase(fld.Name)), "v."+fld.Name, fld.Type, msg+"."+fld.Name, "nil"))
+	}
+
+	for _, fld := range partial {
+		ret.From = append(ret.From, pc.from("ret."+fld.Name, "msg."+protoGoName(text.ToSnakeCase(fld.Name)), fld.Type, msg+"."+fld.Name, "ret"))
+	}
+
+	return ret
+}
+
+// to присваивание dst значения src типа goType, преобразованного к типу поля сообщения.
+// Значения, которые описаны в protobuf как bytes, передаются в формате JSON
+func (pc *protoConv) to(dst, src, goType, field, zero string) *protoStep {
+	_, typ := pc.pf.partialType(goType)
+
+	switch {
+	case pc.isJSON(goType, typ):
+		v := pc.newVar()
+
+		return &protoStep{Kind: protoStepCall, Var: v, Func: "json.Marshal", Src: src, Dst: dst, Expr: v, Field: field, Zero: zero}
+	case goType == pc.goType(goType):
+		return &protoStep{Kind: protoStepAssign, Dst: dst, Expr: src}
+	case strings.HasPrefix(goType, "*"):
+		v := pc.newVar()
+		elem := goType[1:]
+		step := &protoStep{Kind: protoStepPointer, Var: v, Src: src, Dst: dst}
+
+		if pc.goType(elem) == pc.goType(goType) {
+			step.Elem = pc.to(dst, "*"+v, elem, field, zero)
+		} else {
+			step.Value = pc.toValue("*"+v, elem, field, zero)
+			if step.Value.Var == "" {
+				step.Value.Var = pc.newVar()
+			}
+		}
+
+		return step
+	case strings.HasPrefix(goType, "[]") && goType != "[]byte":
+		v, item := pc.newVar(), pc.newVar()
+
+		return &protoStep{Kind: protoStepSlice, Var: v, Src: src, Dst: dst, Type: pc.goType(goType), Index: "_", Item: item, Value: pc.toValue(item, goType[2:], field, zero)}
+	case strings.HasPrefix(goType, "map["):
+		v, key, item := pc.newVar(), pc.newVar(), pc.newVar()
+		keyType, elem, _ := strings.Cut(goType[4:], "]")
+		keyExpr, _ := pc.toExpr(key, keyType)
+
+		return &protoStep{Kind: protoStepMap, Var: v, Src: src, Dst: dst, Type: pc.goType(goType), Index: key, Item: item, Key: protoValue{Expr: keyExpr}, Value: pc.toValue(item, elem, field, zero)}
+	default:
+		if expr, ok := pc.toExpr(src, goType); ok {
+			return &protoStep{Kind: protoStepAssign, Dst: dst, Expr: expr}
+		}
+
+		// Вложенное сообщение
+		v := pc.newVar()
+
+		return &protoStep{Kind: protoStepCall, Var: v, Func: "toProto" + typ, Src: src, Dst: dst, Expr: v, Field: field, Zero: zero}
+	}
+}
+
+// from присваивание dst значения src поля сообщения, преобразованного к типу goType
+func (pc *protoConv) from(dst, src, goType, field, zero string) *protoStep {
+	_, typ := pc.pf.partialType(goType)
+
+	switch {
+	case pc.isJSON(goType, typ):
+		return &protoStep{Kind: protoStepJSON, Src: src, Dst: dst, Field: field, Zero: zero}
+	case goType == pc.goType(goType):
+		return &protoStep{Kind: protoStepAssign, Dst: dst, Expr: src}
+	case strings.HasPrefix(goType, "*"):
+		step := &protoStep{Kind: protoStepPointer, Src: src, Dst: dst}
+		elem := goType[1:]
+
+		if pc.goType(elem) != pc.goType(goType) {
+			src = "*" + src
+		}
+
+		step.Value = pc.fromValue(src, elem, field, zero)
+		if step.Value.Var == "" {
+			step.Value.Var = pc.newVar()
+		}
+
+		return step
+	case strings.HasPrefix(goType, "[]") && goType != "[]byte":
+		item := pc.newVar()
+
+		return &protoStep{Kind: protoStepSlice, Src: src, Dst: dst, Type: goType, Index: "_", Item: item, Value: pc.fromValue(item, goType[2:], field, zero)}
+	case strings.HasPrefix(goType, "map["):
+		key, item := pc.newVar(), pc.newVar()
+		keyType, elem, _ := strings.Cut(goType[4:], "]")
+		step := &protoStep{Kind: protoStepMap, Src: src, Dst: dst, Type: goType, Index: key, Item: item}
+
+		step.Key = pc.fromValue(key, keyType, field, zero)
+		step.Value = pc.fromValue(item, elem, field, zero)
+
+		return step
+	default:
+		if expr, ok := pc.fromExpr(src, goType); ok {
+			return &protoStep{Kind: protoStepAssign, Dst: dst, Expr: expr}
+		}
+
+		v := pc.newVar()
+
+		switch base := pc.baseType(goType); {
+		case typ == protoTimestamp:
+			return &protoStep{Kind: protoStepAssign, Cond: src + " != nil", Dst: dst, Expr: pc.cast(goType, receiver(src)+".AsTime()")}
+		case base == string(octopus.UUID) || base == string(octopus.Decimal):
+			parse := "uuid.Parse"
+			if base == string(octopus.Decimal) {
+				parse = "decimal.NewFromString"
+			}
+
+			return &protoStep{Kind: protoStepCall, Cond: src + ` != ""`, Var: v, Func: parse, Src: src, Dst: dst, Expr: pc.cast(goType, v), Field: field, Zero: zero}
+		case pc.isEnum(typ):
+			return &protoStep{Kind: protoStepCall, Var: v, Func: "enumFromProto" + pc.enums[typ].Name, Src: src, Dst: dst, Expr: v, Field: field, Zero: zero}
+		default:
+			return &protoStep{Kind: protoStepCall, Var: v, Func: "fromProto" + typ, Src: src, Dst: dst, Expr: v, Field: field, Zero: zero}
+		}
+	}
+}
+
+// toValue значение src типа goType, преобразованное к типу поля сообщения: выражение,
+// если преобразование выражается без ошибок, иначе временная переменная
+func (pc *protoConv) toValue(src, goType, field, zero string) protoValue {
+	if expr, ok := pc.toExpr(src, goType); ok {
+		return protoValue{Expr: expr}
+	}
+
+	v := pc.newVar()
+
+	return protoValue{Var: v, Type: pc.goType(goType), Conv: pc.to(v, src, goType, field, zero)}
+}
+
+// fromValue значение src поля сообщения, преобразованное к типу goType: выражение,
+// если преобразование не требует проверок, иначе временная переменная
+func (pc *protoConv) fromValue(src, goType, field, zero string) protoValue {
+	if expr, ok := pc.fromExpr(src, goType); ok {
+		return protoValue{Expr: expr}
+	}
+
+	v := pc.newVar()
+
+	return protoValue{Var: v, Type: goType, Conv: pc.from(v, src, goType, field, zero)}
+}
+
+// toExpr выражение, преобразующее значение src скалярного типа goType к типу поля сообщения.
+// Для составных типов и вложенных сообщений возвращается false
+func (pc *protoConv) toExpr(src, goType string) (string, bool) {
+	label, typ := pc.pf.partialType(goType)
+	if goType == pc.goType(goType) && !pc.isJSON(goType, typ) {
+		return src, true
+	}
+
+	if label != "" || strings.HasPrefix(typ, "map<") || pc.isJSON(goType, typ) || pc.isMessage(typ) {
+		return "", false
+	}
+
+	if pc.isEnum(typ) {
+		return "enumToProto" + pc.enums[typ].Name + "(" + src + ")", true
+	}
+
+	base := pc.baseType(goType)
+	if base != goType {
+		src = base + "(" + src + ")"
+	}
+
+	switch {
+	case typ == protoTimestamp:
+		return "timestamppb.New(" + src + ")", true
+	case base == string(octopus.UUID) || base == string(octopus.Decimal):
+		return receiver(src) + ".String()", true
+	case base == pc.protoGoType(typ):
+		return src, true
+	default:
+		return pc.protoGoType(typ) + "(" + src + ")", true
+	}
+}
+
+// fromExpr выражение, преобразующее значение src поля сообщения к скалярному типу goType.
+// Если преобразование может завершиться ошибкой или требует проверки значения, возвращается false
+func (pc *protoConv) fromExpr(src, goType string) (string, bool) {
+	label, typ := pc.pf.partialType(goType)
+	if goType == pc.goType(goType) && !pc.isJSON(goType, typ) {
+		return src, true
+	}
+
+	if label != "" || strings.HasPrefix(typ, "map<") || pc.isJSON(goType, typ) || pc.isMessage(typ) || pc.isEnum(typ) || typ == protoTimestamp {
+		return "", false
+	}
+
+	base := pc.baseType(goType)
+	if base == string(octopus.UUID) || base == string(octopus.Decimal) {
+		return "", false
+	}
+
+	if goType == pc.protoGoType(typ) {
+		return src, true
+	}
+
+	return goType + "(" + src + ")", true
+}
+
+// goType тип go поля сообщения, соответствующего значению типа goType.
+// Соответствует коду, который генерирует protoc-gen-go
+func (pc *protoConv) goType(goType string) string {
+	label, typ := pc.pf.partialType(goType)
+	ret := pc.protoGoType(typ)
+
+	switch {
+	case label == "repeated ":
+		return "[]" + ret
+	case label == "optional " && !strings.HasPrefix(ret, "*") && !strings.HasPrefix(ret, "[]"):
+		return "*" + ret
+	}
+
+	return ret
+}
+
+// protoGoType тип go для типа protobuf без модификатора
+func (pc *protoConv) protoGoType(typ string) string {
+	switch {
+	case typ == "float":
+		return "float32"
+	case typ == "double":
+		return "float64"
+	case typ == "bytes":
+		return "[]byte"
+	case typ == protoTimestamp:
+		return "*timestamppb.Timestamp"
+	case strings.HasPrefix(typ, "map<"):
+		key, val, _ := strings.Cut(strings.TrimSuffix(typ[4:], ">"), ", ")
+		return "map[" + pc.protoGoType(key) + "]" + pc.protoGoType(val)
+	case pc.isEnum(typ):
+		return pc.pb + "." + typ
+	case pc.isMessage(typ):
+		return "*" + pc.pb + "." + typ
+	}
+
+	return typ
+}
+
+// baseType формат пользовательского типа поля модели, для остальных типов - сам тип
+func (pc *protoConv) baseType(goType string) string {
+	if base, ok := pc.base[goType]; ok {
+		return base
+	}
+
+	return goType
+}
+
+// cast приведение выражения базового формата к пользовательскому типу
+func (pc *protoConv) cast(goType, expr string) string {
+	if pc.baseType(goType) == goType {
+		return expr
+	}
+
+	return goType + "(" + expr + ")"
+}
+
+// isJSON признак значения, которое описано в protobuf как bytes, но не является срезом байт
+func (pc *protoConv) isJSON(goType, typ string) bool {
+	return typ == "bytes" && pc.baseType(strings.TrimLeft(goType, "*")) != "[]byte"
+}
+
+func (pc *protoConv) isEnum(typ string) bool {
+	_, ok := pc.enums[typ]
+	return ok
+}
+
+func (pc *protoConv) isMessage(typ string) bool {
+	_, ok := pc.pf.nested[typ]
+	return ok
+}
+
+// newVar имя новой локальной переменной
+func (pc *protoConv) newVar() string {
+	pc.vars++
+	return fmt.Sprintf("v%d", pc.vars)
+}
+
+// receiver выражение для вызова метода значения, полученного разыменованием указателя
+func receiver(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+
+	return expr
+}
+
+// protoGoName имя поля в структуре, которую генерирует protoc-gen-go для сообщения:
+// имя поля protobuf в CamelCase, буквы после подчёркивания и цифр становятся заглавными
+func protoGoName(name string) string {
+	ret := make([]byte, 0, len(name))
+	upper := true
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c == '_' && i > 0 && i+1 < len(name) && name[i+1] >= 'a' && name[i+1] <= 'z':
+			upper = true
+			continue
+		case c >= '0' && c <= '9':
+			ret = append(ret, c)
+			upper = true
+
+			continue
+		case upper && c >= 'a' && c <= 'z':
+			c -= 'a' - 'A'
+		}
+
+		ret = append(ret, c)
+		upper = false
+	}
+
+	return string(ret)
+}
@@ -0,0 +1,199 @@
+package generator
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+func TestGenerateProtoConv(t *testing.T) {
+	namespace := ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"}
+
+	tests := []struct {
+		name    string
+		cl      ds.RecordPackage
+		wantOk  bool
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "conversion",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				ProtoPkg:  "example.com/pb/foopb",
+				Fields: []ds.FieldDeclaration{
+					{Name: "ID", Format: "int64", PrimaryKey: true},
+					{Name: "Status", Format: "uint8", Enum: []ds.EnumValue{{Name: "Active", Value: "1"}, {Name: "Blocked", Value: "2"}}, Default: "1"},
+					{Name: "Nick", Format: "string", Nullable: true},
+					{Name: "Ref", Format: "uuid.UUID", Nullable: true},
+					{Name: "Price", Format: "int64", TypeOverride: ds.TypeOverride{ImportName: "domain", Type: "Cents"}},
+					{Name: "Created", Format: "time.Time", Timestamp: ds.TimestampUnixMs},
+					{Name: "Profile", Format: "string", Serializer: []string{"Profile"}},
+					{Name: "Raw", Format: "string", Serializer: []string{"Raw"}},
+				},
+				FieldsMap: map[string]int{"ID": 0, "Status": 1, "Nick": 2, "Ref": 3, "Price": 4, "Created": 5, "Profile": 6, "Raw": 7},
+				SerializerMap: map[string]ds.SerializerDeclaration{
+					"Profile": {Name: "Profile", Type: "*domain.Profile", ImportName: "serializer", Marshaler: "JSONMarshal", Unmarshaler: "JSONUnmarshal"},
+					"Raw":     {Name: "Raw", Type: "map[string]any", ImportName: "serializer", Marshaler: "JSONMarshal", Unmarshaler: "JSONUnmarshal"},
+				},
+				ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{
+					"domain.Profile": {{Name: "Age", Type: "int"}, {Name: "Attrs", Type: "map[string]domain.Attr"}, {Name: "Grid", Type: "[][]int"}},
+					"domain.Attr":    {{Name: "Value", Type: "*string"}},
+				},
+			},
+			wantOk: true,
+			want: []string{
+				"func (obj *Foo) ToProto() (*foopb.Foo, error) {",
+				"func FooFromProto(ctx context.Context, msg *foopb.Foo) (*Foo, error) {",
+				"msg.Id = obj.GetID()",
+				"msg.Nick = obj.GetNick()",
+				"msg.Status = enumToProtoStatus(obj.GetStatus())",
+				"msg.Price = int64(obj.GetPrice())",
+				"msg.Created = timestamppb.New(obj.GetCreated())",
+				"v2 := (*v1).String()\n\t\tmsg.Ref = &v2",
+				"v4, err := toProtoProfile(*v3)",
+				"v5, err := serializer.JSONMarshal(obj.GetRaw())",
+				"if err := obj.SetPrice(domain.Cents(msg.Price)); err != nil {",
+				"v10, err := uuid.Parse(*msg.Ref)",
+				"if err := serializer.JSONUnmarshal(string(msg.Raw), &v",
+				"case foopb.FooStatus_FOO_STATUS_BLOCKED:\n\t\treturn StatusBlocked, nil",
+				"case foopb.FooStatus_FOO_STATUS_UNSPECIFIED:\n\t\treturn StatusActive, nil",
+				`return ret, &activerecord.EnumValueError{Entity: "Foo", Field: "Status", Value: v}`,
+				"func toProtoAttr(v domain.Attr) (*foopb.Attr, error) {",
+				"func fromProtoProfile(msg *foopb.Profile) (ret domain.Profile, err error) {",
+				"msg.Attrs = make(map[string]*foopb.Attr, len(",
+				"if err := json.Unmarshal(msg.Grid, &ret.Grid); err != nil {",
+			},
+		},
+		{
+			name: "without proto package",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				Fields:    []ds.FieldDeclaration{{Name: "ID", Format: "int64"}},
+				FieldsMap: map[string]int{"ID": 0},
+			},
+		},
+		{
+			name: "procedure",
+			cl:   ds.RecordPackage{Namespace: namespace, ProtoPkg: "example.com/pb/foopb"},
+		},
+		{
+			name: "unknown format",
+			cl: ds.RecordPackage{
+				Namespace: namespace,
+				ProtoPkg:  "example.com/pb/foopb",
+				Fields:    []ds.FieldDeclaration{{Name: "ID", Format: "complex64"}},
+				FieldsMap: map[string]int{"ID": 0},
+			},
+			wantErr: arerror.ErrGeneratorProtoFormat,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := GenerateProtoConv(ds.AppInfo{}, tt.cl, Options{StableHeader: true})
+			if !errors.Is(err, tt.wantErr) {
+				var errFile *arerror.ErrGeneratorFile
+				if !errors.As(err, &errFile) || !errors.Is(errFile.Err, tt.wantErr) {
+					t.Fatalf("GenerateProtoConv() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("GenerateProtoConv() ok = %v, want %v", ok, tt.wantOk)
+			}
+
+			if !ok {
+				return
+			}
+
+			data, err := processImports("protoconv.go", []byte(got), Options{})
+			if err != nil {
+				t.Fatalf("GenerateProtoConv() generated invalid code: %s\n%s", err, got)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("GenerateProtoConv() missing %q in\n%s", want, data)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateProtoConvTypeCheck(t *testing.T) {
+	if testing.Short() {
+		t.Skip("type check loads sources of activerecord packages")
+	}
+
+	cl := ds.RecordPackage{
+		Server:    ds.ServerDeclaration{Host: "127.0.0.1", Port: "11011", Timeout: 500},
+		Namespace: ds.NamespaceDeclaration{ObjectName: "5", PublicName: "Foo", PackageName: "foo"},
+		Backends:  []string{"octopus"},
+		ProtoPkg:  "example.com/pb/foopb",
+		Fields: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
+			{Name: "Status", Format: "uint8", Enum: []ds.EnumValue{{Name: "Active", Value: "1"}, {Name: "Blocked", Value: "2"}}, Default: "1", Mutators: []string{}, Serializer: []string{}},
+			{Name: "Nick", Format: "string", Size: 16, Mutators: []string{}, Serializer: []string{}},
+		},
+		FieldsMap:       map[string]int{"ID": 0, "Status": 1, "Nick": 2},
+		FieldsObjectMap: map[string]ds.FieldObject{},
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, FieldsMap: map[string]ds.IndexField{"ID": {IndField: 0, Order: 0}}, Primary: true, Unique: true, Type: "int64"},
+		},
+		IndexMap:      map[string]int{"ID": 0},
+		SelectorMap:   map[string]int{"SelectByID": 0},
+		ImportPackage: ds.NewImportPackage(),
+		SerializerMap: map[string]ds.SerializerDeclaration{},
+		TriggerMap:    map[string]ds.TriggerDeclaration{},
+		FlagMap:       map[string]ds.FlagDeclaration{},
+	}
+
+	ret, err := Generate(ds.AppInfo{}, cl, map[string]ds.RecordPackage{}, Options{TypeCheck: true, Logger: log.New(&bytes.Buffer{}, "", 0)})
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	if names := fileNames(ret); names[len(names)-1] != "protoconv.go" {
+		t.Fatalf("Generate() = %v, want protoconv.go", names)
+	}
+
+	// Ошибка в шаблоне, которую не обнаруживает форматирование файла
+	broken := strings.Replace(ProtoConvTmpl, "obj.Set{{ .Name }}({{ .Value.Ref }})", "obj.Set{{ .Name }}({{ .Value.Ref }}, 1)", 1)
+
+	conv, _, err := GenerateProtoConv(ds.AppInfo{}, cl, Options{TemplateOverrides: map[string]string{"protoconv": broken}})
+	if err != nil {
+		t.Fatalf("GenerateProtoConv() error = %v", err)
+	}
+
+	files := append(ret[:len(ret)-1:len(ret)-1], GenerateFile{Name: "protoconv.go", Data: []byte(conv)})
+
+	var errFile *arerror.ErrGeneratorFile
+	if err := typeCheck("Foo", "proto", files); !errors.As(err, &errFile) || errFile.Filename != "protoconv.go" {
+		t.Errorf("typeCheck() error = %v, want type error in protoconv.go", err)
+	}
+}
+
+func Test_protoGoName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "id", want: "Id"},
+		{name: "user_name", want: "UserName"},
+		{name: "field2_name", want: "Field2Name"},
+		{name: "a1b", want: "A1B"},
+		{name: "x__y", want: "X_Y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := protoGoName(tt.name); got != tt.want {
+				t.Errorf("protoGoName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
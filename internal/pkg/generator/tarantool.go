@@ -0,0 +1,91 @@
+package generator
+
+import (
+	"bytes"
+	_ "embed"
+	"log"
+	"text/template"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+//nolint:revive
+//go:embed tmpl/tarantool/main.tmpl
+var TarantoolRootRepositoryTmpl string
+
+// GenerateTarantool2 генерация пакета для работы с tarantool 2.x по протоколу IPROTO.
+// Порядок полей в туплах соответствует порядку полей в декларации
+func GenerateTarantool2(params PkgData, opts Options) (map[string]bytes.Buffer, *arerror.ErrGeneratorPhases) {
+	if err := params.checkImports("tarantool2"); err != nil {
+		return nil, err
+	}
+
+	if err := params.checkIndexes("tarantool2"); err != nil {
+		return nil, err
+	}
+
+	if err := params.checkUnused("tarantool2", opts); err != nil {
+		return nil, err
+	}
+
+	return GenerateFilesByTmpl("tarantool", params, "tarantool2", opts.header(), opts.template("tarantool/main", TarantoolRootRepositoryTmpl))
+}
+
+var TarantoolTemplateFuncs = template.FuncMap{
+	"tarantoolParam": func(format octopus.Format) TarantoolFormatParam {
+		ret, ex := TarantoolFormatMapper[format]
+		if !ex {
+			log.Fatalf("unpacker for type `%s` not found", format)
+		}
+
+		return ret
+	},
+}
+
+// TarantoolFormatParam описание распаковки значения поля из тупла, декодированного из msgpack
+type TarantoolFormatParam struct {
+	UnpackFunc string
+	ConvFunc   string
+	PackFunc   string
+}
+
+// Conv возвращает выражение приведения распакованного значения к типу поля
+func (p TarantoolFormatParam) Conv(varname string) string {
+	if p.ConvFunc != "" {
+		return p.ConvFunc + "(" + varname + ")"
+	}
+
+	return varname
+}
+
+// Pack возвращает выражение, которым значение поля записывается в тупл
+func (p TarantoolFormatParam) Pack(varname string) string {
+	if p.PackFunc != "" {
+		return p.PackFunc + "(" + varname + ")"
+	}
+
+	return varname
+}
+
+var TarantoolFormatMapper = map[octopus.Format]TarantoolFormatParam{
+	octopus.Bool:        {UnpackFunc: "tarantool.UnpackBool"},
+	octopus.Uint8:       {UnpackFunc: "tarantool.UnpackUint64", ConvFunc: "uint8"},
+	octopus.Uint16:      {UnpackFunc: "tarantool.UnpackUint64", ConvFunc: "uint16"},
+	octopus.Uint32:      {UnpackFunc: "tarantool.UnpackUint64", ConvFunc: "uint32"},
+	octopus.Uint64:      {UnpackFunc: "tarantool.UnpackUint64"},
+	octopus.Uint:        {UnpackFunc: "tarantool.UnpackUint64", ConvFunc: "uint"},
+	octopus.Int8:        {UnpackFunc: "tarantool.UnpackInt64", ConvFunc: "int8"},
+	octopus.Int16:       {UnpackFunc: "tarantool.UnpackInt64", ConvFunc: "int16"},
+	octopus.Int32:       {UnpackFunc: "tarantool.UnpackInt64", ConvFunc: "int32"},
+	octopus.Int64:       {UnpackFunc: "tarantool.UnpackInt64"},
+	octopus.Int:         {UnpackFunc: "tarantool.UnpackInt64", ConvFunc: "int"},
+	octopus.Float32:     {UnpackFunc: "tarantool.UnpackFloat64", ConvFunc: "float32"},
+	octopus.Float64:     {UnpackFunc: "tarantool.UnpackFloat64"},
+	octopus.String:      {UnpackFunc: "tarantool.UnpackString"},
+	octopus.UUID:        {UnpackFunc: "tarantool.UnpackUUID", PackFunc: "tarantool.PackUUID"},
+	octopus.Decimal:     {UnpackFunc: "tarantool.UnpackDecimal", PackFunc: "tarantool.PackDecimal"},
+	octopus.TimeUnix:    {UnpackFunc: "tarantool.UnpackTimeUnix", PackFunc: "tarantool.PackTimeUnix"},
+	octopus.TimeUnixMs:  {UnpackFunc: "tarantool.UnpackTimeUnixMs", PackFunc: "tarantool.PackTimeUnixMs"},
+	octopus.TimeRFC3339: {UnpackFunc: "tarantool.UnpackTimeRFC3339", PackFunc: "tarantool.PackTimeRFC3339"},
+}
@@ -0,0 +1,734 @@
+package generator
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+func TestGenerateTarantool2(t *testing.T) {
+	type args struct {
+		params PkgData
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		want    *arerror.ErrGeneratorPhases
+		wantStr []string
+	}{
+		{
+			name: "fieldsPkg",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      "foo",
+					ARPkgTitle: "Foo",
+					Indexes: []ds.IndexDeclaration{
+						{
+							Name:     "ID",
+							Num:      0,
+							Selector: "SelectByID",
+							Fields:   []int{0},
+							Type:     "int64",
+							Primary:  true,
+							Unique:   true,
+						},
+						{
+							Name:     "NameTags",
+							Num:      1,
+							Selector: "SelectByNameTags",
+							Fields:   []int{1, 2},
+							Type:     "NameTagsIndexType",
+						},
+					},
+					FieldList: []ds.FieldDeclaration{
+						{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+						{Name: "Name", Format: "string", Size: 32, Serializer: []string{}, Validation: ds.Validation{Required: true, Regex: "^[a-z]+$"}},
+						{Name: "Tags", Format: "string", Serializer: []string{"NoteJSON"}},
+						{Name: "Age", Format: "int32", Serializer: []string{}, Nullable: true, Default: "18", Validation: ds.Validation{Min: "18"}},
+						{Name: "Token", Format: "uuid.UUID", Serializer: []string{}},
+						{Name: "Amount", Format: "decimal.Decimal", Serializer: []string{}},
+						{Name: "Labels", Format: "string", Serializer: []string{}, Array: true},
+						{Name: "Scores", Format: "int64", Serializer: []string{}, Array: true},
+						{Name: "Level", Format: "uint8", Serializer: []string{}, Enum: []ds.EnumValue{{Name: "Low", Value: "1"}, {Name: "High", Value: "2"}}},
+					},
+					Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301", Retry: 3, RetryDelay: 20, CacheSize: 1000, CacheTTL: 500, HealthFailures: 3, HealthLatency: 200},
+					Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+					Validate:  true,
+					Audit:     true,
+					Serializers: map[string]ds.SerializerDeclaration{
+						"NoteJSON": {
+							Name:        "NoteJSON",
+							Pkg:         "github.com/mailru/activerecord/pkg/serializer",
+							Type:        "[]string",
+							ImportName:  "serializerNoteJSON",
+							Marshaler:   "JSONMarshal",
+							Unmarshaler: "JSONUnmarshal",
+						},
+						"Packed": {
+							Name:  "Packed",
+							Type:  "[]string",
+							Chain: []string{"NoteJSON"},
+						},
+					},
+					Imports: []ds.ImportDeclaration{{Path: "github.com/mailru/activerecord/pkg/serializer", ImportName: "serializerNoteJSON"}, {Path: "github.com/google/uuid"}, {Path: "github.com/shopspring/decimal"}},
+				},
+			},
+			wantStr: []string{
+				`package foo`,
+				`space     string = "users"`,
+				`shard, _ := activerecord.ShardNumFromContext(ctx)`,
+				`func UpdateReturning(ctx context.Context, obj *Foo) (*Foo, error) {`,
+				`func ExportAll(ctx context.Context, fn func(obj *Foo) error) error {`,
+				`func (c *FooCursor) ScanInto(ctx context.Context, dst *Foo) (bool, error) {`,
+				`if err := dst.unpackTuple(ctx, tuple); err != nil {`,
+				`func InsertBatch(ctx context.Context, records []*Foo) error {`,
+				`func ImportAll(ctx context.Context, records <-chan *Foo) error {`,
+				`tuple, err := connection.UpdateReturning(ctx, space, 0, pk, obj.BaseField.UpdateOps)`,
+				`nps, err := NewFromBox(ctx, [][]any{tuple})`,
+				`connection, err := tarantool.Box(ctx, shard, activerecord.ReplicaOrMasterInstanceType, "arcfg", nil)`,
+				`func UnpackID(value any) (ret int64, errRet error) {`,
+				`unpacked, err := tarantool.UnpackInt64(value)`,
+				`err = serializerNoteJSON.JSONUnmarshal(unpacked, &svar)`,
+				`obj.BaseField.UpdateOps = tarantool.SetFieldOp(obj.BaseField.UpdateOps, 1, data)`,
+				`func (obj *Foo) Changed() []string {`,
+				`names := [cntFields]string{"ID", "Name", "Tags", "Age", "Token", "Amount", "Labels", "Scores", "Level"}`,
+				`max length of field 'Foo.Name' is '%d' (received '%d')", 32, len(str))`,
+				`func TupleToStruct(ctx context.Context, tuple []any) (*Foo, error) {`,
+				`func selectBox(ctx context.Context, indexnum, iterator uint32, keysPacked [][]any, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`return connection.Select(ctx, space, indexnum, offset, limit, iterator, key)`,
+				`type NameTagsIndexType struct {`,
+				`func SelectByNameTags(ctx context.Context, key NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`func SelectByNameTagsWithLimit(ctx context.Context, key NameTagsIndexType, limit, offset uint32) ([]*Foo, error) {`,
+				`func SelectByNameTagsDesc(ctx context.Context, key NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`res, err := selectBox(ctx, 1, tarantool.IterReq, [][]any{keyPacked}, limiter)`,
+				`func SelectByNameTagsFields(ctx context.Context, key NameTagsIndexType, fields []string, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`res, err := selectBoxFields(ctx, 1, tarantool.IterEq, [][]any{keyPacked}, limiter, nums)`,
+				`return nil, fmt.Errorf("unknown field '%s' of Foo: %w", name, activerecord.ErrValidation)`,
+				`return connection.SelectFields(ctx, space, indexnum, offset, limit, iterator, key, fields)`,
+				`np.BaseField.Partial = true`,
+				`return fmt.Errorf("can't save partially selected object: %w", activerecord.ErrValidation)`,
+				`func Query() *FooQuery {`,
+				`func (obj *Foo) MarshalJSON() ([]byte, error) {`,
+				`if _, ok := present["name"]; ok {`,
+				`func (q *FooQuery) ByNameTags(key NameTagsIndexType) *FooQuery {`,
+				`case "Name":`,
+				`q.err = fmt.Errorf("%w: '%s'", tarantool.ErrQueryNotIndexed, field)`,
+				`iterator, err := tarantool.QueryIterator(q.cmp, q.order)`,
+				`if err := tarantool.CheckQueryLimit(q.cmp, q.limit); err != nil {`,
+				`func Upsert(ctx context.Context, obj *Foo) error {`,
+				"// Для частичной записи (BaseField.Partial) возвращается ошибка activerecord.ErrValidation\nfunc Upsert(",
+				`func SelectByIDCount(ctx context.Context, key int64) (uint32, error) {`,
+				`func ExistsByID(ctx context.Context, key int64) (bool, error) {`,
+				`func Ping(ctx context.Context) error {`,
+				`func CheckSchema(ctx context.Context) error {`,
+				`return tarantool.CheckSchema(ctx, connection, Schema())`,
+				`func EnsureSchema(ctx context.Context) error {`,
+				`func (obj *Foo) Clone() *Foo {`,
+				`clone.BaseField = activerecord.DeepCopy(obj.BaseField)`,
+				`clone.fieldLabels = activerecord.DeepCopy(obj.fieldLabels)`,
+				`func (obj *Foo) Diff(other *Foo) []string {`,
+				`auditOriginal *Foo`,
+				`obj.auditSnapshot()`,
+				`audit := obj.auditRecords(ctx)`,
+				`tx.OnCommit(func() { activerecord.Audit(ctx, audit) })`,
+				`rec.Old = original.fieldName`,
+				`if !activerecord.EqualPtr(obj.fieldAge, other.fieldAge) {`,
+				`if !activerecord.EqualSlice(obj.fieldLabels, other.fieldLabels) {`,
+				`if !reflect.DeepEqual(obj.fieldTags, other.fieldTags) {`,
+				`if !obj.fieldAmount.Equal(other.fieldAmount) {`,
+				`return tarantool.EnsureSchema(ctx, connection, Schema())`,
+				`Version:   SchemaVersion,`,
+				`retryDelay = 20 * time.Millisecond`,
+				`var primaryCache = activerecord.NewCache(1000, 500*time.Millisecond)`,
+				`func (obj *Foo) MarshalBinary() ([]byte, error) {`,
+				`func MarshalEmbedded(obj *Foo) (string, error) {`,
+				`func UnmarshalEmbedded(data string, obj **Foo) error {`,
+				`np, err := TupleToStruct(context.Background(), tuple)`,
+				`metricStatCnt.Inc(ctx, "cache_hit", 1)`,
+				`invalidateCache(tx, obj.Primary())`,
+				`tx.OnCommit(func() { primaryCache.Delete(key) })`,
+				`observeQuery(ctx, activerecord.QueryLabels{Storage: "tarantool", Entity: "Foo", Namespace: "users", Method: "select", Index: indexName(indexnum)}, started, err)`,
+				`observeQuery(ctx, activerecord.QueryLabels{Storage: "tarantool", Entity: "Foo", Namespace: "users", Method: "cursor", Index: indexName(c.indexnum)}, started, err)`,
+				`var health = activerecord.NewHealth(3, 200 * time.Millisecond)`,
+				`func HealthState() activerecord.HealthState {`,
+				`err := ping(ctx)`,
+				`activerecord.LogQuery(ctx, activerecord.QueryLabels{Storage: "tarantool", Entity: "Foo", Method: "delete"}, obj.Primary(), err)`,
+				`err := obj.insertReplaceInBox(ctx, replace, tx)`,
+				`func (obj *Foo) InsertTx(ctx context.Context, tx *tarantool.Tx) error {`,
+				`func (obj *Foo) UpdateTx(ctx context.Context, tx *tarantool.Tx) error {`,
+				`func (obj *Foo) DeleteTx(ctx context.Context, tx *tarantool.Tx) error {`,
+				`connection, err := obj.writeBox(ctx, tx)`,
+				`err := activerecord.Retry(ctx, retryCount, retryDelay, func() (err error) {`,
+				`return connection.Replace(ctx, space, tuple)`,
+				`if err := connection.Ping(ctx); err != nil {`,
+				`selected, err := SelectByNameTags(ctx, key, activerecord.NewLimiter(1))`,
+				`connection.Count(ctx, space, indexnum, tarantool.IterEq, key)`,
+				`func SelectByID(ctx context.Context, key int64) (*Foo, error) {`,
+				`func (obj *Foo) Update(ctx context.Context) error {`,
+				`connection.Delete(ctx, space, 0, pk)`,
+				`Backend:   "tarantool2",`,
+				`func marshalPacked(v []string) (string, error) {`,
+				`stage0, err := serializerNoteJSON.JSONMarshal(v)`,
+				`func unmarshalPacked(data string, v *[]string) error {`,
+				`return fmt.Errorf("serializer NoteJSON: %w", err)`,
+				`fieldAge *int32`,
+				`func UnpackAge(value any) (ret *int32, errRet error) {`,
+				`if value == nil {`,
+				`ret = new(int32)`,
+				`func packAge(Age *int32) (any, error) {`,
+				`return *Age, nil`,
+				`func (obj *Foo) applyDefaults() {`,
+				`if obj.fieldAge == nil {`,
+				`defaultAge := int32(18)`,
+				`obj.fieldAge = &defaultAge`,
+				`func NewFoo(ctx context.Context, ID int64, Name string, Tags`,
+				`Level LevelEnum) (*Foo, error) {`,
+				`if err := obj.SetName(Name); err != nil {`,
+				`func SelectByNameTagsIn(ctx context.Context, values []NameTagsIndexType, limiter activerecord.SelectorLimiter) ([]*Foo, error) {`,
+				`added := make(map[NameTagsIndexType]struct{}, len(values))`,
+				`res, err := selectBoxIn(ctx, 1, keysPacked, limiter)`,
+				`return selectKeysShard(ctx, shard, indexnum, keysPacked, limiter.Offset(), limit)`,
+				`connection.SelectKeys(ctx, space, indexnum, offset, limit, keysPacked)`,
+				`unpacked, err := tarantool.UnpackUUID(value)`,
+				`return tarantool.PackUUID(Token), nil`,
+				`unpacked, err := tarantool.UnpackDecimal(value)`,
+				`return tarantool.PackDecimal(Amount), nil`,
+				`fieldLabels []string`,
+				`fieldScores []int64`,
+				`func UnpackLabels(value any) (ret []string, errRet error) {`,
+				`items, err := tarantool.UnpackArray(value)`,
+				`ret = make([]string, 0, len(items))`,
+				`unpacked, err := tarantool.UnpackString(item)`,
+				`func UnpackScores(value any) (ret []int64, errRet error) {`,
+				`ret = make([]int64, 0, len(items))`,
+				`unpacked, err := tarantool.UnpackInt64(item)`,
+				`func packScores(Scores []int64) (any, error) {`,
+				`fieldLevel LevelEnum`,
+				`type LevelEnum uint8`,
+				`LevelHigh LevelEnum = 2`,
+				`ret = LevelEnum(uint8(unpacked))`,
+				`return uint8(Level), nil`,
+				`errRet = &activerecord.EnumValueError{Entity: "Foo", Field: "Level", Value: uint8(unpacked)}`,
+				`func UpdateByNameTags(ctx context.Context, key NameTagsIndexType, ops FooUpdateOps) (int, error) {`,
+				`err = tarantool.WithTx(ctx, connection, func(tx *tarantool.Tx) error {`,
+				`if err := obj.UpdateTx(ctx, tx); err != nil {`,
+				"\tAge **int32\n",
+				"\tLabels *[]string\n",
+				`func IterAll(ctx context.Context) *FooCursor {`,
+				`func IterNameTags(ctx context.Context, key NameTagsIndexType) (*FooCursor, error) {`,
+				`err = activerecord.Retry(ctx, retryCount, retryDelay, func() (err error) {`,
+				`tuples, err = connection.SelectAfter(ctx, space, c.indexnum, cursorPageSize, c.iterator, c.key, c.after)`,
+				`c.after = tuples[len(tuples)-1]`,
+				`var validateNameRegex = regexp.MustCompile("^[a-z]+$")`,
+				`func (obj *Foo) Validate() error {`,
+				`func validateName(value string) []activerecord.FieldValidationError {`,
+				`if value == "" {`,
+				`errs = append(errs, activerecord.FieldValidationError{Field: "Name", Rule: "required", Value: value})`,
+				`if !validateNameRegex.MatchString(value) {`,
+				`func validateAge(value *int32) []activerecord.FieldValidationError {`,
+				`if value == nil {`,
+				`if *value < 18 {`,
+				`errs = append(errs, validateAge(obj.fieldAge)...)`,
+				`if errs := validateName(Name); len(errs) != 0 {`,
+				`return &activerecord.ValidationError{Entity: "Foo", Fields: errs}`,
+				`metricErrCnt.Inc(ctx, "insertreplace_validate", 1)`,
+				`metricErrCnt.Inc(ctx, "update_validate", 1)`,
+				`func GetOrCreateByID(ctx context.Context, key int64, newRecord *Foo) (*Foo, bool, error) {`,
+				`if !errors.Is(insertErr, tarantool.ErrDuplicate) {`,
+				`func DeleteByNameTags(ctx context.Context, key NameTagsIndexType) (int, error) {`,
+				`if err := obj.DeleteTx(ctx, tx); err != nil {`,
+			},
+		},
+		{
+			name: "shardedPkg",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      "order",
+					ARPkgTitle: "Order",
+					Indexes: []ds.IndexDeclaration{
+						{
+							Name:     "AccountIDID",
+							Num:      0,
+							Selector: "SelectByAccountIDID",
+							Fields:   []int{0, 1},
+							Type:     "AccountIDIDIndexType",
+							Primary:  true,
+							Unique:   true,
+						},
+						{
+							Name:     "Email",
+							Num:      1,
+							Selector: "SelectByEmail",
+							Fields:   []int{2},
+							Type:     "string",
+							Scatter:  true,
+						},
+						{
+							Name:     "Status",
+							Num:      2,
+							Selector: "SelectByStatus",
+							Fields:   []int{3},
+							Type:     "uint8",
+						},
+					},
+					FieldList: []ds.FieldDeclaration{
+						{Name: "AccountID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+						{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+						{Name: "Email", Format: "string", Serializer: []string{}},
+						{Name: "Status", Format: "uint8", Serializer: []string{}},
+					},
+					FieldMap:  map[string]int{"AccountID": 0, "ID": 1, "Email": 2, "Status": 3},
+					Server:    ds.ServerDeclaration{Conf: "arcfg", Sharding: "ring", ShardBy: "AccountID"},
+					Container: ds.NamespaceDeclaration{ObjectName: "orders", PublicName: "Order", PackageName: "order"},
+				},
+			},
+			wantStr: []string{
+				`func Shard(ctx context.Context, key int64) (int, error) {`,
+				`func MasterBox(ctx context.Context, key int64) (*tarantool.Connection, error) {`,
+				`case indexnum == 0 && len(key) > 0:`,
+				"var scatterIndexes = map[uint32]bool{\n\t1: true,\n}",
+				`return nil, fmt.Errorf("%w: select by index %s without AccountID", activerecord.ErrShardKeyRequired, indexName(indexnum))`,
+				`shardRes, err := selectBoxShard(ctx, shards[0], indexnum, iterator, [][]any{key}, limiter, fields)`,
+				// Ключ, отправленный в несколько шардов, выбирается из каждого шарда с лимитом offset+limit
+				`shardLimiter := activerecord.NewLimitOffset(shardLimit(limiter), 0)`,
+				`res = append(res, limitResult(keyRes, limiter)...)`,
+				`shardKeys, err := shardSelectKeys(ctx, c.indexnum, c.iterator, [][]any{c.key})`,
+				`key, err := packAccountID(obj.fieldAccountID)`,
+				`return recordsTx(ctx, selected, func(tx *tarantool.Tx, obj *Order) error {`,
+				`return eachShard(ctx, activerecord.MasterInstanceType, func(connection *tarantool.Connection) error {`,
+			},
+		},
+		{
+			name: "procPkg",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      "foo",
+					ARPkgTitle: "Foo",
+					FieldList:  []ds.FieldDeclaration{},
+					ProcInFieldList: []ds.ProcFieldDeclaration{
+						{Name: "Input", Format: "string", Type: ds.IN, Serializer: []string{}, Validation: ds.Validation{Required: true}, Enum: []string{"a", "b"}},
+					},
+					ProcOutFieldList: []ds.ProcFieldDeclaration{
+						{Name: "Output", Format: "uint32", Type: ds.OUT, Serializer: []string{}, OrderIndex: 1},
+					},
+					Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+					Container:   ds.NamespaceDeclaration{ObjectName: "foo_proc", PublicName: "Foo", PackageName: "foo"},
+					Indexes:     []ds.IndexDeclaration{},
+					Serializers: map[string]ds.SerializerDeclaration{},
+					Imports:     []ds.ImportDeclaration{},
+				},
+			},
+			wantStr: []string{
+				`procName     string = "foo_proc"`,
+				`func Call(ctx context.Context, params FooParams) (*Foo, error) {`,
+				`func CallOnMaster(ctx context.Context, params FooParams) (*Foo, error) {`,
+				`resp, err := connection.Call(ctx, procName, args)`,
+				`observeQuery(ctx, activerecord.QueryLabels{Storage: "tarantool", Entity: "Foo", Namespace: procName, Method: "call_proc"}, started, err)`,
+				`func HealthState() activerecord.HealthState {`,
+				`func Ping(ctx context.Context) error {`,
+				`valOutput, err := UnpackOutput(tuple[1])`,
+				`return uint32(unpacked), nil`,
+				`func (obj FooParams) Validate() error {`,
+				`if obj.Input != "a" && obj.Input != "b" {`,
+				`errs = append(errs, activerecord.FieldValidationError{Field: "Input", Rule: "required", Value: obj.Input})`,
+				`return &activerecord.ValidationError{Entity: "FooParams", Fields: errs}`,
+				`if err := params.Validate(); err != nil {`,
+				`type FooProcCursor struct {`,
+				`func CallStream(ctx context.Context, params FooParams) (*FooProcCursor, error) {`,
+				`cursor, err := connection.CallCursor(ctx, procName, args)`,
+				`observeQuery(ctx, activerecord.QueryLabels{Storage: "tarantool", Entity: "Foo", Namespace: procName, Method: "call_stream"}, started, err)`,
+				`func (c *FooProcCursor) Next(ctx context.Context) (*Foo, bool, error) {`,
+				`func (c *FooProcCursor) Close() {`,
+			},
+		},
+		{
+			name: "procGroupsPkg",
+			want: nil,
+			args: args{
+				params: PkgData{
+					ARPkg:      "foo",
+					ARPkgTitle: "Foo",
+					FieldList:  []ds.FieldDeclaration{},
+					ProcOutFieldList: []ds.ProcFieldDeclaration{
+						{Name: "Status", Format: "string", Type: ds.OUT, Serializer: []string{}},
+					},
+					ProcOutGroups: []ds.ProcOutGroup{
+						{Name: "Items", Fields: ds.ProcFieldDeclarations{
+							0: {Name: "ItemID", Format: "int64", Type: ds.OUT, Serializer: []string{}, Group: "Items"},
+							1: {Name: "ItemName", Format: "string", Type: ds.OUT, Serializer: []string{}, OrderIndex: 1, Group: "Items"},
+						}},
+						{Name: "Totals", Fields: ds.ProcFieldDeclarations{
+							0: {Name: "Total", Format: "uint32", Type: ds.OUT, Serializer: []string{}, Group: "Totals"},
+						}},
+					},
+					Server:      ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+					Container:   ds.NamespaceDeclaration{ObjectName: "foo_proc", PublicName: "Foo", PackageName: "foo"},
+					Indexes:     []ds.IndexDeclaration{},
+					Serializers: map[string]ds.SerializerDeclaration{},
+					Imports:     []ds.ImportDeclaration{},
+				},
+			},
+			wantStr: []string{
+				`[]FooItems`,
+				`fieldTotals []FooTotals`,
+				`cntOutGroups int    = 2`,
+				`type FooItems struct {`,
+				`func (obj *Foo) GetItems() []FooItems {`,
+				`func TuplesToItems(ctx context.Context, tuples []any) ([]FooItems, error) {`,
+				`valItemName, err := UnpackItemName(tuple[1])`,
+				`func UnpackTotal(value any) (ret uint32, errRet error) {`,
+				`if len(tuples) != cntOutGroups+1 {`,
+				`resultSets := tuples[1:]`,
+				`ret.fieldItems, err = TuplesToItems(ctx, resultSets[0])`,
+				`ret.fieldTotals, err = TuplesToTotals(ctx, resultSets[1])`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ret, got := GenerateTarantool2(tt.args.params, Options{})
+			if got != tt.want {
+				t.Errorf("GenerateTarantool2() = %v, want %v", got, tt.want)
+			}
+
+			buff, ex := ret["tarantool"]
+			if !ex {
+				t.Errorf("GenerateTarantool2() tarantool not generated")
+				return
+			}
+
+			for _, substr := range tt.wantStr {
+				if !strings.Contains(buff.String(), substr) {
+					t.Errorf("GenerateTarantool2() = %v, want %v", buff.String(), substr)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerateTarantool2Prepared(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+			{Name: "Email", Num: 1, Selector: "SelectByEmail", Fields: []int{1, 2}, Type: "FooEmailIndexType", Unique: true},
+			{Name: "Name", Num: 2, Selector: "SelectByName", Fields: []int{2}, Type: "string"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Email", Format: "string", StorageName: "email", Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff := ret["tarantool"]
+	if data := buff.String(); strings.Contains(data, "ExecutePrepared") {
+		t.Errorf("GenerateTarantool2() prepared selects generated without prepared declaration")
+	}
+
+	params.Prepared = true
+
+	ret, got = GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff = ret["tarantool"]
+	data := buff.String()
+
+	// Подготовленные запросы формируются только для уникальных индексов, имена полей берутся из хранилища
+	for _, want := range []string{
+		"0: {name: \"SelectByID\", expr: `SELECT * FROM \"users\" WHERE \"ID\" = ?`, parts: 1},",
+		"1: {name: \"SelectByEmail\", expr: `SELECT * FROM \"users\" WHERE \"email\" = ? AND \"Name\" = ?`, parts: 2},",
+		"if query, ok := preparedSelects[indexnum]; ok && len(fields) == 0 && iterator == tarantool.IterEq && offset == 0 && len(key) == query.parts {\n\t\treturn connection.ExecutePrepared(ctx, query.name, query.expr, key)\n\t}",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("GenerateTarantool2() not contains %s", want)
+		}
+	}
+
+	if strings.Contains(data, "SelectByName\", expr") {
+		t.Errorf("GenerateTarantool2() prepared select generated for not unique index")
+	}
+}
+
+func TestGenerateTarantool2Immutable(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+			{Name: "Name", Num: 1, Selector: "SelectByName", Fields: []int{1}, Type: "string"},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+			{Name: "Created", Format: "int64", Serializer: []string{}, Immutable: true},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff := ret["tarantool"]
+	data := buff.String()
+
+	setter := "func (obj *Foo) SetCreated(Created int64) error {\n\tif obj.BaseField.Exists {\n\t\treturn fmt.Errorf(\"can't modify immutable field 'Foo.Created': %w\", activerecord.ErrValidation)"
+	if !strings.Contains(data, setter) {
+		t.Errorf("GenerateTarantool2() setter of immutable field not guarded: %v", data)
+	}
+
+	// UpdateBy<Index> присваивает поля сеттерами, поэтому неизменяемого поля в FooUpdateOps быть не должно
+	_, ops, found := strings.Cut(data, "type FooUpdateOps struct {")
+	if !found {
+		t.Fatalf("GenerateTarantool2() FooUpdateOps not generated")
+	}
+
+	ops, _, _ = strings.Cut(ops, "}")
+	if !strings.Contains(ops, "Name *string") || strings.Contains(ops, "Created") {
+		t.Errorf("GenerateTarantool2() FooUpdateOps = %v, want without immutable field Created", ops)
+	}
+}
+
+func TestGenerateTarantool2Touch(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+			{Name: "UpdatedAt", Format: "int64", Serializer: []string{}, Touch: true},
+			{Name: "Seen", Format: "string", Serializer: []string{}, Touch: true},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff := ret["tarantool"]
+	data := buff.String()
+
+	if !strings.Contains(data, "func Touch(ctx context.Context, key FooPrimaryKey) error {") {
+		t.Fatalf("GenerateTarantool2() Touch not generated")
+	}
+
+	// В Touch изменяются только поля с тегом touch
+	_, ops, found := strings.Cut(data, "func touchOps() ([]tarantool.Ops, error) {")
+	if !found {
+		t.Fatalf("GenerateTarantool2() touchOps not generated")
+	}
+
+	ops, _, _ = strings.Cut(ops, "\n}\n")
+
+	for _, want := range []string{
+		"packUpdatedAt(int64(time.Now().Unix()))",
+		"ops = tarantool.SetFieldOp(ops, 2, dataUpdatedAt)",
+		"packSeen(time.Now().Format(time.RFC3339))",
+		"ops = tarantool.SetFieldOp(ops, 3, dataSeen)",
+	} {
+		if !strings.Contains(ops, want) {
+			t.Errorf("GenerateTarantool2() touchOps = %v, want %s", ops, want)
+		}
+	}
+
+	if cnt := strings.Count(ops, "SetFieldOp("); cnt != 2 {
+		t.Errorf("GenerateTarantool2() touchOps has %d ops, want 2", cnt)
+	}
+}
+
+func TestGenerateTarantool2NullableGetters(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+			{Name: "Age", Format: "int32", Serializer: []string{}, Nullable: true},
+			{Name: "Nick", Format: "string", Serializer: []string{}, Nullable: true},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff := ret["tarantool"]
+	if data := buff.String(); strings.Contains(data, "OrZero()") {
+		t.Errorf("GenerateTarantool2() nullable getters generated without nullableGetters")
+	}
+
+	params.NullableGetters = true
+
+	ret, got = GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff = ret["tarantool"]
+	data := buff.String()
+
+	// Для NULL возвращается нулевое значение типа и false, для заполненного поля - значение и true
+	for _, want := range []string{
+		"func (obj *Foo) AgeOrZero() int32 {\n\tvalue, _ := obj.AgeValue()\n\n\treturn value\n}",
+		"func (obj *Foo) AgeValue() (int32, bool) {\n\tif obj.fieldAge == nil {\n\t\treturn 0, false\n\t}\n\n\treturn *obj.fieldAge, true\n}",
+		"func (obj *Foo) NickOrZero() string {",
+		"func (obj *Foo) NickValue() (string, bool) {\n\tif obj.fieldNick == nil {\n\t\treturn \"\", false\n\t}\n\n\treturn *obj.fieldNick, true\n}",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("GenerateTarantool2() not contains %s", want)
+		}
+	}
+
+	// Методы формируются только для nullable полей
+	if strings.Contains(data, "NameOrZero") || strings.Contains(data, "NameValue") {
+		t.Errorf("GenerateTarantool2() nullable getters generated for not nullable field")
+	}
+}
+
+func TestGenerateTarantool2Outbox(t *testing.T) {
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+			{Name: "UpdatedAt", Format: "int64", Serializer: []string{}, Touch: true},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff := ret["tarantool"]
+	if data := buff.String(); strings.Contains(data, "appendOutbox") || !strings.Contains(data, "err := obj.insertReplace(ctx, true, nil)") {
+		t.Errorf("GenerateTarantool2() outbox generated without outbox declaration")
+	}
+
+	params.Outbox = "users_outbox"
+
+	ret, got = GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	buff = ret["tarantool"]
+	data := buff.String()
+
+	// Вне транзакции изменение и событие выполняются в отдельной транзакции, событие добавляется после успешного изменения
+	for _, want := range []string{
+		"return tarantool.WithTx(ctx, connection, fn)",
+		"ev, err := activerecord.NewOutboxEvent(\"Foo\", op, key, payload)",
+		"return tx.AppendOutbox(ctx, \"users_outbox\", ev)",
+		"\tif tx == nil {\n\t\treturn obj.outboxTx(ctx, func(tx *tarantool.Tx) error { return obj.InsertTx(ctx, tx) })\n\t}",
+		"err := obj.insertReplace(ctx, false, tx)\n\tif err == nil {\n\t\terr = obj.appendOutbox(ctx, tx, activerecord.OutboxInsert)\n\t}",
+		"\tif tx == nil {\n\t\treturn obj.outboxTx(ctx, func(tx *tarantool.Tx) error { return obj.UpdateTx(ctx, tx) })\n\t}",
+		"tuple, err := obj.updateInBox(ctx, tx)\n\tif err == nil && tuple != nil {\n\t\terr = obj.appendOutbox(ctx, tx, activerecord.OutboxUpdate)\n\t}",
+		"\tif tx == nil {\n\t\treturn obj.outboxTx(ctx, func(tx *tarantool.Tx) error { return obj.DeleteTx(ctx, tx) })\n\t}",
+		"err := obj.deleteFromBox(ctx, tx)\n\tif err == nil {\n\t\terr = obj.appendOutbox(ctx, tx, activerecord.OutboxDelete)\n\t}",
+		// Replace, InsertOrReplace (и Upsert через него), UpdateReturning и Touch также формируют события
+		"if err := obj.insertReplace(ctx, true, tx); err != nil {\n\t\t\treturn err\n\t\t}\n\n\t\treturn obj.appendOutbox(ctx, tx, activerecord.OutboxReplace)",
+		"\t\treturn fmt.Errorf(\"can't replace not exists object: %w\", activerecord.ErrNotFound)\n\t}\n\n\terr := obj.replaceWithOutbox(ctx)",
+		"metricStatCnt.Inc(ctx, \"insertorreplace_request\", 1)\n\n\terr := obj.replaceWithOutbox(ctx)",
+		"\t\ttuple, err = obj.updateInBox(ctx, tx)\n\t\tif err == nil && tuple != nil {\n\t\t\terr = obj.appendOutbox(ctx, tx, activerecord.OutboxUpdate)\n\t\t}",
+		"if tuple, err = obj.touchInBox(ctx, tx); err != nil || tuple == nil {",
+		"return touched[0].appendOutbox(ctx, tx, activerecord.OutboxUpdate)",
+	} {
+		if !strings.Contains(data, want) {
+			t.Errorf("GenerateTarantool2() not contains %s", want)
+		}
+	}
+}
+
+// benchAllocsRe разбирает строку результата бенчмарка с количеством выделений памяти на операцию
+var benchAllocsRe = regexp.MustCompile(`(?m)^BenchmarkCursor/(\w+)\S*\s+\d+\s+.*\s(\d+) allocs/op$`)
+
+// TestGenerateTarantool2CursorAllocs запускает бенчмарк testdata/cursor_bench_test.go в пакете, сгенерированном
+// для модели, и проверяет, что ScanInto при чтении страницы курсора выделяет память меньше, чем Next.
+// Пакет собирается внутри модуля, чтобы импорты github.com/mailru/activerecord/pkg разрешались без сети
+func TestGenerateTarantool2CursorAllocs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("benchmark of generated package is skipped in short mode")
+	}
+
+	params := PkgData{
+		ARPkg:      "foo",
+		ARPkgTitle: "Foo",
+		Indexes: []ds.IndexDeclaration{
+			{Name: "ID", Num: 0, Selector: "SelectByID", Fields: []int{0}, Type: "int64", Primary: true, Unique: true},
+		},
+		FieldList: []ds.FieldDeclaration{
+			{Name: "ID", Format: "int64", PrimaryKey: true, Serializer: []string{}},
+			{Name: "Name", Format: "string", Serializer: []string{}},
+		},
+		Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "3301"},
+		Container: ds.NamespaceDeclaration{ObjectName: "users", PublicName: "Foo", PackageName: "foo"},
+	}
+
+	ret, got := GenerateTarantool2(params, Options{})
+	if got != nil {
+		t.Fatalf("GenerateTarantool2() = %v, want nil", got)
+	}
+
+	out := goTestGenerated(t, map[string]bytes.Buffer{"tarantool": ret["tarantool"]}, "cursor_bench_test.go", "-run", "^$", "-bench", "Cursor", "-benchtime", "20x")
+
+	allocs := map[string]int{}
+
+	for _, m := range benchAllocsRe.FindAllStringSubmatch(string(out), -1) {
+		allocs[m[1]], _ = strconv.Atoi(m[2])
+	}
+
+	next, okNext := allocs["next"]
+	scan, okScan := allocs["scan_into"]
+
+	if !okNext || !okScan {
+		t.Fatalf("benchmark results not found in output:\n%s", out)
+	}
+
+	t.Logf("allocs/op: Next %d, ScanInto %d", next, scan)
+
+	// Next создаёт запись на каждый тупл страницы, ScanInto выделяет память только под курсор
+	if scan >= next || scan > 10 {
+		t.Errorf("ScanInto allocs/op = %d, Next allocs/op = %d, want ScanInto without per record allocations", scan, next)
+	}
+}
@@ -0,0 +1,129 @@
+package foo
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+)
+
+// TestMain инициализирует activerecord: сеттеры octopus пишут в лог предупреждения о размере полей
+func TestMain(m *testing.M) {
+	activerecord.InitActiveRecord()
+
+	os.Exit(m.Run())
+}
+
+// TestBinaryRoundTrip проверяет, что запись с полями всех форматов восстанавливается из MarshalBinary без потерь.
+// Значения устанавливаются сеттерами и сравниваются геттерами через reflect, так как nullable поля
+// в octopus и tarantool2 имеют разные типы
+func TestBinaryRoundTrip(t *testing.T) {
+	filled := map[string]any{
+		"ID":      int64(42),
+		"Ref":     uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8"),
+		"Amount":  decimal.RequireFromString("-12345.678"),
+		"Created": time.Unix(1700000000, 0),
+		"Updated": time.UnixMilli(1700000000123),
+		"Expires": time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("MSK", 3*60*60)),
+		"Nick":    "nick",
+		"Status":  StatusDone,
+		"Tags":    []string{"a", "b"},
+		"Score":   1.5,
+		"Active":  true,
+	}
+
+	null := map[string]any{}
+	for name, value := range filled {
+		null[name] = value
+	}
+
+	null["Nick"] = nil
+	null["Tags"] = []string(nil)
+
+	tests := []struct {
+		name   string
+		values map[string]any
+	}{
+		{name: "filled", values: filled},
+		{name: "null", values: null},
+		{name: "zero", values: map[string]any{"ID": int64(1), "Status": StatusNew}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			obj := New(ctx)
+
+			for name, value := range tt.values {
+				setField(t, obj, name, value)
+			}
+
+			data, err := obj.MarshalBinary()
+			if err != nil {
+				t.Fatalf("MarshalBinary() error = %v", err)
+			}
+
+			restored := New(ctx)
+			if err := restored.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+
+			for _, name := range []string{"ID", "Ref", "Amount", "Created", "Updated", "Expires", "Nick", "Status", "Tags", "Score", "Active"} {
+				want := reflect.ValueOf(obj).MethodByName("Get" + name).Call(nil)[0]
+				got := reflect.ValueOf(restored).MethodByName("Get" + name).Call(nil)[0]
+
+				if !equalValues(got, want) {
+					t.Errorf("Get%s() = %v, want %v", name, got, want)
+				}
+			}
+
+			if err := restored.UnmarshalBinary(data[:len(data)-1]); err == nil {
+				t.Errorf("UnmarshalBinary() of truncated data error = nil")
+			}
+		})
+	}
+}
+
+// setField устанавливает значение сеттером. Для nullable полей значение передаётся указателем
+func setField(t *testing.T, obj *Foo, name string, value any) {
+	setter := reflect.ValueOf(obj).MethodByName("Set" + name)
+	argType := setter.Type().In(0)
+
+	arg := reflect.Zero(argType)
+	if value != nil {
+		arg = reflect.ValueOf(value)
+
+		if argType.Kind() == reflect.Pointer && arg.Type() != argType {
+			ptr := reflect.New(argType.Elem())
+			ptr.Elem().Set(arg)
+			arg = ptr
+		}
+	}
+
+	if err := setter.Call([]reflect.Value{arg})[0].Interface(); err != nil {
+		t.Fatalf("Set%s() error = %v", name, err)
+	}
+}
+
+// equalValues сравнивает значения полей. Значения time.Time и decimal.Decimal сравниваются методом Equal,
+// так как после восстановления у них может отличаться представление: часовой пояс или экспонента
+func equalValues(got, want reflect.Value) bool {
+	if got.Kind() == reflect.Pointer {
+		if got.IsNil() || want.IsNil() {
+			return got.IsNil() == want.IsNil()
+		}
+
+		return equalValues(got.Elem(), want.Elem())
+	}
+
+	if equal := got.MethodByName("Equal"); equal.IsValid() {
+		return equal.Call([]reflect.Value{want})[0].Bool()
+	}
+
+	return reflect.DeepEqual(got.Interface(), want.Interface())
+}
@@ -0,0 +1,65 @@
+package foo
+
+import (
+	"context"
+	"testing"
+)
+
+// cursorBenchPageSize количество туплов в странице курсора
+const cursorBenchPageSize = 1000
+
+// BenchmarkCursor сравнивает чтение страницы курсора методом Next, который создаёт запись на каждый тупл,
+// и методом ScanInto, который распаковывает туплы в одну и ту же запись. Страница заполняется заранее,
+// поэтому курсор не обращается к базе
+func BenchmarkCursor(b *testing.B) {
+	ctx := context.Background()
+
+	page := make([][]any, 0, cursorBenchPageSize)
+	for id := 0; id < cursorBenchPageSize; id++ {
+		page = append(page, []any{int64(id), "name"})
+	}
+
+	newCursor := func() *FooCursor {
+		return &FooCursor{page: append(make([][]any, 0, len(page)), page...), done: true}
+	}
+
+	b.Run("next", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			cursor := newCursor()
+
+			for {
+				_, ok, err := cursor.Next(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if !ok {
+					break
+				}
+			}
+		}
+	})
+
+	b.Run("scan_into", func(b *testing.B) {
+		b.ReportAllocs()
+
+		dst := New(ctx)
+
+		for i := 0; i < b.N; i++ {
+			cursor := newCursor()
+
+			for {
+				ok, err := cursor.ScanInto(ctx, dst)
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				if !ok {
+					break
+				}
+			}
+		}
+	})
+}
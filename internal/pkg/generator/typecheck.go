@@ -0,0 +1,55 @@
+package generator
+
+import (
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+)
+
+// typeCheck проверяет типы в сгенерированных файлах одного пакета. Ошибки импорта не учитываются,
+// так как пакеты, на которые ссылается сгенерированный код, могут быть ещё не сгенерированы.
+// Для таких пакетов go/types не выдаёт ошибок на использование их объявлений
+func typeCheck(name, backend string, files []GenerateFile) error {
+	fset := token.NewFileSet()
+	astFiles := make([]*ast.File, 0, len(files))
+
+	for _, file := range files {
+		astFile, err := parser.ParseFile(fset, file.Name, file.Data, 0)
+		if err != nil {
+			return &arerror.ErrGeneratorFile{Name: name, Backend: backend, Filename: file.Name, Err: err}
+		}
+
+		astFiles = append(astFiles, astFile)
+	}
+
+	var typeErr *types.Error
+
+	cfg := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error: func(err error) {
+			tErr, ok := err.(types.Error)
+			if !ok || typeErr != nil || strings.HasPrefix(tErr.Msg, "could not import") {
+				return
+			}
+
+			typeErr = &tErr
+		},
+	}
+
+	// Ошибки собираются в cfg.Error, поэтому результат Check не используется
+	_, _ = cfg.Check(name, fset, astFiles, nil)
+
+	if typeErr != nil {
+		pos := typeErr.Fset.Position(typeErr.Pos)
+
+		return &arerror.ErrGeneratorFile{Name: name, Backend: backend, Filename: pos.Filename, Err: fmt.Errorf("%s: %s", pos, typeErr.Msg)}
+	}
+
+	return nil
+}
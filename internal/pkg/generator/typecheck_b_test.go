@@ -0,0 +1,56 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+)
+
+func Test_typeCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   []GenerateFile
+		wantErr string
+	}{
+		{
+			name: "valid package",
+			files: []GenerateFile{
+				{Name: "octopus.go", Data: []byte("package foo\n\nimport \"fmt\"\n\nfunc A() string { return fmt.Sprint(B) }\n")},
+				{Name: "mock.go", Data: []byte("package foo\n\nvar B = 1\n")},
+			},
+		},
+		{
+			name: "not generated import",
+			files: []GenerateFile{
+				{Name: "octopus.go", Data: []byte("package foo\n\nimport \"example.com/notexists/bar\"\n\nvar A = bar.B\n")},
+			},
+		},
+		{
+			name: "type error",
+			files: []GenerateFile{
+				{Name: "octopus.go", Data: []byte("package foo\n\nvar A int = B\n")},
+				{Name: "mock.go", Data: []byte("package foo\n\nvar B = \"str\"\n")},
+			},
+			wantErr: "octopus.go:3:13",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := typeCheck("Foo", "octopus", tt.files)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("typeCheck() error = %v", err)
+				}
+
+				return
+			}
+
+			var fileErr *arerror.ErrGeneratorFile
+			if !errors.As(err, &fileErr) || fileErr.Filename != "octopus.go" || !strings.Contains(fileErr.Err.Error(), tt.wantErr) {
+				t.Errorf("typeCheck() error = %v, want position %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package generator
 
 import (
+	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/octopus"
 )
 
 var tmplErrRx = regexp.MustCompile(TemplateName + `:(\d+):`)
@@ -18,6 +24,9 @@ func getTmplErrorLine(lines []string, tmplerror string) (string, error) {
 			return "", arerror.ErrGeneragorGetTmplLine
 		} else if len(lines) == 0 {
 			return "", arerror.ErrGeneragorEmptyTmplLine
+		} else if int(lineNum) > len(lines) {
+			// Ошибка в общем шаблоне, который не входит в переданные строки
+			return "", arerror.ErrGeneragorErrorLineNotFound
 		} else {
 			cntline := 3
 			startLine := int(lineNum) - cntline - 1
@@ -42,3 +51,225 @@ func getTmplErrorLine(lines []string, tmplerror string) (string, error) {
 		return "", arerror.ErrGeneragorErrorLineNotFound
 	}
 }
+
+var tmplErrPosRx = regexp.MustCompile(TemplateName + `:(\d+):(?:(\d+):)?`)
+
+// getTmplErrorPos возвращает строку и колонку ошибки в шаблоне tmpl, нумерация с 1. Ошибки разбора шаблона
+// содержат только строку, ошибки выполнения - строку и смещение в байтах от начала строки. Шаблон разбирается
+// вместе с заголовком header, поэтому его строки вычитаются, чтобы позиция указывала на строку файла шаблона.
+// Если позиция не найдена или находится в общих шаблонах, добавляемых после tmpl, возвращаются нули
+func getTmplErrorPos(header, tmpl, tmplerror string) (line, column int) {
+	pos := tmplErrPosRx.FindStringSubmatch(tmplerror)
+	if len(pos) < 3 {
+		return 0, 0
+	}
+
+	line, err := strconv.Atoi(pos[1])
+	if err != nil {
+		return 0, 0
+	}
+
+	line -= strings.Count(header, "\n")
+	if line < 1 || line > strings.Count(tmpl, "\n")+1 {
+		return 0, 0
+	}
+
+	if pos[2] != "" {
+		if offset, err := strconv.Atoi(pos[2]); err == nil {
+			column = offset + 1
+		}
+	}
+
+	return line, column
+}
+
+// sortedKeys возвращает отсортированный список ключей map со строковыми ключами.
+// Используется для детерминированного порядка обхода map при формировании файлов.
+// Range по map в шаблонах и так идёт в порядке ключей, в шаблонах функция нужна,
+// когда требуется сам список ключей
+func sortedKeys(m any) []string {
+	v := reflect.ValueOf(m)
+	if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+
+	keys := make([]string, 0, v.Len())
+	for _, k := range v.MapKeys() {
+		keys = append(keys, k.String())
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// indexFilter формирует условие на Go, которому удовлетворяет запись obj, попадающая в частичный индекс.
+// Как и в SQL, условие на неравенство не выполняется для nullable полей без значения
+func indexFilter(fields []ds.FieldDeclaration, ind ds.IndexDeclaration, obj string) (string, error) {
+	conds := make([]string, 0, len(ind.Filter))
+
+	for _, cond := range ind.Filter {
+		fld := fields[cond.Field]
+		getter := obj + ".Get" + fld.Name + "()"
+
+		op := "=="
+		if cond.Negate {
+			op = "!="
+		}
+
+		if cond.Value == ds.IndexFilterNull {
+			conds = append(conds, getter+" "+op+" nil")
+			continue
+		}
+
+		lit, err := fld.Literal(cond.Value)
+		if err != nil {
+			return "", fmt.Errorf("invalid filter value of index %s: %w", ind.Name, err)
+		}
+
+		value := getter
+		if fld.Nullable {
+			value = "*" + getter
+		}
+
+		cmp := value + " " + op + " " + lit
+
+		// Логические значения не сравниваются с литералом
+		if fld.Format == octopus.Bool {
+			cmp = value
+			if (lit == "true") == cond.Negate {
+				cmp = "!" + value
+			}
+		}
+
+		if fld.Nullable {
+			cmp = "(" + getter + " != nil && " + cmp + ")"
+		}
+
+		conds = append(conds, cmp)
+	}
+
+	return strings.Join(conds, " && "), nil
+}
+
+// indexFilterDecl условие частичного индекса в том виде, в котором оно записывается в декларации
+func indexFilterDecl(fields []ds.FieldDeclaration, ind ds.IndexDeclaration) string {
+	conds := make([]string, 0, len(ind.Filter))
+
+	for _, cond := range ind.Filter {
+		op := "="
+		if cond.Negate {
+			op = "!="
+		}
+
+		conds = append(conds, fields[cond.Field].Name+op+cond.Value)
+	}
+
+	return strings.Join(conds, ",")
+}
+
+// docCommentWidth ширина строки комментария, по которой переносятся слова в docComment
+const docCommentWidth = 120
+
+// docComment оформляет текст как комментарий Go, перенося слова на новую строку по ширине docCommentWidth
+func docComment(text string) string {
+	lines := []string{}
+	line := "//"
+
+	for _, word := range strings.Fields(text) {
+		if line != "//" && utf8.RuneCountInString(line)+1+utf8.RuneCountInString(word) > docCommentWidth {
+			lines = append(lines, line)
+			line = "//"
+		}
+
+		line += " " + word
+	}
+
+	return strings.Join(append(lines, line), "\n")
+}
+
+// zeroTypes литералы нулевых значений встроенных типов и типов форматов полей
+var zeroTypes = map[string]string{
+	"string":          `""`,
+	"bool":            "false",
+	"int":             "0",
+	"int8":            "0",
+	"int16":           "0",
+	"int32":           "0",
+	"int64":           "0",
+	"uint":            "0",
+	"uint8":           "0",
+	"uint16":          "0",
+	"uint32":          "0",
+	"uint64":          "0",
+	"uintptr":         "0",
+	"byte":            "0",
+	"rune":            "0",
+	"float32":         "0",
+	"float64":         "0",
+	"complex64":       "0",
+	"complex128":      "0",
+	"any":             "nil",
+	"error":           "nil",
+	"uuid.UUID":       "uuid.Nil",
+	"time.Time":       "time.Time{}",
+	"decimal.Decimal": "decimal.Decimal{}",
+}
+
+// Способы сравнения значений поля при упорядочивании записей по первичному ключу
+const (
+	orderOrdered = "ordered" // Базовый тип поддерживает операцию <
+	orderBool    = "bool"    // false меньше true
+	orderTime    = "time"    // time.Time, сравнение методом Before
+	orderDecimal = "decimal" // decimal.Decimal, сравнение методом Cmp
+	orderUUID    = "uuid"    // uuid.UUID, сравнение строкового представления, которое сохраняет порядок байтов
+	orderOther   = "other"   // Тип без естественного порядка, сравнение представления %#v
+)
+
+// orderKind возвращает способ сравнения значений поля fld. Типы-перечисления и пользовательские типы
+// сравниваются по формату поля, так как он является их базовым типом
+func orderKind(fld ds.FieldDeclaration) string {
+	if len(fld.Serializer) > 0 || fld.Array || fld.Nullable {
+		return orderOther
+	}
+
+	switch fld.Format {
+	case octopus.Bool:
+		return orderBool
+	case octopus.Time:
+		return orderTime
+	case octopus.Decimal:
+		return orderDecimal
+	case octopus.UUID:
+		return orderUUID
+	case octopus.StringArray, octopus.ByteArray:
+		return orderOther
+	}
+
+	if _, ok := zeroTypes[string(fld.Format)]; ok {
+		return orderOrdered
+	}
+
+	return orderOther
+}
+
+// zeroValueOf возвращает литерал нулевого значения типа goType: nil для указателей, срезов, map, функций,
+// каналов и интерфейсов, составной литерал для массивов и структур. Для именованных типов, базовый тип
+// которых неизвестен, используется *new(T), корректное для любого типа
+func zeroValueOf(goType string) string {
+	if zero, ok := zeroTypes[goType]; ok {
+		return zero
+	}
+
+	for _, prefix := range []string{"*", "[]", "map[", "func", "chan", "<-chan", "interface"} {
+		if strings.HasPrefix(goType, prefix) {
+			return "nil"
+		}
+	}
+
+	if strings.HasPrefix(goType, "[") || strings.HasPrefix(goType, "struct") {
+		return goType + "{}"
+	}
+
+	return "*new(" + goType + ")"
+}
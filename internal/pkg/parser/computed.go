@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+// ParseComputed парсинг описания вычисляемых полей модели
+func ParseComputed(dst *ds.RecordPackage, fields []*ast.Field) error {
+	for _, field := range fields {
+		if field.Names == nil || len(field.Names) != 1 {
+			return &arerror.ErrParseComputedDecl{Err: arerror.ErrNameDeclaration}
+		}
+
+		name := field.Names[0].Name
+
+		fieldType, err := ParseTypeSerializer(dst, name, field.Type)
+		if err != nil {
+			return &arerror.ErrParseComputedDecl{Name: name, Err: err}
+		}
+
+		computed := ds.FieldDeclaration{
+			Name:       name,
+			Format:     octopus.Format(fieldType),
+			Mutators:   []string{},
+			Serializer: []string{},
+			Compute: ds.Compute{
+				Func:       name,
+				ImportName: "compute" + name,
+			},
+		}
+
+		if err := ParseComputedTag(&computed, field); err != nil {
+			return fmt.Errorf("error parse computed tag: %w", err)
+		}
+
+		if computed.Compute.Pkg == "" {
+			return &arerror.ErrParseComputedDecl{Name: name, Err: arerror.ErrParseComputedPackageNotDefined}
+		}
+
+		if len(computed.Compute.Fields) == 0 {
+			return &arerror.ErrParseComputedDecl{Name: name, Err: arerror.ErrParseComputedFieldsNotDefined}
+		}
+
+		imp, err := dst.FindOrAddImport(computed.Compute.Pkg, computed.Compute.ImportName)
+		if err != nil {
+			return &arerror.ErrParseComputedDecl{Name: name, Err: err}
+		}
+
+		computed.Compute.ImportName = imp.ImportName
+
+		if err = dst.AddComputedField(computed); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ParseComputedTag парсинг тегов вычисляемого поля
+func ParseComputedTag(computed *ds.FieldDeclaration, field *ast.Field) error {
+	tagParam, err := splitTag(field, CheckFlagEmpty, map[TagNameType]ParamValueRule{})
+	if err != nil {
+		return &arerror.ErrParseComputedDecl{Name: computed.Name, Err: err}
+	}
+
+	for _, kv := range tagParam {
+		switch kv[0] {
+		case "pkg":
+			computed.Compute.Pkg = kv[1]
+		case "func":
+			computed.Compute.Func = kv[1]
+		case string(APITag):
+			if !validAPIName(kv[1]) {
+				return &arerror.ErrParseComputedTagDecl{Name: computed.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+			}
+
+			computed.APIName = kv[1]
+		case "fields":
+			for _, name := range strings.Split(kv[1], ",") {
+				if name == "" {
+					return &arerror.ErrParseComputedTagDecl{Name: computed.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				computed.Compute.Fields = append(computed.Compute.Fields, name)
+			}
+		default:
+			return &arerror.ErrParseComputedTagDecl{Name: computed.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
+		}
+	}
+
+	return nil
+}
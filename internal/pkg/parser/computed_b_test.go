@@ -0,0 +1,62 @@
+package parser_test
+
+import (
+	"go/ast"
+	"testing"
+
+	"gotest.tools/assert"
+	"gotest.tools/assert/cmp"
+
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/parser"
+)
+
+func TestParseComputed(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    ds.Compute
+		wantErr bool
+	}{
+		{
+			name: "default func",
+			tag:  "pkg:github.com/mailru/activerecord/notexistsfolder/compute;fields:First,Last",
+			want: ds.Compute{Pkg: "github.com/mailru/activerecord/notexistsfolder/compute", Func: "FullName", ImportName: "computeFullName", Fields: []string{"First", "Last"}},
+		},
+		{
+			name: "custom func",
+			tag:  "pkg:github.com/mailru/activerecord/notexistsfolder/compute;func:JoinName;fields:First",
+			want: ds.Compute{Pkg: "github.com/mailru/activerecord/notexistsfolder/compute", Func: "JoinName", ImportName: "computeFullName", Fields: []string{"First"}},
+		},
+		{name: "without pkg", tag: "fields:First,Last", wantErr: true},
+		{name: "without fields", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/compute", wantErr: true},
+		{name: "empty field name", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/compute;fields:First,", wantErr: true},
+		{name: "unknown tag", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/compute;fields:First;size:5", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := ds.NewRecordPackage()
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "FullName"}},
+					Tag:   &ast.BasicLit{Value: "`ar:\"" + tt.tag + "\"`"},
+					Type:  &ast.Ident{Name: "string"},
+				},
+			}
+
+			err := parser.ParseComputed(dst, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseComputed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assert.Check(t, cmp.Len(dst.ComputedFields, 1))
+			assert.Check(t, cmp.DeepEqual(dst.ComputedFields[0].Compute, tt.want))
+			assert.Check(t, cmp.Equal(string(dst.ComputedFields[0].Format), "string"))
+		})
+	}
+}
@@ -3,17 +3,102 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"go/token"
+	"path"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/iproto/util/text"
 	"github.com/mailru/activerecord/pkg/octopus"
 )
 
+// parseEnum парсинг списка значений перечисления. Значение задаётся в виде `Name=Value`,
+// либо только значением, тогда имя константы формируется из значения в PascalCase
+func parseEnum(str string) ([]ds.EnumValue, error) {
+	ret := []ds.EnumValue{}
+
+	for _, item := range strings.Split(str, ",") {
+		name, value, found := strings.Cut(item, "=")
+		if !found {
+			value = name
+			name = text.ToPascalCase(value)
+		}
+
+		if name == "" || value == "" {
+			return nil, arerror.ErrParseTagValueInvalid
+		}
+
+		ret = append(ret, ds.EnumValue{Name: name, Value: value})
+	}
+
+	return ret, nil
+}
+
+// parseValidationTag парсинг тега ограничения на значение поля модели или входного параметра процедуры
+func parseValidationTag(kv []string, v *ds.Validation) error {
+	if TagNameType(kv[0]) == RequiredTag {
+		v.Required = true
+		return nil
+	}
+
+	if len(kv) != 2 || kv[1] == "" {
+		return arerror.ErrParseTagValueInvalid
+	}
+
+	switch TagNameType(kv[0]) {
+	case MinTag:
+		v.Min = kv[1]
+	case MaxTag:
+		v.Max = kv[1]
+	case RegexTag:
+		// Значение тега записывается по правилам строки Go, поэтому обратная косая черта в нём удвоена
+		regex, err := strconv.Unquote(`"` + kv[1] + `"`)
+		if err != nil {
+			return arerror.ErrParseTagValueInvalid
+		}
+
+		v.Regex = regex
+	case MinLenTag, MaxLenTag:
+		length, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil || length <= 0 {
+			return arerror.ErrParseTagValueInvalid
+		}
+
+		if TagNameType(kv[0]) == MinLenTag {
+			v.MinLen = length
+		} else {
+			v.MaxLen = length
+		}
+	}
+
+	return nil
+}
+
+// validAPIName проверяет имя поля в JSON из тега api: "-" или непустая строка из букв, цифр, '_', '-' и '.'
+func validAPIName(name string) bool {
+	if name == ds.APINameOmit {
+		return true
+	}
+
+	if name == "" {
+		return false
+	}
+
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '-' && r != '.' {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Функция парсинга тегов полей модели
 func ParseFieldsTag(field *ast.Field, newfield *ds.FieldDeclaration, newindex *ds.IndexDeclaration) error {
-	tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue})
+	tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue, DiscriminatorTag: ParamNotNeedValue, NullableTag: ParamNotNeedValue, SensitiveTag: ParamNotNeedValue, ImmutableTag: ParamNotNeedValue, TouchTag: ParamNotNeedValue, RequiredTag: ParamNotNeedValue})
 	if err != nil {
 		return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: string(newfield.Format), Err: err}
 	}
@@ -43,6 +128,84 @@ func ParseFieldsTag(field *ast.Field, newfield *ds.FieldDeclaration, newindex *d
 				}
 			case SerializerTag:
 				newfield.Serializer = strings.Split(kv[1], ",")
+			case SwappableTag:
+				newfield.Swappable = kv[1]
+			case VersionTag:
+				newfield.Version = kv[1]
+			case LeaseTag:
+				if kv[1] != ds.LeaseOwner && kv[1] != ds.LeaseExpire {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.Lease = kv[1]
+			case DiscriminatorTag:
+				newfield.Discriminator = true
+			case NullableTag:
+				newfield.Nullable = true
+			case SensitiveTag:
+				newfield.Sensitive = true
+			case ImmutableTag:
+				newfield.Immutable = true
+			case TouchTag:
+				newfield.Touch = true
+			case APITag:
+				if !validAPIName(kv[1]) {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.APIName = kv[1]
+			case DefaultTag:
+				if kv[1] == "" {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.Default = kv[1]
+			case StorageTag:
+				if kv[1] == "" {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.StorageName = kv[1]
+			case TimestampTag:
+				if !ds.ValidTimestamp(kv[1]) {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.Timestamp = kv[1]
+			case RequiredTag, MinTag, MaxTag, RegexTag, MinLenTag, MaxLenTag:
+				if err := parseValidationTag(kv, &newfield.Validation); err != nil {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: strings.Join(kv[1:], ""), Err: err}
+				}
+			case EnumTag:
+				enum, err := parseEnum(kv[1])
+				if err != nil {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: err}
+				}
+
+				newfield.Enum = enum
+			case TypeTag:
+				// Тип задаётся полным путём пакета и именем типа: github.com/project/domain.Email
+				sep := strings.LastIndex(kv[1], ".")
+				if sep <= 0 || sep < strings.LastIndex(kv[1], "/") || !token.IsIdentifier(kv[1][sep+1:]) {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.TypeOverride = ds.TypeOverride{Pkg: kv[1][:sep], Type: kv[1][sep+1:]}
+			case PayloadTag:
+				newfield.Payload = map[string]string{}
+
+				for _, typeSerializer := range strings.Split(kv[1], ",") {
+					typeName, serializerName, found := strings.Cut(typeSerializer, "=")
+					if !found || typeName == "" || serializerName == "" {
+						return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+					}
+
+					if _, ex := newfield.Payload[typeName]; ex {
+						return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrDuplicate}
+					}
+
+					newfield.Payload[typeName] = serializerName
+				}
 			default:
 				return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 			}
@@ -55,7 +218,15 @@ func ParseFieldsTag(field *ast.Field, newfield *ds.FieldDeclaration, newindex *d
 // Функция парсинга полей модели
 func ParseFields(dst *ds.RecordPackage, fields []*ast.Field) error {
 	for _, field := range fields {
-		if field.Names == nil || len(field.Names) != 1 {
+		if field.Names == nil {
+			if err := parseMixinField(dst, field); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if len(field.Names) != 1 {
 			return &arerror.ErrParseTypeFieldDecl{Err: arerror.ErrNameDeclaration}
 		}
 
@@ -72,7 +243,36 @@ func ParseFields(dst *ds.RecordPackage, fields []*ast.Field) error {
 		switch t := field.Type.(type) {
 		case *ast.Ident:
 			newfield.Format = octopus.Format(t.String())
+		case *ast.SelectorExpr:
+			pkg, ok := t.X.(*ast.Ident)
+			if !ok {
+				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: fmt.Sprintf("%T", t), Err: arerror.ErrUnknown}
+			}
+
+			newfield.Format = octopus.Format(pkg.Name + "." + t.Sel.Name)
+
+			importPath, ok := ds.ExternalFormats[newfield.Format]
+			if !ok {
+				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: string(newfield.Format), Err: arerror.ErrUnknown}
+			}
+
+			if _, err := dst.AddImport(importPath); err != nil {
+				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: string(newfield.Format), Err: err}
+			}
 		case *ast.ArrayType:
+			elt, ok := t.Elt.(*ast.Ident)
+			if !ok {
+				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: fmt.Sprintf("%T", t.Elt), Err: arerror.ErrUnknown}
+			}
+
+			// Срез базового типа описывает поле-массив, формат поля задаёт тип элементов
+			if t.Len == nil && elt.Name != "byte" {
+				newfield.Format = octopus.Format(elt.Name)
+				newfield.Array = true
+
+				break
+			}
+
 			//Todo точно ли массив надо, а не срез?
 			if t.Elt.(*ast.Ident).Name != "byte" {
 				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: t.Elt.(*ast.Ident).Name, Err: arerror.ErrParseFieldArrayOfNotByte}
@@ -91,6 +291,15 @@ func ParseFields(dst *ds.RecordPackage, fields []*ast.Field) error {
 			return fmt.Errorf("error ParseFieldsTag: %w", err)
 		}
 
+		if !newfield.TypeOverride.Empty() {
+			importName, err := typeOverrideImport(dst, newfield.TypeOverride.Pkg)
+			if err != nil {
+				return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: string(newfield.Format), Err: err}
+			}
+
+			newfield.TypeOverride.ImportName = importName
+		}
+
 		if err := dst.AddField(newfield); err != nil {
 			return err
 		}
@@ -109,13 +318,45 @@ func ParseFields(dst *ds.RecordPackage, fields []*ast.Field) error {
 	return nil
 }
 
-// ParseProcFieldsTag парсинг тегов полей декларации процедуры
-func ParseProcFieldsTag(index int, field *ast.Field, newfield *ds.ProcFieldDeclaration) error {
-	tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue})
+// typeOverrideImport добавляет импорт пакета пользовательского типа pkg и возвращает имя, под которым пакет
+// доступен в сгенерированном коде. Импорт, объявленный без псевдонима, используется под последним элементом
+// пути, только если он может быть именем пакета, иначе требуется псевдоним. Новый импорт добавляется
+// с именем, выведенным из пути (без версии vN и недопустимых символов)
+func typeOverrideImport(dst *ds.RecordPackage, pkg string) (string, error) {
+	if imp, err := dst.FindImport(pkg); err == nil {
+		if imp.ImportName != "" {
+			return imp.ImportName, nil
+		}
+
+		if name, ok := ds.ImportNameFromPath(pkg); ok && name == path.Base(pkg) {
+			return name, nil
+		}
+
+		return "", &arerror.ErrParseImportDecl{Path: pkg, Err: arerror.ErrParseImportAliasRequired}
+	}
+
+	name, ok := ds.ImportNameFromPath(pkg)
+	if !ok {
+		return "", &arerror.ErrParseImportDecl{Path: pkg, Err: arerror.ErrParseImportAliasRequired}
+	}
+
+	if _, err := dst.AddImport(pkg, name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// ParseProcFieldsTag парсинг тегов полей декларации процедуры. Если порядковый номер выходного параметра
+// не указан явно, то он вычисляется по количеству уже объявленных параметров в основном тупле или в группе
+func ParseProcFieldsTag(dst *ds.RecordPackage, field *ast.Field, newfield *ds.ProcFieldDeclaration) error {
+	tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue, RequiredTag: ParamNotNeedValue})
 	if err != nil {
 		return &arerror.ErrParseTypeFieldDecl{Name: newfield.Name, FieldType: string(newfield.Format), Err: err}
 	}
 
+	orderDeclared := false
+
 	if len(tagParam) > 0 {
 		for _, kv := range tagParam {
 			switch TagNameType(kv[0]) {
@@ -125,16 +366,22 @@ func ParseProcFieldsTag(index int, field *ast.Field, newfield *ds.ProcFieldDecla
 			case ProcOutputParamTag:
 				//результат бинарной операции 0|OUT => OUT; 1|OUT => INOUT (3); 2|OUT => OUT;
 				newfield.Type = newfield.Type | ds.OUT
-				orderIdx := index
 
 				if len(kv) == 2 {
-					orderIdx, err = strconv.Atoi(kv[1])
+					newfield.OrderIndex, err = strconv.Atoi(kv[1])
 					if err != nil {
 						return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
 					}
+
+					orderDeclared = true
+				}
+			case ProcGroupTag:
+				// Имя группы становится частью имени типа строки группы, поэтому должно быть экспортируемым идентификатором
+				if len(kv) != 2 || !token.IsIdentifier(kv[1]) || !token.IsExported(kv[1]) {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: strings.Join(kv[1:], ""), Err: arerror.ErrParseTagValueInvalid}
 				}
 
-				newfield.OrderIndex = orderIdx
+				newfield.Group = kv[1]
 			case SizeTag:
 				if kv[1] != "" {
 					size, err := strconv.ParseInt(kv[1], 10, 64)
@@ -146,12 +393,26 @@ func ParseProcFieldsTag(index int, field *ast.Field, newfield *ds.ProcFieldDecla
 				}
 			case SerializerTag:
 				newfield.Serializer = strings.Split(kv[1], ",")
+			case RequiredTag, MinTag, MaxTag, RegexTag, MinLenTag, MaxLenTag:
+				if err := parseValidationTag(kv, &newfield.Validation); err != nil {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: strings.Join(kv[1:], ""), Err: err}
+				}
+			case EnumTag:
+				if len(kv) != 2 || kv[1] == "" {
+					return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: strings.Join(kv[1:], ""), Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newfield.Enum = strings.Split(kv[1], ",")
 			default:
 				return &arerror.ErrParseTypeFieldTagDecl{Name: newfield.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 			}
 		}
 	}
 
+	if newfield.Type&ds.OUT != 0 && !orderDeclared {
+		newfield.OrderIndex = dst.NextProcOutIndex(newfield.Group)
+	}
+
 	return nil
 }
 
@@ -167,7 +428,7 @@ func ParseProcFields(dst *ds.RecordPackage, fields []*ast.Field) error {
 			Serializer: []string{},
 		}
 
-		if err := ParseProcFieldsTag(len(dst.ProcOutFields), field, &newField); err != nil {
+		if err := ParseProcFieldsTag(dst, field, &newField); err != nil {
 			return fmt.Errorf("error ParseFieldsTag: %w", err)
 		}
 
@@ -2,10 +2,12 @@ package parser
 
 import (
 	"go/ast"
+	"path"
 	"reflect"
 	"testing"
 
 	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/pkg/octopus"
 )
 
 func TestParseFields(t *testing.T) {
@@ -30,7 +32,27 @@ func TestParseFields(t *testing.T) {
 					{
 						Names: []*ast.Ident{{Name: "BarID"}},
 						Type:  &ast.Ident{Name: "int"},
-						Tag:   &ast.BasicLit{Value: "`" + `ar:""` + "`"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"storage:bar_id;sensitive;immutable"` + "`"},
+					},
+					{
+						Names: []*ast.Ident{{Name: "Nick"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"nullable;default:guest;api:nickname"` + "`"},
+					},
+					{
+						Names: []*ast.Ident{{Name: "Login"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"required;minlen:2;maxlen:32;regex:^\\w+:\\d*$"` + "`"},
+					},
+					{
+						Names: []*ast.Ident{{Name: "Age"}},
+						Type:  &ast.Ident{Name: "int32"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"min:18;max:150"` + "`"},
+					},
+					{
+						Names: []*ast.Ident{{Name: "SeenAt"}},
+						Type:  &ast.Ident{Name: "int64"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"touch"` + "`"},
 					},
 				},
 			},
@@ -40,9 +62,13 @@ func TestParseFields(t *testing.T) {
 				Namespace: ds.NamespaceDeclaration{},
 				Fields: []ds.FieldDeclaration{
 					{Name: "ID", Format: "int", PrimaryKey: true, Mutators: []string{}, Serializer: []string{}},
-					{Name: "BarID", Format: "int", PrimaryKey: false, Mutators: []string{}, Serializer: []string{}},
+					{Name: "BarID", Format: "int", PrimaryKey: false, Mutators: []string{}, Serializer: []string{}, StorageName: "bar_id", Sensitive: true, Immutable: true},
+					{Name: "Nick", Format: "string", Mutators: []string{}, Serializer: []string{}, Nullable: true, Default: "guest", APIName: "nickname"},
+					{Name: "Login", Format: "string", Mutators: []string{}, Serializer: []string{}, Validation: ds.Validation{Required: true, MinLen: 2, MaxLen: 32, Regex: `^\w+:\d*$`}},
+					{Name: "Age", Format: "int32", Mutators: []string{}, Serializer: []string{}, Validation: ds.Validation{Min: "18", Max: "150"}},
+					{Name: "SeenAt", Format: "int64", Mutators: []string{}, Serializer: []string{}, Touch: true},
 				},
-				FieldsMap:       map[string]int{"ID": 0, "BarID": 1},
+				FieldsMap:       map[string]int{"ID": 0, "BarID": 1, "bar_id": 1, "Nick": 2, "Login": 3, "Age": 4, "SeenAt": 5},
 				FieldsObjectMap: map[string]ds.FieldObject{},
 				Indexes: []ds.IndexDeclaration{
 					{
@@ -66,20 +92,353 @@ func TestParseFields(t *testing.T) {
 				FlagMap:       map[string]ds.FlagDeclaration{},
 			},
 		},
+		{
+			name: "invalid lease role",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Worker"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"lease:holder"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty default",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Status"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"default:"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid api name",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Login"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"api:user,login"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid timestamp format",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Created"}},
+						Type:  &ast.SelectorExpr{X: &ast.Ident{Name: "time"}, Sel: &ast.Ident{Name: "Time"}},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"timestamp:iso"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid maxlen",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Login"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"maxlen:-1"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "empty enum value",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Status"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"enum:new,"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid payload",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Data"}},
+						Type:  &ast.Ident{Name: "string"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"payload:order"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	rp := ds.NewRecordPackage()
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := ParseFields(rp, tt.args.fields); (err != nil) != tt.wantErr {
+			err := ParseFields(rp, tt.args.fields)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("ParseFields() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
 
+			if err != nil {
+				return
+			}
+
 			if !reflect.DeepEqual(rp.Indexes, tt.want.Indexes) {
 				t.Errorf("ParseFields() = %+v, want %+v", rp, tt.want)
 			}
 		})
 	}
 }
+
+func TestParseFieldsExternalFormat(t *testing.T) {
+	tests := []struct {
+		name       string
+		typ        ast.Expr
+		tag        string
+		wantFormat octopus.Format
+		wantImport string
+		wantErr    bool
+	}{
+		{
+			name:       "uuid",
+			typ:        &ast.SelectorExpr{X: &ast.Ident{Name: "uuid"}, Sel: &ast.Ident{Name: "UUID"}},
+			wantFormat: octopus.UUID,
+			wantImport: "github.com/google/uuid",
+		},
+		{
+			name:       "decimal",
+			typ:        &ast.SelectorExpr{X: &ast.Ident{Name: "decimal"}, Sel: &ast.Ident{Name: "Decimal"}},
+			wantFormat: octopus.Decimal,
+			wantImport: "github.com/shopspring/decimal",
+		},
+		{
+			name:       "time",
+			typ:        &ast.SelectorExpr{X: &ast.Ident{Name: "time"}, Sel: &ast.Ident{Name: "Time"}},
+			tag:        "timestamp:unix_ms",
+			wantFormat: octopus.Time,
+			wantImport: "time",
+		},
+		{name: "unknown selector", typ: &ast.SelectorExpr{X: &ast.Ident{Name: "big"}, Sel: &ast.Ident{Name: "Int"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := ds.NewRecordPackage()
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "ID"}},
+					Type:  tt.typ,
+					Tag:   &ast.BasicLit{Value: "`" + `ar:"` + tt.tag + `"` + "`"},
+				},
+			}
+
+			err := ParseFields(rp, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFields() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if rp.Fields[0].Format != tt.wantFormat {
+				t.Errorf("ParseFields() format = %s, want %s", rp.Fields[0].Format, tt.wantFormat)
+			}
+
+			if _, ex := rp.ImportMap[tt.wantImport]; !ex {
+				t.Errorf("ParseFields() import %s not added", tt.wantImport)
+			}
+
+			if got := rp.Fields[0].PackFormat(); tt.wantFormat == octopus.Time && got != octopus.TimeUnixMs {
+				t.Errorf("ParseFields() pack format = %s, want %s", got, octopus.TimeUnixMs)
+			}
+		})
+	}
+}
+
+func TestParseFieldsTypeOverride(t *testing.T) {
+	tests := []struct {
+		name     string
+		tag      string
+		imports  []string
+		alias    string // Псевдоним объявленных импортов imports
+		want     ds.TypeOverride
+		wantType string
+		wantErr  bool
+	}{
+		{
+			name:     "domain type",
+			tag:      "type:github.com/foo/domain.Email",
+			want:     ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Email", ImportName: "domain"},
+			wantType: "domain.Email",
+		},
+		{
+			name:     "already imported",
+			tag:      "type:github.com/foo/domain.Email",
+			imports:  []string{"github.com/foo/domain"},
+			want:     ds.TypeOverride{Pkg: "github.com/foo/domain", Type: "Email", ImportName: "domain"},
+			wantType: "domain.Email",
+		},
+		{
+			name:     "package without path",
+			tag:      "type:domain.Email",
+			want:     ds.TypeOverride{Pkg: "domain", Type: "Email", ImportName: "domain"},
+			wantType: "domain.Email",
+		},
+		{
+			name:     "versioned module",
+			tag:      "type:github.com/foo/domain/v2.Email",
+			want:     ds.TypeOverride{Pkg: "github.com/foo/domain/v2", Type: "Email", ImportName: "domain"},
+			wantType: "domain.Email",
+		},
+		{
+			name:     "kebab case path",
+			tag:      "type:github.com/foo/go-domain.Email",
+			want:     ds.TypeOverride{Pkg: "github.com/foo/go-domain", Type: "Email", ImportName: "domain"},
+			wantType: "domain.Email",
+		},
+		{
+			name:     "imported with alias",
+			tag:      "type:github.com/foo/domain/v2.Email",
+			imports:  []string{"github.com/foo/domain/v2"},
+			alias:    "dom",
+			want:     ds.TypeOverride{Pkg: "github.com/foo/domain/v2", Type: "Email", ImportName: "dom"},
+			wantType: "dom.Email",
+		},
+		{name: "versioned import without alias", tag: "type:github.com/foo/domain/v2.Email", imports: []string{"github.com/foo/domain/v2"}, wantErr: true},
+		{name: "type name absent", tag: "type:github.com/foo/domain", wantErr: true},
+		{name: "package absent", tag: "type:Email", wantErr: true},
+		{name: "invalid type name", tag: "type:github.com/foo/domain.1Email", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := ds.NewRecordPackage()
+
+			for _, imp := range tt.imports {
+				if _, err := rp.AddImport(imp, tt.alias); err != nil {
+					t.Fatalf("AddImport() error = %v", err)
+				}
+			}
+
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "Mail"}},
+					Type:  &ast.Ident{Name: "string"},
+					Tag:   &ast.BasicLit{Value: "`" + `ar:"` + tt.tag + `"` + "`"},
+				},
+			}
+
+			err := ParseFields(rp, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFields() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(rp.Fields[0].TypeOverride, tt.want) {
+				t.Errorf("ParseFields() type override = %+v, want %+v", rp.Fields[0].TypeOverride, tt.want)
+			}
+
+			if got := rp.Fields[0].NamedType(); got != tt.wantType {
+				t.Errorf("ParseFields() named type = %s, want %s", got, tt.wantType)
+			}
+
+			imp, err := rp.FindImport(tt.want.Pkg)
+			if err != nil {
+				t.Fatalf("ParseFields() import %s not added", tt.want.Pkg)
+			}
+
+			// Имя, выведенное из пути, должно совпадать с именем импорта в сгенерированном коде
+			if name := imp.ImportName; name != "" && name != tt.want.ImportName {
+				t.Errorf("ParseFields() import name = %s, want %s", name, tt.want.ImportName)
+			}
+
+			if imp.ImportName == "" && path.Base(imp.Path) != tt.want.ImportName {
+				t.Errorf("ParseFields() import %s without alias used as %s", imp.Path, tt.want.ImportName)
+			}
+		})
+	}
+}
+
+func TestParseFieldsArray(t *testing.T) {
+	rp := ds.NewRecordPackage()
+	fields := []*ast.Field{
+		{
+			Names: []*ast.Ident{{Name: "Tags"}},
+			Type:  &ast.ArrayType{Elt: &ast.Ident{Name: "int64"}},
+			Tag:   &ast.BasicLit{Value: "`" + `ar:""` + "`"},
+		},
+	}
+
+	if err := ParseFields(rp, fields); err != nil {
+		t.Fatalf("ParseFields() error = %v", err)
+	}
+
+	if got := rp.Fields[0]; !got.Array || got.Format != octopus.Int64 {
+		t.Errorf("ParseFields() = %+v, want array of int64", got)
+	}
+
+	fields[0].Type = &ast.ArrayType{Len: &ast.BasicLit{Value: "4"}, Elt: &ast.Ident{Name: "int64"}}
+
+	if err := ParseFields(ds.NewRecordPackage(), fields); err == nil {
+		t.Errorf("ParseFields() expect error for fixed size array")
+	}
+}
+
+func TestParseProcFieldsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    ds.ProcFieldDeclaration
+		wantErr bool
+	}{
+		{
+			name: "rules and enum",
+			tag:  "input;required;maxlen:16;regex:^[a-z]+$;enum:admin,user",
+			want: ds.ProcFieldDeclaration{Name: "Login", Format: "string", Type: ds.IN, Serializer: []string{}, Validation: ds.Validation{Required: true, MaxLen: 16, Regex: "^[a-z]+$"}, Enum: []string{"admin", "user"}},
+		},
+		{name: "empty enum", tag: "input;enum:", wantErr: true},
+		{name: "invalid length", tag: "input;minlen:-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := ds.NewRecordPackage()
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "Login"}},
+					Type:  &ast.Ident{Name: "string"},
+					Tag:   &ast.BasicLit{Value: "`" + `ar:"` + tt.tag + `"` + "`"},
+				},
+			}
+
+			err := ParseProcFields(rp, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseProcFields() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if err == nil && !reflect.DeepEqual(rp.ProcInFields[0], tt.want) {
+				t.Errorf("ParseProcFields() = %+v, want %+v", rp.ProcInFields[0], tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"go/ast"
+	"strconv"
 	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
@@ -9,7 +10,8 @@ import (
 )
 
 // Парсинг флагов. В описании модели можно указать, что целочисленное значение используется для хранения
-// битовых флагов. В этом случае на поле навешиваются мутаторы SetFlag и ClearFlag
+// битовых флагов. В этом случае на поле навешиваются мутаторы SetFlag и ClearFlag.
+// Номер бита флага по умолчанию равен его позиции в списке, либо задаётся явно в виде `Name=Bit`
 func ParseFlags(dst *ds.RecordPackage, fields []*ast.Field) error {
 	for _, field := range fields {
 		if field.Names == nil || len(field.Names) != 1 {
@@ -29,17 +31,27 @@ func ParseFlags(dst *ds.RecordPackage, fields []*ast.Field) error {
 		for _, kv := range tagParam {
 			switch kv[0] {
 			case "flags":
-				newflag.Flags = strings.Split(kv[1], ",")
+				if err = parseFlagBits(&newflag, kv[1]); err != nil {
+					return &arerror.ErrParseFlagTagDecl{Name: newflag.Name, TagName: kv[0], TagValue: kv[1], Err: err}
+				}
+			case "exclusive":
+				newflag.Exclusive = append(newflag.Exclusive, strings.Split(kv[1], ","))
 			default:
 				return &arerror.ErrParseFlagTagDecl{Name: newflag.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 			}
 		}
 
+		if err = checkExclusiveFlags(newflag); err != nil {
+			return err
+		}
+
 		fldNum, ok := dst.FieldsMap[newflag.Name]
 		if !ok {
 			return &arerror.ErrParseFlagDecl{Name: newflag.Name, Err: arerror.ErrFieldNotExist}
 		}
 
+		newflag.Format = dst.Fields[fldNum].Format
+
 		foundSet, foundClear := false, false
 
 		for _, mut := range dst.Fields[fldNum].Mutators {
@@ -47,7 +59,7 @@ func ParseFlags(dst *ds.RecordPackage, fields []*ast.Field) error {
 				foundSet = true
 			}
 
-			if mut == ds.SetBitMutator {
+			if mut == ds.ClearBitMutator {
 				foundClear = true
 			}
 		}
@@ -67,3 +79,67 @@ func ParseFlags(dst *ds.RecordPackage, fields []*ast.Field) error {
 
 	return nil
 }
+
+// Парсинг списка флагов вида `Active,Blocked=3`
+func parseFlagBits(flag *ds.FlagDeclaration, value string) error {
+	used := map[int]struct{}{}
+
+	for i, decl := range strings.Split(value, ",") {
+		name, bit := decl, i
+
+		if n, b, ok := strings.Cut(decl, "="); ok {
+			var err error
+
+			name = n
+
+			bit, err = strconv.Atoi(b)
+			if err != nil || bit < 0 || bit > 63 {
+				return arerror.ErrParseTagValueInvalid
+			}
+		}
+
+		if _, ex := used[bit]; ex {
+			return arerror.ErrDuplicate
+		}
+
+		used[bit] = struct{}{}
+
+		flag.Flags = append(flag.Flags, name)
+		flag.Bits = append(flag.Bits, bit)
+	}
+
+	return nil
+}
+
+// Проверка групп взаимоисключающих флагов: в группе минимум два известных флага,
+// каждый флаг входит не более чем в одну группу
+func checkExclusiveFlags(flag ds.FlagDeclaration) error {
+	known := map[string]struct{}{}
+	for _, name := range flag.Flags {
+		known[name] = struct{}{}
+	}
+
+	grouped := map[string]struct{}{}
+
+	for _, group := range flag.Exclusive {
+		value := strings.Join(group, ",")
+
+		if len(group) < 2 {
+			return &arerror.ErrParseFlagTagDecl{Name: flag.Name, TagName: "exclusive", TagValue: value, Err: arerror.ErrParseTagValueInvalid}
+		}
+
+		for _, name := range group {
+			if _, ok := known[name]; !ok {
+				return &arerror.ErrParseFlagTagDecl{Name: flag.Name, TagName: "exclusive", TagValue: value, Err: arerror.ErrUnknown}
+			}
+
+			if _, ok := grouped[name]; ok {
+				return &arerror.ErrParseFlagTagDecl{Name: flag.Name, TagName: "exclusive", TagValue: value, Err: arerror.ErrDuplicate}
+			}
+
+			grouped[name] = struct{}{}
+		}
+	}
+
+	return nil
+}
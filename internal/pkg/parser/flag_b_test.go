@@ -0,0 +1,83 @@
+package parser_test
+
+import (
+	"go/ast"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/parser"
+	"github.com/mailru/activerecord/pkg/octopus"
+)
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     string
+		want    ds.FlagDeclaration
+		wantErr bool
+	}{
+		{
+			name: "positional bits",
+			tag:  "flags:Active,Blocked",
+			want: ds.FlagDeclaration{Name: "Status", Flags: []string{"Active", "Blocked"}, Bits: []int{0, 1}, Format: octopus.Uint32},
+		},
+		{
+			name: "explicit bits",
+			tag:  "flags:Active,Deleted=7",
+			want: ds.FlagDeclaration{Name: "Status", Flags: []string{"Active", "Deleted"}, Bits: []int{0, 7}, Format: octopus.Uint32},
+		},
+		{
+			name: "exclusive group",
+			tag:  "flags:Active,Suspended,Closed,Verified;exclusive:Active,Suspended,Closed",
+			want: ds.FlagDeclaration{
+				Name:      "Status",
+				Flags:     []string{"Active", "Suspended", "Closed", "Verified"},
+				Bits:      []int{0, 1, 2, 3},
+				Format:    octopus.Uint32,
+				Exclusive: [][]string{{"Active", "Suspended", "Closed"}},
+			},
+		},
+		{name: "duplicate bit", tag: "flags:Active,Deleted=0", wantErr: true},
+		{name: "exclusive unknown flag", tag: "flags:Active,Closed;exclusive:Active,Suspended", wantErr: true},
+		{name: "exclusive single flag", tag: "flags:Active,Closed;exclusive:Active", wantErr: true},
+		{name: "flag in two groups", tag: "flags:Active,Suspended,Closed;exclusive:Active,Suspended;exclusive:Active,Closed", wantErr: true},
+		{name: "invalid bit", tag: "flags:Active=first", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := ds.NewRecordPackage()
+
+			if err := dst.AddField(ds.FieldDeclaration{Name: "Status", Format: octopus.Uint32, Mutators: []string{}, Serializer: []string{}}); err != nil {
+				t.Fatalf("AddField() error = %v", err)
+			}
+
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "Status"}},
+					Tag:   &ast.BasicLit{Value: "`ar:\"" + tt.tag + "\"`"},
+					Type:  &ast.Ident{Name: "bool"},
+				},
+			}
+
+			err := parser.ParseFlags(dst, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFlags() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := dst.FlagMap["Status"]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFlags() = %+v, want %+v", got, tt.want)
+			}
+
+			wantMutators := []string{ds.SetBitMutator, ds.ClearBitMutator}
+			if got := dst.Fields[0].Mutators; !reflect.DeepEqual(got, wantMutators) {
+				t.Errorf("ParseFlags() mutators = %v, want %v", got, wantMutators)
+			}
+		})
+	}
+}
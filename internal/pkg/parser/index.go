@@ -32,6 +32,7 @@ func ParseIndexPartTag(field *ast.Field, ind *ds.IndexDeclaration, indexMap map[
 
 			exInd = indexes[exIndNum]
 			ind.Num = exInd.Num
+			ind.Filter = exInd.Filter
 		case "fieldnum":
 			fNum, err := strconv.ParseInt(kv[1], 10, 64)
 			if err != nil {
@@ -99,7 +100,7 @@ func ParseIndexPart(dst *ds.RecordPackage, fields []*ast.Field) error {
 }
 
 func ParseIndexTag(field *ast.Field, ind *ds.IndexDeclaration, fieldsMap map[string]int) error {
-	tagParam, err := splitTag(field, CheckFlagEmpty, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue})
+	tagParam, err := splitTag(field, CheckFlagEmpty, map[TagNameType]ParamValueRule{PrimaryKeyTag: ParamNotNeedValue, UniqueTag: ParamNotNeedValue, ScatterTag: ParamNotNeedValue})
 	if err != nil {
 		return &arerror.ErrParseTypeIndexDecl{IndexType: "index", Name: ind.Name, Err: err}
 	}
@@ -110,19 +111,55 @@ func ParseIndexTag(field *ast.Field, ind *ds.IndexDeclaration, fieldsMap map[str
 			ind.Primary = true
 		case UniqueTag:
 			ind.Unique = true
+		case ScatterTag:
+			ind.Scatter = true
 		case SelectorTag:
 			ind.Selector = kv[1]
 		case FieldsTag:
 			for _, fieldName := range strings.Split(kv[1], ",") {
 				if fldNum, ex := fieldsMap[fieldName]; !ex {
 					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrFieldNotExist}
-				} else if _, ex := ind.FieldsMap[fieldName]; ex {
+				} else if _, ex := ind.FieldsMap[fieldName]; ex || indexHasField(ind, fldNum) {
 					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrDuplicate}
 				} else {
 					ind.FieldsMap[fieldName] = ds.IndexField{IndField: fldNum, Order: ds.IndexOrderAsc}
 					ind.Fields = append(ind.Fields, fldNum)
 				}
 			}
+		case ProjectionTag:
+			projMap := map[string]struct{}{}
+
+			for _, fieldName := range strings.Split(kv[1], ",") {
+				fldNum, ex := fieldsMap[fieldName]
+				if !ex {
+					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrFieldNotExist}
+				}
+
+				if _, ex := projMap[fieldName]; ex {
+					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrDuplicate}
+				}
+
+				projMap[fieldName] = struct{}{}
+				ind.Projection = append(ind.Projection, fldNum)
+			}
+		case FilterTag:
+			for _, cond := range strings.Split(kv[1], ",") {
+				fieldName, value, negate := strings.Cut(cond, "!=")
+				if !negate {
+					fieldName, value, _ = strings.Cut(cond, "=")
+				}
+
+				if fieldName == "" || value == "" {
+					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				fldNum, ex := fieldsMap[fieldName]
+				if !ex {
+					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrFieldNotExist}
+				}
+
+				ind.Filter = append(ind.Filter, ds.IndexFilterCond{Field: fldNum, Value: value, Negate: negate})
+			}
 		case OrderDescTag:
 			for _, fn := range strings.Split(kv[1], ",") {
 				if _, ex := fieldsMap[fn]; !ex {
@@ -131,6 +168,7 @@ func ParseIndexTag(field *ast.Field, ind *ds.IndexDeclaration, fieldsMap map[str
 					return &arerror.ErrParseTypeIndexTagDecl{IndexType: "index", Name: ind.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrFieldNotExist}
 				} else {
 					indField.Order = ds.IndexOrderDesc
+					ind.FieldsMap[fn] = indField
 				}
 			}
 		default:
@@ -141,6 +179,18 @@ func ParseIndexTag(field *ast.Field, ind *ds.IndexDeclaration, fieldsMap map[str
 	return nil
 }
 
+// indexHasField проверяет, входит ли поле в индекс. Поле может быть указано
+// как по имени в модели, так и по имени в хранилище, поэтому дубли ищутся по номеру поля
+func indexHasField(ind *ds.IndexDeclaration, fldNum int) bool {
+	for _, f := range ind.Fields {
+		if f == fldNum {
+			return true
+		}
+	}
+
+	return false
+}
+
 func ParseIndexes(dst *ds.RecordPackage, fields []*ast.Field) error {
 	if len(fields) == 0 {
 		return nil
@@ -118,3 +118,236 @@ func TestParseIndexPart(t *testing.T) {
 		})
 	}
 }
+
+func TestParseIndexes(t *testing.T) {
+	type args struct {
+		fields []*ast.Field
+	}
+
+	prepareRp := func() *ds.RecordPackage {
+		rp := ds.NewRecordPackage()
+		rp.Fields = []ds.FieldDeclaration{
+			{Name: "Field1", Format: "int"},
+			{Name: "Field2", Format: "int"},
+			{Name: "Field3", Format: "string", StorageName: "f3"},
+		}
+		rp.FieldsMap = map[string]int{"Field1": 0, "Field2": 1, "Field3": 2, "f3": 2}
+
+		return rp
+	}
+
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+		want    []ds.IndexDeclaration
+	}{
+		{
+			name: "index with projection",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1Field2"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1,Field2;projection:Field1,Field3"` + "`"},
+					},
+				},
+			},
+			wantErr: false,
+			want: []ds.IndexDeclaration{
+				{
+					Name:     "Field1Field2",
+					Num:      0,
+					Selector: "SelectByField1Field2",
+					Fields:   []int{0, 1},
+					FieldsMap: map[string]ds.IndexField{
+						"Field1": {IndField: 0, Order: 0},
+						"Field2": {IndField: 1, Order: 0},
+					},
+					Projection: []int{0, 2},
+				},
+			},
+		},
+		{
+			name: "index by storage name",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1Field3"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1,f3"` + "`"},
+					},
+				},
+			},
+			wantErr: false,
+			want: []ds.IndexDeclaration{
+				{
+					Name:     "Field1Field3",
+					Num:      0,
+					Selector: "SelectByField1Field3",
+					Fields:   []int{0, 2},
+					FieldsMap: map[string]ds.IndexField{
+						"Field1": {IndField: 0, Order: 0},
+						"f3":     {IndField: 2, Order: 0},
+					},
+				},
+			},
+		},
+		{
+			name: "index with field and its storage name",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field3Field3"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field3,f3"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "index with desc order",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1Field2"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1,Field2;orderdesc:Field2"` + "`"},
+					},
+				},
+			},
+			wantErr: false,
+			want: []ds.IndexDeclaration{
+				{
+					Name:     "Field1Field2",
+					Num:      0,
+					Selector: "SelectByField1Field2",
+					Fields:   []int{0, 1},
+					FieldsMap: map[string]ds.IndexField{
+						"Field1": {IndField: 0, Order: ds.IndexOrderAsc},
+						"Field2": {IndField: 1, Order: ds.IndexOrderDesc},
+					},
+				},
+			},
+		},
+		{
+			name: "index with filter",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1;filter:Field2=1,f3!=null"` + "`"},
+					},
+				},
+			},
+			wantErr: false,
+			want: []ds.IndexDeclaration{
+				{
+					Name:     "Field1",
+					Num:      0,
+					Selector: "SelectByField1",
+					Fields:   []int{0},
+					FieldsMap: map[string]ds.IndexField{
+						"Field1": {IndField: 0, Order: 0},
+					},
+					Filter: []ds.IndexFilterCond{{Field: 1, Value: "1"}, {Field: 2, Value: "null", Negate: true}},
+				},
+			},
+		},
+		{
+			name: "index with scatter",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field2"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field2;scatter"` + "`"},
+					},
+				},
+			},
+			wantErr: false,
+			want: []ds.IndexDeclaration{
+				{
+					Name:     "Field2",
+					Num:      0,
+					Selector: "SelectByField2",
+					Fields:   []int{1},
+					FieldsMap: map[string]ds.IndexField{
+						"Field2": {IndField: 1, Order: 0},
+					},
+					Scatter: true,
+				},
+			},
+		},
+		{
+			name: "filter with unknown field",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1;filter:Field4=1"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "filter without value",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1;filter:Field2"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "projection with unknown field",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1Field2"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1,Field2;projection:Field4"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "projection with duplicate field",
+			args: args{
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Field1Field2"}},
+						Type:  &ast.Ident{Name: "bool"},
+						Tag:   &ast.BasicLit{Value: "`" + `ar:"fields:Field1,Field2;projection:Field1,Field1"` + "`"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rp := prepareRp()
+
+			if err := parser.ParseIndexes(rp, tt.args.fields); (err != nil) != tt.wantErr {
+				t.Errorf("ParseIndexes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			assert.Check(t, cmp.DeepEqual(tt.want, rp.Indexes), "Invalid response, test `%s`", tt.name)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+)
+
+// ParseMixins собирает группы полей (структуры Mixin*) из файла декларации в mixins.
+// Группы полей собираются из всех файлов до разбора моделей, поэтому группу можно
+// объявить в отдельном файле и подключить в модели из других файлов
+func ParseMixins(srcFileName string, mixins map[string]ds.MixinDeclaration) ([]string, error) {
+	fset := token.NewFileSet()
+
+	node, err := parser.ParseFile(fset, srcFileName, nil, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("error parse file `%s`: %w", srcFileName, err)
+	}
+
+	names := []string{}
+
+	for _, decl := range node.Decls {
+		genD, ok := decl.(*ast.GenDecl)
+		if !ok || genD.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range genD.Specs {
+			currType, ok := spec.(*ast.TypeSpec)
+			if !ok {
+				continue
+			}
+
+			curr, ok := currType.Type.(*ast.StructType)
+			if !ok || curr.Fields == nil || !strings.HasPrefix(currType.Name.Name, string(Mixin)) {
+				continue
+			}
+
+			name := strings.TrimPrefix(currType.Name.Name, string(Mixin))
+
+			if _, ex := mixins[name]; ex {
+				return nil, &arerror.ErrParseMixinDecl{Name: name, Err: arerror.ErrRedefined}
+			}
+
+			mixin, err := ParseMixin(name, curr.Fields.List)
+			if err != nil {
+				return nil, fmt.Errorf("error parse mixin in `%s`: %w", srcFileName, err)
+			}
+
+			mixins[name] = mixin
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+// ParseMixin парсинг полей группы полей name. Поля описываются так же, как в Fields*,
+// но не могут объявлять индексы и подключать другие группы полей
+func ParseMixin(name string, fields []*ast.Field) (ds.MixinDeclaration, error) {
+	if !PublicNameChecker.MatchString(name) {
+		return ds.MixinDeclaration{}, &arerror.ErrParseMixinDecl{Name: name, Err: arerror.ErrParseNodeNameInvalid}
+	}
+
+	rc := ds.NewRecordPackage()
+
+	if err := ParseFields(rc, fields); err != nil {
+		return ds.MixinDeclaration{}, &arerror.ErrParseMixinDecl{Name: name, Err: err}
+	}
+
+	if len(rc.Indexes) != 0 {
+		return ds.MixinDeclaration{}, &arerror.ErrParseMixinDecl{Name: name, Err: arerror.ErrParseMixinIndex}
+	}
+
+	for i := range rc.Fields {
+		rc.Fields[i].Mixin = name
+	}
+
+	return ds.MixinDeclaration{Name: name, Fields: rc.Fields}, nil
+}
+
+// parseMixinField подключение группы полей, встроенной в структуру Fields*. Поля группы добавляются
+// в модель в порядке их объявления в группе на место встраивания
+func parseMixinField(dst *ds.RecordPackage, field *ast.Field) error {
+	ident, ok := field.Type.(*ast.Ident)
+	if !ok || !strings.HasPrefix(ident.Name, string(Mixin)) {
+		return &arerror.ErrParseTypeFieldDecl{FieldType: fmt.Sprintf("%T", field.Type), Err: arerror.ErrNameDeclaration}
+	}
+
+	name := strings.TrimPrefix(ident.Name, string(Mixin))
+
+	mixin, ok := dst.Mixins[name]
+	if !ok {
+		return &arerror.ErrParseMixinDecl{Name: name, Err: arerror.ErrParseMixinNotFound}
+	}
+
+	for _, f := range mixin.Fields {
+		if importPath, ok := ds.ExternalFormats[f.Format]; ok {
+			if _, err := dst.AddImport(importPath); err != nil {
+				return &arerror.ErrParseTypeFieldDecl{Name: f.Name, FieldType: string(f.Format), Err: err}
+			}
+		}
+
+		if !f.TypeOverride.Empty() {
+			importName, err := typeOverrideImport(dst, f.TypeOverride.Pkg)
+			if err != nil {
+				return &arerror.ErrParseTypeFieldDecl{Name: f.Name, FieldType: string(f.Format), Err: err}
+			}
+
+			f.TypeOverride.ImportName = importName
+		}
+
+		if err := dst.AddField(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
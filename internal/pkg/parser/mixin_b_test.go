@@ -0,0 +1,187 @@
+package parser_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/arerror"
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/parser"
+)
+
+func TestParseMixins(t *testing.T) {
+	mixinDecl := `package repository
+
+type MixinAudit struct {
+	CreatedAt int64 ` + "`" + `ar:"immutable"` + "`" + `
+	UpdatedAt int64 ` + "`" + `ar:""` + "`" + `
+	CreatedBy string ` + "`" + `ar:"size:64"` + "`" + `
+}
+`
+
+	tests := []struct {
+		name       string
+		model      string
+		wantFields []string
+		wantIndex  []int
+		wantErr    error
+	}{
+		{
+			name: "inline mixin",
+			model: `package repository
+
+//ar:serverHost:127.0.0.1;serverPort:11111;serverTimeout:500
+//ar:namespace:foo
+//ar:backend:tarantool2
+type FieldsFoo struct {
+	ID    int ` + "`" + `ar:"primary_key"` + "`" + `
+	MixinAudit
+	Name  string ` + "`" + `ar:"selector:SelectByName"` + "`" + `
+}
+`,
+			wantFields: []string{"ID", "CreatedAt", "UpdatedAt", "CreatedBy", "Name"},
+			wantIndex:  []int{0, 4},
+		},
+		{
+			name: "conflict with model field",
+			model: `package repository
+
+//ar:serverHost:127.0.0.1;serverPort:11111;serverTimeout:500
+//ar:namespace:foo
+//ar:backend:tarantool2
+type FieldsFoo struct {
+	ID        int ` + "`" + `ar:"primary_key"` + "`" + `
+	UpdatedAt int64 ` + "`" + `ar:""` + "`" + `
+	MixinAudit
+}
+`,
+			wantErr: arerror.ErrParseMixinFieldConflict,
+		},
+		{
+			name: "unknown mixin",
+			model: `package repository
+
+//ar:serverHost:127.0.0.1;serverPort:11111;serverTimeout:500
+//ar:namespace:foo
+//ar:backend:tarantool2
+type FieldsFoo struct {
+	ID int ` + "`" + `ar:"primary_key"` + "`" + `
+	MixinOwner
+}
+`,
+			wantErr: arerror.ErrParseMixinNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			mixinFile := filepath.Join(dir, "mixin.go")
+			modelFile := filepath.Join(dir, "foo.go")
+
+			if err := os.WriteFile(mixinFile, []byte(mixinDecl), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := os.WriteFile(modelFile, []byte(tt.model), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			mixins := map[string]ds.MixinDeclaration{}
+
+			names, err := parser.ParseMixins(mixinFile, mixins)
+			if err != nil || len(names) != 1 {
+				t.Fatalf("ParseMixins() = %v, %v", names, err)
+			}
+
+			rc := ds.NewRecordPackage()
+			rc.Mixins = mixins
+
+			err = parser.Parse(modelFile, rc)
+			if !errorIs(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr != nil {
+				return
+			}
+
+			if len(rc.Fields) != len(tt.wantFields) {
+				t.Fatalf("Parse() fields = %+v, want %v", rc.Fields, tt.wantFields)
+			}
+
+			for i, name := range tt.wantFields {
+				if rc.Fields[i].Name != name || rc.FieldsMap[name] != i {
+					t.Errorf("Parse() field %d = %s, want %s", i, rc.Fields[i].Name, name)
+				}
+			}
+
+			if rc.Fields[1].Mixin != "Audit" || !rc.Fields[1].Immutable || rc.Fields[3].Size != 64 {
+				t.Errorf("Parse() mixin field = %+v", rc.Fields[1])
+			}
+
+			for i, num := range tt.wantIndex {
+				if rc.Indexes[i].Fields[0] != num {
+					t.Errorf("Parse() index %s field = %d, want %d", rc.Indexes[i].Name, rc.Indexes[i].Fields[0], num)
+				}
+			}
+		})
+	}
+}
+
+func TestParseMixinsInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		decl    string
+		wantErr error
+	}{
+		{
+			name: "index in mixin",
+			decl: `package repository
+
+type MixinOwner struct {
+	OwnerID int ` + "`" + `ar:"selector:SelectByOwner"` + "`" + `
+}
+`,
+			wantErr: arerror.ErrParseMixinIndex,
+		},
+		{
+			name: "redefined mixin",
+			decl: `package repository
+
+type MixinOwner struct {
+	OwnerID int ` + "`" + `ar:""` + "`" + `
+}
+
+type MixinOwner struct {
+	OwnerName string ` + "`" + `ar:""` + "`" + `
+}
+`,
+			wantErr: arerror.ErrRedefined,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "mixin.go")
+
+			if err := os.WriteFile(file, []byte(tt.decl), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			if _, err := parser.ParseMixins(file, map[string]ds.MixinDeclaration{}); !errorIs(err, tt.wantErr) {
+				t.Errorf("ParseMixins() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// errorIs проверяет, что ошибка парсинга вызвана ошибкой target. Ошибки парсинга не реализуют Unwrap,
+// поэтому причина ищется в тексте ошибки
+func errorIs(err, target error) bool {
+	if err == nil || target == nil {
+		return err == target
+	}
+
+	return strings.Contains(err.Error(), target.Error())
+}
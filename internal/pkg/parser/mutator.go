@@ -2,6 +2,7 @@ package parser
 
 import (
 	"go/ast"
+	"strconv"
 	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
@@ -32,6 +33,15 @@ func ParseMutators(dst *ds.RecordPackage, fields []*ast.Field) error {
 				mutatorDeclaration.Update = kv[1]
 			case "replace":
 				mutatorDeclaration.Replace = kv[1]
+			case "args":
+				mutatorDeclaration.Args = strings.Split(kv[1], ",")
+			case "arity":
+				arity, err := strconv.Atoi(kv[1])
+				if err != nil || arity < 0 {
+					return &arerror.ErrParseMutatorTagDecl{Name: mutatorDeclaration.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				mutatorDeclaration.Arity = arity
 			default:
 				return &arerror.ErrParseMutatorTagDecl{Name: mutatorDeclaration.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 			}
@@ -53,7 +53,7 @@ func TestParseMutator(t *testing.T) {
 					{
 						Names: []*ast.Ident{{Name: "SimpleTypeMutatorField"}},
 						Tag: &ast.BasicLit{
-							Value: "`ar:\"update:updateSimpleTypeFunc\"`",
+							Value: "`ar:\"update:updateSimpleTypeFunc;args:1,100;arity:2\"`",
 						},
 						Type: &ast.Ident{Name: "int"},
 					},
@@ -92,6 +92,8 @@ func TestParseMutator(t *testing.T) {
 						Type:       "int",
 						ImportName: "mutatorSimpleTypeMutatorField",
 						Update:     "updateSimpleTypeFunc",
+						Args:       []string{"1", "100"},
+						Arity:      2,
 					},
 				},
 				ImportPackage: ds.ImportPackage{
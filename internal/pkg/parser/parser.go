@@ -24,6 +24,8 @@ const (
 	Triggers     StructNameType = "Triggers"
 	Flags        StructNameType = "Flags"
 	Mutators     StructNameType = "Mutators"
+	Computed     StructNameType = "Computed"
+	Mixin        StructNameType = "Mixin"
 )
 
 type TagNameType string
@@ -37,8 +39,38 @@ const (
 	SerializerTag      TagNameType = "serializer"
 	FieldsTag          TagNameType = "fields"
 	OrderDescTag       TagNameType = "orderdesc"
+	ScatterTag         TagNameType = "scatter"
+	ProjectionTag      TagNameType = "projection"
+	FilterTag          TagNameType = "filter"
+	SwappableTag       TagNameType = "swappable"
+	VersionTag         TagNameType = "version"
+	LeaseTag           TagNameType = "lease"
+	DiscriminatorTag   TagNameType = "discriminator"
+	PayloadTag         TagNameType = "payload"
+	JSONTag            TagNameType = "json"
+	MsgpackTag         TagNameType = "msgpack"
+	EncryptTag         TagNameType = "encrypt"
+	ChainTag           TagNameType = "chain"
+	EmbedTag           TagNameType = "embed"
+	NullableTag        TagNameType = "nullable"
+	DefaultTag         TagNameType = "default"
+	StorageTag         TagNameType = "storage"
+	EnumTag            TagNameType = "enum"
+	SensitiveTag       TagNameType = "sensitive"
+	ImmutableTag       TagNameType = "immutable"
+	TouchTag           TagNameType = "touch"
+	APITag             TagNameType = "api"
+	TimestampTag       TagNameType = "timestamp"
+	RequiredTag        TagNameType = "required"
+	MinTag             TagNameType = "min"
+	MaxTag             TagNameType = "max"
+	MinLenTag          TagNameType = "minlen"
+	MaxLenTag          TagNameType = "maxlen"
+	RegexTag           TagNameType = "regex"
+	TypeTag            TagNameType = "type"
 	ProcInputParamTag  TagNameType = "input"
 	ProcOutputParamTag TagNameType = "output"
+	ProcGroupTag       TagNameType = "group"
 )
 
 type TypeName string
@@ -109,6 +141,8 @@ func parseStructNameType(dst *ds.RecordPackage, nodeName string, curr *ast.Struc
 		return ParseProcFields(dst, curr.Fields.List)
 	case Mutators:
 		return ParseMutators(dst, curr.Fields.List)
+	case Computed:
+		return ParseComputed(dst, curr.Fields.List)
 	default:
 		return arerror.ErrUnknown
 	}
@@ -120,6 +154,11 @@ func parseStructType(dst *ds.RecordPackage, name string, doc *ast.CommentGroup,
 		return &arerror.ErrParseGenDecl{Name: name, Err: err}
 	}
 
+	// Группы полей не относятся к модели файла и собираются до разбора моделей в ParseMixins
+	if StructNameType(nodeName) == Mixin {
+		return nil
+	}
+
 	switch StructNameType(nodeName) {
 	case Fields:
 		fallthrough
@@ -223,6 +262,69 @@ func parseDoc(dst *ds.RecordPackage, nodeName string, doc *ast.CommentGroup) err
 					dst.Server.Host = kv[1]
 				case "serverPort":
 					dst.Server.Port = kv[1]
+				case "serverReplicas":
+					replicas := strings.Split(kv[1], ",")
+					for _, replica := range replicas {
+						if host, port, ok := strings.Cut(replica, ":"); !ok || host == "" || port == "" {
+							return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocReplicasDecl}
+						}
+					}
+
+					dst.Server.Replicas = replicas
+				case "sharding":
+					dst.Server.Sharding = kv[1]
+				case "shardBy":
+					dst.Server.ShardBy = kv[1]
+				case "leaseProc":
+					dst.LeaseProc = kv[1]
+				case "softDelete":
+					dst.SoftDelete = kv[1]
+				case "outbox":
+					dst.Outbox = kv[1]
+				case "protoPkg":
+					dst.ProtoPkg = kv[1]
+				case "copyGetters":
+					copyGetters, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocCopyGettersDecl}
+					}
+
+					dst.CopyGetters = copyGetters
+				case "nullableGetters":
+					nullableGetters, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocNullableGettersDecl}
+					}
+
+					dst.NullableGetters = nullableGetters
+				case "prepared":
+					prepared, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocPreparedDecl}
+					}
+
+					dst.Prepared = prepared
+				case "validate":
+					validate, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocValidateDecl}
+					}
+
+					dst.Validate = validate
+				case "trace":
+					trace, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocTraceDecl}
+					}
+
+					dst.Trace = trace
+				case "audit":
+					audit, err := strconv.ParseBool(kv[1])
+					if err != nil {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocAuditDecl}
+					}
+
+					dst.Audit = audit
 				case "serverTimeout":
 					timeout, err := strconv.ParseInt(kv[1], 10, 64)
 					if err != nil {
@@ -230,6 +332,64 @@ func parseDoc(dst *ds.RecordPackage, nodeName string, doc *ast.CommentGroup) err
 					}
 
 					dst.Server.Timeout = timeout
+				case "serverMaxConns", "serverMinConns":
+					conns, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || conns <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocPoolDecl}
+					}
+
+					if kv[0] == "serverMaxConns" {
+						dst.Server.MaxConns = conns
+					} else {
+						dst.Server.MinConns = conns
+					}
+				case "serverRetry", "serverRetryDelay":
+					retry, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || retry <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocRetryDecl}
+					}
+
+					if kv[0] == "serverRetry" {
+						dst.Server.Retry = retry
+					} else {
+						dst.Server.RetryDelay = retry
+					}
+				case "serverConnTimeout":
+					timeout, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || timeout <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocTimeoudDecl}
+					}
+
+					dst.Server.ConnTimeout = timeout
+				case "slowQuery":
+					threshold, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || threshold <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocSlowQueryDecl}
+					}
+
+					dst.Server.SlowQuery = threshold
+				case "cacheSize", "cacheTTL":
+					val, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || val <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocCacheDecl}
+					}
+
+					if kv[0] == "cacheSize" {
+						dst.Server.CacheSize = val
+					} else {
+						dst.Server.CacheTTL = val
+					}
+				case "healthFailures", "healthLatency":
+					val, err := strconv.ParseInt(kv[1], 10, 64)
+					if err != nil || val <= 0 {
+						return &arerror.ErrParseDocDecl{Name: kv[0], Value: kv[1], Err: arerror.ErrParseDocHealthDecl}
+					}
+
+					if kv[0] == "healthFailures" {
+						dst.Server.HealthFailures = val
+					} else {
+						dst.Server.HealthLatency = val
+					}
 				case "namespace":
 					switch StructNameType(nodeName) {
 					case Fields:
@@ -168,6 +168,19 @@ type ProcFieldsFoo struct {
 	InOutParams2    string  ` + "`" + `ar:"input;output:1"` + "`" + `
 	Output  string ` + "`" + `ar:"output:0"` + "`" + `
 }
+`
+
+	textTestGroupsPkg := `package repository
+
+//ar:serverHost:127.0.0.1;serverPort:11111;serverTimeout:500
+//ar:namespace:baz
+//ar:backend:tarantool2
+type ProcFieldsBaz struct {
+	Status    string  ` + "`" + `ar:"output"` + "`" + `
+	ItemID    int64  ` + "`" + `ar:"output;group:Items"` + "`" + `
+	ItemName  string ` + "`" + `ar:"output;group:Items"` + "`" + `
+	Total     int64  ` + "`" + `ar:"output;group:Totals"` + "`" + `
+}
 `
 
 	srcRoot, err := tempDirs.AddTempDir()
@@ -188,6 +201,11 @@ type ProcFieldsFoo struct {
 		return
 	}
 
+	if err = os.WriteFile(filepath.Join(src, "baz.go"), []byte(textTestGroupsPkg), 0644); err != nil {
+		t.Errorf("prepare test files error: %s", err)
+		return
+	}
+
 	type args struct {
 		srcFileName string
 		rc          *ds.RecordPackage
@@ -233,6 +251,51 @@ type ProcFieldsFoo struct {
 				LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
 			},
 		},
+		{
+			name: "proc decl with output groups",
+			args: args{
+				srcFileName: filepath.Join(src, "baz.go"),
+				rc:          ds.NewRecordPackage(),
+			},
+			wantErr: false,
+			want: &ds.RecordPackage{
+				Namespace: ds.NamespaceDeclaration{ObjectName: "baz", PublicName: "Baz", PackageName: "baz"},
+				Server:    ds.ServerDeclaration{Timeout: 500, Host: "127.0.0.1", Port: "11111"},
+				Fields:    []ds.FieldDeclaration{},
+				FieldsMap: map[string]int{},
+				ProcOutFields: ds.ProcFieldDeclarations{
+					0: {Name: "Status", Format: "string", Type: 2, Serializer: []string{}, OrderIndex: 0},
+				},
+				ProcOutGroups: []ds.ProcOutGroup{
+					{
+						Name: "Items",
+						Fields: ds.ProcFieldDeclarations{
+							0: {Name: "ItemID", Format: "int64", Type: 2, Serializer: []string{}, OrderIndex: 0, Group: "Items"},
+							1: {Name: "ItemName", Format: "string", Type: 2, Serializer: []string{}, OrderIndex: 1, Group: "Items"},
+						},
+					},
+					{
+						Name: "Totals",
+						Fields: ds.ProcFieldDeclarations{
+							0: {Name: "Total", Format: "int64", Type: 2, Serializer: []string{}, OrderIndex: 0, Group: "Totals"},
+						},
+					},
+				},
+				ProcFieldsMap:         map[string]int{"Status": 0, "ItemID": 1, "ItemName": 2, "Total": 3},
+				FieldsObjectMap:       map[string]ds.FieldObject{},
+				Indexes:               []ds.IndexDeclaration{},
+				IndexMap:              map[string]int{},
+				SelectorMap:           map[string]int{},
+				Backends:              []string{"tarantool2"},
+				SerializerMap:         map[string]ds.SerializerDeclaration{},
+				ImportPackage:         ds.NewImportPackage(),
+				TriggerMap:            map[string]ds.TriggerDeclaration{},
+				FlagMap:               map[string]ds.FlagDeclaration{},
+				MutatorMap:            map[string]ds.MutatorDeclaration{},
+				ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{},
+				LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -28,7 +28,7 @@ func Test_parseDoc(t *testing.T) {
 				dst: ds.NewRecordPackage(),
 				docs: &ast.CommentGroup{
 					List: []*ast.Comment{
-						{Text: `//ar:serverHost:127.0.0.1;serverPort:11011;serverTimeout:500`},
+						{Text: `//ar:serverHost:127.0.0.1;serverPort:11011;serverTimeout:500;serverReplicas:127.0.0.2:11011,127.0.0.3:11011`},
 						{Text: `//ar:namespace:5`},
 						{Text: `//ar:backend:octopus`},
 					},
@@ -37,9 +37,10 @@ func Test_parseDoc(t *testing.T) {
 			wantErr: false,
 			want: &ds.RecordPackage{
 				Server: ds.ServerDeclaration{
-					Host:    "127.0.0.1",
-					Port:    "11011",
-					Timeout: 500,
+					Host:     "127.0.0.1",
+					Port:     "11011",
+					Timeout:  500,
+					Replicas: []string{"127.0.0.2:11011", "127.0.0.3:11011"},
 				},
 				Namespace: ds.NamespaceDeclaration{
 					ObjectName:  "5",
@@ -64,6 +65,97 @@ func Test_parseDoc(t *testing.T) {
 				LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
 			},
 		},
+		{
+			name: "doc sharding",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:serverConf:box;sharding:ring;shardBy:AccountID`},
+						{Text: `//ar:namespace:5`},
+						{Text: `//ar:backend:octopus`},
+					},
+				},
+			},
+			wantErr: false,
+			want: &ds.RecordPackage{
+				Server: ds.ServerDeclaration{
+					Conf:     "box",
+					Sharding: "ring",
+					ShardBy:  "AccountID",
+				},
+				Namespace: ds.NamespaceDeclaration{
+					ObjectName: "5",
+				},
+				Backends:              []string{"octopus"},
+				Fields:                []ds.FieldDeclaration{},
+				FieldsMap:             map[string]int{},
+				ProcFieldsMap:         map[string]int{},
+				ProcOutFields:         map[int]ds.ProcFieldDeclaration{},
+				FieldsObjectMap:       map[string]ds.FieldObject{},
+				Indexes:               []ds.IndexDeclaration{},
+				IndexMap:              map[string]int{},
+				SelectorMap:           map[string]int{},
+				ImportPackage:         ds.NewImportPackage(),
+				SerializerMap:         map[string]ds.SerializerDeclaration{},
+				TriggerMap:            map[string]ds.TriggerDeclaration{},
+				FlagMap:               map[string]ds.FlagDeclaration{},
+				MutatorMap:            map[string]ds.MutatorDeclaration{},
+				ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{},
+				LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
+			},
+		},
+		{
+			name: "doc lease",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:serverConf:box;cacheSize:1000;cacheTTL:500;healthFailures:3;healthLatency:200`},
+						{Text: `//ar:namespace:5;leaseProc:foo_lease;softDelete:DeletedAt;outbox:foo_outbox;copyGetters:true;nullableGetters:true;prepared:true;validate:true;audit:true`},
+						{Text: `//ar:backend:octopus;protoPkg:example.com/pb/foopb`},
+					},
+				},
+			},
+			wantErr: false,
+			want: &ds.RecordPackage{
+				Server: ds.ServerDeclaration{
+					Conf:           "box",
+					CacheSize:      1000,
+					CacheTTL:       500,
+					HealthFailures: 3,
+					HealthLatency:  200,
+				},
+				Namespace: ds.NamespaceDeclaration{
+					ObjectName: "5",
+				},
+				ProtoPkg:              "example.com/pb/foopb",
+				LeaseProc:             "foo_lease",
+				SoftDelete:            "DeletedAt",
+				Outbox:                "foo_outbox",
+				CopyGetters:           true,
+				NullableGetters:       true,
+				Prepared:              true,
+				Validate:              true,
+				Audit:                 true,
+				Backends:              []string{"octopus"},
+				Fields:                []ds.FieldDeclaration{},
+				FieldsMap:             map[string]int{},
+				ProcFieldsMap:         map[string]int{},
+				ProcOutFields:         map[int]ds.ProcFieldDeclaration{},
+				FieldsObjectMap:       map[string]ds.FieldObject{},
+				Indexes:               []ds.IndexDeclaration{},
+				IndexMap:              map[string]int{},
+				SelectorMap:           map[string]int{},
+				ImportPackage:         ds.NewImportPackage(),
+				SerializerMap:         map[string]ds.SerializerDeclaration{},
+				TriggerMap:            map[string]ds.TriggerDeclaration{},
+				FlagMap:               map[string]ds.FlagDeclaration{},
+				MutatorMap:            map[string]ds.MutatorDeclaration{},
+				ImportStructFieldsMap: map[string][]ds.PartialFieldDeclaration{},
+				LinkedStructsMap:      map[string]ds.LinkedPackageDeclaration{},
+			},
+		},
 		{
 			name: "docComment",
 			args: args{
@@ -90,6 +182,136 @@ func Test_parseDoc(t *testing.T) {
 			wantErr: true,
 			want:    ds.NewRecordPackage(),
 		},
+		{
+			name: "doc invalid slowQuery",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:slowQuery:fast`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid healthFailures",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:healthFailures:0`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid serverMaxConns",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:serverMaxConns:0`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid serverRetry",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:serverRetry:many`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid cacheSize",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:cacheSize:0`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid serverReplicas",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:serverReplicas:127.0.0.2:11011,127.0.0.3`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid validate",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:validate:on`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid audit",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:audit:yes`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid copyGetters",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:copyGetters:maybe`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
+		{
+			name: "doc invalid nullableGetters",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				docs: &ast.CommentGroup{
+					List: []*ast.Comment{
+						{Text: `//ar:nullableGetters:maybe`},
+					},
+				},
+			},
+			wantErr: true,
+			want:    ds.NewRecordPackage(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"go/ast"
+	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
 	"github.com/mailru/activerecord/internal/pkg/ds"
@@ -71,6 +72,14 @@ func ParseTypeSerializer(dst *ds.RecordPackage, serializerName string, t interfa
 	}
 }
 
+// Встроенные сериализаторы из пакета pkg/serializer, включаются флагом в теге,
+// тип из описания сериализатора используется для анмаршалинга напрямую
+var builtinSerializers = map[TagNameType][2]string{
+	JSONTag:    {"JSONMarshal", "JSONUnmarshal"},
+	MsgpackTag: {"MsgpackMarshal", "MsgpackUnmarshal"},
+	EncryptTag: {"EncryptMarshal", "EncryptUnmarshal"},
+}
+
 func ParseSerializer(dst *ds.RecordPackage, fields []*ast.Field) error {
 	defaultSerializerPkg := "github.com/mailru/activerecord/pkg/serializer"
 	for _, field := range fields {
@@ -86,12 +95,28 @@ func ParseSerializer(dst *ds.RecordPackage, fields []*ast.Field) error {
 			Unmarshaler: field.Names[0].Name + "Unmarshal",
 		}
 
-		tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{})
+		tagParam, err := splitTag(field, NoCheckFlag, map[TagNameType]ParamValueRule{JSONTag: ParamNotNeedValue, MsgpackTag: ParamNotNeedValue, EncryptTag: ParamNotNeedValue})
 		if err != nil {
 			return &arerror.ErrParseSerializerDecl{Name: newserializer.Name, Err: err}
 		}
 
+		builtin, custom := "", ""
+
 		for _, kv := range tagParam {
+			if funcs, ex := builtinSerializers[TagNameType(kv[0])]; ex {
+				if builtin != "" {
+					return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: kv[0], Err: arerror.ErrInvalidParams}
+				}
+
+				builtin = kv[0]
+				newserializer.Pkg = defaultSerializerPkg
+				newserializer.Marshaler, newserializer.Unmarshaler = funcs[0], funcs[1]
+
+				continue
+			}
+
+			custom = kv[0]
+
 			switch kv[0] {
 			case "pkg":
 				newserializer.Pkg = kv[1]
@@ -99,21 +124,58 @@ func ParseSerializer(dst *ds.RecordPackage, fields []*ast.Field) error {
 				newserializer.Marshaler = kv[1]
 			case "unmarshaler":
 				newserializer.Unmarshaler = kv[1]
+			case string(ChainTag):
+				newserializer.Chain = strings.Split(kv[1], ",")
+			case string(EmbedTag):
+				if len(kv) != 2 || kv[1] == "" {
+					return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: kv[0], Err: arerror.ErrParseTagValueInvalid}
+				}
+
+				newserializer.Embed = kv[1]
 			default:
 				return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 			}
 		}
 
-		imp, err := dst.FindOrAddImport(newserializer.Pkg, newserializer.ImportName)
-		if err != nil {
-			return &arerror.ErrParseSerializerDecl{Name: newserializer.Name, Err: err}
+		if builtin != "" && custom != "" {
+			return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: custom, Err: arerror.ErrInvalidParams}
 		}
 
-		newserializer.ImportName = imp.ImportName
+		if len(newserializer.Chain) > 0 {
+			if len(tagParam) > 1 {
+				return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: string(ChainTag), Err: arerror.ErrInvalidParams}
+			}
 
-		newserializer.Type, err = ParseTypeSerializer(dst, newserializer.Name, field.Type)
-		if err != nil {
-			return &arerror.ErrParseSerializerDecl{Name: newserializer.Name, Err: err}
+			// Функции цепочки генерируются в пакете модели, импорт не нужен
+			newserializer.Pkg, newserializer.ImportName, newserializer.Marshaler, newserializer.Unmarshaler = "", "", "", ""
+		} else if newserializer.Embed != "" {
+			if len(tagParam) > 1 {
+				return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: string(EmbedTag), Err: arerror.ErrInvalidParams}
+			}
+
+			// Пакет и тип встроенной модели определяются при подготовке к генерации,
+			// когда известны пути импорта всех сгенерированных пакетов
+			newserializer.Pkg, newserializer.ImportName = "", ""
+			newserializer.Marshaler, newserializer.Unmarshaler = "MarshalEmbedded", "UnmarshalEmbedded"
+		} else {
+			imp, err := dst.FindOrAddImport(newserializer.Pkg, newserializer.ImportName)
+			if err != nil {
+				return &arerror.ErrParseSerializerDecl{Name: newserializer.Name, Err: err}
+			}
+
+			newserializer.ImportName = imp.ImportName
+		}
+
+		if newserializer.Embed == "" {
+			newserializer.Type, err = ParseTypeSerializer(dst, newserializer.Name, field.Type)
+			if err != nil {
+				return &arerror.ErrParseSerializerDecl{Name: newserializer.Name, Err: err}
+			}
+		}
+
+		// Шифруются только строки, структуры шифруются в цепочке после сериализатора в строку
+		if builtin == string(EncryptTag) && newserializer.Type != "string" {
+			return &arerror.ErrParseSerializerTagDecl{Name: newserializer.Name, TagName: builtin, TagValue: newserializer.Type, Err: arerror.ErrInvalidParams}
 		}
 
 		if err = dst.AddSerializer(newserializer); err != nil {
@@ -121,5 +183,24 @@ func ParseSerializer(dst *ds.RecordPackage, fields []*ast.Field) error {
 		}
 	}
 
+	return checkSerializerChains(dst)
+}
+
+// checkSerializerChains проверяет, что все сериализаторы из цепочек объявлены
+// и сами не являются цепочками
+func checkSerializerChains(dst *ds.RecordPackage) error {
+	for _, sd := range dst.SerializerMap {
+		for _, name := range sd.Chain {
+			stage, ex := dst.SerializerMap[name]
+			if !ex {
+				return &arerror.ErrParseSerializerTagDecl{Name: sd.Name, TagName: string(ChainTag), TagValue: name, Err: arerror.ErrUnknown}
+			}
+
+			if len(stage.Chain) > 0 {
+				return &arerror.ErrParseSerializerTagDecl{Name: sd.Name, TagName: string(ChainTag), TagValue: name, Err: arerror.ErrInvalidParams}
+			}
+		}
+	}
+
 	return nil
 }
@@ -2,6 +2,7 @@ package parser_test
 
 import (
 	"go/ast"
+	"reflect"
 	"testing"
 
 	"github.com/mailru/activerecord/internal/pkg/ds"
@@ -63,6 +64,174 @@ func TestParseSerializer(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "builtin json serializer",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Params"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"json\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "builtin json serializer with custom marshaler",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Attrs"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"json;marshaler:AttrsMarshal\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "builtin msgpack serializer",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Packed"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"msgpack\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "builtin encrypt serializer",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Secret"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"encrypt\"`"},
+						Type:  &ast.Ident{Name: "string"},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "builtin encrypt serializer not string",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "SecretAttrs"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"encrypt\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "encrypted serializer chain",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "SecretParams"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"chain:Params,Secret\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "serializer chain",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Compressed"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"chain:Params,Packed\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "serializer chain with unknown stage",
+			args: args{
+				dst: ds.NewRecordPackage(),
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Broken"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"chain:Params,Gzip\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "serializer chain with marshaler",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Custom"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"chain:Params;marshaler:CustomMarshal\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "few builtin serializers",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Both"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"json;msgpack\"`"},
+						Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "embedded record with marshaler",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Address"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"embed:address;marshaler:AddressMarshal\"`"},
+						Type:  &ast.Ident{Name: "any"},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "embedded record without package",
+			args: args{
+				dst: dst,
+				fields: []*ast.Field{
+					{
+						Names: []*ast.Ident{{Name: "Address"}},
+						Tag:   &ast.BasicLit{Value: "`ar:\"embed:\"`"},
+						Type:  &ast.Ident{Name: "any"},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -73,6 +242,34 @@ func TestParseSerializer(t *testing.T) {
 	}
 }
 
+func TestParseSerializerJSON(t *testing.T) {
+	dst := ds.NewRecordPackage()
+
+	err := parser.ParseSerializer(dst, []*ast.Field{
+		{
+			Names: []*ast.Ident{{Name: "Params"}},
+			Tag:   &ast.BasicLit{Value: "`ar:\"json\"`"},
+			Type:  &ast.MapType{Key: &ast.Ident{Name: "string"}, Value: &ast.Ident{Name: "int"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseSerializer() error = %v", err)
+	}
+
+	want := ds.SerializerDeclaration{
+		Name:        "Params",
+		Pkg:         "github.com/mailru/activerecord/pkg/serializer",
+		Type:        "map[string]int",
+		ImportName:  "serializerParams",
+		Marshaler:   "JSONMarshal",
+		Unmarshaler: "JSONUnmarshal",
+	}
+
+	if got := dst.SerializerMap["Params"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSerializer() = %+v, want %+v", got, want)
+	}
+}
+
 func TestParseTypeSerializer(t *testing.T) {
 	dst := ds.NewRecordPackage()
 	if _, err := dst.AddImport("github.com/mailru/activerecord/notexistsfolder/dictionary"); err != nil {
@@ -120,3 +317,33 @@ func TestParseTypeSerializer(t *testing.T) {
 		})
 	}
 }
+
+func TestParseSerializerEmbed(t *testing.T) {
+	dst := ds.NewRecordPackage()
+
+	err := parser.ParseSerializer(dst, []*ast.Field{
+		{
+			Names: []*ast.Ident{{Name: "Address"}},
+			Tag:   &ast.BasicLit{Value: "`ar:\"embed:address\"`"},
+			Type:  &ast.Ident{Name: "any"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("ParseSerializer() error = %v", err)
+	}
+
+	want := ds.SerializerDeclaration{
+		Name:        "Address",
+		Marshaler:   "MarshalEmbedded",
+		Unmarshaler: "UnmarshalEmbedded",
+		Embed:       "address",
+	}
+
+	if got := dst.SerializerMap["Address"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseSerializer() = %+v, want %+v", got, want)
+	}
+
+	if len(dst.Imports) != 0 {
+		t.Errorf("ParseSerializer() imports = %+v, want none", dst.Imports)
+	}
+}
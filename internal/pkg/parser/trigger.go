@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"go/ast"
+	"strconv"
 	"strings"
 
 	"github.com/mailru/activerecord/internal/pkg/arerror"
@@ -13,6 +14,12 @@ import (
 var availableTriggers = map[string]map[string]bool{
 	"RepairTuple":        {"Defaults": true},
 	"DublicateUniqTuple": {},
+	"BeforeInsert":       {},
+	"AfterInsert":        {},
+	"BeforeUpdate":       {},
+	"AfterUpdate":        {},
+	"BeforeDelete":       {},
+	"AfterDelete":        {},
 }
 
 // Парсинг заявленных триггеров в описании модели
@@ -56,17 +63,24 @@ func ParseTrigger(dst *ds.RecordPackage, fields []*ast.Field) error {
 	return nil
 }
 
-func ParseTriggerTag(trigger *ds.TriggerDeclaration, field *ast.Field) error {
-	atr, ex := availableTriggers[field.Names[0].Name]
-	if !ex {
-		return &arerror.ErrParseTriggerDecl{Name: field.Names[0].Name, Err: arerror.ErrUnknown}
-	}
+// Этапы жизненного цикла записи, на которых может быть объявлено несколько триггеров
+var lifecyclePhases = map[string]bool{
+	"BeforeInsert": true,
+	"AfterInsert":  true,
+	"BeforeUpdate": true,
+	"AfterUpdate":  true,
+	"BeforeDelete": true,
+	"AfterDelete":  true,
+}
 
+func ParseTriggerTag(trigger *ds.TriggerDeclaration, field *ast.Field) error {
 	tagParam, err := splitTag(field, CheckFlagEmpty, map[TagNameType]ParamValueRule{})
 	if err != nil {
 		return &arerror.ErrParseTriggerDecl{Name: field.Names[0].Name, Err: err}
 	}
 
+	params := []string{}
+
 	for _, kv := range tagParam {
 		switch kv[0] {
 		case "pkg":
@@ -74,17 +88,47 @@ func ParseTriggerTag(trigger *ds.TriggerDeclaration, field *ast.Field) error {
 		case "func":
 			trigger.Func = kv[1]
 		case "param":
-			for _, param := range strings.Split(kv[1], ",") {
-				if _, ex := atr[param]; ex {
-					trigger.Params[param] = true
-				} else {
-					return &arerror.ErrParseTriggerTagDecl{Name: field.Names[0].Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
-				}
+			params = strings.Split(kv[1], ",")
+		case "phase":
+			if !lifecyclePhases[kv[1]] {
+				return &arerror.ErrParseTriggerTagDecl{Name: field.Names[0].Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrUnknown}
+			}
+
+			trigger.Phase = kv[1]
+		case "priority":
+			priority, err := strconv.Atoi(kv[1])
+			if err != nil {
+				return &arerror.ErrParseTriggerTagDecl{Name: field.Names[0].Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagValueInvalid}
 			}
+
+			trigger.Priority = priority
 		default:
 			return &arerror.ErrParseTriggerTagDecl{Name: field.Names[0].Name, TagName: kv[0], TagValue: kv[1], Err: arerror.ErrParseTagUnknown}
 		}
 	}
 
+	// Триггер этапа жизненного цикла может называться произвольно, если этап указан в теге phase
+	if trigger.Phase == "" && lifecyclePhases[trigger.Name] {
+		trigger.Phase = trigger.Name
+	}
+
+	atrName := trigger.Name
+	if trigger.Phase != "" {
+		atrName = trigger.Phase
+	}
+
+	atr, ex := availableTriggers[atrName]
+	if !ex {
+		return &arerror.ErrParseTriggerDecl{Name: field.Names[0].Name, Err: arerror.ErrUnknown}
+	}
+
+	for _, param := range params {
+		if _, ex := atr[param]; !ex {
+			return &arerror.ErrParseTriggerTagDecl{Name: field.Names[0].Name, TagName: "param", TagValue: param, Err: arerror.ErrParseTagUnknown}
+		}
+
+		trigger.Params[param] = true
+	}
+
 	return nil
 }
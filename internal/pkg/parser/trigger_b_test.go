@@ -0,0 +1,84 @@
+package parser_test
+
+import (
+	"go/ast"
+	"testing"
+
+	"github.com/mailru/activerecord/internal/pkg/ds"
+	"github.com/mailru/activerecord/internal/pkg/parser"
+)
+
+func TestParseTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		trigger string
+		wantErr bool
+	}{
+		{name: "before insert", trigger: "BeforeInsert", wantErr: false},
+		{name: "after delete", trigger: "AfterDelete", wantErr: false},
+		{name: "unknown phase", trigger: "BeforeSelect", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := ds.NewRecordPackage()
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: tt.trigger}},
+					Tag:   &ast.BasicLit{Value: "`ar:\"pkg:github.com/mailru/activerecord/notexistsfolder/hooks\"`"},
+					Type:  &ast.Ident{Name: "bool"},
+				},
+			}
+
+			err := parser.ParseTrigger(dst, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTrigger() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !tt.wantErr && dst.TriggerMap[tt.trigger].Func != tt.trigger {
+				t.Errorf("ParseTrigger() func = %v, want %v", dst.TriggerMap[tt.trigger].Func, tt.trigger)
+			}
+		})
+	}
+}
+
+func TestParseTriggerPhase(t *testing.T) {
+	tests := []struct {
+		name         string
+		tag          string
+		wantPhase    string
+		wantPriority int
+		wantErr      bool
+	}{
+		{name: "phase and priority", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/hooks;phase:BeforeInsert;priority:2", wantPhase: "BeforeInsert", wantPriority: 2},
+		{name: "unknown phase", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/hooks;phase:RepairTuple", wantErr: true},
+		{name: "invalid priority", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/hooks;phase:AfterUpdate;priority:first", wantErr: true},
+		{name: "without phase", tag: "pkg:github.com/mailru/activerecord/notexistsfolder/hooks", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := ds.NewRecordPackage()
+			fields := []*ast.Field{
+				{
+					Names: []*ast.Ident{{Name: "Validate"}},
+					Tag:   &ast.BasicLit{Value: "`ar:\"" + tt.tag + "\"`"},
+					Type:  &ast.Ident{Name: "bool"},
+				},
+			}
+
+			err := parser.ParseTrigger(dst, fields)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTrigger() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got := dst.TriggerMap["Validate"]; got.Phase != tt.wantPhase || got.Priority != tt.wantPriority {
+				t.Errorf("ParseTrigger() = %+v, want phase %s priority %d", got, tt.wantPhase, tt.wantPriority)
+			}
+		})
+	}
+}
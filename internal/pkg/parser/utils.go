@@ -22,6 +22,8 @@ var availableNodeName = []StructNameType{
 	Triggers,
 	Flags,
 	Mutators,
+	Computed,
+	Mixin,
 }
 
 func getNodeName(node string) (name string, publicName string, packageName string, err error) {
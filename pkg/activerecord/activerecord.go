@@ -5,11 +5,161 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 )
 
-var ErrNoData = errors.New("no data")
+// Классы ошибок. Ошибки сгенерированных пакетов и драйверов относятся к одному из классов,
+// что позволяет проверять их через errors.Is независимо от бекенда
+var ErrNotFound = errors.New("record not found")
+var ErrConflict = errors.New("conflict")
+var ErrTransient = errors.New("transient error")
+var ErrValidation = errors.New("validation failed")
+
+var ErrNoData = NewClassError("no data", ErrNotFound)
+var ErrUnknownDiscriminator = errors.New("unknown discriminator value")
+var ErrDistinctKeysLimit = errors.New("distinct keys limit exceeded")
+var ErrDuplicateKey = NewClassError("duplicate key in batch", ErrConflict)
+var ErrVersionConflict = NewClassError("version conflict", ErrConflict)
+var ErrExclusiveFlags = NewClassError("mutually exclusive flags are set", ErrValidation)
+var ErrInvalidEnumValue = NewClassError("invalid enum value", ErrValidation)
+var ErrSchemaMismatch = errors.New("schema mismatch")
+var ErrShardKeyRequired = NewClassError("shard key required", ErrValidation)
+
+// ClassError ошибка, относящаяся к классу ошибок (ErrNotFound, ErrConflict, ErrTransient, ErrValidation).
+// errors.Is возвращает true и для самой ошибки, и для её класса
+type ClassError struct {
+	msg   string
+	class error
+}
+
+// NewClassError создаёт ошибку с текстом msg, относящуюся к классу class
+func NewClassError(msg string, class error) error {
+	return &ClassError{msg: msg, class: class}
+}
+
+func (e *ClassError) Error() string {
+	return e.msg
+}
+
+func (e *ClassError) Is(target error) bool {
+	return target == e.class
+}
+
+// DiscriminatorError ошибка декодирования полиморфного поля с неизвестным значением дискриминатора
+type DiscriminatorError struct {
+	Entity string
+	Value  any
+}
+
+func (e *DiscriminatorError) Error() string {
+	return fmt.Sprintf("%s: %s `%v`", e.Entity, ErrUnknownDiscriminator, e.Value)
+}
+
+func (e *DiscriminatorError) Unwrap() error {
+	return ErrUnknownDiscriminator
+}
+
+// EnumValueError ошибка распаковки или установки значения поля-перечисления, не входящего в список допустимых значений
+type EnumValueError struct {
+	Entity string
+	Field  string
+	Value  any
+}
+
+func (e *EnumValueError) Error() string {
+	return fmt.Sprintf("%s.%s: %s `%v`", e.Entity, e.Field, ErrInvalidEnumValue, e.Value)
+}
+
+func (e *EnumValueError) Unwrap() error {
+	return ErrInvalidEnumValue
+}
+
+// FieldValidationError нарушение ограничения на значение поля записи
+type FieldValidationError struct {
+	Field string
+	Rule  string // Нарушенное ограничение, например `required` или `max 100`
+	Value any
+}
+
+func (e FieldValidationError) Error() string {
+	return fmt.Sprintf("%s: %s `%v`", e.Field, e.Rule, e.Value)
+}
+
+// ValidationError ошибка проверки значений полей записи методом Validate, содержит все нарушенные ограничения
+type ValidationError struct {
+	Entity string
+	Fields []FieldValidationError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for _, fe := range e.Fields {
+		fields = append(fields, fe.Error())
+	}
+
+	return fmt.Sprintf("%s: %s: %s", e.Entity, ErrValidation, strings.Join(fields, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// VersionConflictError ошибка обновления записи, версия которой в БД изменилась с момента чтения
+type VersionConflictError struct {
+	Entity  string
+	PK      string
+	Version any // Версия записи, с которой она была прочитана
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("%s '%s': %s: expected version %v", e.Entity, e.PK, ErrVersionConflict, e.Version)
+}
+
+func (e *VersionConflictError) Unwrap() error {
+	return ErrVersionConflict
+}
+
+// TransientError временная ошибка обращения к БД (разрыв соединения, таймаут), после которой запрос можно повторить.
+// Логические ошибки (дубликат ключа, отсутствие записи) временными не являются
+type TransientError struct {
+	Err error
+}
+
+func (e *TransientError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrTransient, e.Err)
+}
+
+func (e *TransientError) Unwrap() error {
+	return e.Err
+}
+
+func (e *TransientError) Is(target error) bool {
+	return target == ErrTransient
+}
+
+// IsTransient проверяет, что err временная ошибка и запрос можно повторить
+func IsTransient(err error) bool {
+	return errors.Is(err, ErrTransient)
+}
+
+// BatchError ошибка проверки записи пакета по уникальному индексу
+type BatchError struct {
+	Entity   string
+	Index    string
+	Position int // Позиция записи в пакете
+	Conflict int // Позиция записи, с которой конфликтует запись, -1 если ключ не удалось сформировать
+	Err      error
+}
+
+func (e BatchError) Error() string {
+	return fmt.Sprintf("%s: index %s: record %d conflicts with record %d: %s", e.Entity, e.Index, e.Position, e.Conflict, e.Err)
+}
+
+func (e BatchError) Unwrap() error {
+	return e.Err
+}
 
 type SelectorLimiter interface {
 	Limit() uint32
@@ -89,6 +239,7 @@ type ConnectionCacherInterface interface {
 	GetOrAdd(shard ShardInstance, connector func(interface{}) (ConnectionInterface, error)) (ConnectionInterface, error)
 	Get(shard ShardInstance) ConnectionInterface
 	CloseConnection(context.Context)
+	Shutdown(context.Context) error
 }
 
 type PingerInterface interface {
@@ -105,6 +256,17 @@ type SerializerInterface interface {
 	Marshal(data interface{}) (interface{}, error)
 }
 
+// KeyProviderInterface источник ключей шифрования для встроенного сериализатора encrypt.
+// Каждый ключ имеет идентификатор, который сохраняется вместе с зашифрованным значением,
+// поэтому после смены текущего ключа ранее записанные данные расшифровываются старым ключом
+type KeyProviderInterface interface {
+	// CurrentKey возвращает идентификатор и ключ, которым шифруются новые значения.
+	// Длина ключа 16, 24 или 32 байта (AES-128, AES-192, AES-256)
+	CurrentKey() (id string, key []byte, err error)
+	// Key возвращает ключ по идентификатору для расшифровки
+	Key(id string) ([]byte, error)
+}
+
 type MetricTimerInterface interface {
 	Timing(ctx context.Context, name string)
 	Finish(ctx context.Context, name string)
@@ -124,6 +286,23 @@ type MetricInterface interface {
 	Timer(storage, entity string) MetricTimerInterface
 }
 
+// QueryLabels метки метрик запроса к БД
+type QueryLabels struct {
+	Storage   string // Тип хранилища
+	Entity    string // Имя модели
+	Namespace string // Имя неймспейса (спейса) модели
+	Method    string // Метод репозитория: select, insertreplace, update, delete
+	Index     string // Имя индекса, по которому выполняется выборка
+	Success   bool   // Признак успешного выполнения запроса
+}
+
+// QueryMetricInterface метрики запросов к БД, позволяет собирать количество и время выполнения запросов,
+// например в счётчики и гистограммы Prometheus
+type QueryMetricInterface interface {
+	Inc(ctx context.Context, labels QueryLabels)
+	Observe(ctx context.Context, labels QueryLabels, d time.Duration)
+}
+
 type ActiveRecord struct {
 	instanceCreator  string
 	config           ConfigInterface
@@ -132,6 +311,11 @@ type ActiveRecord struct {
 	connectionCacher ConnectionCacherInterface
 	configCacher     ConfigCacherInterface
 	pinger           PingerInterface
+	slowQueryHook    SlowQueryHook
+	tracer           TracerInterface
+	queryMetric      QueryMetricInterface
+	keyProvider      KeyProviderInterface
+	auditSink        AuditSinkInterface
 }
 
 var instance *ActiveRecord
@@ -163,6 +347,7 @@ func InitActiveRecord(opts ...Option) {
 		logger:           NewLogger(),
 		config:           NewDefaultConfig(),
 		metric:           NewDefaultNoopMetric(),
+		queryMetric:      NewDefaultNoopQueryMetric(),
 		connectionCacher: newConnectionPool(),
 		configCacher:     newConfigCacher(),
 	}
@@ -188,6 +373,15 @@ func Metric() MetricInterface {
 	return GetInstance().metric
 }
 
+func QueryMetric() QueryMetricInterface {
+	return GetInstance().queryMetric
+}
+
+// KeyProvider возвращает источник ключей шифрования, переданный через WithKeyProvider, или nil
+func KeyProvider() KeyProviderInterface {
+	return GetInstance().keyProvider
+}
+
 func Config() ConfigInterface {
 	return GetInstance().config
 }
@@ -207,3 +401,21 @@ func Ping(ctx context.Context, path string, ping func(ctx context.Context, insta
 
 	return instance.pinger.SchedulePingIfNotExists(ctx, path, ping)
 }
+
+// Shutdown останавливает проверку доступности инстансов и закрывает все открытые пулы соединений,
+// дожидаясь завершения уже отправленных запросов, но не дольше, чем позволяет контекст.
+// Перед вызовом приложение должно перестать принимать новые запросы: запросы, начатые во время
+// или после закрытия, откроют новые соединения
+func Shutdown(ctx context.Context) error {
+	if instance == nil {
+		return nil
+	}
+
+	if p, ok := instance.pinger.(interface{ StopWatch() error }); ok {
+		if err := p.StopWatch(); err != nil {
+			instance.logger.Warn(ctx, "pinger stop: ", err)
+		}
+	}
+
+	return instance.connectionCacher.Shutdown(ctx)
+}
@@ -1,6 +1,8 @@
 package activerecord
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -35,3 +37,56 @@ func TestInitActiveRecord(t *testing.T) {
 		instance = nil
 	}
 }
+
+func TestValidationError(t *testing.T) {
+	err := error(&ValidationError{
+		Entity: "Foo",
+		Fields: []FieldValidationError{
+			{Field: "Name", Rule: "required", Value: ""},
+			{Field: "Age", Rule: "max 100", Value: 120},
+		},
+	})
+
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("errors.Is(%v, ErrValidation) = false, want true", err)
+	}
+
+	want := "Foo: validation failed: Name: required ``; Age: max 100 `120`"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		class error
+	}{
+		{name: "no data", err: fmt.Errorf("select Foo: %w", ErrNoData), class: ErrNotFound},
+		{name: "duplicate key", err: BatchError{Entity: "Foo", Err: ErrDuplicateKey}, class: ErrConflict},
+		{name: "version conflict", err: &VersionConflictError{Entity: "Foo", PK: "1", Version: 1}, class: ErrConflict},
+		{name: "enum value", err: &EnumValueError{Entity: "Foo", Field: "State", Value: 5}, class: ErrValidation},
+		{name: "exclusive flags", err: fmt.Errorf("set flags: %w", ErrExclusiveFlags), class: ErrValidation},
+		{name: "transient", err: &TransientError{Err: errors.New("connection closed")}, class: ErrTransient},
+	}
+	classes := []error{ErrNotFound, ErrConflict, ErrTransient, ErrValidation}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, class := range classes {
+				if got := errors.Is(tt.err, class); got != (class == tt.class) {
+					t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, class, got, class == tt.class)
+				}
+			}
+		})
+	}
+
+	if !errors.Is(ErrDuplicateKey, ErrDuplicateKey) || errors.Is(ErrVersionConflict, ErrDuplicateKey) {
+		t.Errorf("errors.Is() must distinguish errors of the same class")
+	}
+
+	if ErrVersionConflict.Error() != "version conflict" {
+		t.Errorf("Error() = %q, want %q", ErrVersionConflict.Error(), "version conflict")
+	}
+}
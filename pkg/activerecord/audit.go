@@ -0,0 +1,52 @@
+package activerecord
+
+import (
+	"context"
+	"time"
+)
+
+// AuditRecord запись журнала аудита об изменении одного поля записи при Update
+type AuditRecord struct {
+	Entity  string    // Имя модели
+	PK      string    // Первичный ключ записи, для ключей с полями sensitive - RedactedValue
+	Field   string    // Имя изменённого поля
+	Old     any       // Значение поля при загрузке или последнем сохранении записи
+	New     any       // Сохранённое значение поля
+	Actor   string    // Инициатор изменения из контекста, см. WithAuditActor
+	Changed time.Time // Время изменения
+}
+
+// AuditSinkInterface получатель записей журнала аудита. Вызывается после успешного Update,
+// для обновления в транзакции - после её подтверждения. Записи одного обновления передаются одним вызовом
+type AuditSinkInterface interface {
+	Audit(ctx context.Context, records []AuditRecord)
+}
+
+type ctxKeyAuditActor struct{}
+
+// WithAuditActor возвращает контекст, изменения с которым записываются в журнал аудита от имени actor
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, ctxKeyAuditActor{}, actor)
+}
+
+// AuditActorFromContext возвращает инициатора изменения, заданного через WithAuditActor, или пустую строку
+func AuditActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(ctxKeyAuditActor{}).(string)
+
+	return actor
+}
+
+// AuditEnabled проверяет, задан ли получатель журнала аудита. Если не задан, сгенерированные пакеты
+// не формируют записи журнала
+func AuditEnabled() bool {
+	return instance != nil && instance.auditSink != nil
+}
+
+// Audit передаёт записи журнала аудита получателю, заданному через WithAuditSink
+func Audit(ctx context.Context, records []AuditRecord) {
+	if len(records) == 0 || !AuditEnabled() {
+		return
+	}
+
+	instance.auditSink.Audit(ctx, records)
+}
@@ -0,0 +1,49 @@
+package activerecord
+
+import (
+	"context"
+	"testing"
+)
+
+type testAuditSink struct {
+	records []AuditRecord
+}
+
+func (s *testAuditSink) Audit(ctx context.Context, records []AuditRecord) {
+	s.records = append(s.records, records...)
+}
+
+func TestAudit(t *testing.T) {
+	ctx := context.Background()
+	records := []AuditRecord{{Entity: "Foo", Field: "Name", Old: "old", New: "new"}}
+
+	ReinitActiveRecord()
+
+	if AuditEnabled() {
+		t.Fatalf("AuditEnabled() = true without sink")
+	}
+
+	Audit(ctx, records)
+
+	sink := &testAuditSink{}
+	ReinitActiveRecord(WithAuditSink(sink))
+
+	if !AuditEnabled() {
+		t.Fatalf("AuditEnabled() = false with sink")
+	}
+
+	Audit(ctx, nil)
+	Audit(ctx, records)
+
+	if len(sink.records) != 1 || sink.records[0] != records[0] {
+		t.Errorf("Audit() records = %+v, want %+v", sink.records, records)
+	}
+
+	if got := AuditActorFromContext(ctx); got != "" {
+		t.Errorf("AuditActorFromContext() = %q, want empty", got)
+	}
+
+	if got := AuditActorFromContext(WithAuditActor(ctx, "admin")); got != "admin" {
+		t.Errorf("AuditActorFromContext() = %q, want admin", got)
+	}
+}
@@ -0,0 +1,122 @@
+package activerecord
+
+import (
+	"context"
+	"fmt"
+)
+
+// Тип и константы стратегии выбора реплики для запросов на чтение
+type ReadBalanceType uint8
+
+const (
+	RoundRobinBalance  ReadBalanceType = iota // Реплики выбираются по очереди. Используется по умолчанию
+	LeastLoadedBalance                        // Выбирается реплика с наименьшим количеством выполняющихся запросов
+)
+
+// ParseReadBalance разбор названия стратегии выбора реплики из конфига
+func ParseReadBalance(name string) (ReadBalanceType, error) {
+	switch name {
+	case "", "roundrobin":
+		return RoundRobinBalance, nil
+	case "leastloaded":
+		return LeastLoadedBalance, nil
+	default:
+		return RoundRobinBalance, fmt.Errorf("unknown read balance '%s', want roundrobin or leastloaded", name)
+	}
+}
+
+// ConnectionLoadInterface соединение, которое умеет сообщать количество выполняющихся через него запросов.
+// Используется при выборе наименее нагруженной реплики
+type ConnectionLoadInterface interface {
+	InFlight() int64
+}
+
+type ctxKeyMasterRead struct{}
+
+// WithMasterRead возвращает контекст, запросы на чтение с которым выполняются на мастере, а не на реплике.
+// Используется, когда нужно прочитать только что записанные данные
+func WithMasterRead(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxKeyMasterRead{}, true)
+}
+
+// IsMasterRead проверяет, требует ли контекст чтения с мастера
+func IsMasterRead(ctx context.Context) bool {
+	masterRead, _ := ctx.Value(ctxKeyMasterRead{}).(bool)
+
+	return masterRead
+}
+
+type ctxKeyShard struct{}
+
+// WithShardNum возвращает контекст, выборки с которым выполняются в шарде shard, а не в шарде,
+// вычисленном по ключу. Используется, когда шард известен из запроса
+func WithShardNum(ctx context.Context, shard int) context.Context {
+	return context.WithValue(ctx, ctxKeyShard{}, shard)
+}
+
+// ShardNumFromContext возвращает шард, заданный в контексте через WithShardNum. Если шард не задан, возвращается 0 и false
+func ShardNumFromContext(ctx context.Context) (int, bool) {
+	shard, ok := ctx.Value(ctxKeyShard{}).(int)
+
+	return shard, ok
+}
+
+// Instance выбирает инстанс шарда для запроса с типом instType. Для ReplicaOrMasterInstanceType
+// реплика выбирается в соответствии со стратегией шарда, а мастер используется, если в контексте
+// запрошено чтение с мастера (см. WithMasterRead) или в шарде нет доступных реплик
+func (s *Shard) Instance(ctx context.Context, instType ShardInstanceType) (ShardInstance, error) {
+	switch instType {
+	case ReplicaInstanceType:
+		if len(s.Replicas) == 0 {
+			return ShardInstance{}, fmt.Errorf("replicas not set")
+		}
+
+		return s.NextReplica(), nil
+	case ReplicaOrMasterInstanceType:
+		if IsMasterRead(ctx) || len(Online(s.Replicas)) == 0 {
+			return s.NextMaster(), nil
+		}
+
+		if s.Balance == LeastLoadedBalance {
+			return s.leastLoadedReplica(instanceLoad), nil
+		}
+
+		return s.NextReplica(), nil
+	default:
+		return s.NextMaster(), nil
+	}
+}
+
+// leastLoadedReplica выбирает доступную реплику с наименьшей нагрузкой. При равной нагрузке
+// реплики выбираются по очереди, чтобы запросы не уходили на одну и ту же реплику
+func (s *Shard) leastLoadedReplica(load func(ShardInstance) int64) ShardInstance {
+	ret := s.NextReplica()
+	minLoad := load(ret)
+
+	for _, replica := range Online(s.Replicas) {
+		if minLoad == 0 {
+			break
+		}
+
+		if replicaLoad := load(replica); replicaLoad < minLoad {
+			ret, minLoad = replica, replicaLoad
+		}
+	}
+
+	return ret
+}
+
+// instanceLoad количество запросов, выполняющихся через соединение с инстансом.
+// Если соединение ещё не установлено, инстанс считается ненагруженным
+func instanceLoad(inst ShardInstance) int64 {
+	if instance == nil || instance.connectionCacher == nil {
+		return 0
+	}
+
+	conn, ok := instance.connectionCacher.Get(inst).(ConnectionLoadInterface)
+	if !ok {
+		return 0
+	}
+
+	return conn.InFlight()
+}
@@ -0,0 +1,140 @@
+package activerecord
+
+import (
+	"context"
+	"testing"
+)
+
+func TestShard_Instance(t *testing.T) {
+	master := ShardInstance{ParamsID: "master", Config: ShardInstanceConfig{Mode: ModeMaster}}
+	replica1 := ShardInstance{ParamsID: "replica1", Config: ShardInstanceConfig{Mode: ModeReplica}}
+	replica2 := ShardInstance{ParamsID: "replica2", Config: ShardInstanceConfig{Mode: ModeReplica}}
+	offline := ShardInstance{ParamsID: "offline", Config: ShardInstanceConfig{Mode: ModeReplica}, Offline: true}
+
+	tests := []struct {
+		name     string
+		shard    Shard
+		ctx      context.Context
+		instType ShardInstanceType
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "replica",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{replica1}},
+			ctx:      context.Background(),
+			instType: ReplicaOrMasterInstanceType,
+			want:     "replica1",
+		},
+		{
+			name:     "master read",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{replica1}},
+			ctx:      WithMasterRead(context.Background()),
+			instType: ReplicaOrMasterInstanceType,
+			want:     "master",
+		},
+		{
+			name:     "without replicas",
+			shard:    Shard{Masters: []ShardInstance{master}},
+			ctx:      context.Background(),
+			instType: ReplicaOrMasterInstanceType,
+			want:     "master",
+		},
+		{
+			name:     "replicas offline",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{offline}},
+			ctx:      context.Background(),
+			instType: ReplicaOrMasterInstanceType,
+			want:     "master",
+		},
+		{
+			name:     "skip offline replica",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{offline, replica2}},
+			ctx:      context.Background(),
+			instType: ReplicaOrMasterInstanceType,
+			want:     "replica2",
+		},
+		{
+			name:     "only replica offline",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{offline}},
+			ctx:      context.Background(),
+			instType: ReplicaInstanceType,
+			want:     "offline",
+		},
+		{
+			name:     "only replica without replicas",
+			shard:    Shard{Masters: []ShardInstance{master}},
+			ctx:      context.Background(),
+			instType: ReplicaInstanceType,
+			wantErr:  true,
+		},
+		{
+			name:     "write",
+			shard:    Shard{Masters: []ShardInstance{master}, Replicas: []ShardInstance{replica1}},
+			ctx:      context.Background(),
+			instType: MasterInstanceType,
+			want:     "master",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.shard.Instance(tt.ctx, tt.instType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Shard.Instance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got.ParamsID != tt.want {
+				t.Errorf("Shard.Instance() = %v, want %v", got.ParamsID, tt.want)
+			}
+		})
+	}
+}
+
+func TestShard_leastLoadedReplica(t *testing.T) {
+	replicas := []ShardInstance{{ParamsID: "replica1"}, {ParamsID: "replica2"}, {ParamsID: "replica3"}}
+	load := map[string]int64{"replica1": 5, "replica2": 1, "replica3": 3}
+
+	shard := Shard{Replicas: replicas}
+
+	for i := 0; i < len(replicas); i++ {
+		got := shard.leastLoadedReplica(func(inst ShardInstance) int64 { return load[inst.ParamsID] })
+		if got.ParamsID != "replica2" {
+			t.Errorf("Shard.leastLoadedReplica() = %v, want replica2", got.ParamsID)
+		}
+	}
+}
+
+func TestParseReadBalance(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    ReadBalanceType
+		wantErr bool
+	}{
+		{name: "", want: RoundRobinBalance},
+		{name: "roundrobin", want: RoundRobinBalance},
+		{name: "leastloaded", want: LeastLoadedBalance},
+		{name: "random", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReadBalance(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseReadBalance() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseReadBalance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShardNumFromContext(t *testing.T) {
+	if shard, ok := ShardNumFromContext(context.Background()); ok || shard != 0 {
+		t.Errorf("ShardNumFromContext() = %d, %v, want 0, false", shard, ok)
+	}
+
+	if shard, ok := ShardNumFromContext(WithShardNum(context.Background(), 2)); !ok || shard != 2 {
+		t.Errorf("ShardNumFromContext() = %d, %v, want 2, true", shard, ok)
+	}
+}
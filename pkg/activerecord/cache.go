@@ -0,0 +1,132 @@
+package activerecord
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache ограниченный по количеству записей кеш с вытеснением давно не использовавшихся записей (LRU).
+// Используется сгенерированными пакетами, для которых задан размер кеша выборки по первичному ключу.
+// Значения хранятся в сериализованном виде, чтобы изменение полученной из кеша записи не меняло кеш
+type Cache struct {
+	mu      sync.Mutex
+	size    int
+	ttl     time.Duration
+	version uint64
+	order   *list.List
+	items   map[string]*list.Element
+	now     func() time.Time
+}
+
+type cacheItem struct {
+	key      string
+	value    []byte
+	expireAt time.Time
+}
+
+// NewCache создаёт кеш на size записей, ttl - время жизни записи, 0 - без ограничения
+func NewCache(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:  size,
+		ttl:   ttl,
+		order: list.New(),
+		items: make(map[string]*list.Element, size),
+		now:   time.Now,
+	}
+}
+
+// Get возвращает значение по ключу, если оно есть в кеше и время его жизни не истекло
+func (c *Cache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	item := el.Value.(*cacheItem)
+	if c.ttl != 0 && !c.now().Before(item.expireAt) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+
+	return item.value, true
+}
+
+// Version возвращает номер версии кеша, который увеличивается при каждой инвалидации.
+// Номер получают перед запросом в базу и передают в Set, чтобы не сохранить в кеш
+// запись, изменённую в базе во время запроса
+func (c *Cache) Version() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.version
+}
+
+// Set сохраняет значение по ключу, если с момента получения version кеш не инвалидировался.
+// При переполнении вытесняется запись, к которой дольше всего не обращались
+func (c *Cache) Set(key string, value []byte, version uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.version != version || c.size <= 0 {
+		return
+	}
+
+	item := &cacheItem{key: key, value: value}
+	if c.ttl != 0 {
+		item.expireAt = c.now().Add(c.ttl)
+	}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = item
+		c.order.MoveToFront(el)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(item)
+
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete удаляет значение по ключу и увеличивает версию кеша
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+// Purge очищает кеш и увеличивает его версию
+func (c *Cache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	c.order.Init()
+	c.items = make(map[string]*list.Element, c.size)
+}
+
+// Len возвращает количество записей в кеше
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*cacheItem).key)
+}
@@ -0,0 +1,82 @@
+package activerecord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2, 0)
+
+	c.Set("a", []byte("1"), c.Version())
+	c.Set("b", []byte("2"), c.Version())
+
+	if v, ok := c.Get("a"); !ok || string(v) != "1" {
+		t.Errorf("Get(a) = %q, %v, want 1, true", v, ok)
+	}
+
+	// Запись b использовалась раньше a и вытесняется первой
+	c.Set("c", []byte("3"), c.Version())
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("Get(b) found evicted record")
+	}
+
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+
+	c.Delete("a")
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) found deleted record")
+	}
+
+	c.Purge()
+
+	if _, ok := c.Get("c"); ok || c.Len() != 0 {
+		t.Errorf("Get(c) found record after Purge")
+	}
+}
+
+func TestCacheVersion(t *testing.T) {
+	c := NewCache(10, 0)
+
+	version := c.Version()
+
+	c.Delete("a")
+	c.Set("a", []byte("stale"), version)
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Set() stored record read before invalidation")
+	}
+
+	c.Set("a", []byte("1"), c.Version())
+
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Get(a) not found")
+	}
+}
+
+func TestCacheTTL(t *testing.T) {
+	now := time.Now()
+
+	c := NewCache(10, time.Second)
+	c.now = func() time.Time { return now }
+
+	c.Set("a", []byte("1"), c.Version())
+
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("Get(a) not found before ttl")
+	}
+
+	now = now.Add(time.Second)
+
+	if _, ok := c.Get("a"); ok {
+		t.Errorf("Get(a) found expired record")
+	}
+
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", c.Len())
+	}
+}
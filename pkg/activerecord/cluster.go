@@ -57,6 +57,8 @@ func (s *ShardInstance) IsOffline() bool {
 type Shard struct {
 	Masters    []ShardInstance
 	Replicas   []ShardInstance
+	Weight     int             // Вес шарда при распределении ключей кольцом консистентного хеширования
+	Balance    ReadBalanceType // Стратегия выбора реплики для запросов на чтение
 	curMaster  int32
 	curReplica int32
 }
@@ -95,11 +97,19 @@ func Online(shards []ShardInstance) []ShardInstance {
 	return ret
 }
 
-// Инстанс выбирающий конкретный инстанс реплики в конкретном шарде
+// Инстанс выбирающий конкретный инстанс реплики в конкретном шарде.
+// Если доступных реплик нет, выбор идёт среди всех описанных реплик
 func (s *Shard) NextReplica() ShardInstance {
 	replicas := Online(s.Replicas)
+	if len(replicas) == 0 {
+		replicas = s.Replicas
+	}
+
 	length := len(replicas)
-	if length == 1 {
+	switch length {
+	case 0:
+		panic("no replica configured")
+	case 1:
 		return replicas[0]
 	}
 
@@ -120,6 +130,7 @@ type Cluster []Shard
 type MapGlobParam struct {
 	Timeout  time.Duration
 	PoolSize int
+	Balance  ReadBalanceType
 }
 
 // Конструктор который позволяет проинициализировать новый кластер. В опциях передаются все шарды,
@@ -161,6 +172,15 @@ func GetClusterInfoFromCfg(ctx context.Context, path string, globs MapGlobParam,
 
 	globs.PoolSize = globalPoolSize
 
+	if globalBalance, exGlobalBalance := cfg.GetStringIfExists(ctx, path+"/balance"); exGlobalBalance {
+		balance, err := ParseReadBalance(globalBalance)
+		if err != nil {
+			return nil, fmt.Errorf("can't get cluster balance: %w", err)
+		}
+
+		globs.Balance = balance
+	}
+
 	var err error
 
 	if exMaxShardOK {
@@ -188,11 +208,25 @@ func getShardInfoFromCfg(ctx context.Context, path string, globParam MapGlobPara
 	ret := Shard{
 		Masters:  []ShardInstance{},
 		Replicas: []ShardInstance{},
+		Balance:  globParam.Balance,
 	}
 
 	shardTimeout := cfg.GetDuration(ctx, path+"/Timeout", globParam.Timeout)
 	shardPoolSize := cfg.GetInt(ctx, path+"/PoolSize", globParam.PoolSize)
 
+	if weight, exWeight := cfg.GetIntIfExists(ctx, path+"/Weight"); exWeight {
+		ret.Weight = weight
+	}
+
+	if shardBalance, exBalance := cfg.GetStringIfExists(ctx, path+"/balance"); exBalance {
+		balance, err := ParseReadBalance(shardBalance)
+		if err != nil {
+			return Shard{}, err
+		}
+
+		ret.Balance = balance
+	}
+
 	var instances []ShardInstance
 	// информация по местерам
 	master, exMaster := cfg.GetStringIfExists(ctx, path+"/master")
@@ -333,6 +367,8 @@ func (cc *DefaultConfigCacher) Actualize(ctx context.Context, path string, insta
 		clusterShard := Shard{
 			Masters:  []ShardInstance{},
 			Replicas: []ShardInstance{},
+			Weight:   shard.Weight,
+			Balance:  shard.Balance,
 		}
 
 		var instances []ShardInstance
@@ -49,6 +49,7 @@ func TestGetClusterInfoFromCfg(t *testing.T) {
 				mockConfig.EXPECT().GetDuration(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetInt(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetDurationIfExists(mock.Anything, mock.Anything).Return(0, false)
+				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/balance").Return("", false)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/master").Return("", false)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig").Return("host1,host2", true)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/replica").Return("", false)
@@ -94,6 +95,7 @@ func TestGetClusterInfoFromCfg(t *testing.T) {
 				mockConfig.EXPECT().GetDuration(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetInt(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetDurationIfExists(mock.Anything, mock.Anything).Return(0, false)
+				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/balance").Return("", false)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/master").Return("host2", true)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/replica").Return("host1", true)
 			},
@@ -139,6 +141,7 @@ func TestGetClusterInfoFromCfg(t *testing.T) {
 				mockConfig.EXPECT().GetDuration(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetInt(mock.Anything, mock.Anything, mock.Anything).Return(0)
 				mockConfig.EXPECT().GetDurationIfExists(mock.Anything, mock.Anything).Return(0, false)
+				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/balance").Return("", false)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/master").Return("", false)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig").Return("host1", true)
 				mockConfig.EXPECT().GetStringIfExists(mock.Anything, "testconfig/replica").Return("host2", true)
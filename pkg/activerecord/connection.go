@@ -11,6 +11,12 @@ type ConnectionInterface interface {
 	Done() <-chan struct{}
 }
 
+// ConnectionShutdownerInterface пул соединений, поддерживающий мягкое закрытие: новые запросы
+// не принимаются, а уже отправленные дожидаются ответа
+type ConnectionShutdownerInterface interface {
+	Shutdown()
+}
+
 type connectionPool struct {
 	lock      sync.Mutex
 	container map[string]ConnectionInterface
@@ -54,7 +60,7 @@ func (cp *connectionPool) GetOrAdd(shard ShardInstance, connector func(interface
 
 	var err error
 
-	conn := cp.Get(shard)
+	conn := cp.get(shard)
 	if conn == nil {
 		conn, err = cp.add(shard, connector)
 	}
@@ -63,6 +69,13 @@ func (cp *connectionPool) GetOrAdd(shard ShardInstance, connector func(interface
 }
 
 func (cp *connectionPool) Get(shard ShardInstance) ConnectionInterface {
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	return cp.get(shard)
+}
+
+func (cp *connectionPool) get(shard ShardInstance) ConnectionInterface {
 	if conn, ex := cp.container[shard.ParamsID]; ex {
 		return conn
 	}
@@ -85,3 +98,38 @@ func (cp *connectionPool) CloseConnection(ctx context.Context) {
 
 	cp.lock.Unlock()
 }
+
+// Shutdown закрывает все пулы соединений, дожидаясь завершения уже отправленных запросов.
+// Кеш соединений очищается сразу, поэтому запросы, начатые после вызова, откроют новые соединения.
+// Если контекст завершится раньше, оставшиеся пулы закрываются принудительно и возвращается ошибка контекста
+func (cp *connectionPool) Shutdown(ctx context.Context) error {
+	cp.lock.Lock()
+	pools := cp.container
+	cp.container = make(map[string]ConnectionInterface)
+	cp.lock.Unlock()
+
+	for name, pool := range pools {
+		if sd, ok := pool.(ConnectionShutdownerInterface); ok {
+			go sd.Shutdown()
+		} else {
+			pool.Close()
+		}
+
+		Logger().Debug(ctx, "connection shutdown: %s", name)
+	}
+
+	for name, pool := range pools {
+		select {
+		case <-pool.Done():
+			delete(pools, name)
+		case <-ctx.Done():
+			for _, rest := range pools {
+				rest.Close()
+			}
+
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
@@ -1,9 +1,12 @@
 package activerecord
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"sync"
 	"testing"
+	"time"
 )
 
 type TestOptions struct {
@@ -99,3 +102,73 @@ func Test_connectionPool_Add(t *testing.T) {
 		})
 	}
 }
+
+type shutdownConnection struct {
+	done     chan struct{}
+	graceful bool
+	shutdown bool
+	closed   bool
+}
+
+func (sc *shutdownConnection) Shutdown() {
+	sc.shutdown = true
+
+	if sc.graceful {
+		close(sc.done)
+	}
+}
+
+func (sc *shutdownConnection) Close() {
+	sc.closed = true
+}
+
+func (sc *shutdownConnection) Done() <-chan struct{} {
+	return sc.done
+}
+
+func Test_connectionPool_Shutdown(t *testing.T) {
+	ReinitActiveRecord()
+
+	tests := []struct {
+		name       string
+		graceful   bool
+		timeout    time.Duration
+		wantErr    error
+		wantClosed bool
+	}{
+		{
+			name:     "graceful shutdown",
+			graceful: true,
+			timeout:  time.Second,
+		},
+		{
+			name:       "deadline exceeded",
+			graceful:   false,
+			timeout:    10 * time.Millisecond,
+			wantErr:    context.DeadlineExceeded,
+			wantClosed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &shutdownConnection{done: make(chan struct{}), graceful: tt.graceful}
+			cp := newConnectionPool()
+			cp.container["testopt1"] = conn
+
+			ctx, cancel := context.WithTimeout(context.Background(), tt.timeout)
+			defer cancel()
+
+			if err := cp.Shutdown(ctx); !errors.Is(err, tt.wantErr) {
+				t.Errorf("connectionPool.Shutdown() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if len(cp.container) != 0 {
+				t.Errorf("connectionPool.Shutdown() container not empty: %v", cp.container)
+			}
+
+			if conn.closed != tt.wantClosed {
+				t.Errorf("connectionPool.Shutdown() closed = %v, want %v", conn.closed, tt.wantClosed)
+			}
+		})
+	}
+}
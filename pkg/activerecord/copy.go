@@ -0,0 +1,92 @@
+package activerecord
+
+import "reflect"
+
+// DeepCopy возвращает глубокую копию значения: слайсы, мапы, указатели и значения в интерфейсах копируются рекурсивно,
+// поэтому изменение копии не затрагивает исходное значение. Неэкспортируемые поля структур из других пакетов
+// копируются без рекурсии, каналы и функции не копируются. Используется в сгенерированных методах Clone
+func DeepCopy[T any](v T) T {
+	src := reflect.ValueOf(&v).Elem()
+	dst := reflect.New(src.Type()).Elem()
+
+	deepCopyValue(dst, src, map[copiedPointer]reflect.Value{})
+
+	return dst.Interface().(T)
+}
+
+// copiedPointer ключ скопированного указателя: адрес и тип, так как по одному адресу могут находиться
+// структура и её первое поле
+type copiedPointer struct {
+	addr uintptr
+	typ  reflect.Type
+}
+
+// deepCopyValue копирует src в dst, visited хранит уже скопированные указатели, чтобы сохранить
+// ссылки на общие объекты и не зациклиться на циклических структурах
+func deepCopyValue(dst, src reflect.Value, visited map[copiedPointer]reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+
+		key := copiedPointer{addr: src.Pointer(), typ: src.Type()}
+		if cp, ok := visited[key]; ok {
+			dst.Set(cp)
+			return
+		}
+
+		cp := reflect.New(src.Type().Elem())
+		visited[key] = cp
+		deepCopyValue(cp.Elem(), src.Elem(), visited)
+		dst.Set(cp)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+
+		cp := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			deepCopyValue(cp.Index(i), src.Index(i), visited)
+		}
+
+		dst.Set(cp)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+
+		cp := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+
+		for iter.Next() {
+			val := reflect.New(src.Type().Elem()).Elem()
+			deepCopyValue(val, iter.Value(), visited)
+			cp.SetMapIndex(iter.Key(), val)
+		}
+
+		dst.Set(cp)
+	case reflect.Interface:
+		if src.IsNil() {
+			return
+		}
+
+		val := reflect.New(src.Elem().Type()).Elem()
+		deepCopyValue(val, src.Elem(), visited)
+		dst.Set(val)
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			deepCopyValue(dst.Index(i), src.Index(i), visited)
+		}
+	case reflect.Struct:
+		dst.Set(src)
+
+		for i := 0; i < src.NumField(); i++ {
+			if dst.Field(i).CanSet() {
+				deepCopyValue(dst.Field(i), src.Field(i), visited)
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,45 @@
+package activerecord
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDeepCopy(t *testing.T) {
+	type node struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]any
+		Next  *node
+	}
+
+	str := "value"
+	src := &node{
+		Name:  "root",
+		Tags:  []string{"a", "b"},
+		Attrs: map[string]any{"list": []any{int64(1)}, "ptr": &str},
+	}
+	src.Next = src
+
+	got := DeepCopy(src)
+	if got == src || got.Next != got {
+		t.Fatalf("DeepCopy() = %p, next %p, want new self-referenced node", got, got.Next)
+	}
+
+	if !reflect.DeepEqual(got.Tags, src.Tags) || !reflect.DeepEqual(got.Attrs["list"], src.Attrs["list"]) {
+		t.Fatalf("DeepCopy() = %+v, want %+v", got, src)
+	}
+
+	got.Tags[0] = "changed"
+	got.Attrs["list"].([]any)[0] = int64(2)
+	*got.Attrs["ptr"].(*string) = "changed"
+
+	if src.Tags[0] != "a" || src.Attrs["list"].([]any)[0] != int64(1) || str != "value" {
+		t.Errorf("DeepCopy() changes of copy affect source: %+v, %s", src, str)
+	}
+
+	var nilMap map[string]int
+	if DeepCopy(nilMap) != nil {
+		t.Errorf("DeepCopy() of nil map is not nil")
+	}
+}
@@ -0,0 +1,51 @@
+package activerecord
+
+// EqualPtr сравнивает значения по указателям, nil равен только nil.
+// Используется в сгенерированных методах Equal и Diff для nullable полей
+func EqualPtr[T comparable](a, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return *a == *b
+}
+
+// EqualPtrFunc сравнивает значения по указателям функцией eq, например time.Time.Equal
+func EqualPtrFunc[T any](a, b *T, eq func(T, T) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	return eq(*a, *b)
+}
+
+// EqualSlice поэлементно сравнивает слайсы. Пустой слайс равен nil, так как
+// после чтения из БД пустой массив может быть представлен любым из них
+func EqualSlice[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// EqualSliceFunc поэлементно сравнивает слайсы функцией eq
+func EqualSliceFunc[T any](a, b []T, eq func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
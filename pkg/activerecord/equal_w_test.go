@@ -0,0 +1,61 @@
+package activerecord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEqualPtr(t *testing.T) {
+	one, otherOne, two := 1, 1, 2
+
+	tests := []struct {
+		name string
+		a, b *int
+		want bool
+	}{
+		{name: "both nil", want: true},
+		{name: "nil and value", b: &one, want: false},
+		{name: "same value", a: &one, b: &otherOne, want: true},
+		{name: "different value", a: &one, b: &two, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualPtr(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualPtr() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	now := time.Now()
+	utc := now.UTC()
+
+	if !EqualPtrFunc(&now, &utc, time.Time.Equal) || EqualPtrFunc(&now, nil, time.Time.Equal) {
+		t.Errorf("EqualPtrFunc() compares time not by Equal")
+	}
+}
+
+func TestEqualSlice(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		want bool
+	}{
+		{name: "nil and empty", a: nil, b: []string{}, want: true},
+		{name: "same", a: []string{"a", "b"}, b: []string{"a", "b"}, want: true},
+		{name: "order", a: []string{"a", "b"}, b: []string{"b", "a"}, want: false},
+		{name: "length", a: []string{"a"}, b: []string{"a", "b"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualSlice(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualSlice() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	now := time.Now()
+
+	if !EqualSliceFunc([]time.Time{now}, []time.Time{now.UTC()}, time.Time.Equal) {
+		t.Errorf("EqualSliceFunc() compares time not by Equal")
+	}
+}
@@ -0,0 +1,158 @@
+package activerecord
+
+import (
+	"errors"
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+var ErrHashRingEmpty = errors.New("hash ring is empty")
+
+// Количество виртуальных узлов на единицу веса шарда по умолчанию
+const DefaultHashRingReplicas = 160
+
+// Описание узла кольца консистентного хеширования
+type HashRingNode struct {
+	Shard  int // Номер шарда в кластере
+	Weight int // Вес шарда, количество виртуальных узлов пропорционально весу
+}
+
+// Кольцо консистентного хеширования. Используется для распределения ключей по шардам так,
+// что бы при добавлении или удалении шарда перераспределялась только небольшая часть ключей.
+type HashRing struct {
+	lock     sync.RWMutex
+	replicas int
+	nodes    []HashRingNode
+	points   []uint32
+	owners   map[uint32]int
+}
+
+// Конструктор кольца. replicas - количество виртуальных узлов на единицу веса шарда,
+// если не указано, то используется DefaultHashRingReplicas
+func NewHashRing(replicas int, nodes ...HashRingNode) *HashRing {
+	if replicas <= 0 {
+		replicas = DefaultHashRingReplicas
+	}
+
+	ring := &HashRing{
+		replicas: replicas,
+	}
+
+	ring.Rebuild(nodes...)
+
+	return ring
+}
+
+// Rebuild перестраивает кольцо по новому списку узлов
+func (r *HashRing) Rebuild(nodes ...HashRingNode) {
+	points := []uint32{}
+	owners := map[uint32]int{}
+
+	for _, node := range nodes {
+		weight := node.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+
+		for v := 0; v < weight*r.replicas; v++ {
+			point := crc32.ChecksumIEEE([]byte(strconv.Itoa(node.Shard) + "#" + strconv.Itoa(v)))
+			if _, ex := owners[point]; ex {
+				continue
+			}
+
+			owners[point] = node.Shard
+			points = append(points, point)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i] < points[j] })
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.nodes = append([]HashRingNode{}, nodes...)
+	r.points = points
+	r.owners = owners
+}
+
+// Nodes возвращает список узлов, по которому построено кольцо
+func (r *HashRing) Nodes() []HashRingNode {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	return append([]HashRingNode{}, r.nodes...)
+}
+
+// Shard возвращает номер шарда, которому принадлежит ключ
+func (r *HashRing) Shard(key []byte) (int, error) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if len(r.points) == 0 {
+		return 0, ErrHashRingEmpty
+	}
+
+	hash := crc32.ChecksumIEEE(key)
+
+	idx := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= hash })
+	if idx == len(r.points) {
+		idx = 0
+	}
+
+	return r.owners[r.points[idx]], nil
+}
+
+// HashRingNodes возвращает список узлов кольца консистентного хеширования для шардов кластера
+func (c Cluster) HashRingNodes() []HashRingNode {
+	nodes := make([]HashRingNode, 0, len(c))
+
+	for num, shard := range c {
+		nodes = append(nodes, HashRingNode{Shard: num, Weight: shard.Weight})
+	}
+
+	return nodes
+}
+
+// Кеш колец консистентного хеширования по путям в конфиге.
+// Кольцо перестраивается, если поменялся набор шардов кластера или их веса.
+type hashRingCache struct {
+	lock      sync.Mutex
+	container map[string]*HashRing
+}
+
+var hashRings = hashRingCache{container: map[string]*HashRing{}}
+
+// HashRingShard возвращает номер шарда кластера, описанного в конфиге по пути path, которому принадлежит ключ
+func HashRingShard(path string, cluster Cluster, key []byte) (int, error) {
+	nodes := cluster.HashRingNodes()
+
+	hashRings.lock.Lock()
+
+	ring, ex := hashRings.container[path]
+	if !ex {
+		ring = NewHashRing(DefaultHashRingReplicas, nodes...)
+		hashRings.container[path] = ring
+	} else if !equalHashRingNodes(ring.Nodes(), nodes) {
+		ring.Rebuild(nodes...)
+	}
+
+	hashRings.lock.Unlock()
+
+	return ring.Shard(key)
+}
+
+func equalHashRingNodes(a, b []HashRingNode) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,98 @@
+package activerecord
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestHashRing_Shard(t *testing.T) {
+	keys := make([][]byte, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		keys = append(keys, []byte("key"+strconv.Itoa(i)))
+	}
+
+	tests := []struct {
+		name      string
+		nodes     []HashRingNode
+		rebuild   []HashRingNode
+		wantErr   bool
+		maxMoved  int
+		wantShard map[int]bool
+		heavier   []int
+	}{
+		{
+			name:    "empty ring",
+			nodes:   []HashRingNode{},
+			wantErr: true,
+		},
+		{
+			name:      "one shard",
+			nodes:     []HashRingNode{{Shard: 0, Weight: 1}},
+			wantShard: map[int]bool{0: true},
+		},
+		{
+			name:      "add shard",
+			nodes:     []HashRingNode{{Shard: 0, Weight: 1}, {Shard: 1, Weight: 1}, {Shard: 2, Weight: 1}},
+			rebuild:   []HashRingNode{{Shard: 0, Weight: 1}, {Shard: 1, Weight: 1}, {Shard: 2, Weight: 1}, {Shard: 3, Weight: 1}},
+			maxMoved:  400,
+			wantShard: map[int]bool{0: true, 1: true, 2: true, 3: true},
+		},
+		{
+			name:      "weighted shard",
+			nodes:     []HashRingNode{{Shard: 0, Weight: 1}, {Shard: 1, Weight: 3}},
+			wantShard: map[int]bool{0: true, 1: true},
+			heavier:   []int{1, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ring := NewHashRing(0, tt.nodes...)
+
+			before := map[string]int{}
+			for _, key := range keys {
+				shard, err := ring.Shard(key)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("HashRing.Shard() error = %v, wantErr %v", err, tt.wantErr)
+				}
+
+				before[string(key)] = shard
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if tt.rebuild != nil {
+				ring.Rebuild(tt.rebuild...)
+			}
+
+			moved := 0
+			got := map[int]int{}
+
+			for _, key := range keys {
+				shard, err := ring.Shard(key)
+				if err != nil {
+					t.Fatalf("HashRing.Shard() error = %v", err)
+				}
+
+				if !tt.wantShard[shard] {
+					t.Errorf("HashRing.Shard() = %d, want one of %v", shard, tt.wantShard)
+				}
+
+				if before[string(key)] != shard {
+					moved++
+				}
+
+				got[shard]++
+			}
+
+			if moved > tt.maxMoved {
+				t.Errorf("HashRing.Rebuild() moved %d keys, want not more than %d", moved, tt.maxMoved)
+			}
+
+			if len(tt.heavier) == 2 && got[tt.heavier[0]] <= got[tt.heavier[1]] {
+				t.Errorf("HashRing.Shard() weighted distribution = %v, want more keys in shard %d than in %d", got, tt.heavier[0], tt.heavier[1])
+			}
+		})
+	}
+}
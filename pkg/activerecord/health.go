@@ -0,0 +1,88 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// HealthState состояние хранилища модели по результатам последних запросов
+type HealthState int32
+
+const (
+	HealthOK       HealthState = iota // Хранилище отвечает на запросы
+	HealthDegraded                    // Подряд завершилось неудачно не меньше порогового количества запросов
+)
+
+func (s HealthState) String() string {
+	if s == HealthDegraded {
+		return "degraded"
+	}
+
+	return "ok"
+}
+
+// DefaultHealthFailures количество неудачных запросов подряд, после которого хранилище считается деградировавшим,
+// если порог не задан в декларации модели
+const DefaultHealthFailures = 5
+
+// Health отслеживает состояние хранилища модели по результатам запросов. Неудачным считается запрос, завершившийся
+// временной ошибкой (см. IsTransient) или истечением дедлайна контекста, а также запрос, выполнявшийся дольше latency,
+// если порог задан. После failures неудачных запросов подряд хранилище считается деградировавшим и остаётся
+// в этом состоянии до успешной проверки доступности (ObservePing), даже если отдельные запросы выполняются успешно.
+// Используется в сгенерированных пакетах, безопасен для конкурентного использования
+type Health struct {
+	failures    int64
+	latency     time.Duration
+	consecutive int64
+	state       int32
+}
+
+// NewHealth создаёт отслеживание состояния хранилища с порогом failures неудачных запросов подряд
+// и порогом времени выполнения запроса latency, 0 - время выполнения не учитывается
+func NewHealth(failures int64, latency time.Duration) *Health {
+	if failures <= 0 {
+		failures = DefaultHealthFailures
+	}
+
+	return &Health{failures: failures, latency: latency}
+}
+
+// State возвращает текущее состояние хранилища
+func (h *Health) State() HealthState {
+	return HealthState(atomic.LoadInt32(&h.state))
+}
+
+// Observe учитывает результат запроса, выполнявшегося d. Логические ошибки (например, дубль ключа) означают,
+// что хранилище ответило, поэтому учитываются как успешный запрос. Запросы, отменённые вызывающим, не учитываются
+func (h *Health) Observe(err error, d time.Duration) {
+	switch {
+	case errors.Is(err, context.Canceled):
+	case IsTransient(err), errors.Is(err, context.DeadlineExceeded), h.latency > 0 && d > h.latency:
+		h.fail()
+	default:
+		atomic.StoreInt64(&h.consecutive, 0)
+	}
+}
+
+// ObservePing учитывает результат проверки доступности хранилища. Успешная проверка восстанавливает состояние
+// HealthOK, неудачная учитывается как неудачный запрос
+func (h *Health) ObservePing(err error) {
+	if err != nil {
+		if !errors.Is(err, context.Canceled) {
+			h.fail()
+		}
+
+		return
+	}
+
+	atomic.StoreInt64(&h.consecutive, 0)
+	atomic.StoreInt32(&h.state, int32(HealthOK))
+}
+
+func (h *Health) fail() {
+	if atomic.AddInt64(&h.consecutive, 1) >= h.failures {
+		atomic.StoreInt32(&h.state, int32(HealthDegraded))
+	}
+}
@@ -0,0 +1,78 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealth(t *testing.T) {
+	errTransient := &TransientError{Err: errors.New("connection closed")}
+	errLogical := errors.New("duplicate key")
+
+	type observation struct {
+		err  error
+		d    time.Duration
+		ping bool
+	}
+
+	tests := []struct {
+		name         string
+		observations []observation
+		want         HealthState
+	}{
+		{
+			name:         "failures below threshold",
+			observations: []observation{{err: errTransient}, {err: errTransient}},
+			want:         HealthOK,
+		},
+		{
+			name:         "consecutive failures",
+			observations: []observation{{err: errTransient}, {err: context.DeadlineExceeded}, {d: time.Second}},
+			want:         HealthDegraded,
+		},
+		{
+			name:         "success resets counter",
+			observations: []observation{{err: errTransient}, {err: errTransient}, {err: errLogical}, {err: errTransient}, {err: errTransient}},
+			want:         HealthOK,
+		},
+		{
+			name:         "canceled not counted",
+			observations: []observation{{err: errTransient}, {err: errTransient}, {err: context.Canceled}, {err: errTransient}},
+			want:         HealthDegraded,
+		},
+		{
+			name:         "success does not recover",
+			observations: []observation{{err: errTransient}, {err: errTransient}, {err: errTransient}, {}},
+			want:         HealthDegraded,
+		},
+		{
+			name:         "ping recovers",
+			observations: []observation{{err: errTransient}, {err: errTransient}, {err: errTransient}, {ping: true}},
+			want:         HealthOK,
+		},
+		{
+			name:         "failed ping",
+			observations: []observation{{err: errTransient}, {err: errTransient}, {err: errLogical, ping: true}},
+			want:         HealthDegraded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealth(3, 100*time.Millisecond)
+
+			for _, o := range tt.observations {
+				if o.ping {
+					h.ObservePing(o.err)
+				} else {
+					h.Observe(o.err, o.d)
+				}
+			}
+
+			if got := h.State(); got != tt.want {
+				t.Errorf("Health.State() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
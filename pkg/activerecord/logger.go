@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"reflect"
 
 	"github.com/mailru/activerecord/pkg/iproto/iproto"
 )
@@ -154,3 +155,39 @@ func (IprotoLogger) Debugf(ctx context.Context, fmtStr string, v ...interface{})
 	ctx = Logger().SetLoggerValueToContext(ctx, map[string]interface{}{"iproto": "client"})
 	Logger().Debug(ctx, fmt.Sprintf(fmtStr, v...))
 }
+
+// RedactedValue выводится в логи операций вместо ключей, содержащих поля с тегом sensitive
+const RedactedValue = "[REDACTED]"
+
+// FieldString строковое представление значения nullable поля для метода String() модели:
+// выводится значение, на которое указывает указатель, или <nil>
+func FieldString(value interface{}) string {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "<nil>"
+		}
+
+		value = rv.Elem().Interface()
+	}
+
+	return fmt.Sprint(value)
+}
+
+// LogQuery выводит на уровне Debug сведения о выполненной сгенерированным кодом операции:
+// сущность, метод, индекс, значения ключей и ошибку, если операция завершилась неудачно
+func LogQuery(ctx context.Context, labels QueryLabels, keys interface{}, err error) {
+	val := ValueLogPrefix{"entity": labels.Entity, "method": labels.Method, "keys": keys}
+	if labels.Index != "" {
+		val["index"] = labels.Index
+	}
+
+	ctx = Logger().SetLoggerValueToContext(ctx, val)
+
+	if err != nil {
+		Logger().Debug(ctx, labels.Storage, "query failed: ", err)
+		return
+	}
+
+	Logger().Debug(ctx, labels.Storage, "query done")
+}
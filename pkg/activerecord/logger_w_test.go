@@ -0,0 +1,86 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type testQueryLogger struct {
+	DefaultLogger
+	fields ValueLogPrefix
+	args   []interface{}
+}
+
+func (l *testQueryLogger) Debug(ctx context.Context, args ...interface{}) {
+	l.fields, _ = ctx.Value(ContextLogprefix).(ValueLogPrefix)
+	l.args = args
+}
+
+func TestLogQuery(t *testing.T) {
+	tests := []struct {
+		name       string
+		labels     QueryLabels
+		keys       interface{}
+		err        error
+		wantFields ValueLogPrefix
+		wantArgs   []interface{}
+	}{
+		{
+			name:       "select",
+			labels:     QueryLabels{Storage: "octopus", Entity: "Foo", Method: "SelectByIDs", Index: "ID"},
+			keys:       []int32{1, 2},
+			wantFields: ValueLogPrefix{"entity": "Foo", "method": "SelectByIDs", "index": "ID", "keys": []int32{1, 2}},
+			wantArgs:   []interface{}{"octopus", "query done"},
+		},
+		{
+			name:       "failed delete",
+			labels:     QueryLabels{Storage: "tarantool", Entity: "Foo", Method: "delete"},
+			keys:       RedactedValue,
+			err:        errors.New("timeout"),
+			wantFields: ValueLogPrefix{"entity": "Foo", "method": "delete", "keys": RedactedValue},
+			wantArgs:   []interface{}{"tarantool", "query failed: ", errors.New("timeout")},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := &testQueryLogger{}
+
+			ReinitActiveRecord(WithLogger(logger))
+
+			LogQuery(context.Background(), tt.labels, tt.keys, tt.err)
+
+			if !reflect.DeepEqual(logger.fields, tt.wantFields) {
+				t.Errorf("LogQuery() fields = %v, want %v", logger.fields, tt.wantFields)
+			}
+
+			if !reflect.DeepEqual(logger.args, tt.wantArgs) {
+				t.Errorf("LogQuery() args = %v, want %v", logger.args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestFieldString(t *testing.T) {
+	str := "guest"
+
+	var nilStr *string
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "value", value: 42, want: "42"},
+		{name: "pointer", value: &str, want: "guest"},
+		{name: "nil pointer", value: nilStr, want: "<nil>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FieldString(tt.value); got != tt.want {
+				t.Errorf("FieldString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
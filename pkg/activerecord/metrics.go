@@ -1,6 +1,9 @@
 package activerecord
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type DefaultNoopMetric struct{}
 
@@ -28,3 +31,23 @@ func (*DefaultNoopMetricTimer) Finish(ctx context.Context, name string) {}
 type DefaultNoopMetricCount struct{}
 
 func (*DefaultNoopMetricCount) Inc(ctx context.Context, name string, val float64) {}
+
+type DefaultNoopQueryMetric struct{}
+
+func NewDefaultNoopQueryMetric() *DefaultNoopQueryMetric {
+	return &DefaultNoopQueryMetric{}
+}
+
+func (*DefaultNoopQueryMetric) Inc(ctx context.Context, labels QueryLabels)                      {}
+func (*DefaultNoopQueryMetric) Observe(ctx context.Context, labels QueryLabels, d time.Duration) {}
+
+// ObserveQuery учитывает запрос в метриках QueryMetricInterface: увеличивает счётчик запросов и фиксирует
+// время выполнения с момента started. Признак успешности запроса определяется по err.
+// Используется в сгенерированных пакетах для всех выборок и изменений записей
+func ObserveQuery(ctx context.Context, labels QueryLabels, started time.Time, err error) {
+	labels.Success = err == nil
+
+	queryMetric := QueryMetric()
+	queryMetric.Inc(ctx, labels)
+	queryMetric.Observe(ctx, labels, time.Since(started))
+}
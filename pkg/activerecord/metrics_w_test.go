@@ -0,0 +1,59 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testQueryMetric struct {
+	inc     []QueryLabels
+	observe []QueryLabels
+}
+
+func (m *testQueryMetric) Inc(ctx context.Context, labels QueryLabels) {
+	m.inc = append(m.inc, labels)
+}
+
+func (m *testQueryMetric) Observe(ctx context.Context, labels QueryLabels, d time.Duration) {
+	m.observe = append(m.observe, labels)
+}
+
+func TestObserveQuery(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantSuccess bool
+	}{
+		{
+			name:        "success",
+			wantSuccess: true,
+		},
+		{
+			name:        "failure",
+			err:         errors.New("timeout"),
+			wantSuccess: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metric := &testQueryMetric{}
+
+			ReinitActiveRecord(WithQueryMetric(metric))
+
+			labels := QueryLabels{Storage: "octopus", Entity: "Foo", Namespace: "5", Method: "select", Index: "ID"}
+			ObserveQuery(context.Background(), labels, time.Now(), tt.err)
+
+			labels.Success = tt.wantSuccess
+
+			if len(metric.inc) != 1 || metric.inc[0] != labels {
+				t.Errorf("ObserveQuery() inc = %+v, want %+v", metric.inc, labels)
+			}
+
+			if len(metric.observe) != 1 || metric.observe[0] != labels {
+				t.Errorf("ObserveQuery() observe = %+v, want %+v", metric.observe, labels)
+			}
+		})
+	}
+}
@@ -28,12 +28,44 @@ func WithMetrics(metric MetricInterface) Option {
 	})
 }
 
+func WithQueryMetric(queryMetric QueryMetricInterface) Option {
+	return optionFunc(func(a *ActiveRecord) {
+		a.queryMetric = queryMetric
+	})
+}
+
 func WithConnectionPinger(pc PingerInterface) Option {
 	return optionFunc(func(a *ActiveRecord) {
 		a.pinger = pc
 	})
 }
 
+func WithSlowQueryHook(hook SlowQueryHook) Option {
+	return optionFunc(func(a *ActiveRecord) {
+		a.slowQueryHook = hook
+	})
+}
+
+func WithTracer(tracer TracerInterface) Option {
+	return optionFunc(func(a *ActiveRecord) {
+		a.tracer = tracer
+	})
+}
+
+// WithKeyProvider задаёт источник ключей для полей со встроенным сериализатором encrypt
+func WithKeyProvider(provider KeyProviderInterface) Option {
+	return optionFunc(func(a *ActiveRecord) {
+		a.keyProvider = provider
+	})
+}
+
+// WithAuditSink задаёт получателя журнала аудита изменений полей для моделей с параметром audit
+func WithAuditSink(sink AuditSinkInterface) Option {
+	return optionFunc(func(a *ActiveRecord) {
+		a.auditSink = sink
+	})
+}
+
 type clusterOption interface {
 	apply(*Cluster)
 }
@@ -56,6 +88,14 @@ func WithShard(masters []OptionInterface, replicas []OptionInterface) clusterOpt
 			})
 		}
 
+		for _, opt := range replicas {
+			newShard.Replicas = append(newShard.Replicas, ShardInstance{
+				ParamsID: opt.GetConnectionID(),
+				Config:   ShardInstanceConfig{Addr: "static", Mode: ModeReplica},
+				Options:  opt,
+			})
+		}
+
 		*c = append(*c, newShard)
 	})
 }
@@ -0,0 +1,39 @@
+package activerecord
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxOp вид изменения записи, о котором сообщает событие outbox
+type OutboxOp string
+
+const (
+	OutboxInsert  OutboxOp = "insert"  // Запись вставлена
+	OutboxUpdate  OutboxOp = "update"  // Запись обновлена
+	OutboxDelete  OutboxOp = "delete"  // Запись удалена
+	OutboxReplace OutboxOp = "replace" // Запись вставлена или заменена целиком
+)
+
+// OutboxEvent событие об изменении записи, которое сохраняется в неймспейс outbox в одной транзакции
+// с изменением записи. Публикацией событий из неймспейса (например, в Kafka) занимается отдельный процесс
+type OutboxEvent struct {
+	ID      string    // Уникальный идентификатор события
+	Entity  string    // Имя модели
+	Op      OutboxOp  // Вид изменения
+	Key     []byte    // Первичный ключ записи в JSON
+	Payload []byte    // Запись в JSON: для вставки и обновления - после изменения, для удаления - удалённая запись
+	Created time.Time // Время формирования события
+}
+
+// NewOutboxEvent формирует событие outbox со случайным идентификатором
+func NewOutboxEvent(entity string, op OutboxOp, key, payload []byte) (OutboxEvent, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return OutboxEvent{}, fmt.Errorf("can't generate outbox event id: %w", err)
+	}
+
+	return OutboxEvent{ID: id.String(), Entity: entity, Op: op, Key: key, Payload: payload, Created: time.Now()}, nil
+}
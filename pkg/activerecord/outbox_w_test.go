@@ -0,0 +1,25 @@
+package activerecord
+
+import (
+	"testing"
+)
+
+func TestNewOutboxEvent(t *testing.T) {
+	first, err := NewOutboxEvent("User", OutboxInsert, []byte(`1`), []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("NewOutboxEvent() error = %v", err)
+	}
+
+	second, err := NewOutboxEvent("User", OutboxInsert, []byte(`1`), []byte(`{"id":1}`))
+	if err != nil {
+		t.Fatalf("NewOutboxEvent() error = %v", err)
+	}
+
+	if first.ID == "" || first.ID == second.ID {
+		t.Errorf("NewOutboxEvent() ids = %s, %s, want unique", first.ID, second.ID)
+	}
+
+	if first.Entity != "User" || first.Op != OutboxInsert || string(first.Key) != "1" || first.Created.IsZero() {
+		t.Errorf("NewOutboxEvent() = %+v", first)
+	}
+}
@@ -0,0 +1,21 @@
+package activerecord
+
+import "math/rand"
+
+// randomLetters символы, из которых составляются случайные строки
+const randomLetters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+
+// RandomString возвращает случайную строку длиной от 1 до maxLen символов из латинских букв и цифр.
+// Используется в фабриках случайных записей сторов фикстур
+func RandomString(rnd *rand.Rand, maxLen int) string {
+	if maxLen <= 0 {
+		return ""
+	}
+
+	ret := make([]byte, rnd.Intn(maxLen)+1)
+	for i := range ret {
+		ret[i] = randomLetters[rnd.Intn(len(randomLetters))]
+	}
+
+	return string(ret)
+}
@@ -0,0 +1,23 @@
+package activerecord
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestRandomString(t *testing.T) {
+	for seed := int64(0); seed < 100; seed++ {
+		got := RandomString(rand.New(rand.NewSource(seed)), 4)
+		if len(got) == 0 || len(got) > 4 {
+			t.Fatalf("RandomString() = %q, want length from 1 to 4", got)
+		}
+
+		if again := RandomString(rand.New(rand.NewSource(seed)), 4); again != got {
+			t.Fatalf("RandomString() = %q, want %q for the same seed", again, got)
+		}
+	}
+
+	if got := RandomString(rand.New(rand.NewSource(1)), 0); got != "" {
+		t.Errorf("RandomString() = %q, want empty string", got)
+	}
+}
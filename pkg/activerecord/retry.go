@@ -0,0 +1,38 @@
+package activerecord
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// DefaultRetryDelay пауза перед первым повтором запроса, если она не задана в декларации модели
+const DefaultRetryDelay = 10 * time.Millisecond
+
+// Retry выполняет fn и повторяет её не более attempts раз, пока она возвращает временную ошибку (см. IsTransient).
+// Перед каждым повтором выдерживается пауза, которая начинается с delay и удваивается с каждой попыткой,
+// к паузе добавляется случайная добавка до половины её длительности, чтобы клиенты не повторяли запросы одновременно.
+// Если контекст завершён во время паузы, возвращается последняя ошибка fn.
+// Используется в сгенерированных пакетах, для которых задано количество повторов.
+func Retry(ctx context.Context, attempts int, delay time.Duration, fn func() error) error {
+	err := fn()
+
+	for attempt := 0; attempt < attempts && IsTransient(err); attempt++ {
+		backoff := delay << attempt
+		backoff += time.Duration(rand.Int63n(int64(backoff)/2 + 1)) //nolint:gosec
+
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+
+		err = fn()
+	}
+
+	return err
+}
@@ -0,0 +1,83 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetry(t *testing.T) {
+	errTransient := &TransientError{Err: errors.New("connection closed")}
+	errLogical := errors.New("duplicate key")
+
+	tests := []struct {
+		name      string
+		attempts  int
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{
+			name:      "success",
+			attempts:  3,
+			errs:      []error{nil},
+			wantCalls: 1,
+		},
+		{
+			name:      "transient then success",
+			attempts:  3,
+			errs:      []error{errTransient, errTransient, nil},
+			wantCalls: 3,
+		},
+		{
+			name:      "attempts exceeded",
+			attempts:  2,
+			errs:      []error{errTransient, errTransient, errTransient, nil},
+			wantCalls: 3,
+			wantErr:   ErrTransient,
+		},
+		{
+			name:      "logical error",
+			attempts:  3,
+			errs:      []error{errLogical, nil},
+			wantCalls: 1,
+			wantErr:   errLogical,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+
+			err := Retry(context.Background(), tt.attempts, time.Microsecond, func() error {
+				err := tt.errs[calls]
+				calls++
+
+				return err
+			})
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Retry() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("Retry() calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestRetryContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+
+	err := Retry(ctx, 3, time.Hour, func() error {
+		calls++
+
+		return &TransientError{Err: errors.New("timeout")}
+	})
+	if !IsTransient(err) || calls != 1 {
+		t.Errorf("Retry() error = %v, calls = %d, want transient error after 1 call", err, calls)
+	}
+}
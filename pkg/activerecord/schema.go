@@ -0,0 +1,58 @@
+package activerecord
+
+import "fmt"
+
+// SchemaDescriptor описание модели, сформированное по декларации при генерации.
+// Позволяет получить схему хранилища во время выполнения без файлов декларации.
+type SchemaDescriptor struct {
+	Name      string        // Имя модели
+	Version   string        // Версия схемы хранилища, меняется при изменении полей и индексов
+	Package   string        // Имя сгенерированного пакета
+	Backend   string        // Тип хранилища
+	Namespace string        // Номер неймспейса или имя процедуры
+	Fields    []SchemaField // Поля модели или выходные параметры процедуры
+	Params    []SchemaField // Входные параметры процедуры
+	Indexes   []SchemaIndex // Индексы модели
+}
+
+// SchemaMismatchError ошибка проверки схемы хранилища: структура данных в БД не соответствует
+// декларации, по которой сгенерирована модель
+type SchemaMismatchError struct {
+	Entity  string
+	Version string // Версия схемы сгенерированной модели
+	Reason  string // Описание расхождения
+}
+
+func (e *SchemaMismatchError) Error() string {
+	return fmt.Sprintf("%s (schema version %s): %s: %s", e.Entity, e.Version, ErrSchemaMismatch, e.Reason)
+}
+
+func (e *SchemaMismatchError) Unwrap() error {
+	return ErrSchemaMismatch
+}
+
+// SchemaField описание поля модели
+type SchemaField struct {
+	Name       string   // Имя поля
+	Storage    string   // Имя поля в хранилище, если оно отличается от имени поля
+	Format     string   // Формат хранения поля
+	Size       int64    // Размер поля, для строковых значений
+	PrimaryKey bool     // Участвует ли поле в первичном ключе
+	Nullable   bool     // Может ли поле хранить NULL
+	Array      bool     // Хранит ли поле массив значений формата Format
+	Timestamp  string   // Формат хранения поля time.Time
+	Serializer string   // Имя сериализатора
+	Mutators   []string // Список мутаторов
+	Flags      []string // Список флагов
+}
+
+// SchemaIndex описание индекса модели
+type SchemaIndex struct {
+	Name     string   // Имя индекса
+	Num      uint32   // Номер индекса в хранилище
+	Fields   []string // Поля индекса
+	Selector string   // Имя селектора
+	Primary  bool     // Является ли индекс первичным ключом
+	Unique   bool     // Является ли индекс уникальным
+	Partial  bool     // Является ли индекс частью составного индекса
+}
@@ -0,0 +1,24 @@
+package activerecord
+
+import (
+	"time"
+)
+
+// SlowQueryHook функция, вызываемая для запросов, время выполнения которых превысило порог.
+// Получает имя операции, время её выполнения и ключи запроса.
+type SlowQueryHook func(op string, d time.Duration, keys any)
+
+// SlowQuery вызывает SlowQueryHook, если с момента started прошло не меньше threshold.
+// Используется в сгенерированных пакетах, для которых задан порог медленного запроса.
+func SlowQuery(op string, started time.Time, threshold time.Duration, keys any) {
+	if instance == nil || instance.slowQueryHook == nil {
+		return
+	}
+
+	d := time.Since(started)
+	if d < threshold {
+		return
+	}
+
+	instance.slowQueryHook(op, d, keys)
+}
@@ -0,0 +1,60 @@
+package activerecord
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlowQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		hook      bool
+		started   time.Duration
+		threshold time.Duration
+		wantCall  bool
+	}{
+		{
+			name:      "without hook",
+			started:   time.Second,
+			threshold: time.Millisecond,
+			wantCall:  false,
+		},
+		{
+			name:      "fast query",
+			hook:      true,
+			threshold: time.Hour,
+			wantCall:  false,
+		},
+		{
+			name:      "slow query",
+			hook:      true,
+			started:   time.Second,
+			threshold: time.Millisecond,
+			wantCall:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotOp string
+
+			opts := []Option{}
+			if tt.hook {
+				opts = append(opts, WithSlowQueryHook(func(op string, d time.Duration, keys any) {
+					gotOp = op
+				}))
+			}
+
+			ReinitActiveRecord(opts...)
+
+			SlowQuery("Foo.select", time.Now().Add(-tt.started), tt.threshold, []int{1})
+
+			if (gotOp != "") != tt.wantCall {
+				t.Errorf("SlowQuery() hook called = %v, want %v", gotOp != "", tt.wantCall)
+			}
+
+			if tt.wantCall && gotOp != "Foo.select" {
+				t.Errorf("SlowQuery() op = %s, want Foo.select", gotOp)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package activerecord
+
+import (
+	"context"
+)
+
+// SpanAttrs атрибуты span-а запроса к БД
+type SpanAttrs struct {
+	Space string // Имя неймспейса (спейса) модели
+	Index uint32 // Номер индекса, по которому выполняется запрос
+}
+
+// Span span запроса, созданный трассировщиком
+type Span interface {
+	// End завершает span. Если err не nil, ошибка записывается в span
+	End(err error)
+}
+
+// TracerInterface интерфейс трассировщика запросов. Реализация, например поверх OpenTelemetry,
+// передаётся при инициализации опцией WithTracer
+type TracerInterface interface {
+	Start(ctx context.Context, name string, attrs SpanAttrs) (context.Context, Span)
+}
+
+type noopSpan struct{}
+
+func (noopSpan) End(error) {}
+
+// StartSpan создаёт span запроса с именем name. Если трассировщик не передан, span не создаётся
+// и возвращается пустая реализация, не выполняющая никаких действий.
+// Используется в сгенерированных пакетах, для которых включена трассировка.
+func StartSpan(ctx context.Context, name string, attrs SpanAttrs) (context.Context, Span) {
+	if instance == nil || instance.tracer == nil {
+		return ctx, noopSpan{}
+	}
+
+	return instance.tracer.Start(ctx, name, attrs)
+}
@@ -0,0 +1,60 @@
+package activerecord
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+type testTracer struct {
+	name  string
+	attrs SpanAttrs
+	err   error
+	ended bool
+}
+
+func (tr *testTracer) Start(ctx context.Context, name string, attrs SpanAttrs) (context.Context, Span) {
+	tr.name = name
+	tr.attrs = attrs
+
+	return ctx, tr
+}
+
+func (tr *testTracer) End(err error) {
+	tr.ended = true
+	tr.err = err
+}
+
+func TestStartSpan(t *testing.T) {
+	errSelect := errors.New("select error")
+
+	t.Run("without tracer", func(t *testing.T) {
+		ReinitActiveRecord()
+
+		ctx := context.Background()
+
+		gotCtx, span := StartSpan(ctx, "Foo.select", SpanAttrs{Space: "5", Index: 1})
+		if gotCtx != ctx {
+			t.Errorf("StartSpan() context changed without tracer")
+		}
+
+		span.End(errSelect)
+	})
+
+	t.Run("with tracer", func(t *testing.T) {
+		tracer := &testTracer{}
+
+		ReinitActiveRecord(WithTracer(tracer))
+
+		_, span := StartSpan(context.Background(), "Foo.select", SpanAttrs{Space: "5", Index: 1})
+		span.End(errSelect)
+
+		if tracer.name != "Foo.select" || tracer.attrs != (SpanAttrs{Space: "5", Index: 1}) {
+			t.Errorf("StartSpan() name = %s, attrs = %+v", tracer.name, tracer.attrs)
+		}
+
+		if !tracer.ended || !errors.Is(tracer.err, errSelect) {
+			t.Errorf("Span.End() ended = %t, err = %v", tracer.ended, tracer.err)
+		}
+	})
+}
@@ -282,6 +282,11 @@ func (p *Pool) InitAll(ctx context.Context) (err error) {
 	return p.connect(ctx, p.config.Size)
 }
 
+// InitN is the same as Init, except that it tries to get n connections, but no more than PoolConfig.Size.
+func (p *Pool) InitN(ctx context.Context, n int) (err error) {
+	return p.connect(ctx, n)
+}
+
 func (p *Pool) connect(ctx context.Context, n int) error {
 	if n > p.config.Size {
 		n = p.config.Size
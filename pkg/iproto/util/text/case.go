@@ -0,0 +1,77 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// commonInitialisms contains acronyms written in upper case in go names.
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true,
+	"EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true,
+	"IP": true, "JSON": true, "QPS": true, "RAM": true, "RPC": true, "SLA": true,
+	"SMTP": true, "SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true,
+	"UDP": true, "UI": true, "UID": true, "UUID": true, "URI": true, "URL": true,
+	"UTF8": true, "VM": true, "XML": true, "XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// words splits given name into lowercase words. Name can be in any of
+// "snake_case", "kebab-case", "camelCase" or "PascalCase" formats.
+func words(name string) []string {
+	return strings.FieldsFunc(ToSnakeCase(name), func(c rune) bool {
+		return c == '_' || c == '-' || unicode.IsSpace(c)
+	})
+}
+
+// ToPascalCase converts given name to "PascalCaseFormat".
+// Common initialisms are written in upper case: "user_id" -> "UserID", "user_ids" -> "UserIDs".
+func ToPascalCase(name string) string {
+	ret := strings.Builder{}
+
+	for _, word := range words(name) {
+		ret.WriteString(titleWord(word))
+	}
+
+	return ret.String()
+}
+
+// ToCamelCase converts given name to "camelCaseFormat".
+// The first word is always in lower case: "ID" -> "id", "IDs" -> "ids", "user_id" -> "userID".
+func ToCamelCase(name string) string {
+	ret := strings.Builder{}
+
+	for i, word := range words(name) {
+		if i == 0 {
+			ret.WriteString(word)
+			continue
+		}
+
+		ret.WriteString(titleWord(word))
+	}
+
+	return ret.String()
+}
+
+func titleWord(word string) string {
+	if upper := strings.ToUpper(word); commonInitialisms[upper] {
+		return upper
+	}
+
+	// Plural initialism: "ids" -> "IDs"
+	if single := strings.TrimSuffix(word, "s"); single != word && commonInitialisms[strings.ToUpper(single)] {
+		return strings.ToUpper(single) + "s"
+	}
+
+	return upperFirst(word)
+}
+
+func upperFirst(word string) string {
+	if word == "" {
+		return word
+	}
+
+	runes := []rune(word)
+	runes[0] = unicode.ToUpper(runes[0])
+
+	return string(runes)
+}
@@ -0,0 +1,65 @@
+package text
+
+import "testing"
+
+func TestToPascalCase(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"user", "User"},
+		{"user_id", "UserID"},
+		{"UserID", "UserID"},
+		{"userName", "UserName"},
+		{"http_server", "HTTPServer"},
+		{"some-kebab-case", "SomeKebabCase"},
+		{"json_api_url", "JSONAPIURL"},
+		{"user_ids", "UserIDs"},
+		{"UserIDs", "UserIDs"},
+	} {
+		got := ToPascalCase(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] ToPascalCase(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
+
+func TestToCamelCase(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"User", "user"},
+		{"ID", "id"},
+		{"UserID", "userID"},
+		{"user_id", "userID"},
+		{"SomeHTTPStuff", "someHTTPStuff"},
+		{"camelCase", "camelCase"},
+		{"IDs", "ids"},
+		{"UserIDs", "userIDs"},
+	} {
+		got := ToCamelCase(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] ToCamelCase(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
+
+func TestCaseAcronymSnake(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"UserID", "user_id"},
+		{"userURL", "user_url"},
+		{"ParseHTTPRequest", "parse_http_request"},
+		{"UserIDs", "user_ids"},
+		{"IDsCount", "ids_count"},
+		{"HTTPServers", "http_servers"},
+		{"ThisIs", "this_is"},
+	} {
+		got := ToSnakeCase(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] ToSnakeCase(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
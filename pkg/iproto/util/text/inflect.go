@@ -0,0 +1,163 @@
+package text
+
+import (
+	"strings"
+	"unicode"
+)
+
+// irregularPlurals contains plural forms that can not be derived by rules.
+var irregularPlurals = map[string]string{
+	"person": "people", "man": "men", "woman": "women", "child": "children",
+	"tooth": "teeth", "foot": "feet", "mouse": "mice", "goose": "geese", "ox": "oxen",
+	"datum": "data", "medium": "media", "criterion": "criteria", "index": "indices",
+	"matrix": "matrices", "vertex": "vertices", "analysis": "analyses", "basis": "bases",
+	"crisis": "crises", "thesis": "theses", "axis": "axes", "quiz": "quizzes",
+	"knife": "knives", "wife": "wives", "life": "lives", "leaf": "leaves", "half": "halves",
+	"wolf": "wolves", "shelf": "shelves", "thief": "thieves", "loaf": "loaves",
+	"hero": "heroes", "potato": "potatoes", "tomato": "tomatoes", "echo": "echoes",
+	"movie": "movies", "status": "statuses", "bus": "buses", "alias": "aliases",
+	"virus": "viruses", "campus": "campuses", "cookie": "cookies", "pie": "pies", "tie": "ties",
+	"zombie": "zombies", "rookie": "rookies", "calorie": "calories", "selfie": "selfies",
+	"newbie": "newbies", "genie": "genies", "gas": "gases",
+}
+
+// uncountables contains words with the same singular and plural forms.
+var uncountables = map[string]bool{
+	"sheep": true, "fish": true, "deer": true, "series": true, "species": true,
+	"information": true, "equipment": true, "news": true, "money": true, "rice": true,
+	"metadata": true,
+}
+
+var irregularSingulars = func() map[string]string {
+	ret := make(map[string]string, len(irregularPlurals))
+	for single, plural := range irregularPlurals {
+		ret[plural] = single
+	}
+
+	return ret
+}()
+
+// Pluralize returns plural form of the last word in given name: "UserAddress" -> "UserAddresses",
+// "Person" -> "People". Case of the word is preserved, acronyms get lowercase "s": "UserID" -> "UserIDs".
+// Words that are already plural are returned as is: "Movies" -> "Movies", "Data" -> "Data".
+func Pluralize(name string) string {
+	if isPluralAcronym(name) {
+		return name
+	}
+
+	return inflect(name, pluralWord, func(acronym string) string { return acronym + "s" })
+}
+
+// Singularize returns singular form of the last word in given name: "UserAddresses" -> "UserAddress",
+// "People" -> "Person", "UserIDs" -> "UserID".
+func Singularize(name string) string {
+	if isPluralAcronym(name) {
+		return strings.TrimSuffix(name, "s")
+	}
+
+	return inflect(name, singularWord, func(acronym string) string { return acronym })
+}
+
+// isPluralAcronym reports whether name ends with a plural acronym, e.g. "IDs".
+func isPluralAcronym(name string) bool {
+	runes := []rune(name)
+	l := len(runes)
+
+	return l > 2 && runes[l-1] == 's' && unicode.IsUpper(runes[l-2]) && unicode.IsUpper(runes[l-3])
+}
+
+// inflect applies conv to the last word of name preserving its case.
+// Upper case words are considered to be acronyms and converted by convAcronym.
+func inflect(name string, conv, convAcronym func(string) string) string {
+	w := words(name)
+	if len(w) == 0 {
+		return name
+	}
+
+	runes := []rune(name)
+	lastLen := len([]rune(w[len(w)-1]))
+	prefix, word := string(runes[:len(runes)-lastLen]), string(runes[len(runes)-lastLen:])
+
+	switch {
+	case lastLen > 1 && word == strings.ToUpper(word) && word != strings.ToLower(word):
+		return prefix + convAcronym(word)
+	case unicode.IsUpper([]rune(word)[0]):
+		return prefix + upperFirst(conv(strings.ToLower(word)))
+	default:
+		return prefix + conv(word)
+	}
+}
+
+func pluralWord(word string) string {
+	if uncountables[word] {
+		return word
+	}
+
+	if plural, ok := irregularPlurals[word]; ok {
+		return plural
+	}
+
+	// The word is already plural: "data", "movies", "users"
+	if _, ok := irregularSingulars[word]; ok {
+		return word
+	}
+
+	if single := singularWord(word); single != word && regularPlural(single) == word {
+		return word
+	}
+
+	return regularPlural(word)
+}
+
+// regularPlural returns plural form of the word built by rules for regular nouns.
+func regularPlural(word string) string {
+	switch {
+	case hasAnySuffix(word, "s", "x", "z", "ch", "sh"):
+		return word + "es"
+	case strings.HasSuffix(word, "y") && len(word) > 1 && !isVowel(word[len(word)-2]):
+		return word[:len(word)-1] + "ies"
+	default:
+		return word + "s"
+	}
+}
+
+func singularWord(word string) string {
+	if uncountables[word] {
+		return word
+	}
+
+	if single, ok := irregularSingulars[word]; ok {
+		return single
+	}
+
+	if _, ok := irregularPlurals[word]; ok {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case hasAnySuffix(word, "sses", "xes", "zes", "ches", "shes"):
+		return word[:len(word)-2]
+	case hasAnySuffix(word, "ss", "us", "is"):
+		return word
+	case strings.HasSuffix(word, "s"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
+
+func hasAnySuffix(word string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(word, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aeiou", c) != -1
+}
@@ -0,0 +1,77 @@
+package text
+
+import "testing"
+
+func TestPluralize(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"user", "users"},
+		{"User", "Users"},
+		{"UserAddress", "UserAddresses"},
+		{"box", "boxes"},
+		{"Match", "Matches"},
+		{"category", "categories"},
+		{"day", "days"},
+		{"Person", "People"},
+		{"child", "children"},
+		{"OrderItem", "OrderItems"},
+		{"Index", "Indices"},
+		{"knife", "knives"},
+		{"hero", "heroes"},
+		{"photo", "photos"},
+		{"Status", "Statuses"},
+		{"sheep", "sheep"},
+		{"UserInformation", "UserInformation"},
+		{"UserID", "UserIDs"},
+		{"user_id", "user_ids"},
+		{"UserIDs", "UserIDs"},
+		{"Data", "Data"},
+		{"Movies", "Movies"},
+		{"Users", "Users"},
+		{"Boxes", "Boxes"},
+		{"Cookie", "Cookies"},
+		{"Address", "Addresses"},
+		{"gas", "gases"},
+	} {
+		got := Pluralize(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] Pluralize(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
+
+func TestSingularize(t *testing.T) {
+	for i, test := range []struct {
+		In, Out string
+	}{
+		{"", ""},
+		{"users", "user"},
+		{"UserAddresses", "UserAddress"},
+		{"boxes", "box"},
+		{"Matches", "Match"},
+		{"categories", "category"},
+		{"days", "day"},
+		{"cases", "case"},
+		{"People", "Person"},
+		{"children", "child"},
+		{"Indices", "Index"},
+		{"knives", "knife"},
+		{"Statuses", "Status"},
+		{"status", "status"},
+		{"class", "class"},
+		{"sheep", "sheep"},
+		{"UserIDs", "UserID"},
+		{"UserID", "UserID"},
+		{"Cookies", "Cookie"},
+		{"Movies", "Movie"},
+		{"ties", "tie"},
+		{"Data", "Datum"},
+	} {
+		got := Singularize(test.In)
+		if got != test.Out {
+			t.Errorf("[%d] Singularize(%s) = %s; want %s", i, test.In, got, test.Out)
+		}
+	}
+}
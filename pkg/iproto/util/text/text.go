@@ -40,16 +40,22 @@ func ToSnakeCase(name string) string {
 		beforeUpper rune
 	)
 
-	for _, c := range name {
+	runes := []rune(name)
+
+	for i, c := range runes {
 		// Non-lowercase character after uppercase is considered to be uppercase too.
 		isUpper := (unicode.IsUpper(c) || (lastUpper != 0 && !unicode.IsLower(c)))
 
+		// Lowercase 's' ending a row of uppercase characters is a plural acronym (e.g. "IDs"),
+		// it belongs to the acronym and does not start a new word.
+		pluralAcronym := c == 's' && multipleUpper && (i+1 == len(runes) || !unicode.IsLower(runes[i+1]))
+
 		// Output a delimiter if last character was either the first uppercase character
 		// in a row, or the last one in a row (e.g. 'S' in "HTTPServer").
 		// Do not output a delimiter at the beginning of the name.
 		if lastUpper != 0 {
 			firstInRow := !multipleUpper
-			lastInRow := !isUpper
+			lastInRow := !isUpper && !pluralAcronym
 
 			if ret.Len() > 0 && (firstInRow || lastInRow) && beforeUpper != '_' {
 				ret.WriteByte('_')
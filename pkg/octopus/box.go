@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/mailru/activerecord/pkg/activerecord"
 	"github.com/mailru/activerecord/pkg/iproto/iproto"
@@ -15,63 +16,35 @@ import (
 // - сделать статистику по используемым инстансам
 // - прикрутить локальный пингер и исключать недоступные инстансы
 func Box(ctx context.Context, shard int, instType activerecord.ShardInstanceType, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (*Connection, error) {
-	if optionCreator == nil {
-		optionCreator = func(sic activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error) {
-			return NewOptions(
-				sic.Addr,
-				ServerModeType(sic.Mode),
-				WithTimeout(sic.Timeout, sic.Timeout),
-				WithPoolSize(sic.PoolSize),
-				WithPoolLogger(activerecord.IprotoLogger{}),
-			)
-		}
-	}
-
-	clusterInfo, err := activerecord.ConfigCacher().Get(
-		ctx,
-		configPath,
-		activerecord.MapGlobParam{
-			Timeout:  DefaultConnectionTimeout,
-			PoolSize: DefaultPoolSize,
-		},
-		optionCreator,
-	)
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
 	if err != nil {
-		return nil, fmt.Errorf("can't get cluster %s info: %w", configPath, err)
+		return nil, err
 	}
 
 	if len(clusterInfo) < shard {
 		return nil, fmt.Errorf("invalid shard num %d, max = %d", shard, len(clusterInfo))
 	}
 
-	var configBox activerecord.ShardInstance
-
-	switch instType {
-	case activerecord.ReplicaInstanceType:
-		if len(clusterInfo[shard].Replicas) == 0 {
-			return nil, fmt.Errorf("replicas not set")
-		}
-
-		configBox = clusterInfo[shard].NextReplica()
-	case activerecord.ReplicaOrMasterInstanceType:
-		if len(clusterInfo[shard].Replicas) != 0 {
-			configBox = clusterInfo[shard].NextReplica()
-			break
-		}
-
-		fallthrough
-	case activerecord.MasterInstanceType:
-		configBox = clusterInfo[shard].NextMaster()
+	configBox, err := clusterInfo[shard].Instance(ctx, instType)
+	if err != nil {
+		return nil, err
 	}
 
-	conn, err := activerecord.ConnectionCacher().GetOrAdd(configBox, func(options interface{}) (activerecord.ConnectionInterface, error) {
+	connector := func(options interface{}) (activerecord.ConnectionInterface, error) {
 		octopusOpt, ok := options.(*ConnectionOptions)
 		if !ok {
 			return nil, fmt.Errorf("invalit type of options %T, want Options", options)
 		}
 
 		return GetConnection(ctx, octopusOpt)
-	})
+	}
+
+	conn, err := activerecord.ConnectionCacher().GetOrAdd(configBox, connector)
+	if err != nil && instType == activerecord.ReplicaOrMasterInstanceType && configBox.Config.Mode == activerecord.ModeReplica {
+		// Реплика недоступна, чтение выполняется на мастере
+		conn, err = activerecord.ConnectionCacher().GetOrAdd(clusterInfo[shard].NextMaster(), connector)
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("error from connectionCacher: %w", err)
 	}
@@ -84,6 +57,56 @@ func Box(ctx context.Context, shard int, instType activerecord.ShardInstanceType
 	return box, nil
 }
 
+// ShardCount - возвращает количество шардов в кластере
+func ShardCount(ctx context.Context, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (int, error) {
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(clusterInfo), nil
+}
+
+// ShardByKey - возвращает номер шарда, которому принадлежит ключ, при распределении ключей
+// кольцом консистентного хеширования
+func ShardByKey(ctx context.Context, key []byte, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (int, error) {
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
+	if err != nil {
+		return 0, err
+	}
+
+	return activerecord.HashRingShard(configPath, clusterInfo, key)
+}
+
+func getCluster(ctx context.Context, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (activerecord.Cluster, error) {
+	if optionCreator == nil {
+		optionCreator = func(sic activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error) {
+			return NewOptions(
+				sic.Addr,
+				ServerModeType(sic.Mode),
+				WithTimeout(sic.Timeout, sic.Timeout),
+				WithPoolSize(sic.PoolSize),
+				WithPoolLogger(activerecord.IprotoLogger{}),
+			)
+		}
+	}
+
+	clusterInfo, err := activerecord.ConfigCacher().Get(
+		ctx,
+		configPath,
+		activerecord.MapGlobParam{
+			Timeout:  DefaultConnectionTimeout,
+			PoolSize: DefaultPoolSize,
+		},
+		optionCreator,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't get cluster %s info: %w", configPath, err)
+	}
+
+	return clusterInfo, nil
+}
+
 func ProcessResp(respBytes []byte, cntFlag CountFlags) ([]TupleData, error) {
 	tupleCnt, respData, errResp := UnpackResopnseStatus(respBytes)
 	if errResp != nil {
@@ -196,6 +219,23 @@ func PackSelect(ns, indexnum, offset, limit uint32, keys [][][]byte) []byte {
 	return w
 }
 
+// SelectRangeProc lua процедура выборки из дерева индекса записей, начиная с ключа
+const SelectRangeProc = "box.select_range"
+
+// PackSelectRange упаковывает вызов процедуры SelectRangeProc, которая возвращает не больше limit записей индекса
+// indexnum с ключом не меньше key в порядке индекса. Пустой ключ выбирает записи с начала индекса.
+// В отличие от PackSelect, позволяет обходить индекс страницами от последнего прочитанного ключа без смещения
+func PackSelectRange(ns, indexnum, limit uint32, key [][]byte) []byte {
+	args := make([]string, 0, 3+len(key))
+	args = append(args, strconv.FormatUint(uint64(ns), 10), strconv.FormatUint(uint64(indexnum), 10), strconv.FormatUint(uint64(limit), 10))
+
+	for _, field := range key {
+		args = append(args, string(field))
+	}
+
+	return PackLua(SelectRangeProc, args...)
+}
+
 func PackUpdate(ns uint32, primaryKey [][]byte, updateOps []Ops) []byte {
 	w := make([]byte, 0, SpaceLen+FlagsLen+PackedKeyLen(primaryKey)+PackedUpdateOpsLen(updateOps))
 
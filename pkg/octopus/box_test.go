@@ -63,6 +63,47 @@ func TestPackSelect(t *testing.T) {
 	}
 }
 
+func TestPackSelectRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		indexnum uint32
+		limit    uint32
+		key      [][]byte
+		wantArgs [][]byte
+	}{
+		{
+			name:     "from index start",
+			indexnum: 0,
+			limit:    1000,
+			key:      nil,
+			wantArgs: [][]byte{[]byte("2"), []byte("0"), []byte("1000")},
+		},
+		{
+			name:     "after key",
+			indexnum: 1,
+			limit:    10,
+			key:      [][]byte{[]byte("aaa"), {0x10, 0x00}},
+			wantArgs: [][]byte{[]byte("2"), []byte("1"), []byte("10"), []byte("aaa"), {0x10, 0x00}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, args, err := UnpackLua(PackSelectRange(2, tt.indexnum, tt.limit, tt.key))
+			if err != nil {
+				t.Fatalf("UnpackLua() error = %v", err)
+			}
+
+			if name != SelectRangeProc {
+				t.Errorf("PackSelectRange() proc = %s, want %s", name, SelectRangeProc)
+			}
+
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("PackSelectRange() args = %v, want %v", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
 func TestPackInsertReplace(t *testing.T) {
 	fieldValue := []byte{0x0A, 0x00, 0x00, 0x00}
 	namespace := []byte{0x02, 0x00, 0x00, 0x00}
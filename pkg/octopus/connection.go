@@ -2,27 +2,52 @@ package octopus
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 
+	"github.com/mailru/activerecord/pkg/activerecord"
 	"github.com/mailru/activerecord/pkg/iproto/iproto"
 )
 
 var (
-	ErrConnection = fmt.Errorf("error dial to box")
+	ErrConnection = activerecord.NewClassError("error dial to box", activerecord.ErrTransient)
+	ErrDuplicate  = activerecord.NewClassError("duplicate key", activerecord.ErrConflict)
 )
 
+// transientErrors ошибки пула iproto, после которых запрос можно повторить
+var transientErrors = []error{
+	iproto.ErrTimeout,
+	iproto.ErrDroppedConn,
+	iproto.ErrStopped,
+	iproto.ErrCutoff,
+	iproto.ErrNoChannel,
+	iproto.ErrThrottled,
+	iproto.ErrPoolFull,
+	iproto.ErrPolicied,
+}
+
 func GetConnection(ctx context.Context, octopusOpts *ConnectionOptions) (*Connection, error) {
 	pool, err := iproto.Dial(ctx, "tcp", octopusOpts.server, octopusOpts.poolCfg)
 	if err != nil {
 		return nil, fmt.Errorf("%w %s with connect timeout '%d': %s", ErrConnection, octopusOpts.server, octopusOpts.poolCfg.ConnectTimeout, err)
 	}
 
+	if octopusOpts.minPoolSize > 1 {
+		if err = pool.InitN(ctx, octopusOpts.minPoolSize); err != nil {
+			pool.Close()
+
+			return nil, fmt.Errorf("%w %s with min pool size '%d': %s", ErrConnection, octopusOpts.server, octopusOpts.minPoolSize, err)
+		}
+	}
+
 	return &Connection{pool: pool, opts: octopusOpts}, nil
 }
 
 type Connection struct {
-	pool *iproto.Pool
-	opts *ConnectionOptions
+	inflight int64 // Количество выполняющихся через соединение запросов, первое поле для выравнивания атомарных операций
+	pool     *iproto.Pool
+	opts     *ConnectionOptions
 }
 
 func (c *Connection) Call(ctx context.Context, rt RequetsTypeType, data []byte) ([]byte, error) {
@@ -30,7 +55,25 @@ func (c *Connection) Call(ctx context.Context, rt RequetsTypeType, data []byte)
 		return []byte{}, fmt.Errorf("attempt call from empty connection")
 	}
 
-	return c.pool.Call(ctx, uint32(rt), data)
+	atomic.AddInt64(&c.inflight, 1)
+	defer atomic.AddInt64(&c.inflight, -1)
+
+	resp, err := c.pool.Call(ctx, uint32(rt), data)
+	if err != nil {
+		// Ошибки соединения и таймауты помечаются как временные, запросы с такими ошибками можно повторить
+		for _, transient := range transientErrors {
+			if errors.Is(err, transient) {
+				return resp, &activerecord.TransientError{Err: err}
+			}
+		}
+	}
+
+	return resp, err
+}
+
+// InFlight количество запросов, выполняющихся через соединение. Используется при выборе наименее нагруженной реплики
+func (c *Connection) InFlight() int64 {
+	return atomic.LoadInt64(&c.inflight)
 }
 
 func (c *Connection) InstanceMode() any {
@@ -45,6 +88,15 @@ func (c *Connection) Close() {
 	c.pool.Close()
 }
 
+// Shutdown мягкое закрытие пула: новые запросы не принимаются, а уже отправленные дожидаются ответа
+func (c *Connection) Shutdown() {
+	if c == nil || c.pool == nil {
+		return
+	}
+
+	c.pool.Shutdown()
+}
+
 func (c *Connection) Done() <-chan struct{} {
 	return c.pool.Done()
 }
@@ -107,6 +107,16 @@ func TestGetConnection(t *testing.T) {
 			wantErr: false,
 			want:    []string{""},
 		},
+		{
+			name: "connection with min pool size",
+			args: args{
+				ctx:    context.Background(),
+				server: "127.0.0.1:11211",
+				opts:   []ConnectionOption{WithPoolSize(4), WithMinPoolSize(2)},
+			},
+			wantErr: false,
+			want:    []string{""},
+		},
 	}
 
 	oms, err := InitMockServer(WithHost("127.0.0.1", "11211"))
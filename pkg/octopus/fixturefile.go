@@ -0,0 +1,93 @@
+package octopus
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+var ErrUnknownFixtureField = fmt.Errorf("unknown field")
+
+// FixtureFieldError ошибка значения поля записи в файле фикстур
+type FixtureFieldError struct {
+	File  string
+	Line  int
+	Field string
+	Err   error
+}
+
+func (e *FixtureFieldError) Error() string {
+	return fmt.Sprintf("%s:%d: field `%s`: %s", e.File, e.Line, e.Field, e.Err)
+}
+
+func (e *FixtureFieldError) Unwrap() error {
+	return e.Err
+}
+
+// FixtureField поле записи из файла фикстур: имя поля в snake case и узел с его значением
+type FixtureField struct {
+	Name  string
+	Value *yaml.Node
+}
+
+// Decode декодирует значение поля в v. Ошибка несоответствия типа возвращается с именем поля и номером строки
+func (f FixtureField) Decode(v any) error {
+	if err := f.Value.Decode(v); err != nil {
+		return f.Error(err)
+	}
+
+	return nil
+}
+
+// Error возвращает ошибку err, дополненную именем поля и номером строки, в которой задано значение
+func (f FixtureField) Error(err error) error {
+	return &FixtureFieldError{Line: f.Value.Line, Field: f.Name, Err: err}
+}
+
+// DecodeFixtureFile читает файл path с массивом записей фикстур в формате YAML или JSON
+// и вызывает decode для каждой записи со списком её полей
+func DecodeFixtureFile(path string, decode func(fields []FixtureField) error) error {
+	source, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("can't read fixture file: %w", err)
+	}
+
+	var root yaml.Node
+	if err = yaml.Unmarshal(source, &root); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Пустой файл
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	list := root.Content[0]
+	if list.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s:%d: fixtures must be an array of records", path, list.Line)
+	}
+
+	for _, item := range list.Content {
+		if item.Kind != yaml.MappingNode {
+			return fmt.Errorf("%s:%d: fixture record must be a mapping", path, item.Line)
+		}
+
+		fields := make([]FixtureField, 0, len(item.Content)/2)
+		for i := 0; i+1 < len(item.Content); i += 2 {
+			fields = append(fields, FixtureField{Name: item.Content[i].Value, Value: item.Content[i+1]})
+		}
+
+		if err = decode(fields); err != nil {
+			var fieldErr *FixtureFieldError
+			if errors.As(err, &fieldErr) {
+				fieldErr.File = path
+			}
+
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package octopus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeFixtureFile(t *testing.T) {
+	type record struct {
+		ID   int
+		Name string
+	}
+
+	decodeRecords := func(path string) ([]record, error) {
+		ret := []record{}
+
+		err := DecodeFixtureFile(path, func(fields []FixtureField) error {
+			rec := record{}
+
+			for _, field := range fields {
+				switch field.Name {
+				case "id":
+					if err := field.Decode(&rec.ID); err != nil {
+						return err
+					}
+				case "name":
+					if err := field.Decode(&rec.Name); err != nil {
+						return err
+					}
+				default:
+					return field.Error(ErrUnknownFixtureField)
+				}
+			}
+
+			ret = append(ret, rec)
+
+			return nil
+		})
+
+		return ret, err
+	}
+
+	tests := []struct {
+		name      string
+		source    string
+		want      []record
+		wantErr   bool
+		wantField *FixtureFieldError
+	}{
+		{
+			name:   "yaml",
+			source: "- id: 1\n  name: foo\n- id: 2\n",
+			want:   []record{{ID: 1, Name: "foo"}, {ID: 2}},
+		},
+		{
+			name:   "json",
+			source: `[{"id": 3, "name": "bar"}]`,
+			want:   []record{{ID: 3, Name: "bar"}},
+		},
+		{
+			name:   "empty",
+			source: "",
+			want:   []record{},
+		},
+		{
+			name:      "type mismatch",
+			source:    "- id: 1\n- id: foo\n",
+			wantErr:   true,
+			wantField: &FixtureFieldError{Line: 2, Field: "id"},
+		},
+		{
+			name:      "unknown field",
+			source:    "- id: 1\n  title: foo\n",
+			wantErr:   true,
+			wantField: &FixtureFieldError{Line: 2, Field: "title", Err: ErrUnknownFixtureField},
+		},
+		{
+			name:    "not array",
+			source:  "id: 1\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "fixture.yaml")
+			if err := os.WriteFile(path, []byte(tt.source), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := decodeRecords(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeFixtureFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantField != nil {
+				var fieldErr *FixtureFieldError
+				if !errors.As(err, &fieldErr) {
+					t.Fatalf("DecodeFixtureFile() error = %v, want *FixtureFieldError", err)
+				}
+
+				if fieldErr.File != path || fieldErr.Line != tt.wantField.Line || fieldErr.Field != tt.wantField.Field {
+					t.Errorf("DecodeFixtureFile() error = %+v, want %+v", fieldErr, tt.wantField)
+				}
+
+				if tt.wantField.Err != nil && !errors.Is(err, tt.wantField.Err) {
+					t.Errorf("DecodeFixtureFile() error = %v, want %v", err, tt.wantField.Err)
+				}
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DecodeFixtureFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
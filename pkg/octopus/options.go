@@ -38,6 +38,7 @@ type ConnectionOptions struct {
 	server         string
 	Mode           ServerModeType
 	poolCfg        *iproto.PoolConfig
+	minPoolSize    int
 	connectionHash hash.Hash32
 	calculated     bool
 }
@@ -163,6 +164,15 @@ func WithPoolSize(size int) ConnectionOption {
 	})
 }
 
+// WithMinPoolSize - опция для изменения количества соединений, устанавливаемых при создании пулла
+func WithMinPoolSize(size int) ConnectionOption {
+	return optionConnectionFunc(func(octopusCfg *ConnectionOptions) error {
+		octopusCfg.minPoolSize = size
+
+		return octopusCfg.UpdateHash("m", size)
+	})
+}
+
 // WithPoolLogger - опция для логера конекшен пула
 func WithPoolLogger(logger iproto.Logger) ConnectionOption {
 	return optionConnectionFunc(func(octopusCfg *ConnectionOptions) error {
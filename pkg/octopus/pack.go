@@ -3,8 +3,11 @@ package octopus
 import (
 	"bytes"
 	"fmt"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/mailru/activerecord/pkg/iproto/iproto"
+	"github.com/shopspring/decimal"
 )
 
 func ByteLen(length uint32) uint32 {
@@ -333,6 +336,10 @@ func UnpackResopnseStatus(data []byte) (uint32, []byte, error) {
 		errStr = errStr[:len(errStr)-1]
 	}
 
+	if RetCode(retCode) == RcDuplicate || RetCode(retCode) == RcDuplicateKey {
+		return 0, nil, fmt.Errorf("%w: error request to octopus `%s`", ErrDuplicate, errStr)
+	}
+
 	return 0, nil, fmt.Errorf("error request to octopus `%s`", errStr)
 }
 
@@ -394,6 +401,107 @@ func UnpackString(r *bytes.Reader, res *string, mode iproto.PackMode) error {
 	return nil
 }
 
+// PackUUID упаковывает UUID в 16 байт бинарного представления
+func PackUUID(w []byte, field uuid.UUID, mode iproto.PackMode) []byte {
+	return append(w, field[:]...)
+}
+
+func UnpackUUID(r *bytes.Reader, res *uuid.UUID, mode iproto.PackMode) error {
+	bres := make([]byte, r.Len())
+
+	if _, err := r.Read(bres); err != nil && len(bres) != 0 {
+		return fmt.Errorf("error unpack uuid: %w", err)
+	}
+
+	val, err := uuid.FromBytes(bres)
+	if err != nil {
+		return fmt.Errorf("error unpack uuid: %w", err)
+	}
+
+	*res = val
+
+	return nil
+}
+
+// PackDecimal упаковывает число в строковую запись, которая сохраняет точность значения
+func PackDecimal(w []byte, field decimal.Decimal, mode iproto.PackMode) []byte {
+	return append(w, field.String()...)
+}
+
+func UnpackDecimal(r *bytes.Reader, res *decimal.Decimal, mode iproto.PackMode) error {
+	var str string
+
+	if err := UnpackString(r, &str, mode); err != nil {
+		return fmt.Errorf("error unpack decimal: %w", err)
+	}
+
+	val, err := decimal.NewFromString(str)
+	if err != nil {
+		return fmt.Errorf("error unpack decimal: %w", err)
+	}
+
+	*res = val
+
+	return nil
+}
+
+// PackTimeUnix упаковывает время в количество секунд unix time. Нулевое значение time.Time
+// упаковывается как есть (отрицательным числом секунд) и не совпадает с началом эпохи
+func PackTimeUnix(w []byte, field time.Time, mode iproto.PackMode) []byte {
+	return iproto.PackUint64(w, uint64(field.Unix()), mode)
+}
+
+func UnpackTimeUnix(r *bytes.Reader, res *time.Time, mode iproto.PackMode) error {
+	var sec uint64
+
+	if err := iproto.UnpackUint64(r, &sec, mode); err != nil {
+		return fmt.Errorf("error unpack time: %w", err)
+	}
+
+	*res = time.Unix(int64(sec), 0).UTC()
+
+	return nil
+}
+
+// PackTimeUnixMs упаковывает время в количество миллисекунд unix time
+func PackTimeUnixMs(w []byte, field time.Time, mode iproto.PackMode) []byte {
+	return iproto.PackUint64(w, uint64(field.UnixMilli()), mode)
+}
+
+func UnpackTimeUnixMs(r *bytes.Reader, res *time.Time, mode iproto.PackMode) error {
+	var msec uint64
+
+	if err := iproto.UnpackUint64(r, &msec, mode); err != nil {
+		return fmt.Errorf("error unpack time: %w", err)
+	}
+
+	*res = time.UnixMilli(int64(msec)).UTC()
+
+	return nil
+}
+
+// PackTimeRFC3339 упаковывает время в строку в формате RFC 3339 с сохранением долей секунды и смещения зоны
+func PackTimeRFC3339(w []byte, field time.Time, mode iproto.PackMode) []byte {
+	return append(w, field.Format(time.RFC3339Nano)...)
+}
+
+func UnpackTimeRFC3339(r *bytes.Reader, res *time.Time, mode iproto.PackMode) error {
+	var str string
+
+	if err := UnpackString(r, &str, mode); err != nil {
+		return fmt.Errorf("error unpack time: %w", err)
+	}
+
+	val, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return fmt.Errorf("error unpack time: %w", err)
+	}
+
+	*res = val.UTC()
+
+	return nil
+}
+
 func BoolToUint(v bool) uint8 {
 	if v {
 		return 1
@@ -0,0 +1,87 @@
+package octopus
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+	"github.com/mailru/activerecord/pkg/iproto/iproto"
+	"github.com/shopspring/decimal"
+)
+
+func TestPackDecimal(t *testing.T) {
+	sum := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+
+	var got decimal.Decimal
+
+	if err := UnpackDecimal(bytes.NewReader(PackDecimal([]byte{}, sum, iproto.ModeDefault)), &got, iproto.ModeDefault); err != nil {
+		t.Fatalf("UnpackDecimal() error = %v", err)
+	}
+
+	if !got.Equal(decimal.RequireFromString("0.3")) || got.String() != "0.3" {
+		t.Errorf("UnpackDecimal() = %s, want 0.3", got)
+	}
+
+	if err := UnpackDecimal(bytes.NewReader([]byte("0,3")), &got, iproto.ModeDefault); err == nil {
+		t.Errorf("UnpackDecimal() expect error for invalid value")
+	}
+}
+
+func TestPackTime(t *testing.T) {
+	moment := time.Date(2023, 5, 17, 10, 30, 15, 250*int(time.Millisecond), time.UTC)
+
+	tests := []struct {
+		name   string
+		pack   func([]byte, time.Time, iproto.PackMode) []byte
+		unpack func(*bytes.Reader, *time.Time, iproto.PackMode) error
+		value  time.Time
+		want   time.Time
+	}{
+		{name: "unix", pack: PackTimeUnix, unpack: UnpackTimeUnix, value: moment, want: moment.Truncate(time.Second)},
+		{name: "unix epoch", pack: PackTimeUnix, unpack: UnpackTimeUnix, value: time.Unix(0, 0), want: time.Unix(0, 0)},
+		{name: "unix zero", pack: PackTimeUnix, unpack: UnpackTimeUnix, value: time.Time{}, want: time.Time{}},
+		{name: "unix_ms", pack: PackTimeUnixMs, unpack: UnpackTimeUnixMs, value: moment, want: moment},
+		{name: "unix_ms zero", pack: PackTimeUnixMs, unpack: UnpackTimeUnixMs, value: time.Time{}, want: time.Time{}},
+		{name: "rfc3339", pack: PackTimeRFC3339, unpack: UnpackTimeRFC3339, value: moment.In(time.FixedZone("MSK", 3*3600)), want: moment},
+		{name: "rfc3339 zero", pack: PackTimeRFC3339, unpack: UnpackTimeRFC3339, value: time.Time{}, want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Time
+
+			if err := tt.unpack(bytes.NewReader(tt.pack([]byte{}, tt.value, iproto.ModeDefault)), &got, iproto.ModeDefault); err != nil {
+				t.Fatalf("unpack() error = %v", err)
+			}
+
+			if !got.Equal(tt.want) || got.IsZero() != tt.want.IsZero() {
+				t.Errorf("unpack() = %v, want %v", got, tt.want)
+			}
+
+			if got.Location() != time.UTC {
+				t.Errorf("unpack() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+
+	var got time.Time
+
+	if err := UnpackTimeRFC3339(bytes.NewReader([]byte("2023-05-17 10:30:15")), &got, iproto.ModeDefault); err == nil {
+		t.Errorf("UnpackTimeRFC3339() expect error for invalid value")
+	}
+}
+
+func TestUnpackResopnseStatusDuplicate(t *testing.T) {
+	data := iproto.PackUint32([]byte{}, uint32(RcDuplicateKey), iproto.ModeDefault)
+	data = append(data, []byte("Duplicate key exists\x00")...)
+
+	_, _, err := UnpackResopnseStatus(data)
+	if !errors.Is(err, ErrDuplicate) || !errors.Is(err, activerecord.ErrConflict) {
+		t.Errorf("UnpackResopnseStatus() error = %v, want %v and %v", err, ErrDuplicate, activerecord.ErrConflict)
+	}
+
+	if errors.Is(err, activerecord.ErrTransient) {
+		t.Errorf("UnpackResopnseStatus() error = %v is transient", err)
+	}
+}
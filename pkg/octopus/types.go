@@ -162,6 +162,17 @@ const (
 	Float64     Format = "float64"
 	StringArray Format = "[]string"
 	ByteArray   Format = "[]byte"
+	UUID        Format = "uuid.UUID"
+	Decimal     Format = "decimal.Decimal"
+	Time        Format = "time.Time"
+)
+
+// Форматы упаковки полей time.Time. Поле модели имеет формат Time, а упаковщик
+// выбирается по формату хранения, указанному в декларации поля
+const (
+	TimeUnix    Format = "time.Time/unix"
+	TimeUnixMs  Format = "time.Time/unix_ms"
+	TimeRFC3339 Format = "time.Time/rfc3339"
 )
 
 var UnsignedFormat = []Format{Uint8, Uint16, Uint32, Uint64, Uint}
@@ -172,7 +183,7 @@ var AllFormat = append(append(append(
 	NumericFormat,
 	FloatFormat...),
 	DataFormat...),
-	Bool,
+	Bool, UUID, Decimal, Time,
 )
 var AllProcFormat = append(append(append(
 	NumericFormat,
@@ -0,0 +1,103 @@
+package serializer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+	"github.com/mailru/activerecord/pkg/serializer/errs"
+)
+
+// EncryptUnmarshal расшифровывает значение, сохранённое EncryptMarshal, ключом с идентификатором из значения.
+// Если данные повреждены или подменены, возвращается *errs.DecryptError
+func EncryptUnmarshal(data string, v *string) error {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return &errs.DecryptError{Err: err}
+	}
+
+	if len(raw) == 0 || len(raw) < 1+int(raw[0]) {
+		return &errs.DecryptError{Err: fmt.Errorf("invalid data length %d", len(raw))}
+	}
+
+	keyID := string(raw[1 : 1+raw[0]])
+	raw = raw[1+raw[0]:]
+
+	provider := activerecord.KeyProvider()
+	if provider == nil {
+		return &errs.DecryptError{KeyID: keyID, Err: errs.ErrNoKeyProvider}
+	}
+
+	key, err := provider.Key(keyID)
+	if err != nil {
+		return &errs.DecryptError{KeyID: keyID, Err: err}
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return &errs.DecryptError{KeyID: keyID, Err: err}
+	}
+
+	if len(raw) < aead.NonceSize() {
+		return &errs.DecryptError{KeyID: keyID, Err: fmt.Errorf("invalid data length %d", len(raw))}
+	}
+
+	plain, err := aead.Open(nil, raw[:aead.NonceSize()], raw[aead.NonceSize():], []byte(keyID))
+	if err != nil {
+		return &errs.DecryptError{KeyID: keyID, Err: err}
+	}
+
+	*v = string(plain)
+
+	return nil
+}
+
+// EncryptMarshal шифрует значение AES-GCM текущим ключом из activerecord.KeyProvider.
+// Результат в base64 содержит идентификатор ключа, случайный nonce и зашифрованное значение с тегом подлинности
+func EncryptMarshal(v string) (string, error) {
+	provider := activerecord.KeyProvider()
+	if provider == nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrEncrypt, errs.ErrNoKeyProvider)
+	}
+
+	keyID, key, err := provider.CurrentKey()
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrEncrypt, err)
+	}
+
+	if len(keyID) > 255 {
+		return "", fmt.Errorf("%w: key id length %d exceeds 255", errs.ErrEncrypt, len(keyID))
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrEncrypt, err)
+	}
+
+	raw := make([]byte, 0, 1+len(keyID)+aead.NonceSize()+len(v)+aead.Overhead())
+	raw = append(raw, byte(len(keyID)))
+	raw = append(raw, keyID...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrEncrypt, err)
+	}
+
+	raw = append(raw, nonce...)
+	raw = aead.Seal(raw, nonce, []byte(v), []byte(keyID))
+
+	return base64.StdEncoding.EncodeToString(raw), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
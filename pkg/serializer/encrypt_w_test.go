@@ -0,0 +1,105 @@
+package serializer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+	"github.com/mailru/activerecord/pkg/serializer/errs"
+)
+
+type stubKeyProvider struct {
+	current string
+	keys    map[string][]byte
+}
+
+func (p *stubKeyProvider) CurrentKey() (string, []byte, error) {
+	key, err := p.Key(p.current)
+	return p.current, key, err
+}
+
+func (p *stubKeyProvider) Key(id string) ([]byte, error) {
+	key, ok := p.keys[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown key `%s`", id)
+	}
+
+	return key, nil
+}
+
+func TestEncrypt(t *testing.T) {
+	provider := &stubKeyProvider{current: "k1", keys: map[string][]byte{
+		"k1": []byte("0123456789abcdef0123456789abcdef"),
+		"k2": []byte("fedcba9876543210"),
+	}}
+
+	activerecord.ReinitActiveRecord(activerecord.WithKeyProvider(provider))
+
+	old, err := EncryptMarshal("user@example.com")
+	if err != nil {
+		t.Fatalf("EncryptMarshal() error = %v", err)
+	}
+
+	provider.current = "k2"
+
+	tests := []struct {
+		name    string
+		data    func() string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "round trip",
+			data: func() string {
+				data, err := EncryptMarshal(`{"phone":"+7000"}`)
+				if err != nil {
+					t.Fatalf("EncryptMarshal() error = %v", err)
+				}
+
+				return data
+			},
+			want: `{"phone":"+7000"}`,
+		},
+		{
+			name: "previous key",
+			data: func() string { return old },
+			want: "user@example.com",
+		},
+		{
+			name: "tampered",
+			data: func() string {
+				raw, _ := base64.StdEncoding.DecodeString(old)
+				raw[len(raw)-1] ^= 1
+
+				return base64.StdEncoding.EncodeToString(raw)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "not encrypted",
+			data:    func() string { return "plain" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			err := EncryptUnmarshal(tt.data(), &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EncryptUnmarshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var decryptErr *errs.DecryptError
+			if tt.wantErr && (!errors.Is(err, errs.ErrDecrypt) || !errors.As(err, &decryptErr)) {
+				t.Errorf("EncryptUnmarshal() error = %v, want DecryptError", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("EncryptUnmarshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,12 +1,39 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	ErrMarshalJSON            = errors.New("err marshal json")
 	ErrUnmarshalJSON          = errors.New("err unmarshal json")
+	ErrMarshalMsgpack         = errors.New("err marshal msgpack")
+	ErrUnmarshalMsgpack       = errors.New("err unmarshal msgpack")
 	ErrMapstructureNewDecoder = errors.New("err mapstructure new decoder")
 	ErrMapstructureDecode     = errors.New("err mapstructure decode")
 	ErrMapstructureEncode     = errors.New("err mapstructure encode")
 	ErrPrintfParse            = errors.New("err printf parse")
+	ErrEncrypt                = errors.New("err encrypt")
+	ErrDecrypt                = errors.New("err decrypt")
+	ErrNoKeyProvider          = errors.New("key provider is not set")
 )
+
+// DecryptError ошибка расшифровки значения: повреждённые или подменённые данные, не прошедшие
+// проверку подлинности, или отсутствующий ключ. errors.Is(err, ErrDecrypt) возвращает true
+type DecryptError struct {
+	KeyID string // Идентификатор ключа, которым было зашифровано значение
+	Err   error
+}
+
+func (e *DecryptError) Error() string {
+	return fmt.Sprintf("%s with key `%s`: %v", ErrDecrypt, e.KeyID, e.Err)
+}
+
+func (e *DecryptError) Unwrap() error {
+	return e.Err
+}
+
+func (e *DecryptError) Is(target error) bool {
+	return target == ErrDecrypt
+}
@@ -0,0 +1,27 @@
+package serializer
+
+import (
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/mailru/activerecord/pkg/serializer/errs"
+)
+
+func MsgpackUnmarshal(data string, v any) error {
+	err := msgpack.Unmarshal([]byte(data), v)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errs.ErrUnmarshalMsgpack, err)
+	}
+
+	return nil
+}
+
+func MsgpackMarshal(v any) (string, error) {
+	ret, err := msgpack.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", errs.ErrMarshalMsgpack, err)
+	}
+
+	return string(ret), nil
+}
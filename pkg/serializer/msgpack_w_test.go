@@ -0,0 +1,70 @@
+package serializer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/activerecord/pkg/serializer/errs"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+		exec func(string) (any, error)
+	}{
+		{
+			name: "simple map",
+			val:  map[string]string{"key": "value"},
+			exec: func(val string) (any, error) {
+				var got map[string]string
+				err := MsgpackUnmarshal(val, &got)
+				return got, err
+			},
+		},
+		{
+			name: "nested custom type",
+			val:  Services{Quota: 234321523, Flags: map[string]bool{"vip": true}, Gift: &Gift{GiftibleID: "year2020_333_1", GiftQuota: 2343432784}},
+			exec: func(val string) (any, error) {
+				var got Services
+				err := MsgpackUnmarshal(val, &got)
+				return got, err
+			},
+		},
+		{
+			name: "slice",
+			val:  []int64{1, -2, 1 << 40},
+			exec: func(val string) (any, error) {
+				var got []int64
+				err := MsgpackUnmarshal(val, &got)
+				return got, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			packed, err := MsgpackMarshal(tt.val)
+			if err != nil {
+				t.Fatalf("MsgpackMarshal() error = %v", err)
+			}
+
+			got, err := tt.exec(packed)
+			if err != nil {
+				t.Fatalf("MsgpackUnmarshal() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.val) {
+				t.Errorf("MsgpackUnmarshal() = %v, want %v", got, tt.val)
+			}
+		})
+	}
+}
+
+func TestMsgpackUnmarshalError(t *testing.T) {
+	var got Services
+
+	if err := MsgpackUnmarshal("\xc1", &got); !errors.Is(err, errs.ErrUnmarshalMsgpack) {
+		t.Errorf("MsgpackUnmarshal() error = %v, wantErr %v", err, errs.ErrUnmarshalMsgpack)
+	}
+}
@@ -0,0 +1,107 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+)
+
+// Box - возвращает коннектор для БД
+func Box(ctx context.Context, shard int, instType activerecord.ShardInstanceType, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (*Connection, error) {
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(clusterInfo) <= shard {
+		return nil, fmt.Errorf("invalid shard num %d, max = %d", shard, len(clusterInfo))
+	}
+
+	configBox, err := clusterInfo[shard].Instance(ctx, instType)
+	if err != nil {
+		return nil, err
+	}
+
+	connector := func(options interface{}) (activerecord.ConnectionInterface, error) {
+		tarantoolOpt, ok := options.(*ConnectionOptions)
+		if !ok {
+			return nil, fmt.Errorf("invalit type of options %T, want Options", options)
+		}
+
+		return GetConnection(ctx, tarantoolOpt)
+	}
+
+	conn, err := activerecord.ConnectionCacher().GetOrAdd(configBox, connector)
+	if err != nil && instType == activerecord.ReplicaOrMasterInstanceType && configBox.Config.Mode == activerecord.ModeReplica {
+		// Реплика недоступна, чтение выполняется на мастере
+		conn, err = activerecord.ConnectionCacher().GetOrAdd(clusterInfo[shard].NextMaster(), connector)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("error from connectionCacher: %w", err)
+	}
+
+	box, ok := conn.(*Connection)
+	if !ok {
+		return nil, fmt.Errorf("invalid connection type %T, want *tarantool.Connection", conn)
+	}
+
+	return box, nil
+}
+
+// ShardCount - возвращает количество шардов в кластере
+func ShardCount(ctx context.Context, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (int, error) {
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(clusterInfo), nil
+}
+
+// ShardByKey - возвращает номер шарда, которому принадлежит значение ключа шардирования, при распределении
+// ключей кольцом консистентного хеширования. Значение хешируется в представлении msgpack, поэтому для одного
+// поля должно передаваться значением одного и того же типа
+func ShardByKey(ctx context.Context, key any, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (int, error) {
+	clusterInfo, err := getCluster(ctx, configPath, optionCreator)
+	if err != nil {
+		return 0, err
+	}
+
+	keyPacked, err := msgpack.Marshal(key)
+	if err != nil {
+		return 0, fmt.Errorf("can't pack shard key: %w", err)
+	}
+
+	return activerecord.HashRingShard(configPath, clusterInfo, keyPacked)
+}
+
+func getCluster(ctx context.Context, configPath string, optionCreator func(activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error)) (activerecord.Cluster, error) {
+	if optionCreator == nil {
+		optionCreator = func(sic activerecord.ShardInstanceConfig) (activerecord.OptionInterface, error) {
+			return NewOptions(
+				sic.Addr,
+				ServerModeType(sic.Mode),
+				WithTimeout(sic.Timeout),
+			)
+		}
+	}
+
+	clusterInfo, err := activerecord.ConfigCacher().Get(
+		ctx,
+		configPath,
+		activerecord.MapGlobParam{
+			Timeout:  DefaultConnectionTimeout,
+			PoolSize: DefaultPoolSize,
+		},
+		optionCreator,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("can't get cluster %s info: %w", configPath, err)
+	}
+
+	return clusterInfo, nil
+}
@@ -0,0 +1,90 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+// callPollInterval интервал, с которым курсор проверяет отмену контекста, пока ждёт очередной ответ сервера
+const callPollInterval = 100 * time.Millisecond
+
+// CallCursor потоковое чтение ответа хранимой функции. Значения, которые функция отправляет через
+// box.session.push, читаются по мере поступления от сервера, а после них - значения итогового результата
+// функции. Каждое значение ответа должно быть туплом. Ответы сервера распаковываются только при чтении.
+//
+// Драйвер go-tarantool хранит все полученные ответы запроса (и push-сообщения, и итоговый результат) вместе
+// с уже распакованными значениями до завершения запроса, отдельного потокового API у него нет. Поэтому
+// курсор не ограничивает память, занятую ответом: он позволяет начать обработку до получения всего ответа
+// и не создавать объекты для всех строк сразу
+type CallCursor struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	it     gotarantool.ResponseIterator
+	tuples [][]any
+	done   bool
+}
+
+// CallCursor вызов хранимой функции с потоковым чтением ответа. Запрос выполняется, пока ответ
+// не прочитан полностью или не вызван метод Close курсора
+func (c *Connection) CallCursor(ctx context.Context, function string, args []any) (*CallCursor, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt call from empty connection")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	fut := c.conn.Do(gotarantool.NewCall17Request(function).Args(args).Context(ctx))
+
+	return &CallCursor{ctx: ctx, cancel: cancel, it: fut.GetIterator().WithTimeout(callPollInterval)}, nil
+}
+
+// Next возвращает следующий тупл ответа. Если ответ прочитан полностью, возвращается false и запрос завершается
+func (cc *CallCursor) Next() ([]any, bool, error) {
+	for len(cc.tuples) == 0 {
+		if cc.done {
+			cc.Close()
+			return nil, false, nil
+		}
+
+		if !cc.it.Next() {
+			if err := cc.it.Err(); err != nil {
+				cc.Close()
+				return nil, false, convertError(err)
+			}
+
+			if err := cc.ctx.Err(); err != nil {
+				cc.Close()
+				return nil, false, err
+			}
+
+			// Ответ ещё не получен, ожидание продолжается до отмены контекста
+			continue
+		}
+
+		resp := cc.it.Value()
+		cc.done = resp.Code != gotarantool.PushCode
+
+		tuples, err := ProcessResp(resp.Data)
+		if err != nil {
+			cc.Close()
+			return nil, false, err
+		}
+
+		cc.tuples = tuples
+	}
+
+	tuple := cc.tuples[0]
+	cc.tuples = cc.tuples[1:]
+
+	return tuple, true, nil
+}
+
+// Close прерывает чтение ответа: запрос отменяется, и курсор перестаёт ссылаться на полученные ответы.
+// Повторный вызов безопасен
+func (cc *CallCursor) Close() {
+	cc.cancel()
+	cc.done = true
+	cc.tuples = nil
+}
@@ -0,0 +1,102 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+// fakeIterator последовательность ответов сервера для проверки курсора без подключения
+type fakeIterator struct {
+	resps []*gotarantool.Response
+	cur   *gotarantool.Response
+	err   error
+}
+
+func (it *fakeIterator) Next() bool {
+	if len(it.resps) == 0 {
+		it.cur = nil
+		return false
+	}
+
+	it.cur, it.resps = it.resps[0], it.resps[1:]
+
+	return true
+}
+
+func (it *fakeIterator) Value() *gotarantool.Response { return it.cur }
+
+func (it *fakeIterator) Err() error { return it.err }
+
+func TestCallCursor(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cc := &CallCursor{ctx: ctx, cancel: cancel, it: &fakeIterator{resps: []*gotarantool.Response{
+		{Code: gotarantool.PushCode, Data: []any{[]any{"a", 1}}},
+		{Code: gotarantool.PushCode, Data: []any{[]any{"b", 2}}},
+		{Code: gotarantool.OkCode, Data: []any{[]any{"c", 3}, []any{"d", 4}}},
+	}}}
+
+	got := []any{}
+
+	for {
+		tuple, ok, err := cc.Next()
+		if err != nil {
+			t.Fatalf("Next() error = %v", err)
+		}
+
+		if !ok {
+			break
+		}
+
+		got = append(got, tuple[0])
+	}
+
+	if len(got) != 4 || got[0] != "a" || got[3] != "d" {
+		t.Errorf("Next() tuples = %v, want a, b, c, d", got)
+	}
+
+	if ctx.Err() == nil {
+		t.Errorf("request context not canceled after full read")
+	}
+}
+
+func TestCallCursorClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cc := &CallCursor{ctx: ctx, cancel: cancel, it: &fakeIterator{resps: []*gotarantool.Response{
+		{Code: gotarantool.PushCode, Data: []any{[]any{"a"}, []any{"b"}}},
+	}}}
+
+	if _, ok, err := cc.Next(); !ok || err != nil {
+		t.Fatalf("Next() = %v, %v, want tuple", ok, err)
+	}
+
+	cc.Close()
+	cc.Close()
+
+	if ctx.Err() == nil {
+		t.Errorf("request context not canceled by Close")
+	}
+
+	if _, ok, err := cc.Next(); ok || err != nil {
+		t.Errorf("Next() after Close = %v, %v, want end of response", ok, err)
+	}
+}
+
+func TestCallCursorInvalidResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cc := &CallCursor{ctx: ctx, cancel: cancel, it: &fakeIterator{resps: []*gotarantool.Response{
+		{Code: gotarantool.PushCode, Data: []any{"not a tuple"}},
+	}}}
+
+	if _, _, err := cc.Next(); err == nil {
+		t.Errorf("Next() error = nil, want invalid tuple error")
+	}
+
+	cc = &CallCursor{ctx: ctx, cancel: cancel, it: &fakeIterator{err: errors.New("box error")}}
+
+	if _, _, err := cc.Next(); err == nil {
+		t.Errorf("Next() error = nil, want iterator error")
+	}
+}
@@ -0,0 +1,322 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+var (
+	ErrConnection = activerecord.NewClassError("error dial to box", activerecord.ErrTransient)
+	ErrDuplicate  = activerecord.NewClassError("duplicate key", activerecord.ErrConflict)
+)
+
+// Размер буфера канала событий подключения
+const notifyBufferSize = 16
+
+func GetConnection(ctx context.Context, tarantoolOpts *ConnectionOptions) (*Connection, error) {
+	notify := make(chan gotarantool.ConnEvent, notifyBufferSize)
+
+	cfg := tarantoolOpts.cfg
+	cfg.Notify = notify
+
+	conn, err := gotarantool.Connect(tarantoolOpts.server, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s with timeout '%d': %s", ErrConnection, tarantoolOpts.server, tarantoolOpts.cfg.Timeout, err)
+	}
+
+	done := make(chan struct{})
+	prepared := newPreparedCache()
+
+	go watchConnection(notify, done, prepared)
+
+	return &Connection{conn: conn, opts: tarantoolOpts, done: done, prepared: prepared}, nil
+}
+
+// watchConnection обрабатывает события подключения: при переподключении сбрасывает кеш подготовленных
+// запросов, при закрытии соединения закрывает done
+func watchConnection(notify <-chan gotarantool.ConnEvent, done chan struct{}, prepared *preparedCache) {
+	for event := range notify {
+		switch event.Kind {
+		case gotarantool.Connected, gotarantool.Disconnected:
+			prepared.reset()
+		case gotarantool.Closed:
+			close(done)
+			return
+		}
+	}
+}
+
+type Connection struct {
+	inflight int64 // Количество выполняющихся через соединение запросов, первое поле для выравнивания атомарных операций
+	conn     *gotarantool.Connection
+	opts     *ConnectionOptions
+	done     chan struct{}
+	prepared *preparedCache // Подготовленные SQL-запросы текущей сессии
+}
+
+// Select выборка из спейса space по индексу indexnum
+func (c *Connection) Select(ctx context.Context, space string, indexnum, offset, limit, iterator uint32, key []any) ([][]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt select from empty connection")
+	}
+
+	req := gotarantool.NewSelectRequest(space).
+		Index(indexnum).
+		Offset(offset).
+		Limit(limit).
+		Iterator(iterator).
+		Key(key).
+		Context(ctx)
+
+	return c.request(req)
+}
+
+// SelectAfter выборка из спейса space по индексу indexnum не более limit туплов, следующих в порядке итератора
+// за туплом after. Если after пустой, выборка выполняется с начала. В отличие от выборки со смещением
+// стоимость запроса не зависит от количества пропущенных туплов. Требуется tarantool 2.11 и выше
+func (c *Connection) SelectAfter(ctx context.Context, space string, indexnum, limit, iterator uint32, key []any, after []any) ([][]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt select from empty connection")
+	}
+
+	req := gotarantool.NewSelectRequest(space).
+		Index(indexnum).
+		Limit(limit).
+		Iterator(iterator).
+		Key(key).
+		Context(ctx)
+
+	if len(after) > 0 {
+		req = req.After(after)
+	}
+
+	return c.request(req)
+}
+
+// Insert вставка тупла в спейс space. Если запись с таким ключом уже есть, то возвращается ErrDuplicate
+func (c *Connection) Insert(ctx context.Context, space string, tuple []any) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("attempt insert from empty connection")
+	}
+
+	_, err := c.request(gotarantool.NewInsertRequest(space).Tuple(tuple).Context(ctx))
+
+	return err
+}
+
+// Replace вставка или замена тупла в спейсе space
+func (c *Connection) Replace(ctx context.Context, space string, tuple []any) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("attempt replace from empty connection")
+	}
+
+	_, err := c.request(gotarantool.NewReplaceRequest(space).Tuple(tuple).Context(ctx))
+
+	return err
+}
+
+// Update обновление полей записи с ключом key в спейсе space
+func (c *Connection) Update(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("attempt update from empty connection")
+	}
+
+	updateOps, err := updateOperations(ops)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.request(gotarantool.NewUpdateRequest(space).Index(indexnum).Key(key).Operations(updateOps).Context(ctx))
+
+	return err
+}
+
+// UpdateReturning обновление полей записи с ключом key в спейсе space. Возвращает тупл записи после
+// обновления, полученный в ответе на запрос, или nil, если записи с таким ключом нет
+func (c *Connection) UpdateReturning(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) ([]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt update from empty connection")
+	}
+
+	updateOps, err := updateOperations(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tuples, err := c.request(gotarantool.NewUpdateRequest(space).Index(indexnum).Key(key).Operations(updateOps).Context(ctx))
+	if err != nil || len(tuples) == 0 {
+		return nil, err
+	}
+
+	return tuples[0], nil
+}
+
+// Delete удаление записи с ключом key из спейса space. Возвращает количество удалённых записей
+func (c *Connection) Delete(ctx context.Context, space string, indexnum uint32, key []any) (int, error) {
+	if c == nil || c.conn == nil {
+		return 0, fmt.Errorf("attempt delete from empty connection")
+	}
+
+	tuples, err := c.request(gotarantool.NewDeleteRequest(space).Index(indexnum).Key(key).Context(ctx))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tuples), nil
+}
+
+// Ping проверяет доступность инстанса, отправляя ему пустой ping-запрос
+func (c *Connection) Ping(ctx context.Context) error {
+	if c == nil || c.conn == nil {
+		return fmt.Errorf("attempt ping from empty connection")
+	}
+
+	if _, err := c.conn.Do(gotarantool.NewPingRequest().Context(ctx)).Get(); err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+// Call вызов хранимой функции. Возвращает список значений, которые вернула функция
+func (c *Connection) Call(ctx context.Context, function string, args []any) ([]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt call from empty connection")
+	}
+
+	atomic.AddInt64(&c.inflight, 1)
+	defer atomic.AddInt64(&c.inflight, -1)
+
+	resp, err := c.conn.Do(gotarantool.NewCall17Request(function).Args(args).Context(ctx)).Get()
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp.Data, nil
+}
+
+// Eval выполнение выражения на Lua с аргументами args. Возвращает список значений, которые вернуло выражение.
+// Требует права на выполнение произвольного кода, используется для служебных операций, например создания схемы
+func (c *Connection) Eval(ctx context.Context, expr string, args []any) ([]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt eval from empty connection")
+	}
+
+	atomic.AddInt64(&c.inflight, 1)
+	defer atomic.AddInt64(&c.inflight, -1)
+
+	resp, err := c.conn.Do(gotarantool.NewEvalRequest(expr).Args(args).Context(ctx)).Get()
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp.Data, nil
+}
+
+// InFlight количество запросов, выполняющихся через соединение. Используется при выборе наименее нагруженной реплики
+func (c *Connection) InFlight() int64 {
+	return atomic.LoadInt64(&c.inflight)
+}
+
+func (c *Connection) InstanceMode() any {
+	return c.opts.InstanceMode()
+}
+
+func (c *Connection) Close() {
+	if c == nil || c.conn == nil {
+		return
+	}
+
+	c.conn.Close()
+}
+
+// Shutdown мягкое закрытие соединения: новые запросы не принимаются, а уже отправленные дожидаются ответа
+func (c *Connection) Shutdown() {
+	if c == nil || c.conn == nil {
+		return
+	}
+
+	_ = c.conn.CloseGraceful()
+}
+
+func (c *Connection) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *Connection) Info() string {
+	return fmt.Sprintf("Server: %s, timeout; %d", c.opts.server, c.opts.cfg.Timeout)
+}
+
+// updateOperations приводит операции обновления к операциям go-tarantool
+func updateOperations(ops []Ops) (*gotarantool.Operations, error) {
+	updateOps := gotarantool.NewOperations()
+
+	for _, op := range ops {
+		switch op.Op {
+		case OpSet:
+			updateOps.Assign(op.Field, op.Value)
+		case OpAdd:
+			updateOps.Add(op.Field, op.Value)
+		case OpSub:
+			updateOps.Subtract(op.Field, op.Value)
+		default:
+			return nil, fmt.Errorf("unknown update operation '%s'", op.Op)
+		}
+	}
+
+	return updateOps, nil
+}
+
+// request выполняет запрос и возвращает туплы ответа. Пока ответ не получен, запрос учитывается
+// в количестве выполняющихся через соединение
+func (c *Connection) request(req gotarantool.Request) ([][]any, error) {
+	atomic.AddInt64(&c.inflight, 1)
+	defer atomic.AddInt64(&c.inflight, -1)
+
+	return doTuples(c.conn.Do(req))
+}
+
+func doTuples(fut *gotarantool.Future) ([][]any, error) {
+	resp, err := fut.Get()
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return ProcessResp(resp.Data)
+}
+
+// ProcessResp приводит данные ответа к списку туплов
+func ProcessResp(data []any) ([][]any, error) {
+	tuples := make([][]any, 0, len(data))
+
+	for num, rawTuple := range data {
+		tuple, ok := rawTuple.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid tuple %d type %T in response", num, rawTuple)
+		}
+
+		tuples = append(tuples, tuple)
+	}
+
+	return tuples, nil
+}
+
+func convertError(err error) error {
+	var boxErr gotarantool.Error
+	if errors.As(err, &boxErr) && boxErr.Code == gotarantool.ErrTupleFound {
+		return fmt.Errorf("%w: %s", ErrDuplicate, boxErr.Msg)
+	}
+
+	// Ошибки соединения и таймауты помечаются как временные, запросы с такими ошибками можно повторить
+	var clientErr gotarantool.ClientError
+	if errors.As(err, &clientErr) && (clientErr.Temporary() || clientErr.Code == gotarantool.ErrConnectionClosed) {
+		err = &activerecord.TransientError{Err: err}
+	}
+
+	return fmt.Errorf("error response from box: `%w`", err)
+}
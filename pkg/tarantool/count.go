@@ -0,0 +1,72 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+)
+
+// countExpr подсчёт туплов в индексе по ключу средствами самого индекса, без передачи туплов клиенту
+const countExpr = `local space, index, iterator, key = ...
+return box.space[space].index[index]:count(key, {iterator = iterator})`
+
+// Count возвращает количество туплов спейса space с ключом key в индексе indexnum. Подсчёт выполняется
+// на стороне tarantool методом index:count через eval и требует права на выполнение выражений у пользователя подключения
+func (c *Connection) Count(ctx context.Context, space string, indexnum, iterator uint32, key []any) (uint32, error) {
+	if c == nil || c.conn == nil {
+		return 0, fmt.Errorf("attempt count from empty connection")
+	}
+
+	if key == nil {
+		key = []any{}
+	}
+
+	data, err := c.Eval(ctx, countExpr, []any{space, indexnum, iterator, key})
+	if err != nil {
+		return 0, err
+	}
+
+	return countResult(data)
+}
+
+// countResult разбирает ответ подсчёта. Msgpack упаковывает число минимальным типом, поэтому
+// принимаются все целые типы
+func countResult(data []any) (uint32, error) {
+	if len(data) != 1 {
+		return 0, fmt.Errorf("invalid count response length %d", len(data))
+	}
+
+	var cnt int64
+
+	switch v := data[0].(type) {
+	case int8:
+		cnt = int64(v)
+	case int16:
+		cnt = int64(v)
+	case int32:
+		cnt = int64(v)
+	case int64:
+		cnt = v
+	case int:
+		cnt = int64(v)
+	case uint8:
+		cnt = int64(v)
+	case uint16:
+		cnt = int64(v)
+	case uint32:
+		cnt = int64(v)
+	case uint64:
+		if v > uint64(^uint32(0)) {
+			return 0, fmt.Errorf("count %d overflows uint32", v)
+		}
+
+		cnt = int64(v)
+	default:
+		return 0, fmt.Errorf("invalid count response type %T", data[0])
+	}
+
+	if cnt < 0 || cnt > int64(^uint32(0)) {
+		return 0, fmt.Errorf("invalid count %d", cnt)
+	}
+
+	return uint32(cnt), nil
+}
@@ -0,0 +1,35 @@
+package tarantool
+
+import "testing"
+
+func Test_countResult(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []any
+		want    uint32
+		wantErr bool
+	}{
+		{name: "fixint", data: []any{int8(5)}, want: 5},
+		{name: "uint8", data: []any{uint8(200)}, want: 200},
+		{name: "uint16", data: []any{uint16(1000)}, want: 1000},
+		{name: "uint32", data: []any{uint32(100000)}, want: 100000},
+		{name: "uint64", data: []any{uint64(100000)}, want: 100000},
+		{name: "int64", data: []any{int64(7)}, want: 7},
+		{name: "empty response", data: []any{}, wantErr: true},
+		{name: "invalid type", data: []any{"5"}, wantErr: true},
+		{name: "negative", data: []any{int64(-1)}, wantErr: true},
+		{name: "overflow", data: []any{uint64(1) << 33}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := countResult(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("countResult() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("countResult() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
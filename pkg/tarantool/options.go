@@ -0,0 +1,151 @@
+package tarantool
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"time"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+// Константы определяющие дефолтное поведение конектора к tarantool-у
+const (
+	DefaultTimeout           = 20 * time.Millisecond
+	DefaultConnectionTimeout = 20 * time.Millisecond
+	DefaultRedialInterval    = 50 * time.Millisecond
+	DefaultPoolSize          = 1
+)
+
+// Используется для подсчета connectionID
+var crc32table = crc32.MakeTable(0x4C11DB7)
+
+// ServerModeType - тип используемый для описания режима работы инстанса. см. ниже
+type ServerModeType uint8
+
+// Режим работы конкретного инстанса. Мастер или реплика.
+// При селекте из реплики быдет выставляться флаг readonly.
+const (
+	ModeMaster ServerModeType = iota
+	ModeReplica
+)
+
+// ConnectionOptions - опции используемые для подключения
+type ConnectionOptions struct {
+	server         string
+	Mode           ServerModeType
+	cfg            gotarantool.Opts
+	connectionHash hash.Hash32
+	calculated     bool
+}
+
+// NewOptions - cоздание структуры с опциями и дефолтными значениями. Для мидификации значений по умолчанию,
+// надо передавать опции в конструктор
+func NewOptions(server string, mode ServerModeType, opts ...ConnectionOption) (*ConnectionOptions, error) {
+	if server == "" {
+		return nil, fmt.Errorf("invalid param: server is empty")
+	}
+
+	tarantoolOpts := &ConnectionOptions{
+		server: server,
+		Mode:   mode,
+		cfg: gotarantool.Opts{
+			Timeout:   DefaultTimeout,
+			Reconnect: DefaultRedialInterval,
+		},
+		connectionHash: crc32.New(crc32table),
+	}
+
+	for _, opt := range opts {
+		if err := opt.apply(tarantoolOpts); err != nil {
+			return nil, fmt.Errorf("error apply options: %w", err)
+		}
+	}
+
+	err := tarantoolOpts.UpdateHash("S", server)
+	if err != nil {
+		return nil, fmt.Errorf("can't get pool: %w", err)
+	}
+
+	return tarantoolOpts, nil
+}
+
+// UpdateHash - функция расчета ConnectionID, необходима для шаринга конектов между моделями.
+func (o *ConnectionOptions) UpdateHash(data ...interface{}) error {
+	if o.calculated {
+		return fmt.Errorf("can't update hash after calculate")
+	}
+
+	for _, data := range data {
+		var err error
+
+		switch v := data.(type) {
+		case string:
+			err = binary.Write(o.connectionHash, binary.LittleEndian, []byte(v))
+		case int:
+			err = binary.Write(o.connectionHash, binary.LittleEndian, int64(v))
+		default:
+			err = binary.Write(o.connectionHash, binary.LittleEndian, v)
+		}
+
+		if err != nil {
+			return fmt.Errorf("can't calculate connectionID: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetConnectionID - получение ConnecitionID. После первого получения, больше нельзя его модифицировать. Можно только новый Options создать
+func (o *ConnectionOptions) GetConnectionID() string {
+	o.calculated = true
+	hashInBytes := o.connectionHash.Sum(nil)[:]
+
+	return hex.EncodeToString(hashInBytes)
+}
+
+// InstanceMode - метод для получения режима аботы инстанса RO или RW
+func (o *ConnectionOptions) InstanceMode() any {
+	return o.Mode
+}
+
+// ConnectionOption - интерфейс которому должны соответствовать опции передаваемые в конструктор
+type ConnectionOption interface {
+	apply(*ConnectionOptions) error
+}
+
+type optionConnectionFunc func(*ConnectionOptions) error
+
+func (o optionConnectionFunc) apply(c *ConnectionOptions) error {
+	return o(c)
+}
+
+// WithTimeout - опция для изменений таймаута запроса
+func WithTimeout(request time.Duration) ConnectionOption {
+	return optionConnectionFunc(func(tarantoolCfg *ConnectionOptions) error {
+		tarantoolCfg.cfg.Timeout = request
+
+		return tarantoolCfg.UpdateHash("T", request)
+	})
+}
+
+// WithRedialInterval - опция для изменения интервала переподключения
+func WithRedialInterval(redial time.Duration) ConnectionOption {
+	return optionConnectionFunc(func(tarantoolCfg *ConnectionOptions) error {
+		tarantoolCfg.cfg.Reconnect = redial
+
+		return tarantoolCfg.UpdateHash("I", redial)
+	})
+}
+
+// WithCredential - опция для авторизации пользователя
+func WithCredential(user, pass string) ConnectionOption {
+	return optionConnectionFunc(func(tarantoolCfg *ConnectionOptions) error {
+		tarantoolCfg.cfg.User = user
+		tarantoolCfg.cfg.Pass = pass
+
+		return tarantoolCfg.UpdateHash("U", user, pass)
+	})
+}
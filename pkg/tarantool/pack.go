@@ -0,0 +1,242 @@
+package tarantool
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Функции приведения значений полей тупла, декодированных из msgpack, к типам полей модели.
+// msgpack не сохраняет разрядность чисел, поэтому целые значения могут прийти любым целым типом
+
+func UnpackInt64(v any) (int64, error) {
+	switch val := v.(type) {
+	case int64:
+		return val, nil
+	case int:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case int16:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case uint8:
+		return int64(val), nil
+	case uint16:
+		return int64(val), nil
+	case uint32:
+		return int64(val), nil
+	case uint64:
+		if val > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+
+		return int64(val), nil
+	case uint:
+		if uint64(val) > math.MaxInt64 {
+			return 0, fmt.Errorf("value %d overflows int64", val)
+		}
+
+		return int64(val), nil
+	default:
+		return 0, fmt.Errorf("invalid integer value type %T", v)
+	}
+}
+
+func UnpackUint64(v any) (uint64, error) {
+	switch val := v.(type) {
+	case uint64:
+		return val, nil
+	case uint:
+		return uint64(val), nil
+	case uint8:
+		return uint64(val), nil
+	case uint16:
+		return uint64(val), nil
+	case uint32:
+		return uint64(val), nil
+	default:
+		signed, err := UnpackInt64(v)
+		if err != nil {
+			return 0, err
+		}
+
+		if signed < 0 {
+			return 0, fmt.Errorf("negative value %d for unsigned field", signed)
+		}
+
+		return uint64(signed), nil
+	}
+}
+
+func UnpackFloat64(v any) (float64, error) {
+	switch val := v.(type) {
+	case float64:
+		return val, nil
+	case float32:
+		return float64(val), nil
+	default:
+		signed, err := UnpackInt64(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid float value type %T", v)
+		}
+
+		return float64(signed), nil
+	}
+}
+
+func UnpackBool(v any) (bool, error) {
+	val, ok := v.(bool)
+	if !ok {
+		return false, fmt.Errorf("invalid bool value type %T", v)
+	}
+
+	return val, nil
+}
+
+func UnpackString(v any) (string, error) {
+	switch val := v.(type) {
+	case string:
+		return val, nil
+	case []byte:
+		return string(val), nil
+	default:
+		return "", fmt.Errorf("invalid string value type %T", v)
+	}
+}
+
+// UnpackUUID принимает 16 байт бинарного представления или строковую запись UUID
+func UnpackUUID(v any) (uuid.UUID, error) {
+	var (
+		ret uuid.UUID
+		err error
+	)
+
+	switch val := v.(type) {
+	case []byte:
+		ret, err = uuid.FromBytes(val)
+	case string:
+		if len(val) == len(ret) {
+			ret, err = uuid.FromBytes([]byte(val))
+		} else {
+			ret, err = uuid.Parse(val)
+		}
+	default:
+		return uuid.Nil, fmt.Errorf("invalid uuid value type %T", v)
+	}
+
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid uuid value: %w", err)
+	}
+
+	return ret, nil
+}
+
+// PackUUID возвращает 16 байт бинарного представления UUID для записи в тупл
+func PackUUID(v uuid.UUID) []byte {
+	return v[:]
+}
+
+// UnpackDecimal принимает строковую запись числа. Числа с плавающей точкой не принимаются,
+// так как при их распаковке теряется точность
+func UnpackDecimal(v any) (decimal.Decimal, error) {
+	str, err := UnpackString(v)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid decimal value type %T", v)
+	}
+
+	ret, err := decimal.NewFromString(str)
+	if err != nil {
+		return decimal.Zero, fmt.Errorf("invalid decimal value: %w", err)
+	}
+
+	return ret, nil
+}
+
+// PackDecimal возвращает строковую запись числа для записи в тупл
+func PackDecimal(v decimal.Decimal) string {
+	return v.String()
+}
+
+// UnpackTimeUnix принимает время в секундах unix time
+func UnpackTimeUnix(v any) (time.Time, error) {
+	sec, err := UnpackInt64(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time value: %w", err)
+	}
+
+	return time.Unix(sec, 0).UTC(), nil
+}
+
+// PackTimeUnix возвращает время в секундах unix time для записи в тупл. Нулевое значение time.Time
+// записывается как есть (отрицательным числом секунд) и не совпадает с началом эпохи
+func PackTimeUnix(v time.Time) int64 {
+	return v.Unix()
+}
+
+// UnpackTimeUnixMs принимает время в миллисекундах unix time
+func UnpackTimeUnixMs(v any) (time.Time, error) {
+	msec, err := UnpackInt64(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time value: %w", err)
+	}
+
+	return time.UnixMilli(msec).UTC(), nil
+}
+
+// PackTimeUnixMs возвращает время в миллисекундах unix time для записи в тупл
+func PackTimeUnixMs(v time.Time) int64 {
+	return v.UnixMilli()
+}
+
+// UnpackTimeRFC3339 принимает строковую запись времени в формате RFC 3339 и возвращает время в UTC
+func UnpackTimeRFC3339(v any) (time.Time, error) {
+	str, err := UnpackString(v)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time value type %T", v)
+	}
+
+	ret, err := time.Parse(time.RFC3339, str)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time value: %w", err)
+	}
+
+	return ret.UTC(), nil
+}
+
+// PackTimeRFC3339 возвращает строковую запись времени в формате RFC 3339 с сохранением долей секунды
+// и смещения зоны для записи в тупл
+func PackTimeRFC3339(v time.Time) string {
+	return v.Format(time.RFC3339Nano)
+}
+
+// UnpackArray возвращает элементы поля-массива, декодированного из msgpack
+func UnpackArray(v any) ([]any, error) {
+	val, ok := v.([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid array value type %T", v)
+	}
+
+	return val, nil
+}
+
+// PackTuple кодирует тупл записи в msgpack. Используется для хранения записей вне базы
+func PackTuple(tuple []any) ([]byte, error) {
+	return msgpack.Marshal(tuple)
+}
+
+// UnpackTuple декодирует тупл, закодированный PackTuple
+func UnpackTuple(data []byte) ([]any, error) {
+	var tuple []any
+
+	if err := msgpack.Unmarshal(data, &tuple); err != nil {
+		return nil, fmt.Errorf("invalid tuple: %w", err)
+	}
+
+	return tuple, nil
+}
@@ -0,0 +1,216 @@
+package tarantool
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func TestUnpackInt64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    int64
+		wantErr bool
+	}{
+		{name: "int8", value: int8(-5), want: -5},
+		{name: "uint16", value: uint16(300), want: 300},
+		{name: "int64", value: int64(math.MinInt64), want: math.MinInt64},
+		{name: "uint64 overflow", value: uint64(math.MaxUint64), wantErr: true},
+		{name: "string", value: "1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnpackInt64(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnpackInt64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnpackInt64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpackUint64(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		want    uint64
+		wantErr bool
+	}{
+		{name: "uint64", value: uint64(math.MaxUint64), want: math.MaxUint64},
+		{name: "positive int8", value: int8(7), want: 7},
+		{name: "negative", value: int32(-1), wantErr: true},
+		{name: "float", value: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnpackUint64(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UnpackUint64() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("UnpackUint64() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnpackOther(t *testing.T) {
+	if got, err := UnpackFloat64(float32(1.5)); err != nil || got != 1.5 {
+		t.Errorf("UnpackFloat64() = %v, %v", got, err)
+	}
+
+	if got, err := UnpackFloat64(int8(2)); err != nil || got != 2 {
+		t.Errorf("UnpackFloat64() from int = %v, %v", got, err)
+	}
+
+	if _, err := UnpackBool(uint8(1)); err == nil {
+		t.Errorf("UnpackBool() expect error for integer value")
+	}
+
+	if got, err := UnpackString([]byte("bin")); err != nil || got != "bin" {
+		t.Errorf("UnpackString() = %v, %v", got, err)
+	}
+
+	if got, err := UnpackArray([]any{}); err != nil || got == nil || len(got) != 0 {
+		t.Errorf("UnpackArray() = %v, %v", got, err)
+	}
+
+	if _, err := UnpackArray("tags"); err == nil {
+		t.Errorf("UnpackArray() expect error for string value")
+	}
+
+	id := uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+
+	if got, err := UnpackUUID(PackUUID(id)); err != nil || got != id {
+		t.Errorf("UnpackUUID() = %v, %v", got, err)
+	}
+
+	if got, err := UnpackUUID(id.String()); err != nil || got != id {
+		t.Errorf("UnpackUUID() from string = %v, %v", got, err)
+	}
+
+	if _, err := UnpackUUID([]byte("short")); err == nil {
+		t.Errorf("UnpackUUID() expect error for invalid length")
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	sum := decimal.RequireFromString("0.1").Add(decimal.RequireFromString("0.2"))
+
+	got, err := UnpackDecimal(PackDecimal(sum))
+	if err != nil {
+		t.Fatalf("UnpackDecimal() error = %v", err)
+	}
+
+	if !got.Equal(decimal.RequireFromString("0.3")) || got.String() != "0.3" {
+		t.Errorf("UnpackDecimal() = %s, want 0.3", got)
+	}
+
+	if _, err := UnpackDecimal(0.3); err == nil {
+		t.Errorf("UnpackDecimal() expect error for float value")
+	}
+
+	if _, err := UnpackDecimal("0,3"); err == nil {
+		t.Errorf("UnpackDecimal() expect error for invalid value")
+	}
+}
+
+func TestTime(t *testing.T) {
+	moment := time.Date(2023, 5, 17, 10, 30, 15, 250*int(time.Millisecond), time.FixedZone("MSK", 3*3600))
+
+	tests := []struct {
+		name   string
+		pack   func(time.Time) any
+		unpack func(any) (time.Time, error)
+		value  time.Time
+		want   time.Time
+	}{
+		{name: "unix", pack: func(v time.Time) any { return PackTimeUnix(v) }, unpack: UnpackTimeUnix, value: moment, want: moment.Truncate(time.Second)},
+		{name: "unix epoch", pack: func(v time.Time) any { return PackTimeUnix(v) }, unpack: UnpackTimeUnix, value: time.Unix(0, 0), want: time.Unix(0, 0)},
+		{name: "unix zero", pack: func(v time.Time) any { return PackTimeUnix(v) }, unpack: UnpackTimeUnix, value: time.Time{}, want: time.Time{}},
+		{name: "unix_ms", pack: func(v time.Time) any { return PackTimeUnixMs(v) }, unpack: UnpackTimeUnixMs, value: moment, want: moment},
+		{name: "unix_ms zero", pack: func(v time.Time) any { return PackTimeUnixMs(v) }, unpack: UnpackTimeUnixMs, value: time.Time{}, want: time.Time{}},
+		{name: "rfc3339", pack: func(v time.Time) any { return PackTimeRFC3339(v) }, unpack: UnpackTimeRFC3339, value: moment, want: moment},
+		{name: "rfc3339 zero", pack: func(v time.Time) any { return PackTimeRFC3339(v) }, unpack: UnpackTimeRFC3339, value: time.Time{}, want: time.Time{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.unpack(tt.pack(tt.value))
+			if err != nil {
+				t.Fatalf("unpack() error = %v", err)
+			}
+
+			if !got.Equal(tt.want) || got.IsZero() != tt.want.IsZero() {
+				t.Errorf("unpack() = %v, want %v", got, tt.want)
+			}
+
+			if got.Location() != time.UTC {
+				t.Errorf("unpack() location = %v, want UTC", got.Location())
+			}
+		})
+	}
+
+	// Смещение зоны из строки не сохраняется, время приводится к UTC
+	got, err := UnpackTimeRFC3339("2023-05-17T13:30:15+03:00")
+	if err != nil {
+		t.Fatalf("UnpackTimeRFC3339() error = %v", err)
+	}
+
+	if want := time.Date(2023, 5, 17, 10, 30, 15, 0, time.UTC); !got.Equal(want) || got.Location() != time.UTC {
+		t.Errorf("UnpackTimeRFC3339() = %v, want %v", got, want)
+	}
+
+	if _, err := UnpackTimeRFC3339("2023-05-17 10:30:15"); err == nil {
+		t.Errorf("UnpackTimeRFC3339() expect error for invalid value")
+	}
+
+	if _, err := UnpackTimeUnix("1684309815"); err == nil {
+		t.Errorf("UnpackTimeUnix() expect error for string value")
+	}
+}
+
+func TestPackTuple(t *testing.T) {
+	id := uuid.New()
+
+	data, err := PackTuple([]any{int64(-5), uint64(math.MaxUint64), "nick", PackUUID(id), nil, []any{int64(1), int64(2)}})
+	if err != nil {
+		t.Fatalf("PackTuple() error = %v", err)
+	}
+
+	tuple, err := UnpackTuple(data)
+	if err != nil {
+		t.Fatalf("UnpackTuple() error = %v", err)
+	}
+
+	if len(tuple) != 6 {
+		t.Fatalf("UnpackTuple() len = %d, want 6", len(tuple))
+	}
+
+	if v, err := UnpackInt64(tuple[0]); err != nil || v != -5 {
+		t.Errorf("UnpackInt64() = %d, %v, want -5", v, err)
+	}
+
+	if v, err := UnpackUint64(tuple[1]); err != nil || v != math.MaxUint64 {
+		t.Errorf("UnpackUint64() = %d, %v, want %d", v, err, uint64(math.MaxUint64))
+	}
+
+	if v, err := UnpackUUID(tuple[3]); err != nil || v != id {
+		t.Errorf("UnpackUUID() = %s, %v, want %s", v, err, id)
+	}
+
+	if items, err := UnpackArray(tuple[5]); err != nil || len(items) != 2 || tuple[4] != nil {
+		t.Errorf("UnpackTuple() = %v, want nil and array of 2 items", tuple)
+	}
+
+	if _, err := UnpackTuple([]byte{0xc1}); err == nil {
+		t.Errorf("UnpackTuple() expect error for invalid data")
+	}
+}
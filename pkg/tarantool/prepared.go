@@ -0,0 +1,112 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+// preparedCache подготовленные на сервере SQL-запросы соединения по именам методов. Подготовленный запрос
+// существует только в сессии, в которой он создан, поэтому при переподключении кеш сбрасывается
+// и запросы подготавливаются заново при следующем вызове
+type preparedCache struct {
+	mu    sync.RWMutex
+	stmts map[string]*gotarantool.Prepared
+}
+
+func newPreparedCache() *preparedCache {
+	return &preparedCache{stmts: map[string]*gotarantool.Prepared{}}
+}
+
+func (p *preparedCache) get(name string) (*gotarantool.Prepared, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	stmt, ok := p.stmts[name]
+
+	return stmt, ok
+}
+
+func (p *preparedCache) set(name string, stmt *gotarantool.Prepared) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stmts[name] = stmt
+}
+
+// drop удаляет запрос name, если он не был заменён после получения stmt
+func (p *preparedCache) drop(name string, stmt *gotarantool.Prepared) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stmts[name] == stmt {
+		delete(p.stmts, name)
+	}
+}
+
+func (p *preparedCache) reset() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.stmts = map[string]*gotarantool.Prepared{}
+}
+
+// ExecutePrepared выполняет SQL-запрос expr с аргументами args и возвращает строки ответа. Запрос подготавливается
+// на сервере при первом вызове с именем name (как правило, имя метода модели), следующие вызовы передают только
+// идентификатор подготовленного запроса и аргументы. Для разных запросов необходимо использовать разные имена.
+// Если подготовленный запрос удалён на сервере, он подготавливается заново и выполняется повторно
+func (c *Connection) ExecutePrepared(ctx context.Context, name, expr string, args []any) ([][]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt execute prepared from empty connection")
+	}
+
+	stmt, err := c.prepare(ctx, name, expr)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.request(gotarantool.NewExecutePreparedRequest(stmt).Args(args).Context(ctx))
+	if !isPreparedNotFound(err) {
+		return rows, err
+	}
+
+	c.prepared.drop(name, stmt)
+
+	if stmt, err = c.prepare(ctx, name, expr); err != nil {
+		return nil, err
+	}
+
+	return c.request(gotarantool.NewExecutePreparedRequest(stmt).Args(args).Context(ctx))
+}
+
+// prepare возвращает подготовленный запрос name из кеша соединения или подготавливает expr на сервере
+func (c *Connection) prepare(ctx context.Context, name, expr string) (*gotarantool.Prepared, error) {
+	if stmt, ok := c.prepared.get(name); ok {
+		return stmt, nil
+	}
+
+	resp, err := c.conn.Do(gotarantool.NewPrepareRequest(expr).Context(ctx)).Get()
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare `%s`: %w", name, convertError(err))
+	}
+
+	stmt, err := gotarantool.NewPreparedFromResponse(c.conn, resp)
+	if err != nil {
+		return nil, fmt.Errorf("can't prepare `%s`: %w", name, err)
+	}
+
+	c.prepared.set(name, stmt)
+
+	return stmt, nil
+}
+
+// isPreparedNotFound проверяет, что сервер не нашёл подготовленный запрос: он удалён или подготовлен в другой сессии
+func isPreparedNotFound(err error) bool {
+	var boxErr gotarantool.Error
+
+	return errors.As(err, &boxErr) && strings.HasPrefix(boxErr.Msg, "Prepared statement with id")
+}
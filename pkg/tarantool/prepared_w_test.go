@@ -0,0 +1,164 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+func TestPreparedCache(t *testing.T) {
+	cache := newPreparedCache()
+	stmt := &gotarantool.Prepared{StatementID: 1}
+
+	if _, ok := cache.get("SelectByID"); ok {
+		t.Fatalf("get() from empty cache = true, want false")
+	}
+
+	cache.set("SelectByID", stmt)
+
+	if got, ok := cache.get("SelectByID"); !ok || got != stmt {
+		t.Errorf("get() = %v, %v, want %v, true", got, ok, stmt)
+	}
+
+	// Запрос, заменённый после получения устаревшего, не удаляется
+	replaced := &gotarantool.Prepared{StatementID: 2}
+	cache.set("SelectByID", replaced)
+	cache.drop("SelectByID", stmt)
+
+	if got, ok := cache.get("SelectByID"); !ok || got != replaced {
+		t.Errorf("get() after drop of outdated = %v, %v, want %v, true", got, ok, replaced)
+	}
+
+	cache.drop("SelectByID", replaced)
+
+	if _, ok := cache.get("SelectByID"); ok {
+		t.Errorf("get() after drop = true, want false")
+	}
+
+	cache.set("SelectByID", stmt)
+	cache.reset()
+
+	if _, ok := cache.get("SelectByID"); ok {
+		t.Errorf("get() after reset = true, want false")
+	}
+}
+
+func TestWatchConnection(t *testing.T) {
+	notify := make(chan gotarantool.ConnEvent, notifyBufferSize)
+	done := make(chan struct{})
+	prepared := newPreparedCache()
+
+	prepared.set("SelectByID", &gotarantool.Prepared{StatementID: 1})
+
+	go watchConnection(notify, done, prepared)
+
+	notify <- gotarantool.ConnEvent{Kind: gotarantool.Disconnected}
+	notify <- gotarantool.ConnEvent{Kind: gotarantool.Closed}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("done is not closed after Closed event")
+	}
+
+	// Подготовленные запросы прежней сессии после переподключения недействительны
+	if _, ok := prepared.get("SelectByID"); ok {
+		t.Errorf("prepared statement is kept after reconnect")
+	}
+}
+
+func TestExecutePreparedEmptyConnection(t *testing.T) {
+	var conn *Connection
+
+	if _, err := conn.ExecutePrepared(context.Background(), "SelectByID", `SELECT * FROM "foo" WHERE "id" = ?`, []any{1}); err == nil {
+		t.Errorf("ExecutePrepared() error = nil, want error on empty connection")
+	}
+}
+
+func TestIsPreparedNotFound(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "other", err: errors.New("Prepared statement with id 1 does not exist"), want: false},
+		{name: "box error", err: gotarantool.Error{Code: gotarantool.ErrTupleFound, Msg: "Duplicate key exists"}, want: false},
+		{name: "not found", err: fmt.Errorf("error response from box: `%w`", gotarantool.Error{Msg: "Prepared statement with id 1 does not exist"}), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isPreparedNotFound(tt.err); got != tt.want {
+				t.Errorf("isPreparedNotFound() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// benchSpaceLua создаёт спейс для бенчмарка выборок и заполняет его записями
+const benchSpaceLua = `local space = box.schema.space.create('ar_bench', {
+	format = {{name = 'id', type = 'unsigned'}, {name = 'name', type = 'string'}},
+	if_not_exists = true,
+})
+space:create_index('primary', {parts = {'id'}, if_not_exists = true})
+for id = 0, 999 do
+	space:replace({id, 'name' .. id})
+end`
+
+// BenchmarkSelectPrepared сравнивает выборку записи по первичному ключу бинарным запросом select, SQL-запросом,
+// который разбирается сервером при каждом вызове, и подготовленным SQL-запросом. Требуется tarantool 2.x,
+// адрес которого задаётся переменной окружения TARANTOOL_ADDR, без неё бенчмарк пропускается
+func BenchmarkSelectPrepared(b *testing.B) {
+	addr := os.Getenv("TARANTOOL_ADDR")
+	if addr == "" {
+		b.Skip("TARANTOOL_ADDR is not set")
+	}
+
+	ctx := context.Background()
+
+	opts, err := NewOptions(addr, ModeMaster)
+	if err != nil {
+		b.Fatalf("NewOptions() error = %v", err)
+	}
+
+	conn, err := GetConnection(ctx, opts)
+	if err != nil {
+		b.Fatalf("GetConnection() error = %v", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Eval(ctx, benchSpaceLua, []any{}); err != nil {
+		b.Fatalf("can't create bench space: %v", err)
+	}
+
+	const expr = `SELECT * FROM "ar_bench" WHERE "id" = ?`
+
+	b.Run("select", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := conn.Select(ctx, "ar_bench", 0, 0, 1, IterEq, []any{uint64(i % 1000)}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("execute", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := conn.request(gotarantool.NewExecuteRequest(expr).Args([]any{uint64(i % 1000)}).Context(ctx)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("prepared", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := conn.ExecutePrepared(ctx, "SelectByID", expr, []any{uint64(i % 1000)}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
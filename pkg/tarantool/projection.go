@@ -0,0 +1,72 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+)
+
+// selectFieldsExpr выборка из спейса с проекцией полей. Номера полей передаются с нуля, отсутствующие
+// в тупле поля возвращаются как box.NULL, чтобы строки ответа всегда имели одинаковую длину
+const selectFieldsExpr = `local space, index, offset, limit, iterator, key, fields = ...
+local rows = {}
+for _, t in ipairs(box.space[space].index[index]:select(key, {offset = offset, limit = limit, iterator = iterator})) do
+	local row = {}
+	for i, f in ipairs(fields) do
+		local v = t[f + 1]
+		if v == nil then
+			v = box.NULL
+		end
+		row[i] = v
+	end
+	rows[#rows + 1] = row
+end
+return rows`
+
+// SelectFields выборка из спейса space по индексу indexnum, в ответе которой для каждого тупла возвращаются
+// только поля с номерами fields в порядке их перечисления. Iproto не поддерживает проекцию полей, поэтому
+// выборка выполняется через eval и требует права на выполнение выражений у пользователя подключения
+func (c *Connection) SelectFields(ctx context.Context, space string, indexnum, offset, limit, iterator uint32, key []any, fields []uint32) ([][]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt select from empty connection")
+	}
+
+	if key == nil {
+		key = []any{}
+	}
+
+	data, err := c.Eval(ctx, selectFieldsExpr, []any{space, indexnum, offset, limit, iterator, key, fields})
+	if err != nil {
+		return nil, err
+	}
+
+	return projectedRows(data, len(fields))
+}
+
+// projectedRows разбирает ответ выборки с проекцией полей на строки по cntFields значений
+func projectedRows(data []any, cntFields int) ([][]any, error) {
+	if len(data) == 0 {
+		return [][]any{}, nil
+	}
+
+	rows, ok := data[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid select fields response type %T", data[0])
+	}
+
+	ret := make([][]any, 0, len(rows))
+
+	for num, r := range rows {
+		row, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid select fields row %d type %T", num, r)
+		}
+
+		if len(row) != cntFields {
+			return nil, fmt.Errorf("select fields row %d has %d fields but expected %d", num, len(row), cntFields)
+		}
+
+		ret = append(ret, row)
+	}
+
+	return ret, nil
+}
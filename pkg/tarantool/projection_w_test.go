@@ -0,0 +1,40 @@
+package tarantool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_projectedRows(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []any
+		cntFields int
+		want      [][]any
+		wantErr   bool
+	}{
+		{name: "empty response", data: []any{}, cntFields: 2, want: [][]any{}},
+		{name: "no rows", data: []any{[]any{}}, cntFields: 2, want: [][]any{}},
+		{
+			name:      "rows",
+			data:      []any{[]any{[]any{uint64(1), "a"}, []any{uint64(2), nil}}},
+			cntFields: 2,
+			want:      [][]any{{uint64(1), "a"}, {uint64(2), nil}},
+		},
+		{name: "invalid response", data: []any{"rows"}, cntFields: 1, wantErr: true},
+		{name: "invalid row", data: []any{[]any{uint64(1)}}, cntFields: 1, wantErr: true},
+		{name: "short row", data: []any{[]any{[]any{uint64(1)}}}, cntFields: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := projectedRows(tt.data, tt.cntFields)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("projectedRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("projectedRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
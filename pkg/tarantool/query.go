@@ -0,0 +1,79 @@
+package tarantool
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrQueryIndex      = errors.New("query index")
+	ErrQueryNotIndexed = errors.New("field is not indexed")
+	ErrQueryOrder      = errors.New("query order is not supported")
+	ErrQueryLimit      = errors.New("query limit is required")
+)
+
+// Тип и константы сравнения ключа в построителе запросов
+type Cmp uint8
+
+const (
+	CmpEq Cmp = iota // Записи с ключом, равным заданному. Используется по умолчанию
+	CmpGt            // Записи с ключом больше заданного
+	CmpGe            // Записи с ключом больше или равным заданному
+	CmpLt            // Записи с ключом меньше заданного
+	CmpLe            // Записи с ключом меньше или равным заданному
+)
+
+// Тип и константы порядка записей в построителе запросов. Нулевое значение означает порядок итератора:
+// прямой для CmpEq, CmpGt и CmpGe и обратный для CmpLt и CmpLe
+type Order uint8
+
+const (
+	OrderAsc Order = iota + 1
+	OrderDesc
+)
+
+// QueryIterator итератор выборки для сравнения ключа cmp и порядка записей order. Tarantool обходит индекс
+// от ключа в одном направлении, поэтому для сравнений «больше» доступен только прямой порядок,
+// а для сравнений «меньше» только обратный
+func QueryIterator(cmp Cmp, order Order) (uint32, error) {
+	switch cmp {
+	case CmpEq:
+		if order == OrderDesc {
+			return IterReq, nil
+		}
+
+		return IterEq, nil
+	case CmpGt, CmpGe:
+		if order == OrderDesc {
+			return 0, fmt.Errorf("%w: desc order for greater than comparison", ErrQueryOrder)
+		}
+
+		if cmp == CmpGt {
+			return IterGt, nil
+		}
+
+		return IterGe, nil
+	case CmpLt, CmpLe:
+		if order == OrderAsc {
+			return 0, fmt.Errorf("%w: asc order for less than comparison", ErrQueryOrder)
+		}
+
+		if cmp == CmpLt {
+			return IterLt, nil
+		}
+
+		return IterLe, nil
+	default:
+		return 0, fmt.Errorf("%w: unknown comparison %d", ErrQueryOrder, cmp)
+	}
+}
+
+// CheckQueryLimit проверяет лимит запроса. Выборка по сравнению «больше» или «меньше» обходит индекс
+// до его конца, поэтому для неё лимит обязателен, иначе один запрос может вернуть весь спейс
+func CheckQueryLimit(cmp Cmp, limit uint32) error {
+	if cmp != CmpEq && limit == 0 {
+		return fmt.Errorf("%w: range query without limit", ErrQueryLimit)
+	}
+
+	return nil
+}
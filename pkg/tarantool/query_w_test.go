@@ -0,0 +1,60 @@
+package tarantool
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQueryIterator(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmp     Cmp
+		order   Order
+		want    uint32
+		wantErr error
+	}{
+		{name: "eq", cmp: CmpEq, want: IterEq},
+		{name: "eq asc", cmp: CmpEq, order: OrderAsc, want: IterEq},
+		{name: "eq desc", cmp: CmpEq, order: OrderDesc, want: IterReq},
+		{name: "gt", cmp: CmpGt, want: IterGt},
+		{name: "ge asc", cmp: CmpGe, order: OrderAsc, want: IterGe},
+		{name: "ge desc", cmp: CmpGe, order: OrderDesc, wantErr: ErrQueryOrder},
+		{name: "lt", cmp: CmpLt, want: IterLt},
+		{name: "le desc", cmp: CmpLe, order: OrderDesc, want: IterLe},
+		{name: "le asc", cmp: CmpLe, order: OrderAsc, wantErr: ErrQueryOrder},
+		{name: "unknown", cmp: Cmp(100), wantErr: ErrQueryOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := QueryIterator(tt.cmp, tt.order)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("QueryIterator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("QueryIterator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckQueryLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmp     Cmp
+		limit   uint32
+		wantErr error
+	}{
+		{name: "eq without limit", cmp: CmpEq},
+		{name: "gt without limit", cmp: CmpGt, wantErr: ErrQueryLimit},
+		{name: "le without limit", cmp: CmpLe, wantErr: ErrQueryLimit},
+		{name: "ge with limit", cmp: CmpGe, limit: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckQueryLimit(tt.cmp, tt.limit); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckQueryLimit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
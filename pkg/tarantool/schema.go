@@ -0,0 +1,254 @@
+package tarantool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+	gotarantool "github.com/tarantool/go-tarantool"
+)
+
+// Системный спейс с описаниями спейсов, доступных пользователю, и номер его индекса по имени спейса
+const (
+	vspaceSpace     = "_vspace"
+	vspaceNameIndex = 2
+)
+
+// SpaceField описание поля из формата спейса
+type SpaceField struct {
+	Name       string
+	Type       string
+	IsNullable bool
+}
+
+// SpaceInfo описание спейса в БД
+type SpaceInfo struct {
+	Name       string
+	FieldCount uint64       // Обязательное количество полей тупла, 0 - не ограничено
+	Format     []SpaceField // Формат спейса, пустой если формат не задан
+}
+
+// Space читает описание спейса name из системного спейса _vspace. Если спейса нет, возвращается nil
+func (c *Connection) Space(ctx context.Context, name string) (*SpaceInfo, error) {
+	tuples, err := c.Select(ctx, vspaceSpace, vspaceNameIndex, 0, 1, 0, []any{name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(tuples) == 0 {
+		return nil, nil
+	}
+
+	return unpackSpace(tuples[0])
+}
+
+// unpackSpace распаковка тупла _vspace: [id, owner, name, engine, field_count, flags, format]
+func unpackSpace(tuple []any) (*SpaceInfo, error) {
+	if len(tuple) < 7 {
+		return nil, fmt.Errorf("invalid _vspace tuple length %d", len(tuple))
+	}
+
+	name, err := UnpackString(tuple[2])
+	if err != nil {
+		return nil, fmt.Errorf("can't unpack space name: %w", err)
+	}
+
+	fieldCount, err := UnpackUint64(tuple[4])
+	if err != nil {
+		return nil, fmt.Errorf("can't unpack space `%s` field count: %w", name, err)
+	}
+
+	format, err := UnpackArray(tuple[6])
+	if err != nil {
+		return nil, fmt.Errorf("can't unpack space `%s` format: %w", name, err)
+	}
+
+	space := &SpaceInfo{Name: name, FieldCount: fieldCount, Format: make([]SpaceField, 0, len(format))}
+
+	for num, f := range format {
+		var field SpaceField
+
+		switch desc := f.(type) {
+		case map[string]any:
+			field.Name, _ = desc["name"].(string)
+			field.Type, _ = desc["type"].(string)
+			field.IsNullable, _ = desc["is_nullable"].(bool)
+		case map[any]any:
+			field.Name, _ = desc["name"].(string)
+			field.Type, _ = desc["type"].(string)
+			field.IsNullable, _ = desc["is_nullable"].(bool)
+		default:
+			return nil, fmt.Errorf("invalid space `%s` format of field %d: %T", name, num, f)
+		}
+
+		space.Format = append(space.Format, field)
+	}
+
+	return space, nil
+}
+
+// CheckSchema проверяет, что спейс модели в БД соответствует описанию schema, по которому сгенерирована модель:
+// количество полей и их имена в формате спейса (имя поля в хранилище, а если оно не задано, то имя поля модели,
+// без учёта регистра и подчёркиваний). Если формат спейса не задан, проверяется только обязательное количество
+// полей. При расхождении возвращается *activerecord.SchemaMismatchError
+func CheckSchema(ctx context.Context, c *Connection, schema activerecord.SchemaDescriptor) error {
+	space, err := c.Space(ctx, schema.Namespace)
+	if err != nil {
+		return fmt.Errorf("can't read space `%s` schema: %w", schema.Namespace, err)
+	}
+
+	return checkSpace(schema, space)
+}
+
+func checkSpace(schema activerecord.SchemaDescriptor, space *SpaceInfo) error {
+	mismatch := func(format string, args ...any) error {
+		return &activerecord.SchemaMismatchError{Entity: schema.Name, Version: schema.Version, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	if space == nil {
+		return mismatch("space `%s` not found", schema.Namespace)
+	}
+
+	if len(space.Format) == 0 {
+		if space.FieldCount != 0 && space.FieldCount != uint64(len(schema.Fields)) {
+			return mismatch("space `%s` requires %d fields, model has %d", space.Name, space.FieldCount, len(schema.Fields))
+		}
+
+		return nil
+	}
+
+	if len(space.Format) != len(schema.Fields) {
+		return mismatch("space `%s` has %d fields, model has %d", space.Name, len(space.Format), len(schema.Fields))
+	}
+
+	for num, field := range schema.Fields {
+		name := field.Storage
+		if name == "" {
+			name = field.Name
+		}
+
+		if normalizeFieldName(space.Format[num].Name) != normalizeFieldName(name) {
+			return mismatch("field %d is `%s` in space `%s`, `%s` in model", num+1, space.Format[num].Name, space.Name, name)
+		}
+	}
+
+	return nil
+}
+
+// normalizeFieldName приводит имя поля к виду, не зависящему от стиля именования: `user_id` и `UserID` совпадают
+func normalizeFieldName(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "_", ""))
+}
+
+// ensureSchemaLua создаёт спейс с форматом и его индексы, если их ещё нет
+const ensureSchemaLua = `local name, format, indexes = ...
+local space = box.schema.space.create(name, {format = format, if_not_exists = true})
+for _, ind in ipairs(indexes) do
+	space:create_index(ind.name, {id = ind.id, unique = ind.unique, parts = ind.parts, if_not_exists = true})
+end`
+
+// EnsureSchema создаёт спейс модели с форматом и индексы по описанию schema, если их ещё нет. Существующие спейс
+// и индексы не изменяются, поэтому функцию можно вызывать при каждом старте. При одновременном вызове из нескольких
+// экземпляров приложения создание спейса или индекса другим экземпляром не считается ошибкой, создание повторяется.
+// Выполняется на мастере через eval, поэтому требует прав на выполнение кода и изменение схемы
+func EnsureSchema(ctx context.Context, c *Connection, schema activerecord.SchemaDescriptor) error {
+	format, indexes := spaceDefinition(schema)
+
+	_, err := c.Eval(ctx, ensureSchemaLua, []any{schema.Namespace, format, indexes})
+	if isSchemaExistsError(err) {
+		_, err = c.Eval(ctx, ensureSchemaLua, []any{schema.Namespace, format, indexes})
+	}
+
+	if err != nil {
+		return fmt.Errorf("can't create space `%s` schema: %w", schema.Namespace, err)
+	}
+
+	return nil
+}
+
+// isSchemaExistsError проверяет, что спейс или индекс создан параллельно другим запросом
+func isSchemaExistsError(err error) bool {
+	var boxErr gotarantool.Error
+
+	return errors.As(err, &boxErr) && (boxErr.Code == gotarantool.ErrSpaceExists || boxErr.Code == gotarantool.ErrIndexExists)
+}
+
+// spaceDefinition формирует формат спейса и описания индексов для box.schema.space.create и create_index.
+// Индексы, описывающие часть составного индекса, в хранилище не создаются
+func spaceDefinition(schema activerecord.SchemaDescriptor) ([]map[string]any, []map[string]any) {
+	format := make([]map[string]any, 0, len(schema.Fields))
+	fieldNum := make(map[string]int, len(schema.Fields))
+
+	for num, field := range schema.Fields {
+		name := field.Storage
+		if name == "" {
+			name = field.Name
+		}
+
+		format = append(format, map[string]any{"name": name, "type": spaceFieldType(field), "is_nullable": field.Nullable})
+		fieldNum[field.Name] = num
+	}
+
+	indexes := make([]map[string]any, 0, len(schema.Indexes))
+
+	for _, ind := range schema.Indexes {
+		if ind.Partial {
+			continue
+		}
+
+		parts := make([]any, 0, len(ind.Fields))
+		for _, name := range ind.Fields {
+			num := fieldNum[name]
+			parts = append(parts, map[string]any{"field": num + 1, "type": format[num]["type"], "is_nullable": format[num]["is_nullable"]})
+		}
+
+		indexes = append(indexes, map[string]any{"name": ind.Name, "id": ind.Num, "unique": ind.Unique || ind.Primary, "parts": parts})
+	}
+
+	return format, indexes
+}
+
+// spaceFieldTypes типы полей формата спейса по формату поля модели
+var spaceFieldTypes = map[string]string{
+	"int": "integer", "int8": "integer", "int16": "integer", "int32": "integer", "int64": "integer",
+	"uint": "unsigned", "uint8": "unsigned", "uint16": "unsigned", "uint32": "unsigned", "uint64": "unsigned",
+	"float32": "number", "float64": "number",
+	"string":          "string",
+	"bool":            "boolean",
+	"[]string":        "array",
+	"[]byte":          "varbinary",
+	"uuid.UUID":       "uuid",
+	"decimal.Decimal": "decimal",
+}
+
+// spaceTimestampTypes типы полей time.Time по формату хранения: unix time упаковывается
+// беззнаковым целым, RFC 3339 - строкой
+var spaceTimestampTypes = map[string]string{
+	"unix":    "unsigned",
+	"unix_ms": "unsigned",
+	"rfc3339": "string",
+}
+
+// spaceFieldType возвращает тип поля формата спейса. Сериализуемое поле хранится в формате
+// поля, тип поля time.Time определяется форматом хранения. Для неизвестных форматов используется scalar
+func spaceFieldType(field activerecord.SchemaField) string {
+	if field.Array {
+		return "array"
+	}
+
+	if field.Format == "time.Time" {
+		if t, ok := spaceTimestampTypes[field.Timestamp]; ok {
+			return t
+		}
+
+		return "scalar"
+	}
+
+	if t, ok := spaceFieldTypes[field.Format]; ok {
+		return t
+	}
+
+	return "scalar"
+}
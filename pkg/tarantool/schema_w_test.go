@@ -0,0 +1,146 @@
+package tarantool
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+)
+
+func Test_unpackSpace(t *testing.T) {
+	tuple := []any{uint64(512), uint64(1), "users", "memtx", uint64(0), map[any]any{}, []any{
+		map[any]any{"name": "id", "type": "unsigned"},
+		map[string]any{"name": "email", "type": "string", "is_nullable": true},
+	}}
+
+	got, err := unpackSpace(tuple)
+	if err != nil {
+		t.Fatalf("unpackSpace() error = %v", err)
+	}
+
+	want := &SpaceInfo{Name: "users", Format: []SpaceField{
+		{Name: "id", Type: "unsigned"},
+		{Name: "email", Type: "string", IsNullable: true},
+	}}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("unpackSpace() = %+v, want %+v", got, want)
+	}
+
+	if _, err := unpackSpace(tuple[:3]); err == nil {
+		t.Errorf("unpackSpace() error = nil, want error on short tuple")
+	}
+}
+
+func Test_checkSpace(t *testing.T) {
+	schema := activerecord.SchemaDescriptor{
+		Name:      "User",
+		Version:   "0123456789abcdef",
+		Namespace: "users",
+		Fields:    []activerecord.SchemaField{{Name: "UserID"}, {Name: "Login", Storage: "user_login"}},
+	}
+
+	tests := []struct {
+		name    string
+		space   *SpaceInfo
+		wantErr bool
+	}{
+		{
+			name:  "format matches",
+			space: &SpaceInfo{Name: "users", Format: []SpaceField{{Name: "user_id"}, {Name: "user_login"}}},
+		},
+		{
+			name:  "without format",
+			space: &SpaceInfo{Name: "users"},
+		},
+		{
+			name:  "field count without format",
+			space: &SpaceInfo{Name: "users", FieldCount: 2},
+		},
+		{
+			name:    "field count mismatch without format",
+			space:   &SpaceInfo{Name: "users", FieldCount: 3},
+			wantErr: true,
+		},
+		{
+			name:    "not found",
+			wantErr: true,
+		},
+		{
+			name:    "new field not migrated",
+			space:   &SpaceInfo{Name: "users", Format: []SpaceField{{Name: "user_id"}}},
+			wantErr: true,
+		},
+		{
+			name:    "fields order",
+			space:   &SpaceInfo{Name: "users", Format: []SpaceField{{Name: "user_login"}, {Name: "user_id"}}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSpace(schema, tt.space)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSpace() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var mismatchErr *activerecord.SchemaMismatchError
+			if tt.wantErr && (!errors.As(err, &mismatchErr) || mismatchErr.Version != schema.Version) {
+				t.Errorf("checkSpace() error = %v, want SchemaMismatchError", err)
+			}
+		})
+	}
+}
+
+func Test_spaceDefinition(t *testing.T) {
+	schema := activerecord.SchemaDescriptor{
+		Namespace: "users",
+		Fields: []activerecord.SchemaField{
+			{Name: "UserID", Storage: "user_id", Format: "uint64", PrimaryKey: true},
+			{Name: "Login", Format: "string"},
+			{Name: "Tags", Format: "string", Array: true},
+			{Name: "Email", Format: "string", Nullable: true},
+			{Name: "Meta", Format: "string", Serializer: "json"},
+			{Name: "Created", Format: "time.Time", Timestamp: "unix"},
+			{Name: "Updated", Format: "time.Time", Timestamp: "rfc3339"},
+			{Name: "Avatar", Format: "[]byte"},
+			{Name: "Custom", Format: "custom"},
+		},
+		Indexes: []activerecord.SchemaIndex{
+			{Name: "ID", Num: 0, Fields: []string{"UserID"}, Primary: true},
+			{Name: "LoginEmail", Num: 1, Fields: []string{"Login", "Email"}},
+			{Name: "Login", Num: 1, Fields: []string{"Login"}, Partial: true},
+		},
+	}
+
+	format, indexes := spaceDefinition(schema)
+
+	wantFormat := []map[string]any{
+		{"name": "user_id", "type": "unsigned", "is_nullable": false},
+		{"name": "Login", "type": "string", "is_nullable": false},
+		{"name": "Tags", "type": "array", "is_nullable": false},
+		{"name": "Email", "type": "string", "is_nullable": true},
+		{"name": "Meta", "type": "string", "is_nullable": false},
+		{"name": "Created", "type": "unsigned", "is_nullable": false},
+		{"name": "Updated", "type": "string", "is_nullable": false},
+		{"name": "Avatar", "type": "varbinary", "is_nullable": false},
+		{"name": "Custom", "type": "scalar", "is_nullable": false},
+	}
+	if !reflect.DeepEqual(format, wantFormat) {
+		t.Errorf("spaceDefinition() format = %v, want %v", format, wantFormat)
+	}
+
+	wantIndexes := []map[string]any{
+		{"name": "ID", "id": uint32(0), "unique": true, "parts": []any{
+			map[string]any{"field": 1, "type": "unsigned", "is_nullable": false},
+		}},
+		{"name": "LoginEmail", "id": uint32(1), "unique": false, "parts": []any{
+			map[string]any{"field": 2, "type": "string", "is_nullable": false},
+			map[string]any{"field": 4, "type": "string", "is_nullable": true},
+		}},
+	}
+	if !reflect.DeepEqual(indexes, wantIndexes) {
+		t.Errorf("spaceDefinition() indexes = %v, want %v", indexes, wantIndexes)
+	}
+}
@@ -0,0 +1,70 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+)
+
+// selectKeysExpr выборка по набору ключей одним запросом. Смещение и лимит действуют на весь ответ,
+// ключи обходятся в порядке их перечисления
+const selectKeysExpr = `local space, index, offset, limit, keys = ...
+local idx = box.space[space].index[index]
+local rows = {}
+for _, key in ipairs(keys) do
+	for _, t in idx:pairs(key, {iterator = 'EQ'}) do
+		if offset > 0 then
+			offset = offset - 1
+		elseif #rows >= limit then
+			return rows
+		else
+			rows[#rows + 1] = t
+		end
+	end
+end
+return rows`
+
+// SelectKeys выборка из спейса space по индексу indexnum туплов со значением ключа из списка keys одним
+// запросом. В отличие от Select смещение offset и лимит limit действуют на все ключи вместе. Бинарный
+// протокол позволяет передать в запросе только один ключ, поэтому выборка выполняется через eval и требует
+// права на выполнение выражений у пользователя подключения
+func (c *Connection) SelectKeys(ctx context.Context, space string, indexnum, offset, limit uint32, keys [][]any) ([][]any, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt select from empty connection")
+	}
+
+	if len(keys) == 0 {
+		return [][]any{}, nil
+	}
+
+	data, err := c.Eval(ctx, selectKeysExpr, []any{space, indexnum, offset, limit, keys})
+	if err != nil {
+		return nil, err
+	}
+
+	return keysRows(data)
+}
+
+// keysRows разбирает ответ выборки по набору ключей на туплы
+func keysRows(data []any) ([][]any, error) {
+	if len(data) == 0 {
+		return [][]any{}, nil
+	}
+
+	rows, ok := data[0].([]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid select keys response type %T", data[0])
+	}
+
+	ret := make([][]any, 0, len(rows))
+
+	for num, r := range rows {
+		tuple, ok := r.([]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid select keys tuple %d type %T", num, r)
+		}
+
+		ret = append(ret, tuple)
+	}
+
+	return ret, nil
+}
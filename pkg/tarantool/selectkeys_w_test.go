@@ -0,0 +1,37 @@
+package tarantool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_keysRows(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []any
+		want    [][]any
+		wantErr bool
+	}{
+		{name: "empty response", data: []any{}, want: [][]any{}},
+		{name: "no rows", data: []any{[]any{}}, want: [][]any{}},
+		{
+			name: "rows of different length",
+			data: []any{[]any{[]any{uint64(1), "a"}, []any{uint64(2)}}},
+			want: [][]any{{uint64(1), "a"}, {uint64(2)}},
+		},
+		{name: "invalid response", data: []any{"rows"}, wantErr: true},
+		{name: "invalid tuple", data: []any{[]any{uint64(1)}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := keysRows(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("keysRows() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysRows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
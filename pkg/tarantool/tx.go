@@ -0,0 +1,181 @@
+package tarantool
+
+import (
+	"context"
+	"fmt"
+
+	gotarantool "github.com/tarantool/go-tarantool"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+)
+
+// Executor выполняет запросы изменения записей. Реализуется соединением с инстансом
+// и транзакцией, что позволяет сгенерированному коду выполнять одни и те же запросы
+// как вне транзакции, так и в ней
+type Executor interface {
+	Insert(ctx context.Context, space string, tuple []any) error
+	Replace(ctx context.Context, space string, tuple []any) error
+	Update(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) error
+	UpdateReturning(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) ([]any, error)
+	Delete(ctx context.Context, space string, indexnum uint32, key []any) (int, error)
+	Info() string
+}
+
+var (
+	_ Executor = (*Connection)(nil)
+	_ Executor = (*Tx)(nil)
+)
+
+// Tx интерактивная транзакция в tarantool 2.x. Запросы транзакции выполняются в отдельном стриме
+// соединения между box.begin и box.commit (или box.rollback). Для работы интерактивных транзакций
+// на инстансе должен быть включен memtx_use_mvcc_engine, либо использоваться движок vinyl
+type Tx struct {
+	conn     *Connection
+	stream   *gotarantool.Stream
+	onCommit []func()
+}
+
+// Begin начинает транзакцию в новом стриме соединения
+func (c *Connection) Begin(ctx context.Context) (*Tx, error) {
+	if c == nil || c.conn == nil {
+		return nil, fmt.Errorf("attempt begin transaction on empty connection")
+	}
+
+	stream, err := c.conn.NewStream()
+	if err != nil {
+		return nil, fmt.Errorf("can't create stream: %w", err)
+	}
+
+	if _, err = stream.Do(gotarantool.NewBeginRequest().Context(ctx)).Get(); err != nil {
+		return nil, convertError(err)
+	}
+
+	return &Tx{conn: c, stream: stream}, nil
+}
+
+// Commit фиксирует изменения, сделанные в транзакции, и вызывает функции, зарегистрированные OnCommit
+func (tx *Tx) Commit(ctx context.Context) error {
+	if _, err := tx.stream.Do(gotarantool.NewCommitRequest().Context(ctx)).Get(); err != nil {
+		return convertError(err)
+	}
+
+	for _, fn := range tx.onCommit {
+		fn()
+	}
+
+	tx.onCommit = nil
+
+	return nil
+}
+
+// OnCommit регистрирует функцию, которая вызывается после успешной фиксации транзакции.
+// Используется, когда действие должно выполняться только после того, как изменения станут видны вне транзакции
+func (tx *Tx) OnCommit(fn func()) {
+	tx.onCommit = append(tx.onCommit, fn)
+}
+
+// Rollback откатывает изменения, сделанные в транзакции
+func (tx *Tx) Rollback(ctx context.Context) error {
+	tx.onCommit = nil
+
+	if _, err := tx.stream.Do(gotarantool.NewRollbackRequest().Context(ctx)).Get(); err != nil {
+		return convertError(err)
+	}
+
+	return nil
+}
+
+// Insert вставка тупла в спейс space в транзакции. Если запись с таким ключом уже есть, то возвращается ErrDuplicate
+func (tx *Tx) Insert(ctx context.Context, space string, tuple []any) error {
+	_, err := doTuples(tx.stream.Do(gotarantool.NewInsertRequest(space).Tuple(tuple).Context(ctx)))
+
+	return err
+}
+
+// Replace вставка или замена тупла в спейсе space в транзакции
+func (tx *Tx) Replace(ctx context.Context, space string, tuple []any) error {
+	_, err := doTuples(tx.stream.Do(gotarantool.NewReplaceRequest(space).Tuple(tuple).Context(ctx)))
+
+	return err
+}
+
+// Update обновление полей записи с ключом key в спейсе space в транзакции
+func (tx *Tx) Update(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) error {
+	updateOps, err := updateOperations(ops)
+	if err != nil {
+		return err
+	}
+
+	_, err = doTuples(tx.stream.Do(gotarantool.NewUpdateRequest(space).Index(indexnum).Key(key).Operations(updateOps).Context(ctx)))
+
+	return err
+}
+
+// UpdateReturning обновление полей записи с ключом key в спейсе space в транзакции. Возвращает тупл записи
+// после обновления или nil, если записи с таким ключом нет
+func (tx *Tx) UpdateReturning(ctx context.Context, space string, indexnum uint32, key []any, ops []Ops) ([]any, error) {
+	updateOps, err := updateOperations(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	tuples, err := doTuples(tx.stream.Do(gotarantool.NewUpdateRequest(space).Index(indexnum).Key(key).Operations(updateOps).Context(ctx)))
+	if err != nil || len(tuples) == 0 {
+		return nil, err
+	}
+
+	return tuples[0], nil
+}
+
+// Delete удаление записи с ключом key из спейса space в транзакции. Возвращает количество удалённых записей
+func (tx *Tx) Delete(ctx context.Context, space string, indexnum uint32, key []any) (int, error) {
+	tuples, err := doTuples(tx.stream.Do(gotarantool.NewDeleteRequest(space).Index(indexnum).Key(key).Context(ctx)))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(tuples), nil
+}
+
+// OutboxTuple тупл события outbox в спейсе: идентификатор, модель, вид изменения, первичный ключ и запись в JSON,
+// время формирования события в наносекундах unix
+func OutboxTuple(ev activerecord.OutboxEvent) []any {
+	return []any{ev.ID, ev.Entity, string(ev.Op), string(ev.Key), string(ev.Payload), uint64(ev.Created.UnixNano())}
+}
+
+// AppendOutbox вставка события ev в спейс outbox space в транзакции. Событие фиксируется или откатывается
+// вместе с остальными изменениями транзакции
+func (tx *Tx) AppendOutbox(ctx context.Context, space string, ev activerecord.OutboxEvent) error {
+	return tx.Insert(ctx, space, OutboxTuple(ev))
+}
+
+// Info описание соединения, в котором выполняется транзакция
+func (tx *Tx) Info() string {
+	return fmt.Sprintf("%s, stream: %d", tx.conn.Info(), tx.stream.Id)
+}
+
+// WithTx выполняет fn в транзакции на соединении conn. Если fn вернула ошибку или запаниковала,
+// транзакция откатывается, иначе фиксируется
+func WithTx(ctx context.Context, conn *Connection, fn func(tx *Tx) error) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			_ = tx.Rollback(ctx)
+			panic(p)
+		}
+	}()
+
+	if err = fn(tx); err != nil {
+		if errRollback := tx.Rollback(ctx); errRollback != nil {
+			return fmt.Errorf("%w (rollback: %s)", err, errRollback)
+		}
+
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,54 @@
+package tarantool
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mailru/activerecord/pkg/activerecord"
+)
+
+func TestWithTxEmptyConnection(t *testing.T) {
+	called := false
+
+	err := WithTx(context.Background(), nil, func(tx *Tx) error {
+		called = true
+		return nil
+	})
+	if err == nil {
+		t.Errorf("WithTx() error = nil, want error on empty connection")
+	}
+
+	if called {
+		t.Errorf("WithTx() called fn without transaction")
+	}
+}
+
+func Test_updateOperations(t *testing.T) {
+	if _, err := updateOperations([]Ops{{Field: 1, Op: OpSet, Value: 1}, {Field: 2, Op: OpAdd, Value: 2}}); err != nil {
+		t.Errorf("updateOperations() error = %v", err)
+	}
+
+	if _, err := updateOperations([]Ops{{Field: 1, Op: "?", Value: 1}}); err == nil {
+		t.Errorf("updateOperations() error = nil, want unknown operation error")
+	}
+}
+
+func TestUpdateReturningEmptyConnection(t *testing.T) {
+	var c *Connection
+
+	if _, err := c.UpdateReturning(context.Background(), "users", 0, []any{1}, nil); err == nil {
+		t.Errorf("UpdateReturning() error = nil, want error on empty connection")
+	}
+}
+
+func TestOutboxTuple(t *testing.T) {
+	created := time.Unix(1700000000, 5)
+	ev := activerecord.OutboxEvent{ID: "e1", Entity: "User", Op: activerecord.OutboxUpdate, Key: []byte(`{"ID":1}`), Payload: []byte(`{"id":1}`), Created: created}
+
+	want := []any{"e1", "User", "update", `{"ID":1}`, `{"id":1}`, uint64(1700000000000000005)}
+	if got := OutboxTuple(ev); !reflect.DeepEqual(got, want) {
+		t.Errorf("OutboxTuple() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package tarantool
+
+import gotarantool "github.com/tarantool/go-tarantool"
+
+// OpCode операция обновления поля
+type OpCode string
+
+// Операции обновления полей, поддерживаемые tarantool
+const (
+	OpSet OpCode = "="
+	OpAdd OpCode = "+"
+	OpSub OpCode = "-"
+)
+
+// Ops описание операции обновления поля записи
+type Ops struct {
+	Field int
+	Op    OpCode
+	Value any
+}
+
+// SetFieldOp добавляет в список операций обновления присваивание значения value полю field.
+// Tarantool не допускает нескольких операций над одним полем в одном запросе, поэтому
+// предыдущая операция над этим полем заменяется
+func SetFieldOp(ops []Ops, field int, value any) []Ops {
+	for i := range ops {
+		if ops[i].Field == field {
+			ops[i] = Ops{Field: field, Op: OpSet, Value: value}
+
+			return ops
+		}
+	}
+
+	return append(ops, Ops{Field: field, Op: OpSet, Value: value})
+}
+
+type BaseField struct {
+	UpdateOps   []Ops
+	ExtraFields []any
+	Exists      bool
+	IsReplica   bool
+	Readonly    bool
+	Partial     bool // Запись получена выборкой с проекцией полей и содержит не все поля
+}
+
+// Итераторы выборки по индексу
+const (
+	IterEq  = uint32(gotarantool.IterEq)
+	IterReq = uint32(gotarantool.IterReq) // Записи с ключом в обратном порядке индекса
+	IterAll = uint32(gotarantool.IterAll)
+	IterLt  = uint32(gotarantool.IterLt)
+	IterLe  = uint32(gotarantool.IterLe)
+	IterGe  = uint32(gotarantool.IterGe)
+	IterGt  = uint32(gotarantool.IterGt)
+)
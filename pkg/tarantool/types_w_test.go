@@ -0,0 +1,35 @@
+package tarantool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSetFieldOp(t *testing.T) {
+	tests := []struct {
+		name  string
+		ops   []Ops
+		field int
+		want  []Ops
+	}{
+		{
+			name:  "new field",
+			ops:   []Ops{{Field: 1, Op: OpSet, Value: "a"}},
+			field: 2,
+			want:  []Ops{{Field: 1, Op: OpSet, Value: "a"}, {Field: 2, Op: OpSet, Value: "b"}},
+		},
+		{
+			name:  "replace previous op",
+			ops:   []Ops{{Field: 1, Op: OpAdd, Value: 1}, {Field: 2, Op: OpSet, Value: "a"}},
+			field: 1,
+			want:  []Ops{{Field: 1, Op: OpSet, Value: "b"}, {Field: 2, Op: OpSet, Value: "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SetFieldOp(tt.ops, tt.field, "b"); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetFieldOp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}